@@ -36,6 +36,13 @@ func addKnownTypes(s *runtime.Scheme) error {
 		&LVMCluster{}, &LVMClusterList{},
 		&LVMVolumeGroup{}, &LVMVolumeGroupList{},
 		&LVMVolumeGroupNodeStatus{}, &LVMVolumeGroupNodeStatusList{},
+		&LVMVolumeImport{}, &LVMVolumeImportList{},
+		&LVMSnapshotSchedule{}, &LVMSnapshotScheduleList{},
+		&LVMVolumeRevert{}, &LVMVolumeRevertList{},
+		&LVMVolumeMigration{}, &LVMVolumeMigrationList{},
+		&LVMVolumeBackup{}, &LVMVolumeBackupList{},
+		&LVMVolumeRestore{}, &LVMVolumeRestoreList{},
+		&LVMVolumeCheck{}, &LVMVolumeCheckList{},
 	)
 	metav1.AddToGroupVersion(s, GroupVersion)
 	return nil
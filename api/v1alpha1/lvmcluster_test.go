@@ -8,6 +8,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/openshift/lvm-operator/v4/internal/cluster"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
 
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -120,19 +121,8 @@ var _ = Describe("webhook acceptance tests", func() {
 		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
 	})
 
-	It("duplicate LVMClusters get rejected", func(ctx SpecContext) {
-		generatedName := generateUniqueNameForTestCase(ctx)
-		GinkgoT().Setenv(cluster.OperatorNamespaceEnvVar, generatedName)
-		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: generatedName}}
-		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
-		DeferCleanup(func(ctx SpecContext) {
-			Expect(k8sClient.Delete(ctx, namespace)).To(Succeed())
-		})
-
-		resource := defaultClusterTemplate.DeepCopy()
-		resource.SetName(generatedName)
-		resource.SetNamespace(namespace.GetName())
-
+	It("second LVMCluster with another default device class gets rejected", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
 		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 
 		duplicate := resource.DeepCopy()
@@ -145,7 +135,7 @@ var _ = Describe("webhook acceptance tests", func() {
 
 		statusError := &k8serrors.StatusError{}
 		Expect(errors.As(err, &statusError)).To(BeTrue())
-		Expect(statusError.Status().Message).To(ContainSubstring(ErrDuplicateLVMCluster.Error()))
+		Expect(statusError.Status().Message).To(ContainSubstring(ErrOnlyOneDefaultDeviceClassAllowed.Error()))
 
 		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
 	},
@@ -155,6 +145,83 @@ var _ = Describe("webhook acceptance tests", func() {
 		FlakeAttempts(3),
 	)
 
+	It("second LVMCluster gets prefixed volume groups", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		Expect(resource.HasPrefixedVolumeGroups()).To(BeFalse())
+
+		second := resource.DeepCopy()
+		second.SetName(fmt.Sprintf("%s-second", second.GetName()))
+		second.SetResourceVersion("")
+		second.Spec.Storage.DeviceClasses[0].Default = false
+		Expect(k8sClient.Create(ctx, second)).To(Succeed())
+		Expect(second.HasPrefixedVolumeGroups()).To(BeTrue())
+		Expect(second.VolumeGroupName("test-device-class")).To(Equal(second.GetName() + "-test-device-class"))
+
+		By("the prefixed volume groups annotation cannot be removed")
+		delete(second.Annotations, constants.PrefixedVolumeGroupsAnnotation)
+		err := k8sClient.Update(ctx, second)
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(Satisfy(k8serrors.IsForbidden))
+		statusError := &k8serrors.StatusError{}
+		Expect(errors.As(err, &statusError)).To(BeTrue())
+		Expect(statusError.Status().Message).To(ContainSubstring(ErrPrefixedVolumeGroupsCannotBeChanged.Error()))
+
+		Expect(k8sClient.Delete(ctx, &LVMCluster{ObjectMeta: second.ObjectMeta})).To(Succeed())
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+	}, FlakeAttempts(3))
+
+	It("LVMClusters sharing a node need distinct device paths", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   resource.GetName(),
+			Labels: map[string]string{"pool": resource.GetName()},
+		}}
+		Expect(k8sClient.Create(ctx, node)).To(Succeed())
+		DeferCleanup(func(ctx SpecContext) {
+			Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+		})
+
+		resource.Spec.Storage.DeviceClasses[0].NodeSelector = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      "pool",
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{resource.GetName()},
+				}},
+			}},
+		}
+		resource.Spec.Storage.DeviceClasses[0].DeviceSelector = &DeviceSelector{Paths: []DevicePath{"/dev/sda"}}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+		second := resource.DeepCopy()
+		second.SetName(fmt.Sprintf("%s-second", second.GetName()))
+		second.SetResourceVersion("")
+		second.Spec.Storage.DeviceClasses[0].Default = false
+		second.Spec.Storage.DeviceClasses[0].DeviceSelector = nil
+
+		By("rejecting a device class without paths on the shared node")
+		err := k8sClient.Create(ctx, second.DeepCopy())
+		Expect(err).To(HaveOccurred())
+		statusError := &k8serrors.StatusError{}
+		Expect(errors.As(err, &statusError)).To(BeTrue())
+		Expect(statusError.Status().Message).To(ContainSubstring(ErrDeviceClassSharesNodesWithoutPaths.Error()))
+
+		By("rejecting an overlapping device path on the shared node")
+		second.Spec.Storage.DeviceClasses[0].DeviceSelector = &DeviceSelector{Paths: []DevicePath{"/dev/sda"}}
+		err = k8sClient.Create(ctx, second.DeepCopy())
+		Expect(err).To(HaveOccurred())
+		Expect(errors.As(err, &statusError)).To(BeTrue())
+		Expect(statusError.Status().Message).To(ContainSubstring(ErrDevicePathUsedByOtherLVMCluster.Error()))
+
+		By("accepting a distinct device path on the shared node")
+		second.Spec.Storage.DeviceClasses[0].DeviceSelector = &DeviceSelector{Paths: []DevicePath{"/dev/sdb"}}
+		Expect(k8sClient.Create(ctx, second)).To(Succeed())
+
+		Expect(k8sClient.Delete(ctx, second)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+	}, FlakeAttempts(3))
+
 	It("namespace cannot be looked up via ENV", func(ctx SpecContext) {
 		generatedName := generateUniqueNameForTestCase(ctx)
 		inacceptableNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: generatedName}}
@@ -451,6 +518,163 @@ var _ = Describe("webhook acceptance tests", func() {
 		Expect(statusError.Status().Message).To(ContainSubstring(ErrPathsOrOptionalPathsMandatoryWithNonNilDeviceSelector.Error()))
 	})
 
+	It("node overrides with paths on different nodes are allowed", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		resource.Spec.Storage.DeviceClasses[0].DeviceSelector = &DeviceSelector{Paths: []DevicePath{"/dev/sda"}}
+		resource.Spec.Storage.DeviceClasses[0].NodeOverrides = []DeviceClassNodeOverride{{
+			Name: "nvme",
+			NodeSelector: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      "hardware",
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{"nvme"},
+				}},
+			}}},
+			DeviceSelector:      &DeviceSelector{Paths: []DevicePath{"/dev/nvme0n1", "/dev/nvme1n1"}},
+			ThinPoolSizePercent: ptr.To(80),
+		}}
+
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+	})
+
+	It("node override without paths is invalid", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		resource.Spec.Storage.DeviceClasses[0].NodeOverrides = []DeviceClassNodeOverride{{
+			Name:           "empty",
+			NodeSelector:   &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{}}},
+			DeviceSelector: &DeviceSelector{},
+		}}
+
+		err := k8sClient.Create(ctx, resource)
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(Satisfy(k8serrors.IsForbidden))
+
+		statusError := &k8serrors.StatusError{}
+		Expect(errors.As(err, &statusError)).To(BeTrue())
+		Expect(statusError.Status().Message).To(ContainSubstring(ErrPathsOrOptionalPathsMandatoryWithNonNilDeviceSelector.Error()))
+	})
+
+	It("node override keeps its thinPoolSizePercent when its node selector is changed", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		resource.Spec.Storage.DeviceClasses[0].NodeOverrides = []DeviceClassNodeOverride{{
+			Name:                "large",
+			NodeSelector:        nodeSelectorForLabel("hardware", "nvme"),
+			ThinPoolSizePercent: ptr.To(80),
+		}}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+		updated := resource.DeepCopy()
+		updated.Spec.Storage.DeviceClasses[0].NodeOverrides[0].NodeSelector = nodeSelectorForLabel("hardware", "ssd")
+		updated.Spec.Storage.DeviceClasses[0].NodeOverrides[0].ThinPoolSizePercent = ptr.To(70)
+
+		err := k8sClient.Update(ctx, updated)
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(Satisfy(k8serrors.IsForbidden))
+		statusError := &k8serrors.StatusError{}
+		Expect(errors.As(err, &statusError)).To(BeTrue())
+		Expect(statusError.Status().Message).To(ContainSubstring(ErrThinPoolConfigCannotBeChanged.Error()))
+
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+	})
+
+	It("node overrides cannot start or stop matching a node with an existing volume group", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   resource.GetName(),
+			Labels: map[string]string{"hardware": "nvme"},
+		}}
+		Expect(k8sClient.Create(ctx, node)).To(Succeed())
+		DeferCleanup(func(ctx SpecContext) {
+			Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+		})
+		nodeStatus := &LVMVolumeGroupNodeStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: node.GetName(), Namespace: resource.GetNamespace()},
+		}
+		Expect(k8sClient.Create(ctx, nodeStatus)).To(Succeed())
+		nodeStatus.Status.VolumeGroups = []VolumeGroupStatus{{VGStatus: VGStatus{
+			Name:    resource.Spec.Storage.DeviceClasses[0].Name,
+			Status:  VGStatusReady,
+			Devices: []string{"/dev/sda"},
+		}}}
+		Expect(k8sClient.Status().Update(ctx, nodeStatus)).To(Succeed())
+
+		resource.Spec.Storage.DeviceClasses[0].NodeOverrides = []DeviceClassNodeOverride{{
+			Name:                "ssd",
+			NodeSelector:        nodeSelectorForLabel("hardware", "ssd"),
+			ThinPoolSizePercent: ptr.To(80),
+		}}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+		By("rejecting a node selector change that matches the node")
+		updated := resource.DeepCopy()
+		updated.Spec.Storage.DeviceClasses[0].NodeOverrides[0].NodeSelector = nodeSelectorForLabel("hardware", "nvme")
+		err := k8sClient.Update(ctx, updated)
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(Satisfy(k8serrors.IsForbidden))
+		statusError := &k8serrors.StatusError{}
+		Expect(errors.As(err, &statusError)).To(BeTrue())
+		Expect(statusError.Status().Message).To(ContainSubstring(ErrNodeOverrideMatchingCannotBeChanged.Error()))
+
+		By("rejecting a new node override that matches the node")
+		updated = resource.DeepCopy()
+		updated.Spec.Storage.DeviceClasses[0].NodeOverrides = append(updated.Spec.Storage.DeviceClasses[0].NodeOverrides, DeviceClassNodeOverride{
+			Name:                "nvme",
+			NodeSelector:        nodeSelectorForLabel("hardware", "nvme"),
+			ThinPoolSizePercent: ptr.To(80),
+		})
+		err = k8sClient.Update(ctx, updated)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.As(err, &statusError)).To(BeTrue())
+		Expect(statusError.Status().Message).To(ContainSubstring(ErrNodeOverrideMatchingCannotBeChanged.Error()))
+
+		By("accepting a new node override for other nodes")
+		updated.Spec.Storage.DeviceClasses[0].NodeOverrides[1].NodeSelector = nodeSelectorForLabel("hardware", "hdd")
+		Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+		Expect(k8sClient.Delete(ctx, updated)).To(Succeed())
+	})
+
+	It("node overrides cannot start matching a node with a volume group only reported in the deprecated spec", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   resource.GetName(),
+			Labels: map[string]string{"hardware": "nvme"},
+		}}
+		Expect(k8sClient.Create(ctx, node)).To(Succeed())
+		DeferCleanup(func(ctx SpecContext) {
+			Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+		})
+		// node statuses written before the upgrade have no status.volumeGroups yet
+		nodeStatus := &LVMVolumeGroupNodeStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: node.GetName(), Namespace: resource.GetNamespace()},
+			Spec: LVMVolumeGroupNodeStatusSpec{LVMVGStatus: []VGStatus{{
+				Name:    resource.Spec.Storage.DeviceClasses[0].Name,
+				Status:  VGStatusReady,
+				Devices: []string{"/dev/sda"},
+			}}},
+		}
+		Expect(k8sClient.Create(ctx, nodeStatus)).To(Succeed())
+
+		resource.Spec.Storage.DeviceClasses[0].NodeOverrides = []DeviceClassNodeOverride{{
+			Name:                "ssd",
+			NodeSelector:        nodeSelectorForLabel("hardware", "ssd"),
+			ThinPoolSizePercent: ptr.To(80),
+		}}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+		updated := resource.DeepCopy()
+		updated.Spec.Storage.DeviceClasses[0].NodeOverrides[0].NodeSelector = nodeSelectorForLabel("hardware", "nvme")
+		err := k8sClient.Update(ctx, updated)
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(Satisfy(k8serrors.IsForbidden))
+		statusError := &k8serrors.StatusError{}
+		Expect(errors.As(err, &statusError)).To(BeTrue())
+		Expect(statusError.Status().Message).To(ContainSubstring(ErrNodeOverrideMatchingCannotBeChanged.Error()))
+
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+	})
+
 	It("multiple device classes without path list are not allowed", func(ctx SpecContext) {
 		resource := defaultLVMClusterInUniqueNamespace(ctx)
 		resource.Spec.Storage.DeviceClasses = append(resource.Spec.Storage.DeviceClasses, DeviceClass{Name: "test",
@@ -544,7 +768,7 @@ var _ = Describe("webhook acceptance tests", func() {
 		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
 	})
 
-	It("updating NodeSelector is not allowed", func(ctx SpecContext) {
+	It("updating NodeSelector is allowed", func(ctx SpecContext) {
 		resource := defaultLVMClusterInUniqueNamespace(ctx)
 		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 
@@ -561,13 +785,11 @@ var _ = Describe("webhook acceptance tests", func() {
 				},
 			},
 		}}
+		updated.Spec.Storage.DeviceClasses[0].NodeRemovalPolicy = NodeRemovalPolicyRemove
+		Expect(k8sClient.Update(ctx, updated)).To(Succeed())
 
-		err := k8sClient.Update(ctx, updated)
-		Expect(err).To(HaveOccurred())
-		Expect(err).To(Satisfy(k8serrors.IsForbidden))
-		statusError := &k8serrors.StatusError{}
-		Expect(errors.As(err, &statusError)).To(BeTrue())
-		Expect(statusError.Status().Message).To(ContainSubstring(ErrNodeSelectorCannotBeChanged.Error()))
+		updated.Spec.Storage.DeviceClasses[0].NodeSelector = nil
+		Expect(k8sClient.Update(ctx, updated)).To(Succeed())
 
 		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
 	})
@@ -714,6 +936,43 @@ var _ = Describe("webhook acceptance tests", func() {
 		Expect(k8sClient.Delete(ctx, updated2)).To(Succeed())
 	})
 
+	It("device paths can be appended to device class in update", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		resource.Spec.Storage.DeviceClasses[0].DeviceSelector = &DeviceSelector{
+			Paths:                             []DevicePath{"/dev/path1", "/dev/path2"},
+			OptionalPaths:                     []DevicePath{"/dev/optional1"},
+			ForceWipeDevicesAndDestroyAllData: ptr.To(true),
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+		updated := resource.DeepCopy()
+		updated.Spec.Storage.DeviceClasses[0].DeviceSelector.Paths = []DevicePath{"/dev/path2", "/dev/path3"}
+		updated.Spec.Storage.DeviceClasses[0].DeviceSelector.OptionalPaths = []DevicePath{"/dev/optional1", "/dev/optional2"}
+		Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+		Expect(k8sClient.Delete(ctx, updated)).To(Succeed())
+	})
+
+	It("device paths cannot be reordered in update", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		resource.Spec.Storage.DeviceClasses[0].DeviceSelector = &DeviceSelector{
+			Paths: []DevicePath{"/dev/path1", "/dev/path2"},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+		updated := resource.DeepCopy()
+		updated.Spec.Storage.DeviceClasses[0].DeviceSelector.Paths = []DevicePath{"/dev/path3", "/dev/path2"}
+
+		err := k8sClient.Update(ctx, updated)
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(Satisfy(k8serrors.IsForbidden))
+		statusError := &k8serrors.StatusError{}
+		Expect(errors.As(err, &statusError)).To(BeTrue())
+		Expect(statusError.Status().Message).To(ContainSubstring(ErrDevicePathsCanOnlyBeAppended.Error()))
+
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+	})
+
 	It("force wipe option cannot be added in update", func(ctx SpecContext) {
 		resource := defaultLVMClusterInUniqueNamespace(ctx)
 		resource.Spec.Storage.DeviceClasses[0].DeviceSelector = &DeviceSelector{Paths: []DevicePath{"/dev/newpath"}}
@@ -1317,4 +1576,103 @@ var _ = Describe("webhook acceptance tests", func() {
 		Expect(k8sClient.Delete(ctx, updated)).To(Succeed())
 	})
 
+	// ThickSnapshotConfig validation tests
+
+	It("rejects thickSnapshotConfig and thinPoolConfig both set", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		resource.Spec.Storage.DeviceClasses[0].ThickSnapshotConfig = &ThickSnapshotConfig{ReservePercent: 20}
+		err := k8sClient.Create(ctx, resource)
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(Satisfy(k8serrors.IsForbidden))
+		statusError := &k8serrors.StatusError{}
+		Expect(errors.As(err, &statusError)).To(BeTrue())
+		Expect(statusError.Status().Message).To(ContainSubstring(ErrThickSnapshotAndThinPoolMutuallyExclusive.Error()))
+	})
+
+	It("accepts thickSnapshotConfig on a raid device class", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		resource.Spec.Storage.DeviceClasses[0].ThinPoolConfig = nil
+		resource.Spec.Storage.DeviceClasses[0].RAIDConfig = &RAIDConfig{
+			Type: RAIDTypeRAID1,
+		}
+		resource.Spec.Storage.DeviceClasses[0].DeviceSelector = &DeviceSelector{
+			Paths: []DevicePath{"/dev/sda", "/dev/sdb"},
+		}
+		resource.Spec.Storage.DeviceClasses[0].ThickSnapshotConfig = &ThickSnapshotConfig{}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		Expect(resource.Spec.Storage.DeviceClasses[0].ThickSnapshotConfig).To(Equal(&ThickSnapshotConfig{
+			ReservePercent:      20,
+			AutoExtendThreshold: 70,
+			AutoExtendPercent:   20,
+		}))
+
+		updated := resource.DeepCopy()
+		updated.Spec.Storage.DeviceClasses[0].ThickSnapshotConfig.ReservePercent = 50
+		Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+		Expect(k8sClient.Delete(ctx, updated)).To(Succeed())
+	})
+
+	It("rejects adding thickSnapshotConfig to a thin device class on update", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+		updated := resource.DeepCopy()
+		updated.Spec.Storage.DeviceClasses[0].ThickSnapshotConfig = &ThickSnapshotConfig{ReservePercent: 20}
+		err := k8sClient.Update(ctx, updated)
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(Satisfy(k8serrors.IsForbidden))
+
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+	})
+
+	It("accepts mkfsOptions and mountOptions on create", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		resource.Spec.Storage.DeviceClasses[0].MkfsOptions = []string{"-m", "reflink=1"}
+		resource.Spec.Storage.DeviceClasses[0].MountOptions = []string{"noatime", "discard"}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+		updated := resource.DeepCopy()
+		updated.Spec.Storage.DeviceClasses[0].MountOptions = []string{"noatime"}
+		Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+	})
+
+	It("rejects mkfsOptions with whitespace on create", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		resource.Spec.Storage.DeviceClasses[0].MkfsOptions = []string{"-m reflink=1"}
+		err := k8sClient.Create(ctx, resource)
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(Satisfy(k8serrors.IsForbidden))
+		statusError := &k8serrors.StatusError{}
+		Expect(errors.As(err, &statusError)).To(BeTrue())
+		Expect(statusError.Status().Message).To(ContainSubstring("must not be empty or contain whitespace"))
+	})
+
+	It("rejects adding mkfsOptions to an existing device class on update", func(ctx SpecContext) {
+		resource := defaultLVMClusterInUniqueNamespace(ctx)
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+		updated := resource.DeepCopy()
+		updated.Spec.Storage.DeviceClasses[0].MkfsOptions = []string{"-m", "reflink=1"}
+		err := k8sClient.Update(ctx, updated)
+		Expect(err).To(HaveOccurred())
+		statusError := &k8serrors.StatusError{}
+		Expect(errors.As(err, &statusError)).To(BeTrue())
+		Expect(statusError.Status().Message).To(ContainSubstring("mkfsOptions is immutable"))
+
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+	})
+
 })
+
+func nodeSelectorForLabel(key, value string) *corev1.NodeSelector {
+	return &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+		MatchExpressions: []corev1.NodeSelectorRequirement{{
+			Key:      key,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{value},
+		}},
+	}}}
+}
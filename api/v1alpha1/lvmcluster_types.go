@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -34,8 +36,36 @@ type LVMClusterSpec struct {
 	// Storage contains the device class configuration for local storage devices.
 	// +Optional
 	Storage Storage `json:"storage,omitempty"`
+
+	// Paused stops the reconciliation of the LVMCluster and its volume groups on all nodes.
+	// While paused, no resources are created, updated or deleted by the operator and no volume group
+	// operations are performed by the vg-manager. Deletion of the LVMCluster is also held back until it is unpaused.
+	// +kubebuilder:default=false
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// StrandedVolumePolicy specifies what happens to PersistentVolumes whose node was removed from the cluster.
+	// Such volumes are always marked with the lvms.topolvm.io/stranded annotation.
+	// With Delete, PersistentVolumes with the Retain reclaim policy that are no longer bound to a claim are deleted
+	// once their node has been removed for 10 minutes. Bound PersistentVolumes are never deleted.
+	// The policy applies to the PersistentVolumes of the device classes of this LVMCluster. PersistentVolumes
+	// of device classes that are not part of any LVMCluster anymore are retained.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	// +optional
+	StrandedVolumePolicy StrandedVolumePolicy `json:"strandedVolumePolicy,omitempty"`
 }
 
+// StrandedVolumePolicy defines how PersistentVolumes of removed nodes are handled.
+type StrandedVolumePolicy string
+
+const (
+	// StrandedVolumePolicyRetain only marks the PersistentVolumes of removed nodes.
+	StrandedVolumePolicyRetain StrandedVolumePolicy = "Retain"
+	// StrandedVolumePolicyDelete deletes unbound PersistentVolumes of removed nodes.
+	StrandedVolumePolicyDelete StrandedVolumePolicy = "Delete"
+)
+
 type ThinPoolConfig struct {
 	// Name specifies a name for the thin pool.
 	// +kubebuilder:validation:Required
@@ -127,6 +157,26 @@ const (
 	FilesystemTypeXFS  DeviceFilesystemType = "xfs"
 )
 
+// OrphanedLogicalVolumePolicy defines how orphaned logical volumes in a volume group are handled.
+type OrphanedLogicalVolumePolicy string
+
+const (
+	// OrphanedLogicalVolumePolicyRetain only reports orphaned logical volumes.
+	OrphanedLogicalVolumePolicyRetain OrphanedLogicalVolumePolicy = "Retain"
+	// OrphanedLogicalVolumePolicyDelete removes orphaned logical volumes.
+	OrphanedLogicalVolumePolicyDelete OrphanedLogicalVolumePolicy = "Delete"
+)
+
+// NodeRemovalPolicy defines what happens to the volume group on a node that is no longer selected by a device class.
+type NodeRemovalPolicy string
+
+const (
+	// NodeRemovalPolicyRetain stops managing the volume group on the node and keeps it with its logical volumes.
+	NodeRemovalPolicyRetain NodeRemovalPolicy = "Retain"
+	// NodeRemovalPolicyRemove deletes the volume group on the node once it contains no logical volumes anymore.
+	NodeRemovalPolicyRemove NodeRemovalPolicy = "Remove"
+)
+
 // RAIDType represents the LVM RAID level for a device class.
 // +kubebuilder:validation:Enum=raid1;raid4;raid5;raid6;raid10
 type RAIDType string
@@ -190,6 +240,86 @@ func (r *RAIDConfig) EffectiveMirrors() int {
 	return 1
 }
 
+// OverheadFactor returns the raw-to-usable space ratio for the given RAID level and device count.
+func (r *RAIDConfig) OverheadFactor(deviceCount int) float64 {
+	mirrors := r.EffectiveMirrors()
+
+	switch r.Type {
+	case RAIDTypeRAID1:
+		return float64(mirrors + 1)
+	case RAIDTypeRAID4, RAIDTypeRAID5:
+		if r.Stripes != nil {
+			return float64(*r.Stripes+1) / float64(*r.Stripes)
+		}
+		if deviceCount <= 1 {
+			return 1.0
+		}
+		return float64(deviceCount) / float64(deviceCount-1)
+	case RAIDTypeRAID6:
+		if r.Stripes != nil {
+			return float64(*r.Stripes+2) / float64(*r.Stripes)
+		}
+		if deviceCount <= 2 {
+			return 1.0
+		}
+		return float64(deviceCount) / float64(deviceCount-2)
+	case RAIDTypeRAID10:
+		return float64(mirrors + 1)
+	default:
+		return 1.0
+	}
+}
+
+// ThickSnapshotConfig configures copy-on-write snapshots for a thick provisioned device class.
+// Mutually exclusive with ThinPoolConfig.
+type ThickSnapshotConfig struct {
+	// ReservePercent is the size of the copy-on-write space that is reserved in the volume group for a snapshot,
+	// in percent of the size of the snapshotted volume. A snapshot becomes invalid once all changes to its
+	// origin no longer fit into the reserved space.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=20
+	// +optional
+	ReservePercent int `json:"reservePercent,omitempty"`
+
+	// AutoExtendThreshold is the usage of the copy-on-write space in percent at which a snapshot is extended.
+	// Setting it to 100 disables the automatic extension.
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=70
+	// +optional
+	AutoExtendThreshold int `json:"autoExtendThreshold,omitempty"`
+
+	// AutoExtendPercent is the amount in percent of its current size by which a snapshot is extended
+	// once it reaches the AutoExtendThreshold.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=20
+	// +optional
+	AutoExtendPercent int `json:"autoExtendPercent,omitempty"`
+}
+
+// HasPrefixedVolumeGroups returns true if the volume groups of the LVMCluster are prefixed with its name.
+func (l *LVMCluster) HasPrefixedVolumeGroups() bool {
+	return l.GetAnnotations()[constants.PrefixedVolumeGroupsAnnotation] == "true"
+}
+
+// VolumeGroupName returns the name of the volume group of a device class of the LVMCluster.
+// It is used for the LVMVolumeGroup, the volume group on the node and the TopoLVM device class.
+func (l *LVMCluster) VolumeGroupName(deviceClassName string) string {
+	if l.HasPrefixedVolumeGroups() {
+		return l.GetName() + "-" + deviceClassName
+	}
+	return deviceClassName
+}
+
+// IsDefaultDeviceClass returns true if the device class is the default device class of the LVMCluster.
+// A single device class is the default even if it is not explicitly marked, unless the volume groups
+// are prefixed, because then another LVMCluster may provide the default device class.
+func (l *LVMCluster) IsDefaultDeviceClass(dc *DeviceClass) bool {
+	return dc.Default || (len(l.Spec.Storage.DeviceClasses) == 1 && !l.HasPrefixedVolumeGroups())
+}
+
 // HasExplicitPaths returns true if the device class has explicit device paths configured.
 func (dc *DeviceClass) HasExplicitPaths() bool {
 	return dc.DeviceSelector != nil &&
@@ -212,6 +342,17 @@ type DeviceClass struct {
 	// +optional
 	NodeSelector *corev1.NodeSelector `json:"nodeSelector,omitempty"`
 
+	// NodeOverrides override the device selector and the thin pool size of this device class on the nodes matching
+	// their node selector, so that nodes with different hardware layouts can share the device class and its StorageClass.
+	// The first override matching a node is used. Nodes not matching any override use the configuration of the device class.
+	// Overrides are identified by their name, and an override can not start or stop matching a node on which
+	// the volume group of the device class already exists.
+	// +kubebuilder:validation:MaxItems=32
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	NodeOverrides []DeviceClassNodeOverride `json:"nodeOverrides,omitempty"`
+
 	// ThinPoolConfig contains the configuration to create a thin pool in the LVM volume group. If you exclude this field, logical volumes are thick provisioned.
 	// +optional
 	ThinPoolConfig *ThinPoolConfig `json:"thinPoolConfig,omitempty"`
@@ -223,6 +364,11 @@ type DeviceClass struct {
 	// +kubebuilder:validation:XValidation:rule="oldSelf == self",message="raidConfig is immutable after creation"
 	RAIDConfig *RAIDConfig `json:"raidConfig,omitempty"`
 
+	// ThickSnapshotConfig enables copy-on-write snapshots for this thick provisioned device class
+	// and the creation of a VolumeSnapshotClass for it. Mutually exclusive with ThinPoolConfig.
+	// +optional
+	ThickSnapshotConfig *ThickSnapshotConfig `json:"thickSnapshotConfig,omitempty"`
+
 	// Default is a flag to indicate that a device class is the default. You can configure only a single default device class.
 	// +optional
 	Default bool `json:"default,omitempty"`
@@ -236,6 +382,21 @@ type DeviceClass struct {
 	// +kubebuilder:validation:XValidation:rule="oldSelf == self",message="fstype is immutable"
 	FilesystemType DeviceFilesystemType `json:"fstype,omitempty"`
 
+	// MkfsOptions are additional options passed to mkfs when the filesystem of a persistent volume created
+	// from this device class is created, one option or value per entry, for example `["-m", "reflink=1"]` for xfs
+	// or `["-E", "lazy_itable_init=1"]` for ext4. They are applied on top of the default options of TopoLVM
+	// and do not affect existing volumes.
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:XValidation:rule="oldSelf == self",message="mkfsOptions is immutable"
+	// +optional
+	MkfsOptions []string `json:"mkfsOptions,omitempty"`
+
+	// MountOptions are the mount options of the StorageClass created for this device class,
+	// for example `["noatime", "discard"]`. Changes only apply to persistent volumes provisioned afterwards.
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	MountOptions []string `json:"mountOptions,omitempty"`
+
 	// DeviceDiscoveryPolicy specifies the policy for discovering devices for this device class.
 	// Static means the volume group is created with devices found at install time; new devices are ignored.
 	// Dynamic means devices are continuously discovered and added to the volume group.
@@ -251,6 +412,51 @@ type DeviceClass struct {
 	// +optional
 	// +kubebuilder:default={}
 	StorageClassOptions *StorageClassOptions `json:"storageClassOptions,omitempty"`
+
+	// OrphanedLogicalVolumePolicy specifies what happens to logical volumes in the volume group that were provisioned
+	// by TopoLVM but are no longer referenced by any LogicalVolume.
+	// Retain only reports them in the status of the volume group.
+	// Delete additionally removes them once they were found orphaned in two consecutive consistency checks
+	// and for at least 30 minutes. Nothing is deleted while no LogicalVolume exists for the volume group on the node.
+	// Deleted logical volumes and their data cannot be recovered.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	// +optional
+	OrphanedLogicalVolumePolicy OrphanedLogicalVolumePolicy `json:"orphanedLogicalVolumePolicy,omitempty"`
+
+	// NodeRemovalPolicy specifies what happens to the volume group on nodes that are no longer selected by the device class,
+	// either because the node selector was changed or because the labels of the node were changed.
+	// Retain stops managing the volume group on the node, removes it from the lvmd config and keeps it with its logical volumes.
+	// Remove deletes the volume group on the node once it contains no logical volumes anymore.
+	// +kubebuilder:validation:Enum=Retain;Remove
+	// +kubebuilder:default=Retain
+	// +optional
+	NodeRemovalPolicy NodeRemovalPolicy `json:"nodeRemovalPolicy,omitempty"`
+}
+
+// DeviceClassNodeOverride overrides the configuration of a device class on the nodes matching its node selector.
+type DeviceClassNodeOverride struct {
+	// Name identifies the node override within the device class.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Name string `json:"name"`
+
+	// NodeSelector chooses the nodes on which the override is used.
+	// +required
+	NodeSelector *corev1.NodeSelector `json:"nodeSelector"`
+
+	// DeviceSelector replaces the device selector of the device class on the matching nodes.
+	// For device classes with RAIDConfig, these are the devices the RAID logical volumes are spread across.
+	// +optional
+	DeviceSelector *DeviceSelector `json:"deviceSelector,omitempty"`
+
+	// ThinPoolSizePercent replaces the size of the thin pool in percent of the volume group on the matching nodes.
+	// It can only be set if the device class has a ThinPoolConfig.
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ThinPoolSizePercent *int `json:"thinPoolSizePercent,omitempty"`
 }
 
 // StorageClassOptions defines optional overrides for the StorageClass generated by LVMS for a device class.
@@ -342,6 +548,11 @@ type LVMClusterStatus struct {
 	// +optional
 	Ready bool `json:"ready,omitempty"`
 
+	// ObservedGeneration is the generation of the LVMCluster that was last reconciled.
+	// It is not updated while the LVMCluster is paused.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// State describes the LVMCluster state.
 	// +optional
 	State LVMStateType `json:"state,omitempty"`
@@ -360,6 +571,17 @@ const (
 
 	// VolumeGroupsReady indicates whether the volume groups maintained by the operator are in a ready state.
 	VolumeGroupsReady = "VolumeGroupsReady"
+
+	// Paused indicates whether the reconciliation of the LVMCluster is paused via spec.paused.
+	Paused = "Paused"
+)
+
+const (
+	// DeviceClassReady indicates whether the volume group of a device class is ready on all nodes it is expected on.
+	DeviceClassReady = "Ready"
+
+	// DeviceClassDegraded indicates whether the volume group of a device class is failed or degraded on any node.
+	DeviceClassDegraded = "Degraded"
 )
 
 // DeviceClassStatus defines the observed status of the deviceclass across all nodes
@@ -368,6 +590,22 @@ type DeviceClassStatus struct {
 	Name string `json:"name,omitempty"`
 	// NodeStatus tells if the deviceclass was created on the node
 	NodeStatus []NodeStatus `json:"nodeStatus,omitempty"`
+	// Conditions describes the state of the deviceclass across all nodes.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ExpectedNodes is the number of nodes the deviceclass is expected to be created on.
+	// +optional
+	ExpectedNodes int `json:"expectedNodes,omitempty"`
+	// ReadyNodes is the number of nodes the volume group of the deviceclass is ready on.
+	// +optional
+	ReadyNodes int `json:"readyNodes,omitempty"`
+	// FailedNodes is the number of nodes the volume group of the deviceclass failed on.
+	// +optional
+	FailedNodes int `json:"failedNodes,omitempty"`
+	// Capacity is the size and usage of the volume group of the deviceclass summed up over all nodes,
+	// as reported by vg-manager.
+	// +optional
+	Capacity *VGCapacity `json:"capacity,omitempty"`
 }
 
 type Storage struct {
@@ -21,7 +21,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+	"unicode"
 
 	"github.com/openshift/lvm-operator/v4/internal/cluster"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
@@ -30,6 +32,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	corev1helper "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -48,7 +52,9 @@ type lvmClusterValidator struct {
 
 var _ admission.Validator[*LVMCluster] = &lvmClusterValidator{}
 
-type lvmClusterDefaulter struct{}
+type lvmClusterDefaulter struct {
+	client.Client
+}
 
 var _ admission.Defaulter[*LVMCluster] = &lvmClusterDefaulter{}
 
@@ -60,11 +66,19 @@ var (
 	ErrOnlyOneDefaultDeviceClassAllowed                      = errors.New("only one default deviceClass is allowed")
 	ErrPathsOrOptionalPathsMandatoryWithNonNilDeviceSelector = errors.New("either paths or optionalPaths must be specified when DeviceSelector is specified")
 	ErrEmptyPathsWithMultipleDeviceClasses                   = errors.New("path list should not be empty when there are multiple deviceClasses")
-	ErrDuplicateLVMCluster                                   = errors.New("duplicate LVMClusters are not allowed, remove the old LVMCluster or work with the existing instance")
+	ErrVolumeGroupNameConflict                               = errors.New("volume group name is already used by another LVMCluster")
+	ErrDeviceClassSharesNodesWithoutPaths                    = errors.New("device classes of different LVMClusters that share nodes must specify paths or optionalPaths")
+	ErrDevicePathUsedByOtherLVMCluster                       = errors.New("device path is already used by another LVMCluster on a shared node")
+	ErrPrefixedVolumeGroupsCannotBeChanged                   = errors.New("the prefixed volume groups annotation can not be changed")
+	ErrNodeOverrideNodeSelectorNotSet                        = errors.New("nodeSelector must be specified for a node override")
+	ErrNodeOverrideNameNotSet                                = errors.New("name must be specified for a node override")
+	ErrNodeOverrideNameNotUnique                             = errors.New("names of the node overrides of a device class must be unique")
+	ErrNodeOverrideMatchingCannotBeChanged                   = errors.New("node overrides can not start or stop matching a node on which the volume group already exists")
+	ErrNodeOverrideIsEmpty                                   = errors.New("a node override must specify deviceSelector or thinPoolSizePercent")
 	ErrThinPoolConfigCannotBeChanged                         = errors.New("ThinPoolConfig can not be changed")
 	ErrThinPoolMetadataSizeCanOnlyBeIncreased                = errors.New("thin pool metadata size can only be increased")
-	ErrNodeSelectorCannotBeChanged                           = errors.New("NodeSelector can not be changed")
 	ErrDevicePathsCannotBeAddedInUpdate                      = errors.New("device paths can not be added after a device class has been initialized")
+	ErrDevicePathsCanOnlyBeAppended                          = errors.New("existing device paths can not be reordered, new device paths have to be appended")
 	ErrForceWipeOptionCannotBeChanged                        = errors.New("ForceWipeDevicesAndDestroyAllData can not be changed")
 	ErrRAIDAndThinPoolMutuallyExclusive                      = errors.New("raidConfig and thinPoolConfig are mutually exclusive")
 	ErrRAIDMirrorsOnlyForRAID1AndRAID10                      = errors.New("mirrors is only valid for raid1 and raid10")
@@ -74,6 +88,7 @@ var (
 	ErrRAIDStripeSizeNotPowerOf2                             = errors.New("stripeSize must be a power of 2 (e.g., 64Ki, 128Ki, 256Ki, 512Ki)")
 	ErrRAIDConfigCannotBeChanged                             = errors.New("raidConfig cannot be changed")
 	ErrRAIDConfigNotSet                                      = errors.New("RAIDConfig is not set for the DeviceClass")
+	ErrThickSnapshotAndThinPoolMutuallyExclusive             = errors.New("thickSnapshotConfig and thinPoolConfig are mutually exclusive")
 )
 
 //+kubebuilder:webhook:path=/mutate-lvm-topolvm-io-v1alpha1-lvmcluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=lvm.topolvm.io,resources=lvmclusters,verbs=create,versions=v1alpha1,name=mlvmcluster.kb.io,admissionReviewVersions=v1
@@ -81,13 +96,35 @@ var (
 
 func (l *LVMCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr, l).
-		WithDefaulter(&lvmClusterDefaulter{}).
+		WithDefaulter(&lvmClusterDefaulter{Client: mgr.GetClient()}).
 		WithValidator(&lvmClusterValidator{Client: mgr.GetClient()}).
 		Complete()
 }
 
-func (d *lvmClusterDefaulter) Default(_ context.Context, l *LVMCluster) error {
+func (d *lvmClusterDefaulter) Default(ctx context.Context, l *LVMCluster) error {
 	lvmclusterlog.Info("defaulting", "name", l.Name)
+
+	// An LVMCluster created next to an existing one gets volume groups prefixed with its name,
+	// so that LVMVolumeGroups, StorageClasses and VolumeSnapshotClasses do not collide.
+	if !l.HasPrefixedVolumeGroups() {
+		existing := &LVMClusterList{}
+		if err := d.List(ctx, existing, client.InNamespace(l.GetNamespace())); err != nil {
+			return fmt.Errorf("could not list existing LVMClusters: %w", err)
+		}
+		for _, other := range existing.Items {
+			if other.GetName() == l.GetName() {
+				continue
+			}
+			annotations := l.GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[constants.PrefixedVolumeGroupsAnnotation] = "true"
+			l.SetAnnotations(annotations)
+			break
+		}
+	}
+
 	for i := range l.Spec.Storage.DeviceClasses {
 		dc := &l.Spec.Storage.DeviceClasses[i]
 		if dc.DeviceDiscoveryPolicy == nil && !dc.HasExplicitPaths() {
@@ -113,14 +150,6 @@ func (v *lvmClusterValidator) ValidateCreate(ctx context.Context, l *LVMCluster)
 		)
 	}
 
-	existing := &LVMClusterList{}
-	if err := v.List(ctx, existing, &client.ListOptions{Limit: 1, Namespace: l.GetNamespace()}); err != nil {
-		return warnings, fmt.Errorf("could not verify that LVMCluster was not already created %w", err)
-	} else if len(existing.Items) > 0 {
-		return warnings, fmt.Errorf("LVMCluster exists at %q: %w",
-			client.ObjectKeyFromObject(&existing.Items[0]), ErrDuplicateLVMCluster)
-	}
-
 	deviceClassWarnings, err := v.verifyDeviceClass(l)
 	warnings = append(warnings, deviceClassWarnings...)
 	if err != nil {
@@ -132,6 +161,11 @@ func (v *lvmClusterValidator) ValidateCreate(ctx context.Context, l *LVMCluster)
 		return warnings, err
 	}
 
+	err = v.verifyThickSnapshotConfig(l)
+	if err != nil {
+		return warnings, err
+	}
+
 	pathWarnings, err := v.verifyPathsAreNotEmpty(l)
 	warnings = append(warnings, pathWarnings...)
 	if err != nil {
@@ -143,16 +177,36 @@ func (v *lvmClusterValidator) ValidateCreate(ctx context.Context, l *LVMCluster)
 		return warnings, err
 	}
 
+	err = v.verifyNodeOverrides(l)
+	if err != nil {
+		return warnings, err
+	}
+
 	err = v.verifyNoDeviceOverlap(l)
 	if err != nil {
 		return warnings, err
 	}
 
+	err = v.verifyVolumeGroupNames(l)
+	if err != nil {
+		return warnings, err
+	}
+
+	err = v.verifyNoConflictWithOtherLVMClusters(ctx, l, nil)
+	if err != nil {
+		return warnings, err
+	}
+
 	err = v.verifyFstype(l)
 	if err != nil {
 		return warnings, err
 	}
 
+	err = v.verifyFilesystemOptions(l)
+	if err != nil {
+		return warnings, err
+	}
+
 	err = v.verifyChunkSize(l)
 	if err != nil {
 		return warnings, err
@@ -177,10 +231,14 @@ func (v *lvmClusterValidator) ValidateCreate(ctx context.Context, l *LVMCluster)
 }
 
 // ValidateUpdate implements admission.Validator so a webhook will be registered for the type
-func (v *lvmClusterValidator) ValidateUpdate(_ context.Context, oldLVMCluster, l *LVMCluster) (admission.Warnings, error) {
+func (v *lvmClusterValidator) ValidateUpdate(ctx context.Context, oldLVMCluster, l *LVMCluster) (admission.Warnings, error) {
 	lvmclusterlog.Info("validate update", "name", l.Name)
 	warnings := admission.Warnings{}
 
+	if oldLVMCluster.HasPrefixedVolumeGroups() != l.HasPrefixedVolumeGroups() {
+		return warnings, ErrPrefixedVolumeGroupsCannotBeChanged
+	}
+
 	deviceClassWarnings, err := v.verifyDeviceClass(l)
 	warnings = append(warnings, deviceClassWarnings...)
 	if err != nil {
@@ -192,6 +250,11 @@ func (v *lvmClusterValidator) ValidateUpdate(_ context.Context, oldLVMCluster, l
 		return warnings, err
 	}
 
+	err = v.verifyThickSnapshotConfig(l)
+	if err != nil {
+		return warnings, err
+	}
+
 	pathWarnings, err := v.verifyPathsAreNotEmpty(l)
 	warnings = append(warnings, pathWarnings...)
 	if err != nil {
@@ -203,16 +266,36 @@ func (v *lvmClusterValidator) ValidateUpdate(_ context.Context, oldLVMCluster, l
 		return warnings, err
 	}
 
+	err = v.verifyNodeOverrides(l)
+	if err != nil {
+		return warnings, err
+	}
+
 	err = v.verifyNoDeviceOverlap(l)
 	if err != nil {
 		return warnings, err
 	}
 
+	err = v.verifyVolumeGroupNames(l)
+	if err != nil {
+		return warnings, err
+	}
+
+	err = v.verifyNoConflictWithOtherLVMClusters(ctx, l, oldLVMCluster)
+	if err != nil {
+		return warnings, err
+	}
+
 	err = v.verifyFstype(l)
 	if err != nil {
 		return warnings, err
 	}
 
+	err = v.verifyFilesystemOptions(l)
+	if err != nil {
+		return warnings, err
+	}
+
 	scOptionWarnings, err := v.validateAdditionalParamsAndLabels(l)
 	warnings = append(warnings, scOptionWarnings...)
 	if err != nil {
@@ -223,6 +306,10 @@ func (v *lvmClusterValidator) ValidateUpdate(_ context.Context, oldLVMCluster, l
 		return warnings, fmt.Errorf("storageClassOptions upgrade validation failed: %w", err)
 	}
 
+	if err := validateMkfsOptionsUpdate(oldLVMCluster.Spec.Storage.DeviceClasses, l.Spec.Storage.DeviceClasses); err != nil {
+		return warnings, err
+	}
+
 	// Validate device class removal follows the business rules
 	err = validateDeviceClassRemoval(oldLVMCluster.Spec.Storage.DeviceClasses, l.Spec.Storage.DeviceClasses)
 	if err != nil {
@@ -284,14 +371,6 @@ func (v *lvmClusterValidator) ValidateUpdate(_ context.Context, oldLVMCluster, l
 			}
 		}
 
-		newNodeSelector := deviceClass.NodeSelector
-		oldNodeSelector, err := v.getNodeSelectorOfDeviceClass(oldLVMCluster, deviceClass.Name)
-		if (newNodeSelector != nil && oldNodeSelector == nil && !errors.Is(err, ErrDeviceClassNotFound)) ||
-			(newNodeSelector == nil && oldNodeSelector != nil) ||
-			(newNodeSelector != nil && oldNodeSelector != nil && !reflect.DeepEqual(newNodeSelector, oldNodeSelector)) {
-			return warnings, ErrNodeSelectorCannotBeChanged
-		}
-
 		if deviceClass.DeviceSelector != nil {
 			newDevices = deviceClass.DeviceSelector.Paths
 			newOptionalDevices = deviceClass.DeviceSelector.OptionalPaths
@@ -305,6 +384,13 @@ func (v *lvmClusterValidator) ValidateUpdate(_ context.Context, oldLVMCluster, l
 			continue
 		}
 
+		if err := validateNodeOverridesUpdate(oldLVMCluster, &deviceClass); err != nil {
+			return warnings, err
+		}
+		if err := v.verifyNodeOverridesMatchExistingVolumeGroups(ctx, oldLVMCluster, l, &deviceClass); err != nil {
+			return warnings, err
+		}
+
 		// Make sure ForceWipeDevicesAndDestroyAllData was not changed
 		if (oldForceWipeOption == nil && newForceWipeOption != nil) ||
 			(oldForceWipeOption != nil && newForceWipeOption == nil) ||
@@ -324,11 +410,183 @@ func (v *lvmClusterValidator) ValidateUpdate(_ context.Context, oldLVMCluster, l
 		if len(oldDevices)+len(oldOptionalDevices) > 0 && len(newDevices)+len(newOptionalDevices) == 0 {
 			return warnings, fmt.Errorf("cannot remove all device paths from device class %s: at least one device path must remain", deviceClass.Name)
 		}
+
+		if err := validateDevicePathsAppended(oldDevices, newDevices); err != nil {
+			return warnings, fmt.Errorf("paths of device class %s are invalid: %w", deviceClass.Name, err)
+		}
+		if err := validateDevicePathsAppended(oldOptionalDevices, newOptionalDevices); err != nil {
+			return warnings, fmt.Errorf("optionalPaths of device class %s are invalid: %w", deviceClass.Name, err)
+		}
 	}
 
 	return warnings, nil
 }
 
+// validateNodeOverridesUpdate makes sure that the node overrides of a device class that were present before,
+// identified by their name, follow the same update rules as the device class itself, even if their node selector
+// was changed. Whether added, removed or changed node overrides affect existing volume groups is checked by
+// verifyNodeOverridesMatchExistingVolumeGroups.
+func validateNodeOverridesUpdate(old *LVMCluster, deviceClass *DeviceClass) error {
+	var oldDeviceClass *DeviceClass
+	for i := range old.Spec.Storage.DeviceClasses {
+		if old.Spec.Storage.DeviceClasses[i].Name == deviceClass.Name {
+			oldDeviceClass = &old.Spec.Storage.DeviceClasses[i]
+		}
+	}
+	if oldDeviceClass == nil {
+		return nil
+	}
+
+	for _, override := range deviceClass.NodeOverrides {
+		oldOverride := nodeOverrideWithName(oldDeviceClass, override.Name)
+		if oldOverride == nil {
+			continue
+		}
+		if !reflect.DeepEqual(override.ThinPoolSizePercent, oldOverride.ThinPoolSizePercent) {
+			return fmt.Errorf("thinPoolSizePercent of a node override of device class %s is invalid: %w",
+				deviceClass.Name, ErrThinPoolConfigCannotBeChanged)
+		}
+		if (oldOverride.DeviceSelector == nil) != (override.DeviceSelector == nil) {
+			return fmt.Errorf("deviceSelector of a node override of device class %s can not be added or removed in update: %w",
+				deviceClass.Name, ErrDevicePathsCannotBeAddedInUpdate)
+		}
+		if override.DeviceSelector != nil &&
+			!reflect.DeepEqual(override.DeviceSelector.ForceWipeDevicesAndDestroyAllData, oldOverride.DeviceSelector.ForceWipeDevicesAndDestroyAllData) {
+			return fmt.Errorf("node override of device class %s is invalid: %w", deviceClass.Name, ErrForceWipeOptionCannotBeChanged)
+		}
+		if override.DeviceSelector != nil {
+			if err := validateDevicePathsAppended(oldOverride.DeviceSelector.Paths, override.DeviceSelector.Paths); err != nil {
+				return fmt.Errorf("paths of a node override of device class %s are invalid: %w", deviceClass.Name, err)
+			}
+			if err := validateDevicePathsAppended(oldOverride.DeviceSelector.OptionalPaths, override.DeviceSelector.OptionalPaths); err != nil {
+				return fmt.Errorf("optionalPaths of a node override of device class %s are invalid: %w", deviceClass.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateDevicePathsAppended makes sure that new device paths are only appended to the existing ones.
+// Existing paths can be removed, but the remaining ones have to keep their order and come before any new path,
+// so that a path can not be replaced in place by a different device.
+func validateDevicePathsAppended(old, new []DevicePath) error {
+	var kept []DevicePath
+	for _, path := range old {
+		if slices.Contains(new, path) {
+			kept = append(kept, path)
+		}
+	}
+	if len(new) < len(kept) || !slices.Equal(kept, new[:len(kept)]) {
+		return ErrDevicePathsCanOnlyBeAppended
+	}
+	return nil
+}
+
+// nodeOverrideWithName returns the node override of the device class with the given name.
+func nodeOverrideWithName(deviceClass *DeviceClass, name string) *DeviceClassNodeOverride {
+	for i := range deviceClass.NodeOverrides {
+		if deviceClass.NodeOverrides[i].Name == name {
+			return &deviceClass.NodeOverrides[i]
+		}
+	}
+	return nil
+}
+
+// verifyNodeOverridesMatchExistingVolumeGroups makes sure that every node on which the volume group of the device class
+// already exists keeps using the same node override, or none, after the node overrides were added, removed or their
+// node selectors were changed. Switching the configuration of an existing volume group would replace its devices.
+func (v *lvmClusterValidator) verifyNodeOverridesMatchExistingVolumeGroups(ctx context.Context, old, l *LVMCluster, deviceClass *DeviceClass) error {
+	var oldDeviceClass *DeviceClass
+	for i := range old.Spec.Storage.DeviceClasses {
+		if old.Spec.Storage.DeviceClasses[i].Name == deviceClass.Name {
+			oldDeviceClass = &old.Spec.Storage.DeviceClasses[i]
+		}
+	}
+	if oldDeviceClass == nil || !nodeOverrideSelectorsChanged(oldDeviceClass, deviceClass) {
+		return nil
+	}
+
+	nodeStatuses := &LVMVolumeGroupNodeStatusList{}
+	if err := v.List(ctx, nodeStatuses, client.InNamespace(l.GetNamespace())); err != nil {
+		return fmt.Errorf("could not list LVMVolumeGroupNodeStatuses to verify the node overrides of device class %s: %w", deviceClass.Name, err)
+	}
+	vgName := l.VolumeGroupName(deviceClass.Name)
+	for _, nodeStatus := range nodeStatuses.Items {
+		if !hasVolumeGroup(&nodeStatus, vgName) {
+			continue
+		}
+		node := &corev1.Node{}
+		if err := v.Get(ctx, client.ObjectKey{Name: nodeStatus.GetName()}, node); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("could not get node %s to verify the node overrides of device class %s: %w", nodeStatus.GetName(), deviceClass.Name, err)
+		} else if err != nil {
+			continue
+		}
+		oldOverride, err := matchingNodeOverride(oldDeviceClass, node)
+		if err != nil {
+			return err
+		}
+		newOverride, err := matchingNodeOverride(deviceClass, node)
+		if err != nil {
+			return err
+		}
+		if oldOverride != newOverride {
+			return fmt.Errorf("node %s of device class %s would use node override %q instead of %q: %w",
+				node.GetName(), deviceClass.Name, newOverride, oldOverride, ErrNodeOverrideMatchingCannotBeChanged)
+		}
+	}
+	return nil
+}
+
+// nodeOverrideSelectorsChanged returns true if node overrides were added, removed or their node selectors or order
+// were changed, which can change the node override used on a node.
+func nodeOverrideSelectorsChanged(old, deviceClass *DeviceClass) bool {
+	if len(old.NodeOverrides) != len(deviceClass.NodeOverrides) {
+		return true
+	}
+	for i := range deviceClass.NodeOverrides {
+		if old.NodeOverrides[i].Name != deviceClass.NodeOverrides[i].Name ||
+			!reflect.DeepEqual(old.NodeOverrides[i].NodeSelector, deviceClass.NodeOverrides[i].NodeSelector) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchingNodeOverride returns the name of the first node override of the device class matching the node,
+// which is the one used by vg-manager, or an empty string if none matches.
+func matchingNodeOverride(deviceClass *DeviceClass, node *corev1.Node) (string, error) {
+	for _, override := range deviceClass.NodeOverrides {
+		if override.NodeSelector == nil {
+			continue
+		}
+		matches, err := corev1helper.MatchNodeSelectorTerms(node, override.NodeSelector)
+		if err != nil {
+			return "", fmt.Errorf("failed to match node override %s of device class %s to node %s: %w",
+				override.Name, deviceClass.Name, node.GetName(), err)
+		}
+		if matches {
+			return override.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// hasVolumeGroup returns true if the volume group was created on the node of the node status.
+// Node statuses that were not yet written by an upgraded vg-manager only report the volume groups
+// in the deprecated spec.nodeStatus.
+func hasVolumeGroup(nodeStatus *LVMVolumeGroupNodeStatus, vgName string) bool {
+	if len(nodeStatus.Status.VolumeGroups) == 0 {
+		for _, vgStatus := range nodeStatus.Spec.LVMVGStatus {
+			if vgStatus.Name == vgName {
+				return len(vgStatus.Devices) > 0
+			}
+		}
+		return false
+	}
+	vgStatus := nodeStatus.VolumeGroupStatus(vgName)
+	return vgStatus != nil && len(vgStatus.Devices) > 0
+}
+
 // validateDeviceClassRemoval validates that device class removal follows the business rules:
 // 1. Cannot delete the last device class
 // 2. Cannot delete default device class
@@ -468,49 +726,254 @@ func (v *lvmClusterValidator) verifyNoDeviceOverlap(l *LVMCluster) error {
 	devices := make(map[string]map[DevicePath]string)
 
 	for _, deviceClass := range l.Spec.Storage.DeviceClasses {
+		// the device selectors of node overrides are checked against the device selectors with the same node selector
+		selectors := []DeviceClassNodeOverride{{NodeSelector: deviceClass.NodeSelector, DeviceSelector: deviceClass.DeviceSelector}}
+		selectors = append(selectors, deviceClass.NodeOverrides...)
 
-		if deviceClass.DeviceSelector == nil {
-			continue
+		for _, selector := range selectors {
+			if err := addDevicesWithoutOverlap(devices, deviceClass.Name, selector.NodeSelector, selector.DeviceSelector); err != nil {
+				return err
+			}
 		}
+	}
+
+	return nil
+}
+
+// addDevicesWithoutOverlap adds the paths of the device selector to the devices per node selector,
+// failing if one of them was already added before.
+func addDevicesWithoutOverlap(devices map[string]map[DevicePath]string, deviceClassName string, nodeSelectorTerms *corev1.NodeSelector, deviceSelector *DeviceSelector) error {
+	if deviceSelector == nil {
+		return nil
+	}
 
-		nodeSelector := deviceClass.NodeSelector.String()
+	nodeSelector := nodeSelectorTerms.String()
 
-		// Required paths
-		for _, path := range deviceClass.DeviceSelector.Paths {
-			if val, ok := devices[nodeSelector][path]; ok {
-				if val != deviceClass.Name {
-					return fmt.Errorf("error: device path %s overlaps in two different deviceClasss %s and %s", path, val, deviceClass.Name)
-				}
-				return fmt.Errorf("error: device path %s is specified at multiple places in deviceClass %s", path, val)
+	// Required paths
+	for _, path := range deviceSelector.Paths {
+		if val, ok := devices[nodeSelector][path]; ok {
+			if val != deviceClassName {
+				return fmt.Errorf("error: device path %s overlaps in two different deviceClasss %s and %s", path, val, deviceClassName)
 			}
+			return fmt.Errorf("error: device path %s is specified at multiple places in deviceClass %s", path, val)
+		}
+
+		if devices[nodeSelector] == nil {
+			devices[nodeSelector] = make(map[DevicePath]string)
+		}
 
-			if devices[nodeSelector] == nil {
-				devices[nodeSelector] = make(map[DevicePath]string)
+		devices[nodeSelector][path] = deviceClassName
+	}
+
+	// Optional paths
+	for _, path := range deviceSelector.OptionalPaths {
+		if val, ok := devices[nodeSelector][path]; ok {
+			if val != deviceClassName {
+				return fmt.Errorf("error: optional device path %s overlaps in two different deviceClasss %s and %s", path, val, deviceClassName)
 			}
+			return fmt.Errorf("error: optional device path %s is specified at multiple places in deviceClass %s", path, val)
+		}
 
-			devices[nodeSelector][path] = deviceClass.Name
+		if devices[nodeSelector] == nil {
+			devices[nodeSelector] = make(map[DevicePath]string)
 		}
 
-		// Optional paths
-		for _, path := range deviceClass.DeviceSelector.OptionalPaths {
-			if val, ok := devices[nodeSelector][path]; ok {
-				if val != deviceClass.Name {
-					return fmt.Errorf("error: optional device path %s overlaps in two different deviceClasss %s and %s", path, val, deviceClass.Name)
+		devices[nodeSelector][path] = deviceClassName
+	}
+
+	return nil
+}
+
+// verifyNodeOverrides makes sure that every node override of a device class has a unique name, selects nodes and overrides
+// a valid device selector or thin pool size.
+func (v *lvmClusterValidator) verifyNodeOverrides(l *LVMCluster) error {
+	for _, deviceClass := range l.Spec.Storage.DeviceClasses {
+		names := make(map[string]struct{}, len(deviceClass.NodeOverrides))
+		for _, override := range deviceClass.NodeOverrides {
+			if override.Name == "" {
+				return fmt.Errorf("invalid node override of device class %s: %w", deviceClass.Name, ErrNodeOverrideNameNotSet)
+			}
+			if _, ok := names[override.Name]; ok {
+				return fmt.Errorf("node override %s of device class %s is invalid: %w", override.Name, deviceClass.Name, ErrNodeOverrideNameNotUnique)
+			}
+			names[override.Name] = struct{}{}
+			if override.NodeSelector == nil {
+				return fmt.Errorf("invalid node override of device class %s: %w", deviceClass.Name, ErrNodeOverrideNodeSelectorNotSet)
+			}
+			if override.DeviceSelector == nil && override.ThinPoolSizePercent == nil {
+				return fmt.Errorf("invalid node override of device class %s: %w", deviceClass.Name, ErrNodeOverrideIsEmpty)
+			}
+			if override.ThinPoolSizePercent != nil && deviceClass.ThinPoolConfig == nil {
+				return fmt.Errorf("thinPoolSizePercent is set in a node override of device class %s: %w", deviceClass.Name, ErrThinPoolConfigNotSet)
+			}
+			if override.DeviceSelector == nil {
+				continue
+			}
+			if len(override.DeviceSelector.Paths) == 0 && len(override.DeviceSelector.OptionalPaths) == 0 {
+				return fmt.Errorf("invalid node override of device class %s: %w", deviceClass.Name, ErrPathsOrOptionalPathsMandatoryWithNonNilDeviceSelector)
+			}
+			for _, path := range append(slices.Clone(override.DeviceSelector.Paths), override.DeviceSelector.OptionalPaths...) {
+				if !strings.HasPrefix(path.Unresolved(), "/dev/") {
+					return fmt.Errorf("path %s in a node override of device class %s must be an absolute path to the device", path.Unresolved(), deviceClass.Name)
 				}
-				return fmt.Errorf("error: optional device path %s is specified at multiple places in deviceClass %s", path, val)
 			}
+		}
+	}
+	return nil
+}
 
-			if devices[nodeSelector] == nil {
-				devices[nodeSelector] = make(map[DevicePath]string)
-			}
+// verifyVolumeGroupNames makes sure that the StorageClass names derived from the volume group names are valid.
+// This can only fail for prefixed volume groups with a long LVMCluster name.
+func (v *lvmClusterValidator) verifyVolumeGroupNames(l *LVMCluster) error {
+	for _, deviceClass := range l.Spec.Storage.DeviceClasses {
+		scName := constants.StorageClassPrefix + l.VolumeGroupName(deviceClass.Name)
+		if errs := k8svalidation.IsDNS1123Subdomain(scName); len(errs) > 0 {
+			return fmt.Errorf("deviceClass %s results in the invalid StorageClass name %q: %s",
+				deviceClass.Name, scName, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// verifyNoConflictWithOtherLVMClusters makes sure that the LVMCluster can be operated next to the other LVMClusters
+// in the namespace. Volume group names must be unique and only one device class may be the default.
+// Device classes of different LVMClusters may only be placed on the same node if both use explicit device paths
+// that do not overlap. On update, only device classes that were added or changed are checked.
+func (v *lvmClusterValidator) verifyNoConflictWithOtherLVMClusters(ctx context.Context, l *LVMCluster, old *LVMCluster) error {
+	existing := &LVMClusterList{}
+	if err := v.List(ctx, existing, client.InNamespace(l.GetNamespace())); err != nil {
+		return fmt.Errorf("could not verify that LVMCluster does not conflict with other LVMClusters: %w", err)
+	}
+
+	var others []LVMCluster
+	for _, other := range existing.Items {
+		if other.GetName() != l.GetName() {
+			others = append(others, other)
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := v.List(ctx, nodes); err != nil {
+		return fmt.Errorf("could not list nodes to verify that LVMCluster does not conflict with other LVMClusters: %w", err)
+	}
+
+	for i := range l.Spec.Storage.DeviceClasses {
+		deviceClass := &l.Spec.Storage.DeviceClasses[i]
+		if old != nil && !deviceClassChanged(old, deviceClass) {
+			continue
+		}
+		for j := range others {
+			other := &others[j]
+			for k := range other.Spec.Storage.DeviceClasses {
+				otherDeviceClass := &other.Spec.Storage.DeviceClasses[k]
+
+				if vgName := l.VolumeGroupName(deviceClass.Name); vgName == other.VolumeGroupName(otherDeviceClass.Name) {
+					return fmt.Errorf("volume group %s of deviceClass %s is used by LVMCluster %s: %w",
+						vgName, deviceClass.Name, other.GetName(), ErrVolumeGroupNameConflict)
+				}
+
+				if l.IsDefaultDeviceClass(deviceClass) && other.IsDefaultDeviceClass(otherDeviceClass) {
+					return fmt.Errorf("deviceClass %s of LVMCluster %s is already the default: %w",
+						otherDeviceClass.Name, other.GetName(), ErrOnlyOneDefaultDeviceClassAllowed)
+				}
+
+				node, err := firstSharedNode(nodes, l, deviceClass, other, otherDeviceClass)
+				if err != nil {
+					return err
+				}
+				if node == "" {
+					continue
+				}
 
-			devices[nodeSelector][path] = deviceClass.Name
+				if !deviceClass.HasExplicitPaths() || !otherDeviceClass.HasExplicitPaths() {
+					return fmt.Errorf("deviceClass %s and deviceClass %s of LVMCluster %s are both placed on node %s: %w",
+						deviceClass.Name, otherDeviceClass.Name, other.GetName(), node, ErrDeviceClassSharesNodesWithoutPaths)
+				}
+
+				otherPaths := make(map[DevicePath]struct{})
+				for _, path := range allPathsOfDeviceClass(otherDeviceClass) {
+					otherPaths[path] = struct{}{}
+				}
+				for _, path := range allPathsOfDeviceClass(deviceClass) {
+					if _, ok := otherPaths[path]; ok {
+						return fmt.Errorf("device path %s of deviceClass %s is used by deviceClass %s of LVMCluster %s on node %s: %w",
+							path, deviceClass.Name, otherDeviceClass.Name, other.GetName(), node, ErrDevicePathUsedByOtherLVMCluster)
+					}
+				}
+			}
 		}
 	}
 
 	return nil
 }
 
+// deviceClassChanged returns true if the device class was added or its devices or default flag were changed
+// compared to the old LVMCluster.
+func deviceClassChanged(old *LVMCluster, deviceClass *DeviceClass) bool {
+	for _, oldDeviceClass := range old.Spec.Storage.DeviceClasses {
+		if oldDeviceClass.Name == deviceClass.Name {
+			return oldDeviceClass.Default != deviceClass.Default ||
+				!reflect.DeepEqual(oldDeviceClass.DeviceSelector, deviceClass.DeviceSelector) ||
+				!reflect.DeepEqual(oldDeviceClass.NodeOverrides, deviceClass.NodeOverrides)
+		}
+	}
+	return true
+}
+
+// firstSharedNode returns the name of the first node that both device classes would be placed on, or an empty string.
+func firstSharedNode(nodes *corev1.NodeList, l *LVMCluster, deviceClass *DeviceClass, other *LVMCluster, otherDeviceClass *DeviceClass) (string, error) {
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if matches, err := deviceClassMatchesNode(l, deviceClass, node); err != nil {
+			return "", err
+		} else if !matches {
+			continue
+		}
+		if matches, err := deviceClassMatchesNode(other, otherDeviceClass, node); err != nil {
+			return "", err
+		} else if matches {
+			return node.GetName(), nil
+		}
+	}
+	return "", nil
+}
+
+// deviceClassMatchesNode returns true if the device class of the LVMCluster would be placed on the node.
+// Taints of nodes that are not ready or unreachable are ignored as their devices are still present.
+func deviceClassMatchesNode(l *LVMCluster, deviceClass *DeviceClass, node *corev1.Node) (bool, error) {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == corev1.TaintNodeNotReady || taint.Key == corev1.TaintNodeUnreachable {
+			continue
+		}
+		if !corev1helper.TolerationsTolerateTaint(klog.Background(), l.Spec.Tolerations, &taint, true) {
+			return false, nil
+		}
+	}
+	if deviceClass.NodeSelector == nil {
+		return true, nil
+	}
+	return corev1helper.MatchNodeSelectorTerms(node, deviceClass.NodeSelector)
+}
+
+// allPathsOfDeviceClass returns the paths and optional paths of the device class and its node overrides.
+func allPathsOfDeviceClass(deviceClass *DeviceClass) []DevicePath {
+	var paths []DevicePath
+	if deviceClass.DeviceSelector != nil {
+		paths = append(paths, deviceClass.DeviceSelector.Paths...)
+		paths = append(paths, deviceClass.DeviceSelector.OptionalPaths...)
+	}
+	for _, override := range deviceClass.NodeOverrides {
+		if override.DeviceSelector != nil {
+			paths = append(paths, override.DeviceSelector.Paths...)
+			paths = append(paths, override.DeviceSelector.OptionalPaths...)
+		}
+	}
+	return paths
+}
+
 func (v *lvmClusterValidator) getPathsOfDeviceClass(l *LVMCluster, deviceClassName string) (required []DevicePath, optional []DevicePath, forceWipe *bool, err error) {
 	for _, deviceClass := range l.Spec.Storage.DeviceClasses {
 		if deviceClass.Name == deviceClassName {
@@ -527,20 +990,6 @@ func (v *lvmClusterValidator) getPathsOfDeviceClass(l *LVMCluster, deviceClassNa
 	return
 }
 
-func (v *lvmClusterValidator) getNodeSelectorOfDeviceClass(l *LVMCluster, deviceClassName string) (*corev1.NodeSelector, error) {
-
-	for _, deviceClass := range l.Spec.Storage.DeviceClasses {
-		if deviceClass.Name == deviceClassName {
-			if deviceClass.NodeSelector != nil {
-				return deviceClass.NodeSelector, nil
-			}
-			return nil, ErrNodeSelectorNotSet
-		}
-	}
-
-	return nil, ErrDeviceClassNotFound
-}
-
 func (v *lvmClusterValidator) getThinPoolsConfigOfDeviceClass(l *LVMCluster, deviceClassName string) (*ThinPoolConfig, error) {
 
 	for _, deviceClass := range l.Spec.Storage.DeviceClasses {
@@ -565,6 +1014,42 @@ func (v *lvmClusterValidator) verifyFstype(l *LVMCluster) error {
 	return nil
 }
 
+// verifyFilesystemOptions rejects mkfs and mount options that are empty or contain whitespace,
+// as the mkfs options are passed to the node as a single StorageClass parameter separated by spaces.
+func (v *lvmClusterValidator) verifyFilesystemOptions(l *LVMCluster) error {
+	for _, deviceClass := range l.Spec.Storage.DeviceClasses {
+		for _, option := range deviceClass.MkfsOptions {
+			if option == "" || strings.ContainsFunc(option, unicode.IsSpace) {
+				return fmt.Errorf("device class %q: mkfsOptions entry %q must not be empty or contain whitespace, use one entry per option and value", deviceClass.Name, option)
+			}
+			if option == "-t" || strings.HasPrefix(option, "--type") {
+				return fmt.Errorf("device class %q: mkfsOptions must not set the filesystem type, use fstype instead", deviceClass.Name)
+			}
+		}
+		for _, option := range deviceClass.MountOptions {
+			if option == "" || strings.ContainsFunc(option, unicode.IsSpace) {
+				return fmt.Errorf("device class %q: mountOptions entry %q must not be empty or contain whitespace", deviceClass.Name, option)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateMkfsOptionsUpdate rejects changes of the mkfs options of existing device classes, as they are a
+// parameter of the StorageClass, which can not be updated. The CRD XValidation transition rule does not
+// fire when the options are added to a device class without them.
+func validateMkfsOptionsUpdate(oldDeviceClasses, newDeviceClasses []DeviceClass) error {
+	for _, oldDeviceClass := range oldDeviceClasses {
+		for _, newDeviceClass := range newDeviceClasses {
+			if newDeviceClass.Name == oldDeviceClass.Name && !slices.Equal(newDeviceClass.MkfsOptions, oldDeviceClass.MkfsOptions) {
+				return fmt.Errorf("device class %q: mkfsOptions is immutable", newDeviceClass.Name)
+			}
+		}
+	}
+	return nil
+}
+
 func (v *lvmClusterValidator) verifyChunkSize(l *LVMCluster) error {
 	for _, dc := range l.Spec.Storage.DeviceClasses {
 		if dc.ThinPoolConfig == nil {
@@ -630,6 +1115,7 @@ func (v *lvmClusterValidator) verifyDeviceDiscoveryPolicy(l *LVMCluster) admissi
 var lvmsOwnedParameterKeys = map[string]struct{}{
 	constants.DeviceClassKey: {},
 	constants.FsTypeKey:      {},
+	constants.MkfsOptionsKey: {},
 }
 
 // validateAdditionalParamsAndLabels rejects LVMS-owned parameter keys and operator-reserved
@@ -673,6 +1159,15 @@ func (v *lvmClusterValidator) validateAdditionalParamsAndLabels(l *LVMCluster) (
 	return warnings, nil
 }
 
+func (v *lvmClusterValidator) verifyThickSnapshotConfig(l *LVMCluster) error {
+	for _, dc := range l.Spec.Storage.DeviceClasses {
+		if dc.ThickSnapshotConfig != nil && dc.ThinPoolConfig != nil {
+			return fmt.Errorf("device class %q: %w", dc.Name, ErrThickSnapshotAndThinPoolMutuallyExclusive)
+		}
+	}
+	return nil
+}
+
 func (v *lvmClusterValidator) verifyRAIDConfig(l *LVMCluster) error {
 	for _, dc := range l.Spec.Storage.DeviceClasses {
 		if dc.RAIDConfig == nil {
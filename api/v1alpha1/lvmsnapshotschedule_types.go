@@ -0,0 +1,145 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LVMSnapshotScheduleSpec defines the desired state of LVMSnapshotSchedule
+type LVMSnapshotScheduleSpec struct {
+	// Schedule is the schedule in Cron format (UTC) at which VolumeSnapshots are created,
+	// e.g. "0 */6 * * *". The macros @hourly, @daily, @weekly and @monthly are supported as well.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// PersistentVolumeClaimSelector selects the PersistentVolumeClaims that are snapshotted by their labels.
+	// If empty, all PersistentVolumeClaims of LVMS StorageClasses in the selected namespaces are snapshotted.
+	// +optional
+	PersistentVolumeClaimSelector *metav1.LabelSelector `json:"persistentVolumeClaimSelector,omitempty"`
+
+	// NamespaceSelector selects the namespaces in which PersistentVolumeClaims are snapshotted.
+	// If empty, only PersistentVolumeClaims in the namespace of the LVMSnapshotSchedule are snapshotted.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// VolumeSnapshotClassName is the VolumeSnapshotClass used for the VolumeSnapshots.
+	// If empty, the LVMS VolumeSnapshotClass of the device class of the PersistentVolumeClaim is used.
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+
+	// Retention limits the number and age of the VolumeSnapshots kept for every PersistentVolumeClaim.
+	// If empty, VolumeSnapshots are never deleted by the schedule.
+	// +optional
+	Retention *SnapshotRetention `json:"retention,omitempty"`
+
+	// Paused suspends the creation and deletion of VolumeSnapshots until it is unset again.
+	// Schedules that were missed while paused are not caught up.
+	// +kubebuilder:default=false
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// SnapshotRetention describes which VolumeSnapshots created by a schedule are kept.
+// If both limits are set, a VolumeSnapshot is deleted once it exceeds either of them.
+// +kubebuilder:validation:XValidation:rule="has(self.maxCount) || has(self.maxAge)",message="at least one of maxCount or maxAge must be set"
+type SnapshotRetention struct {
+	// MaxCount is the maximum number of VolumeSnapshots kept for every PersistentVolumeClaim.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxCount *int32 `json:"maxCount,omitempty"`
+
+	// MaxAge is the maximum age of the VolumeSnapshots kept for every PersistentVolumeClaim, e.g. "168h".
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+}
+
+const (
+	// SnapshotScheduleReady indicates whether the schedule is valid and creating VolumeSnapshots
+	SnapshotScheduleReady = "Ready"
+)
+
+// SkippedPersistentVolumeClaim is a selected PersistentVolumeClaim that is not snapshotted by the schedule.
+type SkippedPersistentVolumeClaim struct {
+	// Namespace is the namespace of the PersistentVolumeClaim.
+	Namespace string `json:"namespace"`
+	// Name is the name of the PersistentVolumeClaim.
+	Name string `json:"name"`
+	// Reason is a machine-readable reason why the PersistentVolumeClaim is skipped.
+	Reason string `json:"reason"`
+	// Message is a human-readable description why the PersistentVolumeClaim is skipped.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// LVMSnapshotScheduleStatus defines the observed state of LVMSnapshotSchedule
+type LVMSnapshotScheduleStatus struct {
+	// Conditions describes the state of the schedule.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastScheduleTime is the last time VolumeSnapshots were created by the schedule.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextScheduleTime is the next time VolumeSnapshots will be created by the schedule.
+	// It is not set while the schedule is paused.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// PersistentVolumeClaims is the number of PersistentVolumeClaims that are snapshotted by the schedule.
+	// +optional
+	PersistentVolumeClaims int32 `json:"persistentVolumeClaims,omitempty"`
+
+	// VolumeSnapshots is the number of VolumeSnapshots that currently exist for the schedule.
+	// +optional
+	VolumeSnapshots int32 `json:"volumeSnapshots,omitempty"`
+
+	// Skipped lists the selected PersistentVolumeClaims that are not snapshotted, e.g. because
+	// they are provisioned from a thick device class without thickSnapshotConfig.
+	// +optional
+	Skipped []SkippedPersistentVolumeClaim `json:"skipped,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+//+kubebuilder:printcolumn:name="Paused",type=boolean,JSONPath=`.spec.paused`
+//+kubebuilder:printcolumn:name="Last Schedule",type=date,JSONPath=`.status.lastScheduleTime`
+//+kubebuilder:printcolumn:name="Next Schedule",type=date,JSONPath=`.status.nextScheduleTime`
+//+kubebuilder:printcolumn:name="PVCs",type=integer,JSONPath=`.status.persistentVolumeClaims`
+
+// LVMSnapshotSchedule is the Schema for the lvmsnapshotschedules API.
+// It periodically creates VolumeSnapshots of the selected LVMS PersistentVolumeClaims
+// and deletes them according to its retention.
+type LVMSnapshotSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LVMSnapshotScheduleSpec   `json:"spec,omitempty"`
+	Status LVMSnapshotScheduleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LVMSnapshotScheduleList contains a list of LVMSnapshotSchedule
+type LVMSnapshotScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LVMSnapshotSchedule `json:"items"`
+}
@@ -0,0 +1,204 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LVMVolumeBackupSpec defines the desired state of LVMVolumeBackup
+type LVMVolumeBackupSpec struct {
+	// VolumeSnapshot references the VolumeSnapshot whose data is backed up.
+	// The VolumeSnapshot has to be ready and provisioned by LVMS.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="volumeSnapshot is immutable"
+	VolumeSnapshot VolumeBackupSnapshotReference `json:"volumeSnapshot"`
+
+	// Location is the object storage the backup is uploaded to.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="location is immutable"
+	Location BackupLocation `json:"location"`
+
+	// BaseBackupName is the name of a completed LVMVolumeBackup in the same namespace the backup is
+	// taken incrementally to. Only the blocks that changed since the base backup are uploaded, which
+	// requires both VolumeSnapshots to be thin snapshots of the same volume and the VolumeSnapshot of
+	// the base backup to still exist. Otherwise, a full backup is taken.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="baseBackupName is immutable"
+	BaseBackupName string `json:"baseBackupName,omitempty"`
+
+	// EncryptionKeySecretName is the name of a Secret in the same namespace whose "key" is used to
+	// encrypt the backup with AES-256-GCM. If empty, the backup is not encrypted.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="encryptionKeySecretName is immutable"
+	EncryptionKeySecretName string `json:"encryptionKeySecretName,omitempty"`
+}
+
+// VolumeBackupSnapshotReference identifies the VolumeSnapshot of a backup.
+type VolumeBackupSnapshotReference struct {
+	// Name is the name of the VolumeSnapshot.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace is the namespace of the VolumeSnapshot.
+	// +kubebuilder:validation:Required
+	Namespace string `json:"namespace"`
+}
+
+// BackupLocation describes a bucket of an S3-compatible object storage.
+type BackupLocation struct {
+	// Endpoint is the URL of the object storage, for example https://s3.us-east-1.amazonaws.com.
+	// Buckets are addressed path-style.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+
+	// Region is the region the requests to the object storage are signed for.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=us-east-1
+	Region string `json:"region,omitempty"`
+
+	// Bucket is the name of the bucket.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+
+	// Prefix is prepended to the keys of all objects of the backup.
+	// +kubebuilder:validation:Optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// CredentialsSecretName is the name of a Secret in the same namespace that contains the
+	// "accessKeyID" and "secretAccessKey" of the object storage.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	CredentialsSecretName string `json:"credentialsSecretName"`
+}
+
+type LVMVolumeBackupPhase string
+
+const (
+	// LVMVolumeBackupPending means that the snapshot of the backup is being resolved
+	LVMVolumeBackupPending LVMVolumeBackupPhase = "Pending"
+	// LVMVolumeBackupUploading means that the data of the snapshot is being uploaded
+	LVMVolumeBackupUploading LVMVolumeBackupPhase = "Uploading"
+	// LVMVolumeBackupCompleted means that the backup was uploaded
+	LVMVolumeBackupCompleted LVMVolumeBackupPhase = "Completed"
+	// LVMVolumeBackupFailed means that the backup could not be taken
+	LVMVolumeBackupFailed LVMVolumeBackupPhase = "Failed"
+)
+
+const (
+	// VolumeBackedUp indicates whether the backup was uploaded
+	VolumeBackedUp = "VolumeBackedUp"
+)
+
+// LVMVolumeBackupStatus defines the observed state of LVMVolumeBackup
+type LVMVolumeBackupStatus struct {
+	// Phase describes the progress of the backup.
+	// +optional
+	Phase LVMVolumeBackupPhase `json:"phase,omitempty"`
+
+	// Conditions describes the state of the backup.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// NodeName is the node that holds the snapshot.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// DeviceClass is the device class of the snapshot.
+	// +optional
+	DeviceClass string `json:"deviceClass,omitempty"`
+
+	// SourceLogicalVolume is the name of the logical volume the snapshot was taken of.
+	// +optional
+	SourceLogicalVolume string `json:"sourceLogicalVolume,omitempty"`
+
+	// SnapshotLogicalVolume is the name of the logical volume of the snapshot.
+	// +optional
+	SnapshotLogicalVolume string `json:"snapshotLogicalVolume,omitempty"`
+
+	// SizeBytes is the size of the snapshot.
+	// +optional
+	SizeBytes int64 `json:"sizeBytes,omitempty"`
+
+	// VolumeMode is the volume mode of the volume the snapshot was taken of.
+	// +optional
+	VolumeMode corev1.PersistentVolumeMode `json:"volumeMode,omitempty"`
+
+	// FSType is the filesystem of the volume the snapshot was taken of.
+	// +optional
+	FSType string `json:"fsType,omitempty"`
+
+	// ManifestKey is the key of the object that describes the uploaded backup.
+	// +optional
+	ManifestKey string `json:"manifestKey,omitempty"`
+
+	// Incremental is true if only the blocks that changed since the base backup were uploaded.
+	// +optional
+	Incremental bool `json:"incremental,omitempty"`
+
+	// BaseBackupName is the name of the backup the backup was taken incrementally to.
+	// +optional
+	BaseBackupName string `json:"baseBackupName,omitempty"`
+
+	// BytesUploaded is the amount of compressed data uploaded to the object storage.
+	// +optional
+	BytesUploaded int64 `json:"bytesUploaded,omitempty"`
+
+	// Progress is the percentage of the snapshot that was read.
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// StartTime is the time the upload was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the backup completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Snapshot",type=string,JSONPath=`.spec.volumeSnapshot.name`
+//+kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.status.nodeName`
+//+kubebuilder:printcolumn:name="Incremental",type=boolean,JSONPath=`.status.incremental`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.progress`
+
+// LVMVolumeBackup is the Schema for the lvmvolumebackups API.
+// It uploads the data of a VolumeSnapshot compressed and optionally encrypted to an S3-compatible
+// object storage, from where it can be restored with an LVMVolumeRestore.
+type LVMVolumeBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LVMVolumeBackupSpec   `json:"spec,omitempty"`
+	Status LVMVolumeBackupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LVMVolumeBackupList contains a list of LVMVolumeBackup
+type LVMVolumeBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LVMVolumeBackup `json:"items"`
+}
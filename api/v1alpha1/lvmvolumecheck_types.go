@@ -0,0 +1,178 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LVMVolumeCheckSpec defines the desired state of LVMVolumeCheck
+// +kubebuilder:validation:XValidation:rule="self.mode != 'Repair' || self.source == 'Volume'",message="a repair requires the Volume source"
+type LVMVolumeCheckSpec struct {
+	// PersistentVolumeClaim references the PersistentVolumeClaim whose filesystem is checked.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="persistentVolumeClaim is immutable"
+	PersistentVolumeClaim VolumeCheckClaimReference `json:"persistentVolumeClaim"`
+
+	// Mode is Check to only report errors of the filesystem, or Repair to also repair them.
+	// +kubebuilder:validation:Enum=Check;Repair
+	// +kubebuilder:default=Check
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="mode is immutable"
+	// +optional
+	Mode LVMVolumeCheckMode `json:"mode,omitempty"`
+
+	// Source is Volume to check the volume once it is no longer in use by any pod, or Snapshot to check
+	// a temporary snapshot of the volume while it stays in use. Snapshots are only supported for volumes
+	// in thin pools. As a snapshot of a mounted filesystem is taken without flushing it, the check of a
+	// snapshot may report a dirty log or journal that is replayed on the next mount.
+	// +kubebuilder:validation:Enum=Volume;Snapshot
+	// +kubebuilder:default=Volume
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="source is immutable"
+	// +optional
+	Source LVMVolumeCheckSource `json:"source,omitempty"`
+}
+
+// VolumeCheckClaimReference identifies the PersistentVolumeClaim of a check.
+type VolumeCheckClaimReference struct {
+	// Name is the name of the PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace is the namespace of the PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	Namespace string `json:"namespace"`
+}
+
+type LVMVolumeCheckMode string
+
+const (
+	// LVMVolumeCheckModeCheck checks the filesystem without modifying it
+	LVMVolumeCheckModeCheck LVMVolumeCheckMode = "Check"
+	// LVMVolumeCheckModeRepair repairs the errors found in the filesystem
+	LVMVolumeCheckModeRepair LVMVolumeCheckMode = "Repair"
+)
+
+type LVMVolumeCheckSource string
+
+const (
+	// LVMVolumeCheckSourceVolume checks the volume once it is unpublished
+	LVMVolumeCheckSourceVolume LVMVolumeCheckSource = "Volume"
+	// LVMVolumeCheckSourceSnapshot checks a temporary thin snapshot of the volume
+	LVMVolumeCheckSourceSnapshot LVMVolumeCheckSource = "Snapshot"
+)
+
+type LVMVolumeCheckPhase string
+
+const (
+	// LVMVolumeCheckPending means that the volume of the check is being resolved
+	LVMVolumeCheckPending LVMVolumeCheckPhase = "Pending"
+	// LVMVolumeCheckWaitingForUnpublish means that the volume is still in use on the node
+	LVMVolumeCheckWaitingForUnpublish LVMVolumeCheckPhase = "WaitingForUnpublish"
+	// LVMVolumeCheckChecking means that the filesystem is being checked
+	LVMVolumeCheckChecking LVMVolumeCheckPhase = "Checking"
+	// LVMVolumeCheckCompleted means that the filesystem was checked, the result is found in the status
+	LVMVolumeCheckCompleted LVMVolumeCheckPhase = "Completed"
+	// LVMVolumeCheckFailed means that the filesystem could not be checked
+	LVMVolumeCheckFailed LVMVolumeCheckPhase = "Failed"
+)
+
+type LVMVolumeCheckResult string
+
+const (
+	// LVMVolumeCheckResultClean means that no errors were found in the filesystem
+	LVMVolumeCheckResultClean LVMVolumeCheckResult = "Clean"
+	// LVMVolumeCheckResultErrorsFound means that errors were found in the filesystem that were not repaired
+	LVMVolumeCheckResultErrorsFound LVMVolumeCheckResult = "ErrorsFound"
+	// LVMVolumeCheckResultRepaired means that errors were found in the filesystem and repaired
+	LVMVolumeCheckResultRepaired LVMVolumeCheckResult = "Repaired"
+)
+
+const (
+	// VolumeChecked indicates whether the filesystem of the volume was checked
+	VolumeChecked = "VolumeChecked"
+)
+
+// LVMVolumeCheckStatus defines the observed state of LVMVolumeCheck
+type LVMVolumeCheckStatus struct {
+	// Phase describes the progress of the check.
+	// +optional
+	Phase LVMVolumeCheckPhase `json:"phase,omitempty"`
+
+	// Conditions describes the state of the check.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// NodeName is the node that holds the volume.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// DeviceClass is the device class of the volume.
+	// +optional
+	DeviceClass string `json:"deviceClass,omitempty"`
+
+	// LogicalVolume is the name of the logical volume of the PersistentVolumeClaim.
+	// +optional
+	LogicalVolume string `json:"logicalVolume,omitempty"`
+
+	// FilesystemType is the type of the filesystem that was checked.
+	// +optional
+	FilesystemType string `json:"filesystemType,omitempty"`
+
+	// Result is the outcome of the check.
+	// +kubebuilder:validation:Enum=Clean;ErrorsFound;Repaired
+	// +optional
+	Result LVMVolumeCheckResult `json:"result,omitempty"`
+
+	// Output is the end of the output of the check.
+	// +optional
+	Output string `json:"output,omitempty"`
+
+	// StartTime is the time the check was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the check completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="PVC",type=string,JSONPath=`.spec.persistentVolumeClaim.name`
+//+kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+//+kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.status.nodeName`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Result",type=string,JSONPath=`.status.result`
+
+// LVMVolumeCheck is the Schema for the lvmvolumechecks API.
+// It checks and optionally repairs the filesystem of the volume of a PersistentVolumeClaim
+// on the node of the volume.
+type LVMVolumeCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LVMVolumeCheckSpec   `json:"spec,omitempty"`
+	Status LVMVolumeCheckStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LVMVolumeCheckList contains a list of LVMVolumeCheck
+type LVMVolumeCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LVMVolumeCheck `json:"items"`
+}
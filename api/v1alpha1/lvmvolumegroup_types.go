@@ -25,6 +25,7 @@ import (
 
 // LVMVolumeGroupSpec defines the desired state of LVMVolumeGroup
 // +kubebuilder:validation:XValidation:rule="!(has(self.raidConfig) && has(self.thinPoolConfig))",message="raidConfig and thinPoolConfig are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!(has(self.thickSnapshotConfig) && has(self.thinPoolConfig))",message="thickSnapshotConfig and thinPoolConfig are mutually exclusive"
 type LVMVolumeGroupSpec struct {
 	// DeviceSelector is a set of rules that should match for a device to be included in this TopoLVMCluster
 	// +optional
@@ -34,6 +35,13 @@ type LVMVolumeGroupSpec struct {
 	// +optional
 	NodeSelector *corev1.NodeSelector `json:"nodeSelector,omitempty"`
 
+	// NodeOverrides override the device selector and the thin pool size on the nodes matching their node selector.
+	// The first override matching a node is used.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	NodeOverrides []DeviceClassNodeOverride `json:"nodeOverrides,omitempty"`
+
 	// ThinPoolConfig contains configurations for the thin-pool
 	// +optional
 	ThinPoolConfig *ThinPoolConfig `json:"thinPoolConfig,omitempty"`
@@ -44,6 +52,11 @@ type LVMVolumeGroupSpec struct {
 	// +kubebuilder:validation:XValidation:rule="oldSelf == self",message="raidConfig is immutable after creation"
 	RAIDConfig *RAIDConfig `json:"raidConfig,omitempty"`
 
+	// ThickSnapshotConfig enables copy-on-write snapshots for this thick provisioned volume group.
+	// Mutually exclusive with ThinPoolConfig.
+	// +optional
+	ThickSnapshotConfig *ThickSnapshotConfig `json:"thickSnapshotConfig,omitempty"`
+
 	// Default is a flag to indicate whether the device-class is the default
 	// +optional
 	Default bool `json:"default,omitempty"`
@@ -55,10 +68,35 @@ type LVMVolumeGroupSpec struct {
 	// +kubebuilder:validation:Enum=Static;Dynamic
 	// +optional
 	DeviceDiscoveryPolicy *DeviceDiscoveryPolicySpec `json:"deviceDiscoveryPolicy,omitempty"`
+
+	// OrphanedLogicalVolumePolicy specifies what happens to logical volumes in the volume group that were provisioned
+	// by TopoLVM but are no longer referenced by any LogicalVolume.
+	// Retain only reports them in the status of the volume group.
+	// Delete additionally removes them once they were found orphaned in two consecutive consistency checks
+	// and for at least 30 minutes. Nothing is deleted while no LogicalVolume exists for the volume group on the node.
+	// Deleted logical volumes and their data cannot be recovered.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	// +optional
+	OrphanedLogicalVolumePolicy OrphanedLogicalVolumePolicy `json:"orphanedLogicalVolumePolicy,omitempty"`
+
+	// NodeRemovalPolicy specifies what happens to the volume group on nodes that are no longer selected by the node selector.
+	// Retain stops managing the volume group on the node and keeps it with its logical volumes.
+	// Remove deletes the volume group on the node once it contains no logical volumes anymore.
+	// +kubebuilder:validation:Enum=Retain;Remove
+	// +kubebuilder:default=Retain
+	// +optional
+	NodeRemovalPolicy NodeRemovalPolicy `json:"nodeRemovalPolicy,omitempty"`
 }
 
 // LVMVolumeGroupStatus defines the observed state of LVMVolumeGroup
 type LVMVolumeGroupStatus struct {
+	// Conditions describes the state of the volume group across all nodes.
+	// The conditions of the nodes are aggregated, a condition is only True if it is True on every node.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -49,7 +50,9 @@ const (
 
 // LVMVolumeGroupNodeStatusSpec defines the desired state of LVMVolumeGroupNodeStatus
 type LVMVolumeGroupNodeStatusSpec struct {
-	// NodeStatus contains the per node status of the VG
+	// NodeStatus contains the per node status of the VG.
+	// Deprecated: the volume groups are reported in status.volumeGroups. NodeStatus mirrors them without the fields
+	// that are only reported there and is kept for clients that still read it.
 	LVMVGStatus []VGStatus `json:"nodeStatus,omitempty"`
 }
 
@@ -64,10 +67,57 @@ const (
 	VGStatusFailed VGStatusType = "Failed"
 	// VGStatusDegraded means that the VG has been created but is not using the specified config
 	VGStatusDegraded VGStatusType = "Degraded"
+	// VGStatusPaused means that the node is in maintenance or the LVMCluster is paused and the VG is not reconciled
+	VGStatusPaused VGStatusType = "Paused"
+)
+
+// VGTransition tells whether a node is joining or leaving a volume group.
+// +kubebuilder:validation:Enum=Joining;Leaving
+type VGTransition string
+
+const (
+	// VGTransitionJoining means that the node is selected for the VG, but the VG was not created on it yet
+	VGTransitionJoining VGTransition = "Joining"
+	// VGTransitionLeaving means that the node is no longer selected for the VG and the VG is being torn down on it
+	VGTransitionLeaving VGTransition = "Leaving"
+)
+
+// VGReasonCode is a machine-readable reason for a failed or degraded volume group.
+// +kubebuilder:validation:Enum=NoAvailableDevices;MandatoryPathMissing;DeviceRemovalFailed;VGCreateOrExtendFailed;ThinPoolCreateOrExtendFailed;InconsistentLVs;ThinPoolMetadataFull;RAIDMissingPV;LVMDConfigReadFailed;LVMDConfigWriteFailed;ThinPoolDeletionFailed;VGDeletionFailed;Unknown
+type VGReasonCode string
+
+const (
+	// VGReasonNoAvailableDevices means that there are not enough available devices to create the VG
+	VGReasonNoAvailableDevices VGReasonCode = "NoAvailableDevices"
+	// VGReasonMandatoryPathMissing means that a device path of the deviceSelector cannot be used for the VG
+	VGReasonMandatoryPathMissing VGReasonCode = "MandatoryPathMissing"
+	// VGReasonDeviceRemovalFailed means that a device removed from the deviceSelector could not be removed from the VG
+	VGReasonDeviceRemovalFailed VGReasonCode = "DeviceRemovalFailed"
+	// VGReasonVGCreateOrExtendFailed means that the VG could not be created or extended with new devices
+	VGReasonVGCreateOrExtendFailed VGReasonCode = "VGCreateOrExtendFailed"
+	// VGReasonThinPoolCreateOrExtendFailed means that the thin pool could not be created or extended
+	VGReasonThinPoolCreateOrExtendFailed VGReasonCode = "ThinPoolCreateOrExtendFailed"
+	// VGReasonInconsistentLVs means that the logical volumes of the VG, including the thin pool, are not in the expected state
+	VGReasonInconsistentLVs VGReasonCode = "InconsistentLVs"
+	// VGReasonThinPoolMetadataFull means that the metadata of the thin pool is close to being full
+	VGReasonThinPoolMetadataFull VGReasonCode = "ThinPoolMetadataFull"
+	// VGReasonRAIDMissingPV means that the RAID VG has missing physical volumes
+	VGReasonRAIDMissingPV VGReasonCode = "RAIDMissingPV"
+	// VGReasonLVMDConfigReadFailed means that the lvmd config could not be read
+	VGReasonLVMDConfigReadFailed VGReasonCode = "LVMDConfigReadFailed"
+	// VGReasonLVMDConfigWriteFailed means that the lvmd config could not be written
+	VGReasonLVMDConfigWriteFailed VGReasonCode = "LVMDConfigWriteFailed"
+	// VGReasonThinPoolDeletionFailed means that the thin pool could not be deleted
+	VGReasonThinPoolDeletionFailed VGReasonCode = "ThinPoolDeletionFailed"
+	// VGReasonVGDeletionFailed means that the VG could not be deleted
+	VGReasonVGDeletionFailed VGReasonCode = "VGDeletionFailed"
+	// VGReasonUnknown means that the VG failed for a reason that has no reason code
+	VGReasonUnknown VGReasonCode = "Unknown"
 )
 
 type VGStatus struct {
 	// Name is the name of the volume group
+	// +kubebuilder:validation:Required
 	Name string `json:"name,omitempty"`
 	// Status tells if the volume group was created on the node
 	Status VGStatusType `json:"status,omitempty"`
@@ -91,6 +141,63 @@ type VGStatus struct {
 	RAIDStatus *RAIDStatus `json:"raidStatus,omitempty"`
 }
 
+// VGCapacity reports the size and usage of a volume group.
+type VGCapacity struct {
+	// Size is the total size of the volume group.
+	Size resource.Quantity `json:"size"`
+	// Free is the size of the volume group that is not allocated to any logical volume.
+	Free resource.Quantity `json:"free"`
+	// ThinPoolSize is the size of the thin pool of the volume group. Only set when the volume group has a thin pool.
+	// +optional
+	ThinPoolSize *resource.Quantity `json:"thinPoolSize,omitempty"`
+	// ThinPoolUsed is the size of the thin pool that is used by thin volumes and their snapshots.
+	// Only set when the volume group has a thin pool.
+	// +optional
+	ThinPoolUsed *resource.Quantity `json:"thinPoolUsed,omitempty"`
+}
+
+// LogicalVolumeFindingType is the type of inconsistency found between the TopoLVM LogicalVolumes and the logical volumes on the node.
+// +kubebuilder:validation:Enum=Orphaned;Missing;SizeDrift;SnapshotArtifact;StuckFinalizer
+type LogicalVolumeFindingType string
+
+const (
+	// LogicalVolumeFindingOrphaned means that a logical volume provisioned by TopoLVM is not referenced by any LogicalVolume.
+	LogicalVolumeFindingOrphaned LogicalVolumeFindingType = "Orphaned"
+	// LogicalVolumeFindingMissing means that a provisioned LogicalVolume has no logical volume on the node.
+	LogicalVolumeFindingMissing LogicalVolumeFindingType = "Missing"
+	// LogicalVolumeFindingSizeDrift means that the size of the logical volume differs from the size of the LogicalVolume,
+	// e.g. because it was extended out-of-band.
+	LogicalVolumeFindingSizeDrift LogicalVolumeFindingType = "SizeDrift"
+	// LogicalVolumeFindingSnapshotArtifact means that a snapshot or a leftover of a snapshot operation
+	// (such as a "-real" or "-cow" volume) is not referenced by any LogicalVolume.
+	LogicalVolumeFindingSnapshotArtifact LogicalVolumeFindingType = "SnapshotArtifact"
+	// LogicalVolumeFindingStuckFinalizer means that a LogicalVolume has been pending deletion for a long time.
+	LogicalVolumeFindingStuckFinalizer LogicalVolumeFindingType = "StuckFinalizer"
+)
+
+// LogicalVolumeFinding is a single inconsistency found by the consistency check.
+type LogicalVolumeFinding struct {
+	// Type is the type of the inconsistency.
+	Type LogicalVolumeFindingType `json:"type"`
+	// Name is the name of the logical volume in the volume group.
+	Name string `json:"name"`
+	// LogicalVolume is the name of the related TopoLVM LogicalVolume, if there is one.
+	// +optional
+	LogicalVolume string `json:"logicalVolume,omitempty"`
+	// Message provides more detail on the inconsistency.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// LogicalVolumeConsistency reports the inconsistencies between the TopoLVM LogicalVolumes and the logical volumes on the node.
+type LogicalVolumeConsistency struct {
+	// FindingCount is the total number of inconsistencies found.
+	FindingCount int `json:"findingCount"`
+	// Findings contains the inconsistencies found. At most 50 findings are listed.
+	// +optional
+	Findings []LogicalVolumeFinding `json:"findings,omitempty"`
+}
+
 // RAIDHealthStatus represents the overall health of RAID in a device class.
 // +kubebuilder:validation:Enum=Healthy;Degraded;Failed
 type RAIDHealthStatus string
@@ -141,11 +248,68 @@ type ExcludedDevice struct {
 	Reasons []string `json:"reasons"`
 }
 
+const (
+	// VGConditionReady indicates whether the volume group is ready.
+	VGConditionReady = "Ready"
+	// VGConditionReconciling indicates that the volume group is still being set up or torn down.
+	// Together with Ready and Stalled it follows the kstatus conventions, so that GitOps tools can assess the health.
+	VGConditionReconciling = "Reconciling"
+	// VGConditionStalled indicates that the volume group failed and can not make progress without intervention.
+	VGConditionStalled = "Stalled"
+	// VGConditionDevicesReady indicates whether the selected devices are attached to the volume group.
+	VGConditionDevicesReady = "DevicesReady"
+	// VGConditionThinPoolHealthy indicates whether the thin pool of the volume group is present and healthy.
+	// Only set for volume groups with a thin pool.
+	VGConditionThinPoolHealthy = "ThinPoolHealthy"
+	// VGConditionLVMDConfigured indicates whether the volume group is configured in the lvmd config of the node.
+	VGConditionLVMDConfigured = "LVMDConfigured"
+	// VGConditionRAIDHealthy indicates whether the RAID logical volumes of the volume group are healthy.
+	// Only set for volume groups with a RAID configuration.
+	VGConditionRAIDHealthy = "RAIDHealthy"
+)
+
+// VolumeGroupStatus is the observed state of a volume group on a node.
+type VolumeGroupStatus struct {
+	VGStatus `json:",inline"`
+	// ReasonCode is a machine-readable reason for a failed or degraded volume group.
+	// Unlike Reason it does not contain the error message and is stable across releases.
+	// +optional
+	ReasonCode VGReasonCode `json:"reasonCode,omitempty"`
+	// Transition tells whether the node is joining or leaving the volume group.
+	// It is empty once the volume group was created on a node that is selected for it.
+	// +optional
+	Transition VGTransition `json:"transition,omitempty"`
+	// LogicalVolumeConsistency reports the result of the last consistency check between the TopoLVM LogicalVolumes
+	// of this node and the logical volumes in the volume group.
+	// +optional
+	LogicalVolumeConsistency *LogicalVolumeConsistency `json:"logicalVolumeConsistency,omitempty"`
+	// Capacity reports the size and usage of the volume group on the node.
+	// It is not set until the volume group was created on the node.
+	// +optional
+	Capacity *VGCapacity `json:"capacity,omitempty"`
+	// AppliedGeneration is the generation of the LVMVolumeGroup that was last applied successfully on the node.
+	// It is behind the generation of the LVMVolumeGroup while a changed spec is not yet rolled out to the node.
+	// +optional
+	AppliedGeneration int64 `json:"appliedGeneration,omitempty"`
+	// Conditions describes the state of the volume group on the node.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
 // LVMVolumeGroupNodeStatusStatus defines the observed state of LVMVolumeGroupNodeStatus
 type LVMVolumeGroupNodeStatusStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
-
+	// Conditions summarizes the state of all volume groups on the node with the Ready, Reconciling and Stalled conditions.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// VolumeGroups contains the status of every volume group on the node.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	VolumeGroups []VolumeGroupStatus `json:"volumeGroups,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -168,3 +332,13 @@ type LVMVolumeGroupNodeStatusList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []LVMVolumeGroupNodeStatus `json:"items"`
 }
+
+// VolumeGroupStatus returns the status of the named volume group on the node, or nil if it is not reported.
+func (s *LVMVolumeGroupNodeStatus) VolumeGroupStatus(name string) *VolumeGroupStatus {
+	for i := range s.Status.VolumeGroups {
+		if s.Status.VolumeGroups[i].Name == name {
+			return &s.Status.VolumeGroups[i]
+		}
+	}
+	return nil
+}
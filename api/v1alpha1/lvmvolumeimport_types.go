@@ -0,0 +1,174 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LVMVolumeImportSpec defines the desired state of LVMVolumeImport
+type LVMVolumeImportSpec struct {
+	// NodeName is the name of the node that holds the volume group containing the logical volume.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="nodeName is immutable"
+	NodeName string `json:"nodeName"`
+
+	// DeviceClass is the name of the device class (and therefore the volume group) that contains the logical volume.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="deviceClass is immutable"
+	DeviceClass string `json:"deviceClass"`
+
+	// LogicalVolumeName is the name of the logical volume inside the volume group that should be imported.
+	// If empty, no volume is imported and the logical volumes that are available for import are
+	// reported in the status instead.
+	// +optional
+	LogicalVolumeName string `json:"logicalVolumeName,omitempty"`
+
+	// ClaimRef references the PersistentVolumeClaim the imported PersistentVolume is pre-bound to.
+	// The claim does not have to exist at the time of the import.
+	// +optional
+	ClaimRef *VolumeImportClaimReference `json:"claimRef,omitempty"`
+
+	// VolumeMode is the volume mode of the imported PersistentVolume.
+	// +kubebuilder:validation:Enum=Filesystem;Block
+	// +kubebuilder:default=Filesystem
+	// +optional
+	VolumeMode corev1.PersistentVolumeMode `json:"volumeMode,omitempty"`
+
+	// FilesystemType is the filesystem of the logical volume. If empty, the filesystem is detected
+	// from the logical volume and falls back to the filesystem of the device class StorageClass.
+	// Ignored if VolumeMode is Block.
+	// +kubebuilder:validation:Enum=xfs;ext4
+	// +optional
+	FilesystemType DeviceFilesystemType `json:"fstype,omitempty"`
+
+	// ReclaimPolicy is the reclaim policy of the imported PersistentVolume.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	// +optional
+	ReclaimPolicy corev1.PersistentVolumeReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+// VolumeImportClaimReference identifies the PersistentVolumeClaim an imported volume is bound to.
+type VolumeImportClaimReference struct {
+	// Name is the name of the PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace is the namespace of the PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	Namespace string `json:"namespace"`
+}
+
+type LVMVolumeImportPhase string
+
+const (
+	// LVMVolumeImportPending means that no logical volume was selected for import yet
+	LVMVolumeImportPending LVMVolumeImportPhase = "Pending"
+	// LVMVolumeImportImporting means that the import is in progress
+	LVMVolumeImportImporting LVMVolumeImportPhase = "Importing"
+	// LVMVolumeImportImported means that the LogicalVolume and PersistentVolume were created
+	LVMVolumeImportImported LVMVolumeImportPhase = "Imported"
+	// LVMVolumeImportFailed means that the logical volume could not be imported
+	LVMVolumeImportFailed LVMVolumeImportPhase = "Failed"
+)
+
+const (
+	// VolumeImported indicates whether the logical volume was imported
+	VolumeImported = "VolumeImported"
+)
+
+// ImportableLogicalVolume is a logical volume in the volume group that is not used by a PersistentVolume
+// bound to a claim.
+type ImportableLogicalVolume struct {
+	// Name is the name of the logical volume.
+	Name string `json:"name"`
+	// Size is the size of the logical volume.
+	Size resource.Quantity `json:"size"`
+	// FilesystemType is the detected filesystem of the logical volume, if any.
+	// +optional
+	FilesystemType string `json:"fstype,omitempty"`
+	// LogicalVolume is the TopoLVM LogicalVolume that still references the logical volume, for example
+	// after the PersistentVolumeClaim of a volume with the Retain reclaim policy was deleted.
+	// The import adopts it instead of creating a new one.
+	// +optional
+	LogicalVolume string `json:"logicalVolume,omitempty"`
+	// PersistentVolume is the released PersistentVolume of the logical volume, which is replaced by the import.
+	// +optional
+	PersistentVolume string `json:"persistentVolume,omitempty"`
+}
+
+// LVMVolumeImportStatus defines the observed state of LVMVolumeImport
+type LVMVolumeImportStatus struct {
+	// Phase describes the progress of the import.
+	// +optional
+	Phase LVMVolumeImportPhase `json:"phase,omitempty"`
+
+	// Conditions describes the state of the import.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ImportableLogicalVolumes lists the logical volumes in the volume group that can be imported.
+	// +optional
+	ImportableLogicalVolumes []ImportableLogicalVolume `json:"importableLogicalVolumes,omitempty"`
+
+	// LogicalVolume is the name of the TopoLVM LogicalVolume created or adopted for the import.
+	// +optional
+	LogicalVolume string `json:"logicalVolume,omitempty"`
+
+	// PersistentVolume is the name of the PersistentVolume created for the import.
+	// +optional
+	PersistentVolume string `json:"persistentVolume,omitempty"`
+
+	// Size is the size of the imported logical volume.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+
+	// FilesystemType is the filesystem used for the imported PersistentVolume.
+	// +optional
+	FilesystemType string `json:"fstype,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.spec.nodeName`
+//+kubebuilder:printcolumn:name="DeviceClass",type=string,JSONPath=`.spec.deviceClass`
+//+kubebuilder:printcolumn:name="LogicalVolume",type=string,JSONPath=`.spec.logicalVolumeName`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+
+// LVMVolumeImport is the Schema for the lvmvolumeimports API.
+// It adopts an existing logical volume of an LVMS volume group that is not used by a bound PersistentVolume
+// into a TopoLVM LogicalVolume and a pre-bound static PersistentVolume.
+type LVMVolumeImport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LVMVolumeImportSpec   `json:"spec,omitempty"`
+	Status LVMVolumeImportStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LVMVolumeImportList contains a list of LVMVolumeImport
+type LVMVolumeImportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LVMVolumeImport `json:"items"`
+}
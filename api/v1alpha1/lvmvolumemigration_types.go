@@ -0,0 +1,225 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LVMVolumeMigrationSpec defines the desired state of LVMVolumeMigration
+// +kubebuilder:validation:XValidation:rule="has(self.targetNodeName) || has(self.targetDeviceClass)",message="either targetNodeName or targetDeviceClass must be set"
+type LVMVolumeMigrationSpec struct {
+	// PersistentVolumeClaim references the PersistentVolumeClaim whose volume is migrated.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="persistentVolumeClaim is immutable"
+	PersistentVolumeClaim VolumeMigrationClaimReference `json:"persistentVolumeClaim"`
+
+	// TargetNodeName is the node the volume is migrated to. The node needs a volume group
+	// for the target device class. If empty, the volume stays on its node and is only moved
+	// to TargetDeviceClass.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="targetNodeName is immutable"
+	TargetNodeName string `json:"targetNodeName,omitempty"`
+
+	// TargetDeviceClass is the device class the volume is migrated to. If empty, the volume keeps
+	// its device class. The PersistentVolumeClaim is switched to the StorageClass of the device class.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="targetDeviceClass is immutable"
+	TargetDeviceClass string `json:"targetDeviceClass,omitempty"`
+}
+
+// VolumeMigrationClaimReference identifies the PersistentVolumeClaim of a migration.
+type VolumeMigrationClaimReference struct {
+	// Name is the name of the PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace is the namespace of the PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	Namespace string `json:"namespace"`
+}
+
+type LVMVolumeMigrationPhase string
+
+const (
+	// LVMVolumeMigrationPending means that the volume of the migration is being resolved
+	LVMVolumeMigrationPending LVMVolumeMigrationPhase = "Pending"
+	// LVMVolumeMigrationPreparing means that the target volume is being created
+	LVMVolumeMigrationPreparing LVMVolumeMigrationPhase = "Preparing"
+	// LVMVolumeMigrationInitialSync means that a snapshot of the volume is copied to the target volume
+	LVMVolumeMigrationInitialSync LVMVolumeMigrationPhase = "InitialSync"
+	// LVMVolumeMigrationCatchingUp means that the changes to the volume are copied to the target volume
+	// until the volume is no longer in use
+	LVMVolumeMigrationCatchingUp LVMVolumeMigrationPhase = "CatchingUp"
+	// LVMVolumeMigrationFinalSync means that the last changes of the unused volume are copied to the target volume
+	LVMVolumeMigrationFinalSync LVMVolumeMigrationPhase = "FinalSync"
+	// LVMVolumeMigrationSwitching means that the PersistentVolumeClaim is rebound to the target volume
+	LVMVolumeMigrationSwitching LVMVolumeMigrationPhase = "Switching"
+	// LVMVolumeMigrationVerifying means that the PersistentVolumeClaim was deleted and the source node
+	// verifies that the unused volume did not change since the last copy before the claim is recreated
+	LVMVolumeMigrationVerifying LVMVolumeMigrationPhase = "Verifying"
+	// LVMVolumeMigrationCompleted means that the volume was migrated to the target node or device class
+	LVMVolumeMigrationCompleted LVMVolumeMigrationPhase = "Completed"
+	// LVMVolumeMigrationFailed means that the volume could not be migrated
+	LVMVolumeMigrationFailed LVMVolumeMigrationPhase = "Failed"
+)
+
+const (
+	// VolumeMigrated indicates whether the volume was migrated
+	VolumeMigrated = "VolumeMigrated"
+)
+
+// LVMVolumeMigrationStatus defines the observed state of LVMVolumeMigration
+type LVMVolumeMigrationStatus struct {
+	// Phase describes the progress of the migration.
+	// +optional
+	Phase LVMVolumeMigrationPhase `json:"phase,omitempty"`
+
+	// Conditions describes the state of the migration.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// SourceNodeName is the node that holds the volume before the migration.
+	// +optional
+	SourceNodeName string `json:"sourceNodeName,omitempty"`
+
+	// DeviceClass is the device class of the volume before the migration.
+	// +optional
+	DeviceClass string `json:"deviceClass,omitempty"`
+
+	// TargetNodeName is the node the volume is migrated to.
+	// +optional
+	TargetNodeName string `json:"targetNodeName,omitempty"`
+
+	// TargetDeviceClass is the device class the volume is migrated to.
+	// +optional
+	TargetDeviceClass string `json:"targetDeviceClass,omitempty"`
+
+	// SourcePersistentVolumeName is the name of the PersistentVolume the PersistentVolumeClaim was bound to
+	// before the migration.
+	// +optional
+	SourcePersistentVolumeName string `json:"sourcePersistentVolumeName,omitempty"`
+
+	// SourceLogicalVolume is the name of the logical volume on the source node.
+	// +optional
+	SourceLogicalVolume string `json:"sourceLogicalVolume,omitempty"`
+
+	// TargetLogicalVolumeName is the name of the TopoLVM LogicalVolume and the PersistentVolume
+	// created for the target volume.
+	// +optional
+	TargetLogicalVolumeName string `json:"targetLogicalVolumeName,omitempty"`
+
+	// TargetLogicalVolume is the name of the target logical volume.
+	// +optional
+	TargetLogicalVolume string `json:"targetLogicalVolume,omitempty"`
+
+	// TargetAddress is the address the vg-manager on the target node receives the data of the volume on.
+	// It is empty if the volume stays on its node, as the volume is then copied directly.
+	// +optional
+	TargetAddress string `json:"targetAddress,omitempty"`
+
+	// SizeBytes is the size of the volume.
+	// +optional
+	SizeBytes int64 `json:"sizeBytes,omitempty"`
+
+	// Passes is the number of completed copies of the volume to the target volume.
+	// +optional
+	Passes int32 `json:"passes,omitempty"`
+
+	// Progress is the percentage of the volume that was read by the running copy.
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// BytesTransferred is the amount of data copied to the target volume by all copies.
+	// +optional
+	BytesTransferred int64 `json:"bytesTransferred,omitempty"`
+
+	// LastPassChangedBytes is the amount of data that changed since the previous copy and was sent
+	// to the target volume by the last copy.
+	// +optional
+	LastPassChangedBytes int64 `json:"lastPassChangedBytes,omitempty"`
+
+	// LastPassTime is the time the last copy completed.
+	// +optional
+	LastPassTime *metav1.Time `json:"lastPassTime,omitempty"`
+
+	// SourceVerified is set once the source node verified that the volume is no longer in use and did not
+	// change since the last copy after the PersistentVolumeClaim was deleted.
+	// The source volume is only removed once it is set.
+	// +optional
+	SourceVerified bool `json:"sourceVerified,omitempty"`
+
+	// Claim is the PersistentVolumeClaim as it was before it was recreated for the target volume.
+	// +optional
+	Claim *MigratedClaim `json:"claim,omitempty"`
+
+	// ReclaimPolicy is the reclaim policy of the PersistentVolume before the migration.
+	// It is applied to the PersistentVolume of the target volume.
+	// +optional
+	ReclaimPolicy corev1.PersistentVolumeReclaimPolicy `json:"reclaimPolicy,omitempty"`
+
+	// StartTime is the time the first copy was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the migration completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// MigratedClaim is a copy of a PersistentVolumeClaim that is recreated during a migration.
+type MigratedClaim struct {
+	// Labels are the labels of the PersistentVolumeClaim.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are the annotations of the PersistentVolumeClaim.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Spec is the spec of the PersistentVolumeClaim.
+	Spec corev1.PersistentVolumeClaimSpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="PVC",type=string,JSONPath=`.spec.persistentVolumeClaim.name`
+//+kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.status.sourceNodeName`
+//+kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.status.targetNodeName`
+//+kubebuilder:printcolumn:name="Device Class",type=string,JSONPath=`.status.targetDeviceClass`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.progress`
+
+// LVMVolumeMigration is the Schema for the lvmvolumemigrations API.
+// It moves the volume of a PersistentVolumeClaim to another node or another device class by copying
+// its data between the vg-manager pods of both nodes and rebinding the PersistentVolumeClaim.
+type LVMVolumeMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LVMVolumeMigrationSpec   `json:"spec,omitempty"`
+	Status LVMVolumeMigrationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LVMVolumeMigrationList contains a list of LVMVolumeMigration
+type LVMVolumeMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LVMVolumeMigration `json:"items"`
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LVMVolumeRestoreSpec defines the desired state of LVMVolumeRestore
+type LVMVolumeRestoreSpec struct {
+	// BackupName is the name of a completed LVMVolumeBackup in the same namespace that is restored.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="backupName is immutable"
+	BackupName string `json:"backupName"`
+
+	// PersistentVolumeClaim is the PersistentVolumeClaim that is created for the restored volume.
+	// It must not exist yet.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="persistentVolumeClaim is immutable"
+	PersistentVolumeClaim VolumeRestoreClaimReference `json:"persistentVolumeClaim"`
+
+	// NodeName is the node the volume is restored on. It does not have to be the node the backup was taken on.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="nodeName is immutable"
+	NodeName string `json:"nodeName"`
+
+	// DeviceClass is the device class the volume is restored to. If empty, the device class of the backup is used.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="deviceClass is immutable"
+	DeviceClass string `json:"deviceClass,omitempty"`
+}
+
+// VolumeRestoreClaimReference identifies the PersistentVolumeClaim of a restore.
+type VolumeRestoreClaimReference struct {
+	// Name is the name of the PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace is the namespace of the PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	Namespace string `json:"namespace"`
+}
+
+type LVMVolumeRestorePhase string
+
+const (
+	// LVMVolumeRestorePending means that the backup of the restore is being resolved
+	LVMVolumeRestorePending LVMVolumeRestorePhase = "Pending"
+	// LVMVolumeRestoreRestoring means that the data of the backup is being downloaded to the volume
+	LVMVolumeRestoreRestoring LVMVolumeRestorePhase = "Restoring"
+	// LVMVolumeRestoreBinding means that the PersistentVolume and PersistentVolumeClaim are being created
+	LVMVolumeRestoreBinding LVMVolumeRestorePhase = "Binding"
+	// LVMVolumeRestoreCompleted means that the volume was restored
+	LVMVolumeRestoreCompleted LVMVolumeRestorePhase = "Completed"
+	// LVMVolumeRestoreFailed means that the volume could not be restored
+	LVMVolumeRestoreFailed LVMVolumeRestorePhase = "Failed"
+)
+
+const (
+	// VolumeRestored indicates whether the volume was restored
+	VolumeRestored = "VolumeRestored"
+)
+
+// LVMVolumeRestoreStatus defines the observed state of LVMVolumeRestore
+type LVMVolumeRestoreStatus struct {
+	// Phase describes the progress of the restore.
+	// +optional
+	Phase LVMVolumeRestorePhase `json:"phase,omitempty"`
+
+	// Conditions describes the state of the restore.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// DeviceClass is the device class the volume is restored to.
+	// +optional
+	DeviceClass string `json:"deviceClass,omitempty"`
+
+	// LogicalVolumeName is the name of the TopoLVM LogicalVolume and the PersistentVolume
+	// created for the restored volume.
+	// +optional
+	LogicalVolumeName string `json:"logicalVolumeName,omitempty"`
+
+	// LogicalVolume is the name of the restored logical volume.
+	// +optional
+	LogicalVolume string `json:"logicalVolume,omitempty"`
+
+	// SizeBytes is the size of the restored volume.
+	// +optional
+	SizeBytes int64 `json:"sizeBytes,omitempty"`
+
+	// BytesDownloaded is the amount of compressed data downloaded from the object storage.
+	// +optional
+	BytesDownloaded int64 `json:"bytesDownloaded,omitempty"`
+
+	// Progress is the percentage of the volume that was written.
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// StartTime is the time the download was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the restore completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Backup",type=string,JSONPath=`.spec.backupName`
+//+kubebuilder:printcolumn:name="PVC",type=string,JSONPath=`.spec.persistentVolumeClaim.name`
+//+kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.spec.nodeName`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.progress`
+
+// LVMVolumeRestore is the Schema for the lvmvolumerestores API.
+// It restores an LVMVolumeBackup from object storage into a new volume on a node and binds it
+// to a new PersistentVolumeClaim.
+type LVMVolumeRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LVMVolumeRestoreSpec   `json:"spec,omitempty"`
+	Status LVMVolumeRestoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LVMVolumeRestoreList contains a list of LVMVolumeRestore
+type LVMVolumeRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LVMVolumeRestore `json:"items"`
+}
@@ -0,0 +1,139 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LVMVolumeRevertSpec defines the desired state of LVMVolumeRevert
+type LVMVolumeRevertSpec struct {
+	// PersistentVolumeClaim references the PersistentVolumeClaim that is reverted.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="persistentVolumeClaim is immutable"
+	PersistentVolumeClaim VolumeRevertClaimReference `json:"persistentVolumeClaim"`
+
+	// VolumeSnapshotName is the name of the VolumeSnapshot of the PersistentVolumeClaim the volume is reverted to.
+	// The VolumeSnapshot has to be in the namespace of the PersistentVolumeClaim. It is consumed by the revert
+	// and deleted once the revert completed.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="volumeSnapshotName is immutable"
+	VolumeSnapshotName string `json:"volumeSnapshotName"`
+}
+
+// VolumeRevertClaimReference identifies the PersistentVolumeClaim of a revert.
+type VolumeRevertClaimReference struct {
+	// Name is the name of the PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace is the namespace of the PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	Namespace string `json:"namespace"`
+}
+
+type LVMVolumeRevertPhase string
+
+const (
+	// LVMVolumeRevertPending means that the volume and snapshot of the revert are being resolved
+	LVMVolumeRevertPending LVMVolumeRevertPhase = "Pending"
+	// LVMVolumeRevertWaitingForUnpublish means that the volume is still in use on the node
+	LVMVolumeRevertWaitingForUnpublish LVMVolumeRevertPhase = "WaitingForUnpublish"
+	// LVMVolumeRevertMerging means that the snapshot is being merged into the volume
+	LVMVolumeRevertMerging LVMVolumeRevertPhase = "Merging"
+	// LVMVolumeRevertCompleted means that the volume was reverted to the snapshot
+	LVMVolumeRevertCompleted LVMVolumeRevertPhase = "Completed"
+	// LVMVolumeRevertFailed means that the volume could not be reverted
+	LVMVolumeRevertFailed LVMVolumeRevertPhase = "Failed"
+)
+
+const (
+	// VolumeReverted indicates whether the volume was reverted to the snapshot
+	VolumeReverted = "VolumeReverted"
+)
+
+// LVMVolumeRevertStatus defines the observed state of LVMVolumeRevert
+type LVMVolumeRevertStatus struct {
+	// Phase describes the progress of the revert.
+	// +optional
+	Phase LVMVolumeRevertPhase `json:"phase,omitempty"`
+
+	// Conditions describes the state of the revert.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// NodeName is the node that holds the volume.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// DeviceClass is the device class of the volume.
+	// +optional
+	DeviceClass string `json:"deviceClass,omitempty"`
+
+	// LogicalVolume is the name of the logical volume of the PersistentVolumeClaim.
+	// +optional
+	LogicalVolume string `json:"logicalVolume,omitempty"`
+
+	// SnapshotLogicalVolume is the name of the logical volume of the VolumeSnapshot.
+	// +optional
+	SnapshotLogicalVolume string `json:"snapshotLogicalVolume,omitempty"`
+
+	// Progress is the percentage of the snapshot that was merged into the volume.
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// SnapshotUsageAtStart is the usage in percent of the copy-on-write space of a thick snapshot when the merge
+	// started. The Progress of the merge is calculated from it.
+	// +optional
+	SnapshotUsageAtStart string `json:"snapshotUsageAtStart,omitempty"`
+
+	// StartTime is the time the merge was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the revert completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="PVC",type=string,JSONPath=`.spec.persistentVolumeClaim.name`
+//+kubebuilder:printcolumn:name="VolumeSnapshot",type=string,JSONPath=`.spec.volumeSnapshotName`
+//+kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.status.nodeName`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.progress`
+
+// LVMVolumeRevert is the Schema for the lvmvolumereverts API.
+// It reverts the volume of a PersistentVolumeClaim in place to one of its VolumeSnapshots
+// by merging the snapshot into the volume.
+type LVMVolumeRevert struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LVMVolumeRevertSpec   `json:"spec,omitempty"`
+	Status LVMVolumeRevertStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LVMVolumeRevertList contains a list of LVMVolumeRevert
+type LVMVolumeRevertList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LVMVolumeRevert `json:"items"`
+}
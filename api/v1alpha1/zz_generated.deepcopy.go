@@ -27,6 +27,21 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupLocation) DeepCopyInto(out *BackupLocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupLocation.
+func (in *BackupLocation) DeepCopy() *BackupLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceClass) DeepCopyInto(out *DeviceClass) {
 	*out = *in
@@ -40,6 +55,13 @@ func (in *DeviceClass) DeepCopyInto(out *DeviceClass) {
 		*out = new(v1.NodeSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeOverrides != nil {
+		in, out := &in.NodeOverrides, &out.NodeOverrides
+		*out = make([]DeviceClassNodeOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ThinPoolConfig != nil {
 		in, out := &in.ThinPoolConfig, &out.ThinPoolConfig
 		*out = new(ThinPoolConfig)
@@ -50,6 +72,21 @@ func (in *DeviceClass) DeepCopyInto(out *DeviceClass) {
 		*out = new(RAIDConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ThickSnapshotConfig != nil {
+		in, out := &in.ThickSnapshotConfig, &out.ThickSnapshotConfig
+		*out = new(ThickSnapshotConfig)
+		**out = **in
+	}
+	if in.MkfsOptions != nil {
+		in, out := &in.MkfsOptions, &out.MkfsOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MountOptions != nil {
+		in, out := &in.MountOptions, &out.MountOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.DeviceDiscoveryPolicy != nil {
 		in, out := &in.DeviceDiscoveryPolicy, &out.DeviceDiscoveryPolicy
 		*out = new(DeviceDiscoveryPolicySpec)
@@ -72,6 +109,36 @@ func (in *DeviceClass) DeepCopy() *DeviceClass {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClassNodeOverride) DeepCopyInto(out *DeviceClassNodeOverride) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.NodeSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeviceSelector != nil {
+		in, out := &in.DeviceSelector, &out.DeviceSelector
+		*out = new(DeviceSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ThinPoolSizePercent != nil {
+		in, out := &in.ThinPoolSizePercent, &out.ThinPoolSizePercent
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClassNodeOverride.
+func (in *DeviceClassNodeOverride) DeepCopy() *DeviceClassNodeOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClassNodeOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceClassStatus) DeepCopyInto(out *DeviceClassStatus) {
 	*out = *in
@@ -82,6 +149,18 @@ func (in *DeviceClassStatus) DeepCopyInto(out *DeviceClassStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(VGCapacity)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClassStatus.
@@ -144,6 +223,22 @@ func (in *ExcludedDevice) DeepCopy() *ExcludedDevice {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportableLogicalVolume) DeepCopyInto(out *ImportableLogicalVolume) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportableLogicalVolume.
+func (in *ImportableLogicalVolume) DeepCopy() *ImportableLogicalVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportableLogicalVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LVMCluster) DeepCopyInto(out *LVMCluster) {
 	*out = *in
@@ -256,26 +351,26 @@ func (in *LVMClusterStatus) DeepCopy() *LVMClusterStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LVMVolumeGroup) DeepCopyInto(out *LVMVolumeGroup) {
+func (in *LVMSnapshotSchedule) DeepCopyInto(out *LVMSnapshotSchedule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroup.
-func (in *LVMVolumeGroup) DeepCopy() *LVMVolumeGroup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMSnapshotSchedule.
+func (in *LVMSnapshotSchedule) DeepCopy() *LVMSnapshotSchedule {
 	if in == nil {
 		return nil
 	}
-	out := new(LVMVolumeGroup)
+	out := new(LVMSnapshotSchedule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LVMVolumeGroup) DeepCopyObject() runtime.Object {
+func (in *LVMSnapshotSchedule) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -283,31 +378,31 @@ func (in *LVMVolumeGroup) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LVMVolumeGroupList) DeepCopyInto(out *LVMVolumeGroupList) {
+func (in *LVMSnapshotScheduleList) DeepCopyInto(out *LVMSnapshotScheduleList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]LVMVolumeGroup, len(*in))
+		*out = make([]LVMSnapshotSchedule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupList.
-func (in *LVMVolumeGroupList) DeepCopy() *LVMVolumeGroupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMSnapshotScheduleList.
+func (in *LVMSnapshotScheduleList) DeepCopy() *LVMSnapshotScheduleList {
 	if in == nil {
 		return nil
 	}
-	out := new(LVMVolumeGroupList)
+	out := new(LVMSnapshotScheduleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LVMVolumeGroupList) DeepCopyObject() runtime.Object {
+func (in *LVMSnapshotScheduleList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -315,26 +410,91 @@ func (in *LVMVolumeGroupList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LVMVolumeGroupNodeStatus) DeepCopyInto(out *LVMVolumeGroupNodeStatus) {
+func (in *LVMSnapshotScheduleSpec) DeepCopyInto(out *LVMSnapshotScheduleSpec) {
+	*out = *in
+	if in.PersistentVolumeClaimSelector != nil {
+		in, out := &in.PersistentVolumeClaimSelector, &out.PersistentVolumeClaimSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(SnapshotRetention)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMSnapshotScheduleSpec.
+func (in *LVMSnapshotScheduleSpec) DeepCopy() *LVMSnapshotScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMSnapshotScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMSnapshotScheduleStatus) DeepCopyInto(out *LVMSnapshotScheduleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Skipped != nil {
+		in, out := &in.Skipped, &out.Skipped
+		*out = make([]SkippedPersistentVolumeClaim, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMSnapshotScheduleStatus.
+func (in *LVMSnapshotScheduleStatus) DeepCopy() *LVMSnapshotScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMSnapshotScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeBackup) DeepCopyInto(out *LVMVolumeBackup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupNodeStatus.
-func (in *LVMVolumeGroupNodeStatus) DeepCopy() *LVMVolumeGroupNodeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeBackup.
+func (in *LVMVolumeBackup) DeepCopy() *LVMVolumeBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(LVMVolumeGroupNodeStatus)
+	out := new(LVMVolumeBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LVMVolumeGroupNodeStatus) DeepCopyObject() runtime.Object {
+func (in *LVMVolumeBackup) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -342,31 +502,31 @@ func (in *LVMVolumeGroupNodeStatus) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LVMVolumeGroupNodeStatusList) DeepCopyInto(out *LVMVolumeGroupNodeStatusList) {
+func (in *LVMVolumeBackupList) DeepCopyInto(out *LVMVolumeBackupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]LVMVolumeGroupNodeStatus, len(*in))
+		*out = make([]LVMVolumeBackup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupNodeStatusList.
-func (in *LVMVolumeGroupNodeStatusList) DeepCopy() *LVMVolumeGroupNodeStatusList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeBackupList.
+func (in *LVMVolumeBackupList) DeepCopy() *LVMVolumeBackupList {
 	if in == nil {
 		return nil
 	}
-	out := new(LVMVolumeGroupNodeStatusList)
+	out := new(LVMVolumeBackupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LVMVolumeGroupNodeStatusList) DeepCopyObject() runtime.Object {
+func (in *LVMVolumeBackupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -374,162 +534,967 @@ func (in *LVMVolumeGroupNodeStatusList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LVMVolumeGroupNodeStatusSpec) DeepCopyInto(out *LVMVolumeGroupNodeStatusSpec) {
+func (in *LVMVolumeBackupSpec) DeepCopyInto(out *LVMVolumeBackupSpec) {
 	*out = *in
-	if in.LVMVGStatus != nil {
-		in, out := &in.LVMVGStatus, &out.LVMVGStatus
-		*out = make([]VGStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.VolumeSnapshot = in.VolumeSnapshot
+	out.Location = in.Location
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupNodeStatusSpec.
-func (in *LVMVolumeGroupNodeStatusSpec) DeepCopy() *LVMVolumeGroupNodeStatusSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeBackupSpec.
+func (in *LVMVolumeBackupSpec) DeepCopy() *LVMVolumeBackupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LVMVolumeGroupNodeStatusSpec)
+	out := new(LVMVolumeBackupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LVMVolumeGroupNodeStatusStatus) DeepCopyInto(out *LVMVolumeGroupNodeStatusStatus) {
+func (in *LVMVolumeBackupStatus) DeepCopyInto(out *LVMVolumeBackupStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupNodeStatusStatus.
-func (in *LVMVolumeGroupNodeStatusStatus) DeepCopy() *LVMVolumeGroupNodeStatusStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeBackupStatus.
+func (in *LVMVolumeBackupStatus) DeepCopy() *LVMVolumeBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LVMVolumeGroupNodeStatusStatus)
+	out := new(LVMVolumeBackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LVMVolumeGroupSpec) DeepCopyInto(out *LVMVolumeGroupSpec) {
+func (in *LVMVolumeCheck) DeepCopyInto(out *LVMVolumeCheck) {
 	*out = *in
-	if in.DeviceSelector != nil {
-		in, out := &in.DeviceSelector, &out.DeviceSelector
-		*out = new(DeviceSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = new(v1.NodeSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ThinPoolConfig != nil {
-		in, out := &in.ThinPoolConfig, &out.ThinPoolConfig
-		*out = new(ThinPoolConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.RAIDConfig != nil {
-		in, out := &in.RAIDConfig, &out.RAIDConfig
-		*out = new(RAIDConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.DeviceDiscoveryPolicy != nil {
-		in, out := &in.DeviceDiscoveryPolicy, &out.DeviceDiscoveryPolicy
-		*out = new(DeviceDiscoveryPolicySpec)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupSpec.
-func (in *LVMVolumeGroupSpec) DeepCopy() *LVMVolumeGroupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeCheck.
+func (in *LVMVolumeCheck) DeepCopy() *LVMVolumeCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(LVMVolumeGroupSpec)
+	out := new(LVMVolumeCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeCheck) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LVMVolumeGroupStatus) DeepCopyInto(out *LVMVolumeGroupStatus) {
+func (in *LVMVolumeCheckList) DeepCopyInto(out *LVMVolumeCheckList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LVMVolumeCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupStatus.
-func (in *LVMVolumeGroupStatus) DeepCopy() *LVMVolumeGroupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeCheckList.
+func (in *LVMVolumeCheckList) DeepCopy() *LVMVolumeCheckList {
 	if in == nil {
 		return nil
 	}
-	out := new(LVMVolumeGroupStatus)
+	out := new(LVMVolumeCheckList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeCheckList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
+func (in *LVMVolumeCheckSpec) DeepCopyInto(out *LVMVolumeCheckSpec) {
 	*out = *in
-	in.VGStatus.DeepCopyInto(&out.VGStatus)
+	out.PersistentVolumeClaim = in.PersistentVolumeClaim
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeStatus.
-func (in *NodeStatus) DeepCopy() *NodeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeCheckSpec.
+func (in *LVMVolumeCheckSpec) DeepCopy() *LVMVolumeCheckSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeStatus)
+	out := new(LVMVolumeCheckSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RAIDConfig) DeepCopyInto(out *RAIDConfig) {
+func (in *LVMVolumeCheckStatus) DeepCopyInto(out *LVMVolumeCheckStatus) {
 	*out = *in
-	if in.Mirrors != nil {
-		in, out := &in.Mirrors, &out.Mirrors
-		*out = new(int)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.Stripes != nil {
-		in, out := &in.Stripes, &out.Stripes
-		*out = new(int)
-		**out = **in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
 	}
-	if in.StripeSize != nil {
-		in, out := &in.StripeSize, &out.StripeSize
-		x := (*in).DeepCopy()
-		*out = &x
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAIDConfig.
-func (in *RAIDConfig) DeepCopy() *RAIDConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeCheckStatus.
+func (in *LVMVolumeCheckStatus) DeepCopy() *LVMVolumeCheckStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RAIDConfig)
+	out := new(LVMVolumeCheckStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RAIDLVHealth) DeepCopyInto(out *RAIDLVHealth) {
+func (in *LVMVolumeGroup) DeepCopyInto(out *LVMVolumeGroup) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAIDLVHealth.
-func (in *RAIDLVHealth) DeepCopy() *RAIDLVHealth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroup.
+func (in *LVMVolumeGroup) DeepCopy() *LVMVolumeGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(RAIDLVHealth)
+	out := new(LVMVolumeGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RAIDStatus) DeepCopyInto(out *RAIDStatus) {
-	*out = *in
-	if in.MinSyncPercent != nil {
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeGroupList) DeepCopyInto(out *LVMVolumeGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LVMVolumeGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupList.
+func (in *LVMVolumeGroupList) DeepCopy() *LVMVolumeGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeGroupNodeStatus) DeepCopyInto(out *LVMVolumeGroupNodeStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupNodeStatus.
+func (in *LVMVolumeGroupNodeStatus) DeepCopy() *LVMVolumeGroupNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeGroupNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeGroupNodeStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeGroupNodeStatusList) DeepCopyInto(out *LVMVolumeGroupNodeStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LVMVolumeGroupNodeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupNodeStatusList.
+func (in *LVMVolumeGroupNodeStatusList) DeepCopy() *LVMVolumeGroupNodeStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeGroupNodeStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeGroupNodeStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeGroupNodeStatusSpec) DeepCopyInto(out *LVMVolumeGroupNodeStatusSpec) {
+	*out = *in
+	if in.LVMVGStatus != nil {
+		in, out := &in.LVMVGStatus, &out.LVMVGStatus
+		*out = make([]VGStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupNodeStatusSpec.
+func (in *LVMVolumeGroupNodeStatusSpec) DeepCopy() *LVMVolumeGroupNodeStatusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeGroupNodeStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeGroupNodeStatusStatus) DeepCopyInto(out *LVMVolumeGroupNodeStatusStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeGroups != nil {
+		in, out := &in.VolumeGroups, &out.VolumeGroups
+		*out = make([]VolumeGroupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupNodeStatusStatus.
+func (in *LVMVolumeGroupNodeStatusStatus) DeepCopy() *LVMVolumeGroupNodeStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeGroupNodeStatusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeGroupSpec) DeepCopyInto(out *LVMVolumeGroupSpec) {
+	*out = *in
+	if in.DeviceSelector != nil {
+		in, out := &in.DeviceSelector, &out.DeviceSelector
+		*out = new(DeviceSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.NodeSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeOverrides != nil {
+		in, out := &in.NodeOverrides, &out.NodeOverrides
+		*out = make([]DeviceClassNodeOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ThinPoolConfig != nil {
+		in, out := &in.ThinPoolConfig, &out.ThinPoolConfig
+		*out = new(ThinPoolConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RAIDConfig != nil {
+		in, out := &in.RAIDConfig, &out.RAIDConfig
+		*out = new(RAIDConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ThickSnapshotConfig != nil {
+		in, out := &in.ThickSnapshotConfig, &out.ThickSnapshotConfig
+		*out = new(ThickSnapshotConfig)
+		**out = **in
+	}
+	if in.DeviceDiscoveryPolicy != nil {
+		in, out := &in.DeviceDiscoveryPolicy, &out.DeviceDiscoveryPolicy
+		*out = new(DeviceDiscoveryPolicySpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupSpec.
+func (in *LVMVolumeGroupSpec) DeepCopy() *LVMVolumeGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeGroupStatus) DeepCopyInto(out *LVMVolumeGroupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeGroupStatus.
+func (in *LVMVolumeGroupStatus) DeepCopy() *LVMVolumeGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeImport) DeepCopyInto(out *LVMVolumeImport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeImport.
+func (in *LVMVolumeImport) DeepCopy() *LVMVolumeImport {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeImport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeImportList) DeepCopyInto(out *LVMVolumeImportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LVMVolumeImport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeImportList.
+func (in *LVMVolumeImportList) DeepCopy() *LVMVolumeImportList {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeImportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeImportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeImportSpec) DeepCopyInto(out *LVMVolumeImportSpec) {
+	*out = *in
+	if in.ClaimRef != nil {
+		in, out := &in.ClaimRef, &out.ClaimRef
+		*out = new(VolumeImportClaimReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeImportSpec.
+func (in *LVMVolumeImportSpec) DeepCopy() *LVMVolumeImportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeImportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeImportStatus) DeepCopyInto(out *LVMVolumeImportStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImportableLogicalVolumes != nil {
+		in, out := &in.ImportableLogicalVolumes, &out.ImportableLogicalVolumes
+		*out = make([]ImportableLogicalVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeImportStatus.
+func (in *LVMVolumeImportStatus) DeepCopy() *LVMVolumeImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeMigration) DeepCopyInto(out *LVMVolumeMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeMigration.
+func (in *LVMVolumeMigration) DeepCopy() *LVMVolumeMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeMigrationList) DeepCopyInto(out *LVMVolumeMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LVMVolumeMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeMigrationList.
+func (in *LVMVolumeMigrationList) DeepCopy() *LVMVolumeMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeMigrationSpec) DeepCopyInto(out *LVMVolumeMigrationSpec) {
+	*out = *in
+	out.PersistentVolumeClaim = in.PersistentVolumeClaim
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeMigrationSpec.
+func (in *LVMVolumeMigrationSpec) DeepCopy() *LVMVolumeMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeMigrationStatus) DeepCopyInto(out *LVMVolumeMigrationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastPassTime != nil {
+		in, out := &in.LastPassTime, &out.LastPassTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Claim != nil {
+		in, out := &in.Claim, &out.Claim
+		*out = new(MigratedClaim)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeMigrationStatus.
+func (in *LVMVolumeMigrationStatus) DeepCopy() *LVMVolumeMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeRestore) DeepCopyInto(out *LVMVolumeRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeRestore.
+func (in *LVMVolumeRestore) DeepCopy() *LVMVolumeRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeRestoreList) DeepCopyInto(out *LVMVolumeRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LVMVolumeRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeRestoreList.
+func (in *LVMVolumeRestoreList) DeepCopy() *LVMVolumeRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeRestoreSpec) DeepCopyInto(out *LVMVolumeRestoreSpec) {
+	*out = *in
+	out.PersistentVolumeClaim = in.PersistentVolumeClaim
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeRestoreSpec.
+func (in *LVMVolumeRestoreSpec) DeepCopy() *LVMVolumeRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeRestoreStatus) DeepCopyInto(out *LVMVolumeRestoreStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeRestoreStatus.
+func (in *LVMVolumeRestoreStatus) DeepCopy() *LVMVolumeRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeRevert) DeepCopyInto(out *LVMVolumeRevert) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeRevert.
+func (in *LVMVolumeRevert) DeepCopy() *LVMVolumeRevert {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeRevert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeRevert) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeRevertList) DeepCopyInto(out *LVMVolumeRevertList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LVMVolumeRevert, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeRevertList.
+func (in *LVMVolumeRevertList) DeepCopy() *LVMVolumeRevertList {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeRevertList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LVMVolumeRevertList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeRevertSpec) DeepCopyInto(out *LVMVolumeRevertSpec) {
+	*out = *in
+	out.PersistentVolumeClaim = in.PersistentVolumeClaim
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeRevertSpec.
+func (in *LVMVolumeRevertSpec) DeepCopy() *LVMVolumeRevertSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeRevertSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LVMVolumeRevertStatus) DeepCopyInto(out *LVMVolumeRevertStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LVMVolumeRevertStatus.
+func (in *LVMVolumeRevertStatus) DeepCopy() *LVMVolumeRevertStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LVMVolumeRevertStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalVolumeConsistency) DeepCopyInto(out *LogicalVolumeConsistency) {
+	*out = *in
+	if in.Findings != nil {
+		in, out := &in.Findings, &out.Findings
+		*out = make([]LogicalVolumeFinding, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalVolumeConsistency.
+func (in *LogicalVolumeConsistency) DeepCopy() *LogicalVolumeConsistency {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalVolumeConsistency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalVolumeFinding) DeepCopyInto(out *LogicalVolumeFinding) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalVolumeFinding.
+func (in *LogicalVolumeFinding) DeepCopy() *LogicalVolumeFinding {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalVolumeFinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigratedClaim) DeepCopyInto(out *MigratedClaim) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigratedClaim.
+func (in *MigratedClaim) DeepCopy() *MigratedClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(MigratedClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
+	*out = *in
+	in.VGStatus.DeepCopyInto(&out.VGStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeStatus.
+func (in *NodeStatus) DeepCopy() *NodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RAIDConfig) DeepCopyInto(out *RAIDConfig) {
+	*out = *in
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = new(int)
+		**out = **in
+	}
+	if in.Stripes != nil {
+		in, out := &in.Stripes, &out.Stripes
+		*out = new(int)
+		**out = **in
+	}
+	if in.StripeSize != nil {
+		in, out := &in.StripeSize, &out.StripeSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAIDConfig.
+func (in *RAIDConfig) DeepCopy() *RAIDConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RAIDConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RAIDLVHealth) DeepCopyInto(out *RAIDLVHealth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAIDLVHealth.
+func (in *RAIDLVHealth) DeepCopy() *RAIDLVHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(RAIDLVHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RAIDStatus) DeepCopyInto(out *RAIDStatus) {
+	*out = *in
+	if in.MinSyncPercent != nil {
 		in, out := &in.MinSyncPercent, &out.MinSyncPercent
 		*out = new(int)
 		**out = **in
@@ -551,6 +1516,46 @@ func (in *RAIDStatus) DeepCopy() *RAIDStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SkippedPersistentVolumeClaim) DeepCopyInto(out *SkippedPersistentVolumeClaim) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SkippedPersistentVolumeClaim.
+func (in *SkippedPersistentVolumeClaim) DeepCopy() *SkippedPersistentVolumeClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(SkippedPersistentVolumeClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRetention) DeepCopyInto(out *SnapshotRetention) {
+	*out = *in
+	if in.MaxCount != nil {
+		in, out := &in.MaxCount, &out.MaxCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRetention.
+func (in *SnapshotRetention) DeepCopy() *SnapshotRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Storage) DeepCopyInto(out *Storage) {
 	*out = *in
@@ -612,6 +1617,21 @@ func (in *StorageClassOptions) DeepCopy() *StorageClassOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThickSnapshotConfig) DeepCopyInto(out *ThickSnapshotConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThickSnapshotConfig.
+func (in *ThickSnapshotConfig) DeepCopy() *ThickSnapshotConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ThickSnapshotConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ThinPoolConfig) DeepCopyInto(out *ThinPoolConfig) {
 	*out = *in
@@ -637,6 +1657,33 @@ func (in *ThinPoolConfig) DeepCopy() *ThinPoolConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VGCapacity) DeepCopyInto(out *VGCapacity) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+	out.Free = in.Free.DeepCopy()
+	if in.ThinPoolSize != nil {
+		in, out := &in.ThinPoolSize, &out.ThinPoolSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.ThinPoolUsed != nil {
+		in, out := &in.ThinPoolUsed, &out.ThinPoolUsed
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VGCapacity.
+func (in *VGCapacity) DeepCopy() *VGCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(VGCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VGStatus) DeepCopyInto(out *VGStatus) {
 	*out = *in
@@ -668,3 +1715,126 @@ func (in *VGStatus) DeepCopy() *VGStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeBackupSnapshotReference) DeepCopyInto(out *VolumeBackupSnapshotReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeBackupSnapshotReference.
+func (in *VolumeBackupSnapshotReference) DeepCopy() *VolumeBackupSnapshotReference {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeBackupSnapshotReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeCheckClaimReference) DeepCopyInto(out *VolumeCheckClaimReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeCheckClaimReference.
+func (in *VolumeCheckClaimReference) DeepCopy() *VolumeCheckClaimReference {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeCheckClaimReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeGroupStatus) DeepCopyInto(out *VolumeGroupStatus) {
+	*out = *in
+	in.VGStatus.DeepCopyInto(&out.VGStatus)
+	if in.LogicalVolumeConsistency != nil {
+		in, out := &in.LogicalVolumeConsistency, &out.LogicalVolumeConsistency
+		*out = new(LogicalVolumeConsistency)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(VGCapacity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeGroupStatus.
+func (in *VolumeGroupStatus) DeepCopy() *VolumeGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeImportClaimReference) DeepCopyInto(out *VolumeImportClaimReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeImportClaimReference.
+func (in *VolumeImportClaimReference) DeepCopy() *VolumeImportClaimReference {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeImportClaimReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeMigrationClaimReference) DeepCopyInto(out *VolumeMigrationClaimReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeMigrationClaimReference.
+func (in *VolumeMigrationClaimReference) DeepCopy() *VolumeMigrationClaimReference {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeMigrationClaimReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeRestoreClaimReference) DeepCopyInto(out *VolumeRestoreClaimReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeRestoreClaimReference.
+func (in *VolumeRestoreClaimReference) DeepCopy() *VolumeRestoreClaimReference {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeRestoreClaimReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeRevertClaimReference) DeepCopyInto(out *VolumeRevertClaimReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeRevertClaimReference.
+func (in *VolumeRevertClaimReference) DeepCopy() *VolumeRevertClaimReference {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeRevertClaimReference)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/openshift/lvm-operator/v4/internal/controllers/node/removal"
 	persistent_volume "github.com/openshift/lvm-operator/v4/internal/controllers/persistent-volume"
 	persistent_volume_claim "github.com/openshift/lvm-operator/v4/internal/controllers/persistent-volume-claim"
+	snapshot_schedule "github.com/openshift/lvm-operator/v4/internal/controllers/snapshot-schedule"
 	internalCSI "github.com/openshift/lvm-operator/v4/internal/csi"
 	"github.com/openshift/lvm-operator/v4/internal/migration/microlvms"
 	wipe_refactor "github.com/openshift/lvm-operator/v4/internal/migration/wipe-refactor"
@@ -289,6 +290,13 @@ func run(cmd *cobra.Command, _ []string, opts *Options) error {
 		return fmt.Errorf("unable to create PersistentVolumeClaim controller: %w", err)
 	}
 
+	if enableSnapshotting {
+		snapshotScheduleController := snapshot_schedule.NewReconciler(mgr.GetClient(), mgr.GetEventRecorder("lvms-snapshot-schedule-controller"))
+		if err := snapshotScheduleController.SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("unable to create LVMSnapshotSchedule controller: %w", err)
+		}
+	}
+
 	// register TopoLVM controllers
 	if err := topolvmcontrollers.SetupNodeReconciler(mgr, mgr.GetClient(), false); err != nil {
 		return fmt.Errorf("unable to create TopoLVM Node controller: %w", err)
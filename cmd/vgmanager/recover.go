@@ -0,0 +1,93 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vgmanager
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lsblk"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/recovery"
+	"github.com/spf13/cobra"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type RecoverOptions struct {
+	*Options
+
+	nodeName string
+	apply    bool
+}
+
+// NewRecoverCmd creates a new CLI command that reconstructs LogicalVolumes and PersistentVolumes from node state.
+func NewRecoverCmd(opts *Options) *cobra.Command {
+	recoverOpts := &RecoverOptions{Options: opts}
+	cmd := &cobra.Command{
+		Use:   "recover",
+		Short: "Reconstruct LogicalVolumes and PersistentVolumes from the logical volumes on the node",
+		Long: `Scans all volume groups managed by LVMS on the node for logical volumes provisioned by TopoLVM that are
+not referenced by any LogicalVolume, e.g. after etcd was restored from an outdated backup or the cluster was
+rebuilt on top of the same disks. By default, the LogicalVolumes and PersistentVolumes that would be created
+are printed as YAML for review. With --apply, they are created in the cluster.`,
+		SilenceErrors: false,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRecover(cmd, args, recoverOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&recoverOpts.nodeName, "node-name", os.Getenv("NODE_NAME"), "The name of the node the logical volumes are located on.",
+	)
+	cmd.Flags().BoolVar(
+		&recoverOpts.apply, "apply", false, "Create the recovered objects instead of printing them.",
+	)
+	return cmd
+}
+
+func runRecover(cmd *cobra.Command, _ []string, opts *RecoverOptions) error {
+	ctx := log.IntoContext(cmd.Context(), opts.SetupLog)
+
+	if opts.nodeName == "" {
+		return fmt.Errorf("node name is required, set --node-name or the NODE_NAME environment variable")
+	}
+
+	clnt, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: opts.Scheme})
+	if err != nil {
+		return fmt.Errorf("unable to initialize client: %w", err)
+	}
+
+	r := recovery.NewRecovery(clnt, lvm.NewDefaultHostLVM(), lsblk.NewDefaultHostLSBLK(), opts.nodeName)
+	volumes, err := r.Plan(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to determine logical volumes to recover: %w", err)
+	}
+	opts.SetupLog.Info("found logical volumes to recover", "count", len(volumes), "node", opts.nodeName)
+
+	if !opts.apply {
+		return recovery.WriteYAML(cmd.OutOrStdout(), volumes)
+	}
+
+	if err := r.Apply(ctx, volumes); err != nil {
+		return fmt.Errorf("unable to recover logical volumes: %w", err)
+	}
+	return nil
+}
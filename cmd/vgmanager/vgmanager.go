@@ -21,10 +21,12 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -37,13 +39,23 @@ import (
 	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/lvmcluster/resource"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/consistency"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/dmsetup"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/filter"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/fsck"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lsblk"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvmd"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/mkfs"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/thicksnapshot"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/thindelta"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/util"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/wipefs"
+	volume_backup "github.com/openshift/lvm-operator/v4/internal/controllers/volume-backup"
+	volume_check "github.com/openshift/lvm-operator/v4/internal/controllers/volume-check"
+	volume_import "github.com/openshift/lvm-operator/v4/internal/controllers/volume-import"
+	volume_migration "github.com/openshift/lvm-operator/v4/internal/controllers/volume-migration"
+	volume_revert "github.com/openshift/lvm-operator/v4/internal/controllers/volume-revert"
 	icsi "github.com/openshift/lvm-operator/v4/internal/csi"
 	"github.com/spf13/cobra"
 	"github.com/topolvm/topolvm/pkg/controller"
@@ -77,6 +89,8 @@ const (
 	DefaultHealthProbeAddr = ":8081"
 )
 
+var DefaultMigrationAddr = ":" + strconv.Itoa(constants.VGManagerMigrationPort)
+
 var ErrConfigModified = errors.New("lvmd config file is modified")
 var ErrTLSProfileModified = errors.New("API server TLS profile changed")
 var ErrNoDeviceClassesAvailable = errors.New("no device classes in lvmd.yaml configured, can not startup correctly")
@@ -88,6 +102,7 @@ type Options struct {
 
 	diagnosticsAddr string
 	healthProbeAddr string
+	migrationAddr   string
 }
 
 // NewCmd creates a new CLI command
@@ -109,6 +124,11 @@ func NewCmd(opts *Options) *cobra.Command {
 	cmd.Flags().StringVar(
 		&opts.healthProbeAddr, "health-probe-bind-address", DefaultHealthProbeAddr, "The address the probe endpoint binds to.",
 	)
+	cmd.Flags().StringVar(
+		&opts.migrationAddr, "migration-bind-address", DefaultMigrationAddr, "The address the endpoint receiving migrated volumes binds to.",
+	)
+
+	cmd.AddCommand(NewRecoverCmd(opts))
 	return cmd
 }
 
@@ -199,6 +219,15 @@ func run(cmd *cobra.Command, _ []string, opts *Options) error {
 	for _, c := range vgmanager.RAIDMetrics() {
 		ctrlmetrics.Registry.MustRegister(c)
 	}
+	for _, c := range vgmanager.LogicalVolumeMetrics() {
+		ctrlmetrics.Registry.MustRegister(c)
+	}
+	for _, c := range vgmanager.VolumeGroupMetrics() {
+		ctrlmetrics.Registry.MustRegister(c)
+	}
+	for _, c := range consistency.Metrics() {
+		ctrlmetrics.Registry.MustRegister(c)
+	}
 
 	tlsWatcherController := &ctrlRuntimeCommon.SecurityProfileWatcher{
 		Client:                mgr.GetClient(),
@@ -226,7 +255,10 @@ func run(cmd *cobra.Command, _ []string, opts *Options) error {
 	if err := loadConfFile(ctx, lvmdConfig, lvmd.DefaultFileConfigPath); err != nil {
 		opts.SetupLog.Error(err, "lvmd config could not be loaded, starting without topolvm components and attempting bootstrap")
 	} else {
-		lvclnt, vgclnt := topoLVMD.NewEmbeddedServiceClients(ctx, lvmdConfig.DeviceClasses, lvmdConfig.LvcreateOptionClasses)
+		topoLVMLVClnt, vgclnt := topoLVMD.NewEmbeddedServiceClients(ctx, lvmdConfig.DeviceClasses, lvmdConfig.LvcreateOptionClasses)
+		// snapshots of thick device classes are handled by LVMS as they are not supported by TopoLVM
+		lvclnt := thicksnapshot.NewLVService(topoLVMLVClnt, vgclnt, mgr.GetClient(), lvm.NewDefaultHostLVM(),
+			operatorNamespace, lvmdConfig.DeviceClasses)
 
 		if err := controller.SetupLogicalVolumeReconcilerWithServices(mgr, mgr.GetClient(), nodeName, vgclnt, lvclnt); err != nil {
 			return fmt.Errorf("unable to create LogicalVolumeReconciler: %w", err)
@@ -249,7 +281,7 @@ func run(cmd *cobra.Command, _ []string, opts *Options) error {
 		if err != nil {
 			return fmt.Errorf("could not setup topolvm node server: %w", err)
 		}
-		csi.RegisterNodeServer(csiGrpcServer, nodeServer)
+		csi.RegisterNodeServer(csiGrpcServer, icsi.NewNodeServer(nodeServer, mgr.GetClient(), mgr.GetAPIReader(), mkfs.NewDefaultHostMkfs(), nodeName))
 		err = mgr.Add(icsi.NewGRPCRunner(csiGrpcServer, constants.DefaultCSISocket, false))
 		if err != nil {
 			return fmt.Errorf("could not add grpc runner for node server: %w", err)
@@ -280,6 +312,103 @@ func run(cmd *cobra.Command, _ []string, opts *Options) error {
 		return fmt.Errorf("unable to create controller VGManager: %w", err)
 	}
 
+	// thin_delta and thin_ls share the single metadata snapshot of a thin pool, so they have to use the same instance
+	thinDelta := thindelta.NewDefaultHostThinDelta()
+
+	if err := mgr.Add(vgmanager.NewLogicalVolumeMetricsExporter(mgr.GetClient(), lvm.NewDefaultHostLVM(), thinDelta, nodeName, operatorNamespace)); err != nil {
+		return fmt.Errorf("could not add logical volume metrics: %w", err)
+	}
+
+	if err := mgr.Add(thicksnapshot.NewAutoExtender(mgr.GetClient(), lvm.NewDefaultHostLVM(), nodeName, operatorNamespace)); err != nil {
+		return fmt.Errorf("could not add thick snapshot auto extension: %w", err)
+	}
+
+	if err = volume_import.NewReconciler(
+		mgr.GetClient(),
+		mgr.GetEventRecorder(volume_import.ControllerName),
+		lvm.NewDefaultHostLVM(),
+		lsblk.NewDefaultHostLSBLK(),
+		nodeName,
+	).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create LVMVolumeImport controller: %w", err)
+	}
+
+	if err = volume_revert.NewReconciler(
+		mgr.GetClient(),
+		mgr.GetAPIReader(),
+		mgr.GetEventRecorder(volume_revert.ControllerName),
+		lvm.NewDefaultHostLVM(),
+		nodeName,
+		operatorNamespace,
+	).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create LVMVolumeRevert controller: %w", err)
+	}
+
+	migrationReceiver, err := volume_migration.NewReceiver(opts.migrationAddr, tlsOpts...)
+	if err != nil {
+		return fmt.Errorf("unable to create volume migration receiver: %w", err)
+	}
+	if err := mgr.Add(migrationReceiver); err != nil {
+		return fmt.Errorf("could not add volume migration receiver: %w", err)
+	}
+
+	if err = volume_migration.NewReconciler(
+		mgr.GetClient(),
+		mgr.GetAPIReader(),
+		mgr.GetEventRecorder(volume_migration.ControllerName),
+		lvm.NewDefaultHostLVM(),
+		migrationReceiver,
+		nodeName,
+		operatorNamespace,
+		net.JoinHostPort(os.Getenv("POD_IP"), strconv.Itoa(constants.VGManagerMigrationPort)),
+	).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create LVMVolumeMigration controller: %w", err)
+	}
+
+	if err = volume_backup.NewBackupReconciler(
+		mgr.GetClient(),
+		mgr.GetAPIReader(),
+		mgr.GetEventRecorder(volume_backup.BackupControllerName),
+		lvm.NewDefaultHostLVM(),
+		thinDelta,
+		nodeName,
+		operatorNamespace,
+	).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create LVMVolumeBackup controller: %w", err)
+	}
+
+	if err = volume_backup.NewRestoreReconciler(
+		mgr.GetClient(),
+		mgr.GetAPIReader(),
+		mgr.GetEventRecorder(volume_backup.RestoreControllerName),
+		lvm.NewDefaultHostLVM(),
+		nodeName,
+		operatorNamespace,
+	).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create LVMVolumeRestore controller: %w", err)
+	}
+
+	if err = volume_check.NewReconciler(
+		mgr.GetClient(),
+		mgr.GetAPIReader(),
+		mgr.GetEventRecorder(volume_check.ControllerName),
+		lvm.NewDefaultHostLVM(),
+		fsck.NewDefaultHostFsck(),
+		nodeName,
+		operatorNamespace,
+	).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create LVMVolumeCheck controller: %w", err)
+	}
+
+	if err = consistency.NewReconciler(
+		mgr.GetClient(),
+		mgr.GetEventRecorder(consistency.ControllerName),
+		lvm.NewDefaultHostLVM(),
+		nodeName,
+	).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create logical volume consistency controller: %w", err)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		return fmt.Errorf("unable to set up health check: %w", err)
 	}
@@ -24,6 +24,11 @@ const (
 	VgManagerMemRequest = "45Mi"
 	VgManagerCPURequest = "5m"
 
+	// VGManagerMigrationPortName is the name of the vg-manager port that receives migrated volumes
+	VGManagerMigrationPortName = "migration"
+	// VGManagerMigrationPort is the vg-manager port that receives migrated volumes
+	VGManagerMigrationPort = 9444
+
 	// topoLVM Node
 	CSIKubeletRootDir               = "/var/lib/kubelet/"
 	TopolvmNodeContainerHealthzName = "healthz"
@@ -45,6 +50,35 @@ const (
 	// DevicesWipedAnnotationPrefix is an annotation prefix that marks when a device has been wiped on a certain node
 	DevicesWipedAnnotationPrefix = "wiped.devices.lvms.openshift.io/"
 
+	// WipedDevicePathsAnnotationPrefix is an annotation prefix that records the device paths that have been wiped
+	// on a certain node as a JSON list, so that devices appended to a device class are wiped exactly once
+	WipedDevicePathsAnnotationPrefix = "wiped-paths.devices.lvms.openshift.io/"
+
+	// MaintenanceAnnotation pauses all volume group operations of vg-manager on a node if it is set to "true" on the Node
+	MaintenanceAnnotation = "lvms.topolvm.io/maintenance"
+
+	// PrefixedVolumeGroupsAnnotation is set to "true" on an LVMCluster that was created while another LVMCluster
+	// already existed. The volume groups, StorageClasses and VolumeSnapshotClasses of such a cluster are prefixed
+	// with the name of the LVMCluster to keep them unique.
+	PrefixedVolumeGroupsAnnotation = "lvms.topolvm.io/prefixed-volume-groups"
+
+	// StrandedVolumeAnnotation marks a PersistentVolume whose node or volume group is no longer available with the reason
+	StrandedVolumeAnnotation = "lvms.topolvm.io/stranded"
+	// StrandedSinceAnnotation records when a PersistentVolume was first detected as stranded
+	StrandedSinceAnnotation = "lvms.topolvm.io/stranded-since"
+
+	// ReadIOPSKey limits the read operations per second of a volume, set as StorageClass parameter or PersistentVolumeClaim annotation
+	ReadIOPSKey = "lvms.topolvm.io/read-iops"
+	// WriteIOPSKey limits the write operations per second of a volume, set as StorageClass parameter or PersistentVolumeClaim annotation
+	WriteIOPSKey = "lvms.topolvm.io/write-iops"
+	// ReadBPSKey limits the bytes read per second of a volume, set as StorageClass parameter or PersistentVolumeClaim annotation
+	ReadBPSKey = "lvms.topolvm.io/read-bps"
+	// WriteBPSKey limits the bytes written per second of a volume, set as StorageClass parameter or PersistentVolumeClaim annotation
+	WriteBPSKey = "lvms.topolvm.io/write-bps"
+
+	// MkfsOptionsKey is the StorageClass parameter with the options the filesystem of a volume is created with, separated by spaces
+	MkfsOptionsKey = "lvms.topolvm.io/mkfs-options"
+
 	// labels and values
 
 	// AppKubernetesPartOfLabel is the Kubernetes recommended part-of label
@@ -35,7 +35,9 @@ import (
 	topolvmv1 "github.com/topolvm/topolvm/api/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
@@ -53,6 +55,7 @@ const (
 	EventReasonErrorDeletionPending                  EventReasonError = "DeletionPending"
 	EventReasonErrorResourceReconciliationIncomplete EventReasonError = "ResourceReconciliationIncomplete"
 	EventReasonResourceReconciliationSuccess         EventReasonInfo  = "ResourceReconciliationSuccess"
+	EventReasonReconciliationPaused                  EventReasonInfo  = "ReconciliationPaused"
 
 	lvmClusterFinalizer = "lvmcluster.topolvm.io"
 	podNameEnv          = "NAME"
@@ -153,15 +156,6 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	logger := log.FromContext(ctx)
 	logger.V(2).Info("reconciling")
 
-	// Checks that only a single LVMCluster instance exists
-	lvmClusterList := &lvmv1alpha1.LVMClusterList{}
-	if err := r.List(context.TODO(), lvmClusterList, &client.ListOptions{}); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to list LVMCluster instances: %w", err)
-	}
-	if size := len(lvmClusterList.Items); size > 1 {
-		return ctrl.Result{}, fmt.Errorf("there should be a single LVMCluster but multiple were found, %d clusters found", size)
-	}
-
 	// get lvmcluster
 	lvmCluster := &lvmv1alpha1.LVMCluster{}
 	if err := r.Get(ctx, req.NamespacedName, lvmCluster); err != nil {
@@ -173,6 +167,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, fmt.Errorf("failed to introspect running pod image: %w", err)
 	}
 
+	// A paused LVMCluster is not changed in any way, including its deletion
+	if lvmCluster.Spec.Paused {
+		return r.reconcilePaused(ctx, lvmCluster)
+	}
+
 	// The resource was deleted
 	if !lvmCluster.DeletionTimestamp.IsZero() {
 		// check for stale vgmanager finalizer in case if node deleted but vg still exist
@@ -231,6 +230,7 @@ func (r *Reconciler) reconcile(ctx context.Context, instance *lvmv1alpha1.LVMClu
 
 	setResourcesAvailableConditionInProgress(instance)
 	setVolumeGroupsReadyConditionInProgress(instance)
+	instance.Status.ObservedGeneration = instance.GetGeneration()
 
 	if updated := controllerutil.AddFinalizer(instance, lvmClusterFinalizer); updated {
 		if err := r.Update(ctx, instance); err != nil {
@@ -239,6 +239,9 @@ func (r *Reconciler) reconcile(ctx context.Context, instance *lvmv1alpha1.LVMClu
 		logger.Info("successfully added finalizer")
 	}
 
+	// the LVMCluster is not (or no longer) paused, so reconciliation resumes
+	removePausedCondition(instance)
+
 	resources := []resource.Manager{
 		resource.NetworkPolicies(),
 		resource.CSIDriver(),
@@ -304,6 +307,28 @@ func (r *Reconciler) reconcile(ctx context.Context, instance *lvmv1alpha1.LVMClu
 	return ctrl.Result{Requeue: true, RequeueAfter: 1 * time.Minute}, nil
 }
 
+// reconcilePaused only refreshes the status of a paused LVMCluster, none of the resource managers are run.
+func (r *Reconciler) reconcilePaused(ctx context.Context, instance *lvmv1alpha1.LVMCluster) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !instance.DeletionTimestamp.IsZero() {
+		logger.Info("LVMCluster is paused, deletion is held back until it is unpaused")
+	} else {
+		logger.Info("LVMCluster is paused, skipping reconciliation of resources")
+	}
+
+	if !meta.IsStatusConditionTrue(instance.Status.Conditions, lvmv1alpha1.Paused) {
+		r.NormalEvent(ctx, instance, EventReasonReconciliationPaused, MessageReconciliationPaused)
+	}
+	setPausedConditionTrue(instance)
+
+	if err := r.updateLVMClusterStatus(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+}
+
 func (r *Reconciler) updateLVMClusterStatus(ctx context.Context, instance *lvmv1alpha1.LVMCluster) error {
 	logger := log.FromContext(ctx)
 
@@ -320,8 +345,19 @@ func (r *Reconciler) updateLVMClusterStatus(ctx context.Context, instance *lvmv1
 		if err := r.List(ctx, nodes); err != nil {
 			return fmt.Errorf("failed to list Nodes: %w", err)
 		}
-		setVolumeGroupsReadyCondition(ctx, instance, nodes, vgNodeStatusList)
-		instance.Status.DeviceClassStatuses = computeDeviceClassStatuses(vgNodeStatusList)
+		volumeGroups, err := r.getVolumeGroups(ctx, instance)
+		if err != nil {
+			return err
+		}
+		setVolumeGroupsReadyCondition(ctx, instance, nodes, vgNodeStatusList, volumeGroups)
+		deviceClassStatuses, err := aggregateDeviceClassStatuses(instance, nodes, vgNodeStatusList, computeDeviceClassStatuses(instance, vgNodeStatusList))
+		if err != nil {
+			return fmt.Errorf("failed to aggregate device class statuses: %w", err)
+		}
+		instance.Status.DeviceClassStatuses = deviceClassStatuses
+		if err := r.updateVolumeGroupStatuses(ctx, volumeGroups, vgNodeStatusList); err != nil {
+			return err
+		}
 	}
 
 	instance.Status.State, instance.Status.Ready = computeLVMClusterReadiness(instance.Status.Conditions)
@@ -335,6 +371,41 @@ func (r *Reconciler) updateLVMClusterStatus(ctx context.Context, instance *lvmv1
 	return nil
 }
 
+// getVolumeGroups returns the LVMVolumeGroups of the device classes of the LVMCluster.
+// LVMVolumeGroups that were not created yet are skipped.
+func (r *Reconciler) getVolumeGroups(ctx context.Context, instance *lvmv1alpha1.LVMCluster) ([]lvmv1alpha1.LVMVolumeGroup, error) {
+	var volumeGroups []lvmv1alpha1.LVMVolumeGroup
+	for _, deviceClass := range instance.Spec.Storage.DeviceClasses {
+		volumeGroup := lvmv1alpha1.LVMVolumeGroup{}
+		key := client.ObjectKey{Name: instance.VolumeGroupName(deviceClass.Name), Namespace: r.Namespace}
+		if err := r.Get(ctx, key, &volumeGroup); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get LVMVolumeGroup %s: %w", key.Name, err)
+		}
+		volumeGroups = append(volumeGroups, volumeGroup)
+	}
+	return volumeGroups, nil
+}
+
+// updateVolumeGroupStatuses aggregates the conditions reported by the nodes into the status
+// of every LVMVolumeGroup of the LVMCluster.
+func (r *Reconciler) updateVolumeGroupStatuses(ctx context.Context, volumeGroups []lvmv1alpha1.LVMVolumeGroup, vgNodeStatusList *lvmv1alpha1.LVMVolumeGroupNodeStatusList) error {
+	for i := range volumeGroups {
+		volumeGroup := &volumeGroups[i]
+		base := volumeGroup.DeepCopy()
+		setVolumeGroupConditions(volumeGroup, vgNodeStatusList)
+		if equality.Semantic.DeepEqual(base.Status, volumeGroup.Status) {
+			continue
+		}
+		if err := r.Status().Update(ctx, volumeGroup); err != nil {
+			return fmt.Errorf("failed to update status of LVMVolumeGroup %s: %w", volumeGroup.GetName(), err)
+		}
+	}
+	return nil
+}
+
 // getRunningPodImage gets the operator image and set it in reconciler struct
 func (r *Reconciler) setRunningPodImage(ctx context.Context) error {
 
@@ -373,7 +444,7 @@ func (r *Reconciler) checkForDeletedDeviceClasses(ctx context.Context, instance
 
 	desiredVgNames := make(map[string]struct{})
 	for _, dc := range instance.Spec.Storage.DeviceClasses {
-		desiredVgNames[dc.Name] = struct{}{}
+		desiredVgNames[instance.VolumeGroupName(dc.Name)] = struct{}{}
 	}
 
 	for _, currentVG := range currentVGs.Items {
@@ -460,7 +531,7 @@ func (r *Reconciler) logicalVolumesExist(ctx context.Context, nodes map[string]s
 // activePVCsExistForClusterStorageClasses checks if any PVCs reference StorageClasses created by LVMS for this cluster.
 func (r *Reconciler) activePVCsExistForClusterStorageClasses(ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) (bool, error) {
 	for _, dc := range lvmCluster.Spec.Storage.DeviceClasses {
-		scName := resource.GetStorageClassName(dc.Name)
+		scName := resource.GetStorageClassName(lvmCluster.VolumeGroupName(dc.Name))
 		pvcList := &corev1.PersistentVolumeClaimList{}
 		if err := r.List(ctx, pvcList, client.MatchingFields{"spec.storageClassName": scName}, client.Limit(1)); err != nil {
 			return false, fmt.Errorf("failed to list PVCs for StorageClass %s: %w", scName, err)
@@ -479,7 +550,7 @@ func (r *Reconciler) retainPVsExistForCluster(ctx context.Context, lvmCluster *l
 			*dc.StorageClassOptions.ReclaimPolicy != corev1.PersistentVolumeReclaimRetain {
 			continue
 		}
-		scName := resource.GetStorageClassName(dc.Name)
+		scName := resource.GetStorageClassName(lvmCluster.VolumeGroupName(dc.Name))
 		pvList := &corev1.PersistentVolumeList{}
 		if err := r.List(ctx, pvList, client.MatchingFields{"spec.storageClassName": scName}, client.Limit(1)); err != nil {
 			return false, fmt.Errorf("failed to list PVs for StorageClass %s: %w", scName, err)
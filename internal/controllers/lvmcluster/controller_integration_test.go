@@ -85,11 +85,13 @@ var _ = Describe("LVMCluster controller", func() {
 			Name:      testNodeName,
 			Namespace: testLvmClusterNamespace,
 		},
-		Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-			LVMVGStatus: []lvmv1alpha1.VGStatus{
+		Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+			VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 				{
-					Name:   testDeviceClassName,
-					Status: lvmv1alpha1.VGStatusReady,
+					VGStatus: lvmv1alpha1.VGStatus{
+						Name:   testDeviceClassName,
+						Status: lvmv1alpha1.VGStatusReady,
+					},
 				},
 			},
 		},
@@ -130,7 +132,10 @@ var _ = Describe("LVMCluster controller", func() {
 
 			Expect(k8sClient.Create(ctx, lvmClusterIn)).Should(Succeed())
 
+			nodeStatus := lvmVolumeGroupNodeStatusIn.Status.DeepCopy()
 			Expect(k8sClient.Create(ctx, lvmVolumeGroupNodeStatusIn)).Should(Succeed())
+			lvmVolumeGroupNodeStatusIn.Status = *nodeStatus
+			Expect(k8sClient.Status().Update(ctx, lvmVolumeGroupNodeStatusIn)).Should(Succeed())
 
 			By("verifying LVMCluster .Status.Ready is true")
 			Eventually(func(ctx context.Context) bool {
@@ -31,12 +31,15 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func newGinkgoReconciler(objs ...client.Object) *Reconciler {
@@ -255,3 +258,38 @@ var _ = Describe("Deletion Gates", func() {
 		})
 	})
 })
+
+var _ = Describe("Pausing", func() {
+	It("should only update the status of a paused LVMCluster", func(ctx context.Context) {
+		cluster := testLVMCluster(lvmv1alpha1.DeviceClass{Name: "vg1"})
+		cluster.Spec.Paused = true
+
+		r := newGinkgoReconciler()
+		r.Client = fake.NewClientBuilder().WithScheme(r.Scheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(cluster, &lvmv1alpha1.LVMVolumeGroup{}).
+			Build()
+		r.ImageName = "test-image"
+		r.EventRecorder = events.NewFakeRecorder(10)
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		Expect(cluster.GetFinalizers()).To(BeEmpty(), "should not add the finalizer while paused")
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, lvmv1alpha1.Paused)).To(BeTrue())
+
+		vgs := &lvmv1alpha1.LVMVolumeGroupList{}
+		Expect(r.List(ctx, vgs)).To(Succeed())
+		Expect(vgs.Items).To(BeEmpty(), "should not create LVMVolumeGroups while paused")
+
+		By("unpausing the LVMCluster")
+		cluster.Spec.Paused = false
+		Expect(r.Update(ctx, cluster)).To(Succeed())
+		_, _ = r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		Expect(cluster.GetFinalizers()).To(ContainElement(lvmClusterFinalizer))
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, lvmv1alpha1.Paused)).To(BeNil())
+	})
+})
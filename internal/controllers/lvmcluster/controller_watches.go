@@ -35,6 +35,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -70,7 +71,8 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&lvmv1alpha1.LVMCluster{}).
-		Owns(&appsv1.DaemonSet{}).
+		// the vg-manager DaemonSet is shared and owned by all LVMClusters
+		Owns(&appsv1.DaemonSet{}, ctrlbuilder.MatchEveryOwner).
 		Owns(&appsv1.Deployment{}).
 		Owns(&lvmv1alpha1.LVMVolumeGroup{}).
 		Watches(
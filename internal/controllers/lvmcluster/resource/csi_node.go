@@ -59,7 +59,20 @@ func (c csiNode) EnsureDeleted(r Reconciler, ctx context.Context, cluster *lvmv1
 
 	csiNodes, err := c.GetAllCSINodeCandidates(ctx, r, cluster)
 
+	// the driver stays registered on nodes that are still used by other LVMClusters
+	nodeList := &v1.NodeList{}
+	if err := r.List(ctx, nodeList); err != nil {
+		return err
+	}
+	remainingNodes, remainingErr := remainingLVMClusterNodes(r, ctx, cluster, nodeList)
+	if remainingErr != nil {
+		return remainingErr
+	}
+
 	for _, csiNode := range csiNodes {
+		if _, ok := remainingNodes[csiNode.Name]; ok {
+			continue
+		}
 		found := false
 		for _, driver := range csiNode.Spec.Drivers {
 			if driver.Name == constants.TopolvmCSIDriverName {
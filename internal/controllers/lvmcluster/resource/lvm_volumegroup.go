@@ -50,7 +50,7 @@ func (c lvmVG) GetName() string {
 func (c lvmVG) EnsureCreated(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) error {
 	logger := log.FromContext(ctx).WithValues("topolvmNode", c.GetName())
 
-	lvmVolumeGroups := lvmVolumeGroups(r.GetNamespace(), lvmCluster.Spec.Storage.DeviceClasses)
+	lvmVolumeGroups := lvmVolumeGroups(r.GetNamespace(), lvmCluster)
 
 	for _, volumeGroup := range lvmVolumeGroups {
 		existingVolumeGroup := &lvmv1alpha1.LVMVolumeGroup{
@@ -88,7 +88,7 @@ func (c lvmVG) EnsureCreated(r Reconciler, ctx context.Context, lvmCluster *lvmv
 
 func (c lvmVG) EnsureDeleted(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) error {
 	logger := log.FromContext(ctx).WithValues("resourceManager", c.GetName())
-	vgcrs := lvmVolumeGroups(r.GetNamespace(), lvmCluster.Spec.Storage.DeviceClasses)
+	vgcrs := lvmVolumeGroups(r.GetNamespace(), lvmCluster)
 
 	var volumeGroupsPendingDelete []string
 
@@ -127,23 +127,27 @@ func (c lvmVG) EnsureDeleted(r Reconciler, ctx context.Context, lvmCluster *lvmv
 	return nil
 }
 
-func lvmVolumeGroups(namespace string, deviceClasses []lvmv1alpha1.DeviceClass) []*lvmv1alpha1.LVMVolumeGroup {
-
+func lvmVolumeGroups(namespace string, lvmCluster *lvmv1alpha1.LVMCluster) []*lvmv1alpha1.LVMVolumeGroup {
+	deviceClasses := lvmCluster.Spec.Storage.DeviceClasses
 	lvmVolumeGroups := make([]*lvmv1alpha1.LVMVolumeGroup, 0, len(deviceClasses))
 
 	for _, deviceClass := range deviceClasses {
 		lvmVolumeGroup := &lvmv1alpha1.LVMVolumeGroup{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      deviceClass.Name,
+				Name:      lvmCluster.VolumeGroupName(deviceClass.Name),
 				Namespace: namespace,
 			},
 			Spec: lvmv1alpha1.LVMVolumeGroupSpec{
-				NodeSelector:          deviceClass.NodeSelector,
-				DeviceSelector:        deviceClass.DeviceSelector,
-				ThinPoolConfig:        deviceClass.ThinPoolConfig,
-				RAIDConfig:            deviceClass.RAIDConfig,
-				Default:               len(deviceClasses) == 1 || deviceClass.Default, // True if there is only one device class or default is explicitly set.
-				DeviceDiscoveryPolicy: deviceClass.DeviceDiscoveryPolicy,
+				NodeSelector:                deviceClass.NodeSelector,
+				DeviceSelector:              deviceClass.DeviceSelector,
+				NodeOverrides:               deviceClass.NodeOverrides,
+				ThinPoolConfig:              deviceClass.ThinPoolConfig,
+				RAIDConfig:                  deviceClass.RAIDConfig,
+				ThickSnapshotConfig:         deviceClass.ThickSnapshotConfig,
+				Default:                     lvmCluster.IsDefaultDeviceClass(&deviceClass),
+				DeviceDiscoveryPolicy:       deviceClass.DeviceDiscoveryPolicy,
+				OrphanedLogicalVolumePolicy: deviceClass.OrphanedLogicalVolumePolicy,
+				NodeRemovalPolicy:           deviceClass.NodeRemovalPolicy,
 			},
 		}
 		lvmVolumeGroups = append(lvmVolumeGroups, lvmVolumeGroup)
@@ -0,0 +1,70 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"testing"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLVMVolumeGroupsPropagation(t *testing.T) {
+	lvmCluster := &lvmv1alpha1.LVMCluster{Spec: lvmv1alpha1.LVMClusterSpec{Storage: lvmv1alpha1.Storage{
+		DeviceClasses: []lvmv1alpha1.DeviceClass{
+			{Name: "vg1", OrphanedLogicalVolumePolicy: lvmv1alpha1.OrphanedLogicalVolumePolicyDelete, NodeRemovalPolicy: lvmv1alpha1.NodeRemovalPolicyRemove},
+			{Name: "vg2", Default: true, ThickSnapshotConfig: &lvmv1alpha1.ThickSnapshotConfig{ReservePercent: 30}},
+		},
+	}}}
+
+	volumeGroups := lvmVolumeGroups("openshift-lvm-storage", lvmCluster)
+	require.Len(t, volumeGroups, 2)
+
+	assert.Equal(t, "vg1", volumeGroups[0].Name)
+	assert.Equal(t, "openshift-lvm-storage", volumeGroups[0].Namespace)
+	assert.Equal(t, lvmv1alpha1.OrphanedLogicalVolumePolicyDelete, volumeGroups[0].Spec.OrphanedLogicalVolumePolicy)
+	assert.Equal(t, lvmv1alpha1.NodeRemovalPolicyRemove, volumeGroups[0].Spec.NodeRemovalPolicy)
+	assert.False(t, volumeGroups[0].Spec.Default)
+
+	assert.Nil(t, volumeGroups[0].Spec.ThickSnapshotConfig)
+
+	assert.Empty(t, volumeGroups[1].Spec.OrphanedLogicalVolumePolicy)
+	assert.Empty(t, volumeGroups[1].Spec.NodeRemovalPolicy)
+	assert.Equal(t, &lvmv1alpha1.ThickSnapshotConfig{ReservePercent: 30}, volumeGroups[1].Spec.ThickSnapshotConfig)
+	assert.True(t, volumeGroups[1].Spec.Default)
+}
+
+func TestLVMVolumeGroupsPrefixed(t *testing.T) {
+	lvmCluster := &lvmv1alpha1.LVMCluster{Spec: lvmv1alpha1.LVMClusterSpec{Storage: lvmv1alpha1.Storage{
+		DeviceClasses: []lvmv1alpha1.DeviceClass{{Name: "vg1"}},
+	}}}
+
+	volumeGroups := lvmVolumeGroups("openshift-lvm-storage", lvmCluster)
+	require.Len(t, volumeGroups, 1)
+	assert.Equal(t, "vg1", volumeGroups[0].Name)
+	assert.True(t, volumeGroups[0].Spec.Default, "a single device class is the default")
+
+	lvmCluster.SetName("team-a")
+	lvmCluster.SetAnnotations(map[string]string{constants.PrefixedVolumeGroupsAnnotation: "true"})
+
+	volumeGroups = lvmVolumeGroups("openshift-lvm-storage", lvmCluster)
+	require.Len(t, volumeGroups, 1)
+	assert.Equal(t, "team-a-vg1", volumeGroups[0].Name)
+	assert.False(t, volumeGroups[0].Spec.Default, "a single prefixed device class is not implicitly the default")
+}
@@ -25,7 +25,6 @@ import (
 	"github.com/openshift/lvm-operator/v4/internal/controllers/lvmcluster/selector"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	cutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -70,8 +69,9 @@ func (l lvmVGNodeStatus) EnsureCreated(r Reconciler, ctx context.Context, cluste
 			},
 		}
 		result, err := cutil.CreateOrUpdate(ctx, r, lvmVGNodeStatus, func() error {
-			if err := ctrl.SetControllerReference(cluster, lvmVGNodeStatus, r.Scheme()); err != nil {
-				return fmt.Errorf("failed to set controller reference: %w", err)
+			// the node status is shared by all LVMClusters that are placed on the node
+			if err := cutil.SetOwnerReference(cluster, lvmVGNodeStatus, r.Scheme()); err != nil {
+				return fmt.Errorf("failed to set owner reference: %w", err)
 			}
 			if !hasDeleteProtectionFinalizer(lvmVGNodeStatus.Finalizers) {
 				lvmVGNodeStatus.SetFinalizers(append(lvmVGNodeStatus.Finalizers, constants.DeleteProtectionFinalizer))
@@ -103,9 +103,17 @@ func (l lvmVGNodeStatus) EnsureDeleted(r Reconciler, ctx context.Context, cluste
 		return fmt.Errorf("failed to list nodes: %w", err)
 	}
 
+	remainingNodes, err := remainingLVMClusterNodes(r, ctx, cluster, &nodeList)
+	if err != nil {
+		return err
+	}
+
 	validNodes, err := selector.ValidNodes(cluster, &nodeList)
 
 	for _, status := range nodeStatusList.Items {
+		if _, ok := remainingNodes[status.Name]; ok {
+			continue
+		}
 		if isValidNode(status.Name, validNodes) {
 			if err := l.deleteNodeStatus(r, ctx, status); err != nil {
 				return err
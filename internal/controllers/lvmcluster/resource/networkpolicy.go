@@ -28,7 +28,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	cutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -53,6 +52,11 @@ func (n networkPolicyManager) GetName() string {
 func (n networkPolicyManager) EnsureCreated(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) error {
 	logger := log.FromContext(ctx).WithValues("resourceManager", n.GetName())
 
+	owner, err := sharedOwner(r, ctx, lvmCluster)
+	if err != nil {
+		return fmt.Errorf("%s failed to reconcile: %w", n.GetName(), err)
+	}
+
 	// Allow policies first, then default-deny — avoids transient traffic blocking during
 	// initial deployment or upgrade.
 	policies := allNetworkPolicies(r.GetNamespace())
@@ -66,8 +70,8 @@ func (n networkPolicyManager) EnsureCreated(r Reconciler, ctx context.Context, l
 
 		result, err := cutil.CreateOrUpdate(ctx, r, np, func() error {
 			np.Spec = template.Spec
-			labels.SetManagedLabels(r.Scheme(), np, lvmCluster)
-			return ctrl.SetControllerReference(lvmCluster, np, r.Scheme())
+			labels.SetManagedLabels(r.Scheme(), np, owner)
+			return cutil.SetOwnerReference(lvmCluster, np, r.Scheme())
 		})
 		if err != nil {
 			return fmt.Errorf("%s failed to reconcile NetworkPolicy %s: %w", n.GetName(), template.Name, err)
@@ -80,9 +84,16 @@ func (n networkPolicyManager) EnsureCreated(r Reconciler, ctx context.Context, l
 	return nil
 }
 
-func (n networkPolicyManager) EnsureDeleted(r Reconciler, ctx context.Context, _ *lvmv1alpha1.LVMCluster) error {
+func (n networkPolicyManager) EnsureDeleted(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) error {
 	logger := log.FromContext(ctx).WithValues("resourceManager", n.GetName())
 
+	if remaining, err := remainingLVMClusters(r, ctx, lvmCluster); err != nil {
+		return fmt.Errorf("failed to check for other LVMClusters: %w", err)
+	} else if len(remaining) > 0 {
+		logger.Info("skipping NetworkPolicy deletion as they are still used by other LVMClusters")
+		return nil
+	}
+
 	// Reverse order: delete default-deny first so allow policies remain active
 	// while the operator still needs API server egress for subsequent deletes.
 	policies := allNetworkPolicies(r.GetNamespace())
@@ -192,8 +203,38 @@ func vgManagerAllowPolicy(namespace string) networkingv1.NetworkPolicy {
 						networkPolicyPort(corev1.ProtocolTCP, 8443),
 					},
 				},
+				{
+					// volumes migrated between nodes are copied from vg-manager to vg-manager
+					From: []networkingv1.NetworkPolicyPeer{vgManagerPeer()},
+					Ports: []networkingv1.NetworkPolicyPort{
+						networkPolicyPort(corev1.ProtocolTCP, constants.VGManagerMigrationPort),
+					},
+				},
+			},
+			Egress: append(commonEgressRules(),
+				networkingv1.NetworkPolicyEgressRule{
+					To: []networkingv1.NetworkPolicyPeer{vgManagerPeer()},
+					Ports: []networkingv1.NetworkPolicyPort{
+						networkPolicyPort(corev1.ProtocolTCP, constants.VGManagerMigrationPort),
+					},
+				},
+				networkingv1.NetworkPolicyEgressRule{
+					// volume backups are uploaded to and restored from object storage
+					Ports: []networkingv1.NetworkPolicyPort{
+						networkPolicyPort(corev1.ProtocolTCP, 443),
+					},
+				},
+			),
+		},
+	}
+}
+
+func vgManagerPeer() networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				constants.AppKubernetesNameLabel: constants.VGManagerLabelVal,
 			},
-			Egress: commonEgressRules(),
 		},
 	}
 }
@@ -161,14 +161,14 @@ func TestNetworkPolicy_VGManagerAllowSpec(t *testing.T) {
 		t.Errorf("expected podSelector to target %s, got %v", constants.VGManagerLabelVal, got.Spec.PodSelector.MatchLabels)
 	}
 
-	// Should have 1 ingress rule (metrics 8443 only, no webhook)
-	if len(got.Spec.Ingress) != 1 {
-		t.Errorf("expected 1 ingress rule, got %d", len(got.Spec.Ingress))
+	// Should have 2 ingress rules (metrics 8443 and migration from vg-manager, no webhook)
+	if len(got.Spec.Ingress) != 2 {
+		t.Errorf("expected 2 ingress rules, got %d", len(got.Spec.Ingress))
 	}
 
-	// Should have egress rules (API server + DNS)
-	if len(got.Spec.Egress) != 2 {
-		t.Errorf("expected 2 egress rules, got %d", len(got.Spec.Egress))
+	// Should have egress rules (API server + DNS + migration to vg-manager + object storage)
+	if len(got.Spec.Egress) != 4 {
+		t.Errorf("expected 4 egress rules, got %d", len(got.Spec.Egress))
 	}
 }
 
@@ -53,6 +53,11 @@ func (c openshiftSccs) GetName() string {
 
 func (c openshiftSccs) EnsureCreated(r Reconciler, ctx context.Context, cluster *lvmv1alpha1.LVMCluster) error {
 	logger := log.FromContext(ctx).WithValues("resourceManager", c.GetName())
+	owner, err := sharedOwner(r, ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("%s failed to reconcile: %w", c.GetName(), err)
+	}
+
 	sccs := getAllSCCs(r.GetNamespace())
 	for _, template := range sccs {
 		scc := &secv1.SecurityContextConstraints{
@@ -65,7 +70,7 @@ func (c openshiftSccs) EnsureCreated(r Reconciler, ctx context.Context, cluster
 			if scc.CreationTimestamp.IsZero() {
 				template.DeepCopyInto(scc)
 			}
-			labels.SetManagedLabels(r.Scheme(), scc, cluster)
+			labels.SetManagedLabels(r.Scheme(), scc, owner)
 			scc.Users = template.Users
 			return nil
 		})
@@ -80,8 +85,16 @@ func (c openshiftSccs) EnsureCreated(r Reconciler, ctx context.Context, cluster
 	return nil
 }
 
-func (c openshiftSccs) EnsureDeleted(r Reconciler, ctx context.Context, _ *lvmv1alpha1.LVMCluster) error {
+func (c openshiftSccs) EnsureDeleted(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) error {
 	logger := log.FromContext(ctx).WithValues("resourceManager", c.GetName())
+
+	if remaining, err := remainingLVMClusters(r, ctx, lvmCluster); err != nil {
+		return fmt.Errorf("failed to check for other LVMClusters: %w", err)
+	} else if len(remaining) > 0 {
+		logger.Info("skipping SecurityContextConstraint deletion as they are still used by other LVMClusters")
+		return nil
+	}
+
 	sccs := getAllSCCs(r.GetNamespace())
 	for _, scc := range sccs {
 		name := types.NamespacedName{Name: scName}
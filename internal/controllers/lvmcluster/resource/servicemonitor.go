@@ -116,10 +116,15 @@ func (s serviceMonitorManager) EnsureCreated(r Reconciler, ctx context.Context,
 		},
 	}
 
+	owner, err := sharedOwner(r, ctx, lvmCluster)
+	if err != nil {
+		return fmt.Errorf("%s failed to reconcile: %w", s.GetName(), err)
+	}
+
 	// Create or update the ServiceMonitor
 	result, err := cutil.CreateOrUpdate(ctx, r, serviceMonitor, func() error {
 		// Set managed labels
-		labels.SetManagedLabels(r.Scheme(), serviceMonitor, lvmCluster)
+		labels.SetManagedLabels(r.Scheme(), serviceMonitor, owner)
 		return nil
 	})
 
@@ -137,6 +142,13 @@ func (s serviceMonitorManager) EnsureCreated(r Reconciler, ctx context.Context,
 func (s serviceMonitorManager) EnsureDeleted(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) error {
 	logger := log.FromContext(ctx).WithValues("resourceManager", s.GetName())
 
+	if remaining, err := remainingLVMClusters(r, ctx, lvmCluster); err != nil {
+		return fmt.Errorf("failed to check for other LVMClusters: %w", err)
+	} else if len(remaining) > 0 {
+		logger.Info("skipping ServiceMonitor deletion as it is still used by other LVMClusters")
+		return nil
+	}
+
 	isPrometheusAvailable, err := s.IsPrometheusAvailable(r, ctx)
 	if err != nil {
 		return fmt.Errorf("failed to check if Prometheus is available: %w", err)
@@ -0,0 +1,100 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/lvmcluster/selector"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// lvmClusters returns all LVMClusters in the namespace of the operator sorted by name.
+// The given LVMCluster replaces its listed copy so that its latest state is used.
+func lvmClusters(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) ([]*lvmv1alpha1.LVMCluster, error) {
+	list := &lvmv1alpha1.LVMClusterList{}
+	if err := r.List(ctx, list, client.InNamespace(r.GetNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list LVMClusters: %w", err)
+	}
+
+	clusters := []*lvmv1alpha1.LVMCluster{lvmCluster}
+	for i := range list.Items {
+		if list.Items[i].GetName() != lvmCluster.GetName() {
+			clusters = append(clusters, &list.Items[i])
+		}
+	}
+	slices.SortFunc(clusters, func(a, b *lvmv1alpha1.LVMCluster) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return clusters, nil
+}
+
+// remainingLVMClusters returns the LVMClusters other than the given one that are not being deleted.
+// Resources shared by all LVMClusters are only removed once no other LVMCluster remains.
+func remainingLVMClusters(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) ([]*lvmv1alpha1.LVMCluster, error) {
+	clusters, err := lvmClusters(r, ctx, lvmCluster)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(clusters, func(other *lvmv1alpha1.LVMCluster) bool {
+		return other.GetName() == lvmCluster.GetName() || !other.GetDeletionTimestamp().IsZero()
+	}), nil
+}
+
+// sharedOwner returns the LVMCluster that is set in the managed labels of resources shared by all LVMClusters.
+// It is the oldest LVMCluster that is not being deleted, so the labels do not flip between the reconciliations
+// of different LVMClusters.
+func sharedOwner(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) (*lvmv1alpha1.LVMCluster, error) {
+	remaining, err := remainingLVMClusters(r, ctx, lvmCluster)
+	if err != nil {
+		return nil, err
+	}
+	owner := lvmCluster
+	for _, other := range remaining {
+		created, ownerCreated := other.GetCreationTimestamp(), owner.GetCreationTimestamp()
+		if created.Before(&ownerCreated) || (created.Equal(&ownerCreated) && other.GetName() < owner.GetName()) {
+			owner = other
+		}
+	}
+	return owner, nil
+}
+
+// remainingLVMClusterNodes returns the names of the nodes that are valid for the remaining LVMClusters.
+// Node specific resources of these nodes are kept when the given LVMCluster is deleted.
+func remainingLVMClusterNodes(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster, nodes *corev1.NodeList) (map[string]struct{}, error) {
+	remaining, err := remainingLVMClusters(r, ctx, lvmCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{})
+	for _, other := range remaining {
+		validNodes, err := selector.ValidNodes(other, nodes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine the nodes of LVMCluster %s: %w", other.GetName(), err)
+		}
+		for _, node := range validNodes {
+			names[node.GetName()] = struct{}{}
+		}
+	}
+	return names, nil
+}
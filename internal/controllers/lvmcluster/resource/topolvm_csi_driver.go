@@ -55,8 +55,13 @@ func (c csiDriver) EnsureCreated(r Reconciler, ctx context.Context, cluster *lvm
 	logger := log.FromContext(ctx).WithValues("resourceManager", c.GetName())
 	csiDriverResource := getCSIDriverResource()
 
+	owner, err := sharedOwner(r, ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("%s failed to reconcile: %w", c.GetName(), err)
+	}
+
 	result, err := cutil.CreateOrUpdate(ctx, r, csiDriverResource, func() error {
-		labels.SetManagedLabels(r.Scheme(), csiDriverResource, cluster)
+		labels.SetManagedLabels(r.Scheme(), csiDriverResource, owner)
 		// no need to mutate any field
 		return nil
 	})
@@ -70,9 +75,16 @@ func (c csiDriver) EnsureCreated(r Reconciler, ctx context.Context, cluster *lvm
 	return nil
 }
 
-func (c csiDriver) EnsureDeleted(r Reconciler, ctx context.Context, _ *lvmv1alpha1.LVMCluster) error {
+func (c csiDriver) EnsureDeleted(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) error {
 	name := types.NamespacedName{Name: constants.TopolvmCSIDriverName}
 	logger := log.FromContext(ctx).WithValues("resourceManager", c.GetName(), "CSIDriver", constants.TopolvmCSIDriverName)
+
+	if remaining, err := remainingLVMClusters(r, ctx, lvmCluster); err != nil {
+		return fmt.Errorf("failed to check for other LVMClusters: %w", err)
+	} else if len(remaining) > 0 {
+		logger.Info("skipping CSIDriver deletion as it is still used by other LVMClusters")
+		return nil
+	}
 	csiDriverResource := &storagev1.CSIDriver{}
 	if err := r.Get(ctx, name, csiDriverResource); err != nil {
 		return client.IgnoreNotFound(err)
@@ -77,7 +77,7 @@ func (s topolvmVolumeSnapshotClass) EnsureDeleted(r Reconciler, ctx context.Cont
 	for _, deviceClass := range lvmCluster.Spec.Storage.DeviceClasses {
 		// construct name of volume snapshot class based on CR spec deviceClass field and
 		// delete the corresponding volume snapshot class
-		vscName := GetVolumeSnapshotClassName(deviceClass.Name)
+		vscName := GetVolumeSnapshotClassName(lvmCluster.VolumeGroupName(deviceClass.Name))
 		logger := logger.WithValues("VolumeSnapshotClass", vscName)
 
 		vsc := &snapapi.VolumeSnapshotClass{}
@@ -104,12 +104,12 @@ func getTopolvmSnapshotClasses(lvmCluster *lvmv1alpha1.LVMCluster) []*snapapi.Vo
 	var vsc []*snapapi.VolumeSnapshotClass
 
 	for _, deviceClass := range lvmCluster.Spec.Storage.DeviceClasses {
-		if deviceClass.ThinPoolConfig == nil {
+		if deviceClass.ThinPoolConfig == nil && deviceClass.ThickSnapshotConfig == nil {
 			continue
 		}
 		snapshotClass := &snapapi.VolumeSnapshotClass{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: GetVolumeSnapshotClassName(deviceClass.Name),
+				Name: GetVolumeSnapshotClassName(lvmCluster.VolumeGroupName(deviceClass.Name)),
 			},
 
 			Driver:         constants.TopolvmCSIDriverName,
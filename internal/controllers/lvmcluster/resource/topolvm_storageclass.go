@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"strings"
 
 	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
@@ -79,7 +80,7 @@ func (s topolvmStorageClass) EnsureDeleted(r Reconciler, ctx context.Context, lv
 	// construct name of storage class based on CR spec deviceClass field and
 	// delete the corresponding storage class
 	for _, deviceClass := range lvmCluster.Spec.Storage.DeviceClasses {
-		scName := GetStorageClassName(deviceClass.Name)
+		scName := GetStorageClassName(lvmCluster.VolumeGroupName(deviceClass.Name))
 		logger := logger.WithValues("StorageClass", scName)
 
 		sc := &storagev1.StorageClass{}
@@ -128,7 +129,7 @@ func (s topolvmStorageClass) getTopolvmStorageClasses(r Reconciler, ctx context.
 
 	var sc []*storagev1.StorageClass
 	for _, deviceClass := range lvmCluster.Spec.Storage.DeviceClasses {
-		scName := GetStorageClassName(deviceClass.Name)
+		scName := GetStorageClassName(lvmCluster.VolumeGroupName(deviceClass.Name))
 
 		// Defaults
 		reclaimPolicy := corev1.PersistentVolumeReclaimDelete
@@ -149,8 +150,12 @@ func (s topolvmStorageClass) getTopolvmStorageClasses(r Reconciler, ctx context.
 			maps.Copy(parameters, deviceClass.StorageClassOptions.AdditionalParameters)
 		}
 		// Set LVMS-owned keys after copy so they can't be overwritten.
-		parameters[constants.DeviceClassKey] = deviceClass.Name
+		parameters[constants.DeviceClassKey] = lvmCluster.VolumeGroupName(deviceClass.Name)
 		parameters[constants.FsTypeKey] = string(deviceClass.FilesystemType)
+		// only set if configured, as adding a parameter to an existing StorageClass is forbidden
+		if len(deviceClass.MkfsOptions) > 0 {
+			parameters[constants.MkfsOptionsKey] = strings.Join(deviceClass.MkfsOptions, " ")
+		}
 
 		// Always declare the default-class annotation so the SSA field manager
 		// owns it and can toggle or remove it on day-2 changes.
@@ -177,6 +182,7 @@ func (s topolvmStorageClass) getTopolvmStorageClasses(r Reconciler, ctx context.
 			VolumeBindingMode:    &volumeBindingMode,
 			AllowVolumeExpansion: &allowVolumeExpansion,
 			Parameters:           parameters,
+			MountOptions:         deviceClass.MountOptions,
 		}
 
 		storageClass.Labels = make(map[string]string)
@@ -19,6 +19,7 @@ package resource
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/go-logr/logr/testr"
@@ -213,6 +214,41 @@ func TestGetTopolvmStorageClasses_AdditionalParams(t *testing.T) {
 	}
 }
 
+func TestGetTopolvmStorageClasses_FilesystemOptions(t *testing.T) {
+	scheme := newTestScheme(t)
+	r := newFakeStorageClassReconciler(t, scheme)
+	ctx := log.IntoContext(context.Background(), testr.New(t))
+
+	cluster := testCluster(
+		lvmv1alpha1.DeviceClass{
+			Name:           "vg1",
+			FilesystemType: lvmv1alpha1.FilesystemTypeXFS,
+			MkfsOptions:    []string{"-m", "reflink=1"},
+			MountOptions:   []string{"noatime", "discard"},
+		},
+		lvmv1alpha1.DeviceClass{
+			Name:           "vg2",
+			FilesystemType: lvmv1alpha1.FilesystemTypeExt4,
+		},
+	)
+
+	sc := topolvmStorageClass{}
+	result := sc.getTopolvmStorageClasses(r, ctx, cluster)
+
+	if got := result[0].Parameters[constants.MkfsOptionsKey]; got != "-m reflink=1" {
+		t.Errorf("expected mkfs options %q, got %q", "-m reflink=1", got)
+	}
+	if !reflect.DeepEqual(result[0].MountOptions, []string{"noatime", "discard"}) {
+		t.Errorf("expected mount options noatime,discard, got %v", result[0].MountOptions)
+	}
+	if _, ok := result[1].Parameters[constants.MkfsOptionsKey]; ok {
+		t.Errorf("mkfs options parameter should not be set without mkfsOptions")
+	}
+	if result[1].MountOptions != nil {
+		t.Errorf("expected no mount options, got %v", result[1].MountOptions)
+	}
+}
+
 func TestGetTopolvmStorageClasses_AdditionalLabels(t *testing.T) {
 	scheme := newTestScheme(t)
 	r := newFakeStorageClassReconciler(t, scheme)
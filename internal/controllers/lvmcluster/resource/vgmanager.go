@@ -55,17 +55,23 @@ func (v vgManager) GetName() string {
 func (v vgManager) EnsureCreated(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) error {
 	logger := log.FromContext(ctx).WithValues("resourceManager", v.GetName())
 
+	// the daemonset is shared by all LVMClusters and has to run on the nodes of all of them
+	clusters, err := lvmClusters(r, ctx, lvmCluster)
+	if err != nil {
+		return fmt.Errorf("%s failed to reconcile: %w", v.GetName(), err)
+	}
+
 	// get desired daemonset spec
 	dsTemplate := templateVGManagerDaemonset(
-		lvmCluster,
+		clusters,
 		v.clusterType,
 		r.GetNamespace(),
 		r.GetImageName(),
 		r.GetVGManagerCommand(),
 		r.GetLogPassthroughOptions().VGManager.AsArgs(),
 	)
-	if err := ctrl.SetControllerReference(lvmCluster, &dsTemplate, r.Scheme()); err != nil {
-		return fmt.Errorf("failed to set controller reference on vgManager daemonset %q. %v", dsTemplate.Name, err)
+	if err := cutil.SetOwnerReference(lvmCluster, &dsTemplate, r.Scheme()); err != nil {
+		return fmt.Errorf("failed to set owner reference on vgManager daemonset %q. %v", dsTemplate.Name, err)
 	}
 
 	// create desired daemonset or update mutable fields on existing one
@@ -84,8 +90,9 @@ func (v vgManager) EnsureCreated(r Reconciler, ctx context.Context, lvmCluster *
 			return nil
 		}
 
-		if err := ctrl.SetControllerReference(lvmCluster, ds, r.Scheme()); err != nil {
-			return fmt.Errorf("failed to set controller reference on vgManager daemonset %q. %v", dsTemplate.Name, err)
+		// every LVMCluster is an owner, so the daemonset is only garbage collected once all of them are gone
+		if err := cutil.SetOwnerReference(lvmCluster, ds, r.Scheme()); err != nil {
+			return fmt.Errorf("failed to set owner reference on vgManager daemonset %q. %v", dsTemplate.Name, err)
 		}
 
 		// if update, update only mutable fields
@@ -112,9 +119,11 @@ func (v vgManager) EnsureCreated(r Reconciler, ctx context.Context, lvmCluster *
 		ds.Spec.Template.Spec.PriorityClassName = dsTemplate.Spec.Template.Spec.PriorityClassName
 		ds.Spec.Template.Spec.Tolerations = dsTemplate.Spec.Template.Spec.Tolerations
 
+		var nodeSelector *v1.NodeSelector
 		if dsTemplate.Spec.Template.Spec.Affinity != nil {
-			setDaemonsetNodeSelector(dsTemplate.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution, ds)
+			nodeSelector = dsTemplate.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
 		}
+		setDaemonsetNodeSelector(nodeSelector, ds)
 
 		return nil
 	})
@@ -135,13 +144,21 @@ func (v vgManager) EnsureCreated(r Reconciler, ctx context.Context, lvmCluster *
 }
 
 // EnsureDeleted makes sure that the driver is removed from the cluster and the daemonset is gone.
-// Deletion will be triggered again even though we also have an owner reference
+// Deletion will be triggered again even though we also have an owner reference.
+// The daemonset is kept as long as other LVMClusters still use it.
 func (v vgManager) EnsureDeleted(r Reconciler, ctx context.Context, lvmCluster *lvmv1alpha1.LVMCluster) error {
 	logger := log.FromContext(ctx).WithValues("resourceManager", v.GetName())
 
+	if remaining, err := remainingLVMClusters(r, ctx, lvmCluster); err != nil {
+		return fmt.Errorf("failed to check for other LVMClusters: %w", err)
+	} else if len(remaining) > 0 {
+		logger.Info("skipping DaemonSet deletion as it is still used by other LVMClusters")
+		return nil
+	}
+
 	// delete the daemonset
 	ds := templateVGManagerDaemonset(
-		lvmCluster,
+		[]*lvmv1alpha1.LVMCluster{lvmCluster},
 		v.clusterType,
 		r.GetNamespace(),
 		r.GetImageName(),
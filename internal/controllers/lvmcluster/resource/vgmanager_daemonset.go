@@ -223,9 +223,9 @@ var (
 	}
 )
 
-// templateVGManagerDaemonset returns the desired vgmanager daemonset for a given LVMCluster
+// templateVGManagerDaemonset returns the desired vgmanager daemonset for the given LVMClusters
 func templateVGManagerDaemonset(
-	lvmCluster *lvmv1alpha1.LVMCluster,
+	lvmClusters []*lvmv1alpha1.LVMCluster,
 	clusterType cluster.Type,
 	namespace, vgImage string,
 	command, args []string,
@@ -236,7 +236,7 @@ func templateVGManagerDaemonset(
 		confMapVolume.HostPath.Path = filepath.Dir(lvmd.MicroShiftFileConfigPath)
 	}
 
-	nodeSelector, tolerations := selector.ExtractNodeSelectorAndTolerations(lvmCluster)
+	nodeSelector, tolerations := selector.ExtractNodeSelectorAndTolerationsOfAll(lvmClusters)
 	volumes := []corev1.Volume{
 		RegistrationVol,
 		NodePluginVol,
@@ -287,6 +287,9 @@ func templateVGManagerDaemonset(
 				{Name: constants.TopolvmNodeContainerHealthzName,
 					ContainerPort: 8081,
 					Protocol:      corev1.ProtocolTCP},
+				{Name: constants.VGManagerMigrationPortName,
+					ContainerPort: constants.VGManagerMigrationPort,
+					Protocol:      corev1.ProtocolTCP},
 			},
 			StartupProbe: &corev1.Probe{
 				ProbeHandler: corev1.ProbeHandler{
@@ -351,6 +354,14 @@ func templateVGManagerDaemonset(
 						},
 					},
 				},
+				{
+					Name: "POD_IP",
+					ValueFrom: &corev1.EnvVarSource{
+						FieldRef: &corev1.ObjectFieldSelector{
+							FieldPath: "status.podIP",
+						},
+					},
+				},
 			},
 			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 		},
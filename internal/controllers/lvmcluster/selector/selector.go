@@ -3,6 +3,7 @@ package selector
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -32,6 +33,35 @@ func ExtractNodeSelectorAndTolerations(lvmCluster *lvmv1alpha1.LVMCluster) (*cor
 	return nodeSelector, lvmCluster.Spec.Tolerations
 }
 
+// ExtractNodeSelectorAndTolerationsOfAll combines and extracts scheduling parameters from multiple lvmClusters
+// that share the same vg-manager DaemonSet
+func ExtractNodeSelectorAndTolerationsOfAll(lvmClusters []*lvmv1alpha1.LVMCluster) (*corev1.NodeSelector, []corev1.Toleration) {
+	var terms []corev1.NodeSelectorTerm
+	var tolerations []corev1.Toleration
+	allNodes := false
+
+	for _, lvmCluster := range lvmClusters {
+		nodeSelector, clusterTolerations := ExtractNodeSelectorAndTolerations(lvmCluster)
+		if nodeSelector == nil {
+			allNodes = true
+		} else {
+			terms = append(terms, nodeSelector.NodeSelectorTerms...)
+		}
+		for _, toleration := range clusterTolerations {
+			if !slices.ContainsFunc(tolerations, func(existing corev1.Toleration) bool {
+				return existing.MatchToleration(&toleration)
+			}) {
+				tolerations = append(tolerations, toleration)
+			}
+		}
+	}
+
+	if allNodes || len(terms) == 0 {
+		return nil, tolerations
+	}
+	return &corev1.NodeSelector{NodeSelectorTerms: terms}, tolerations
+}
+
 func ValidNodes(lvmCluster *lvmv1alpha1.LVMCluster, nodes *corev1.NodeList) ([]corev1.Node, error) {
 	var validNodes []corev1.Node
 	nodeSelector, tolerations := ExtractNodeSelectorAndTolerations(lvmCluster)
@@ -62,3 +62,38 @@ func TestExtractNodeSelectorAndTolerations(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractNodeSelectorAndTolerationsOfAll(t *testing.T) {
+	selectorFor := func(value string) *corev1.NodeSelector {
+		return &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{
+				Key:      "pool",
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{value},
+			}},
+		}}}
+	}
+	toleration := corev1.Toleration{Key: "storage", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	clusterFor := func(nodeSelector *corev1.NodeSelector, tolerations ...corev1.Toleration) *lvmv1alpha1.LVMCluster {
+		return &lvmv1alpha1.LVMCluster{Spec: lvmv1alpha1.LVMClusterSpec{
+			Tolerations: tolerations,
+			Storage: lvmv1alpha1.Storage{DeviceClasses: []lvmv1alpha1.DeviceClass{{
+				Name:         "vg1",
+				NodeSelector: nodeSelector,
+			}}},
+		}}
+	}
+
+	nodeSelector, tolerations := ExtractNodeSelectorAndTolerationsOfAll([]*lvmv1alpha1.LVMCluster{
+		clusterFor(selectorFor("a"), toleration),
+		clusterFor(selectorFor("b"), toleration),
+	})
+	assert.Len(t, nodeSelector.NodeSelectorTerms, 2, "the node selector terms of both clusters are combined")
+	assert.Equal(t, []corev1.Toleration{toleration}, tolerations, "duplicate tolerations are only added once")
+
+	nodeSelector, _ = ExtractNodeSelectorAndTolerationsOfAll([]*lvmv1alpha1.LVMCluster{
+		clusterFor(selectorFor("a")),
+		clusterFor(nil),
+	})
+	assert.Nil(t, nodeSelector, "a cluster without node selector considers all nodes")
+}
@@ -3,12 +3,15 @@ package lvmcluster
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/lvmcluster/selector"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1helper "k8s.io/component-helpers/scheduling/corev1"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -33,13 +36,52 @@ const (
 	ReasonVGsDegraded  = "VGsDegraded"
 	MessageVGsDegraded = "One or more VGs are degraded"
 
+	ReasonVGsPaused  = "VGsPaused"
+	MessageVGsPaused = "One or more VGs are paused and not reconciled"
+
 	ReasonVGsReady  = "VGsReady"
 	MessageVGsReady = "All the VGs are ready"
 
+	MessageVGsOutdated = "The latest spec of the VGs is not yet applied on nodes: %s"
+
 	ReasonVGsUnmanaged  = "VGsUnmanaged"
 	MessageVGsUnmanaged = "VGs are unmanaged and not part of the LVMCluster, but the manager is running"
+
+	ReasonReconciliationPaused  = "ReconciliationPaused"
+	MessageReconciliationPaused = "Reconciliation is paused, no changes are applied to the resources and volume groups of the LVMCluster"
+
+	ReasonVGsHealthy  = "VGsHealthy"
+	MessageVGsHealthy = "None of the VGs are failed or degraded"
+
+	ReasonNoNodesSelected  = "NoNodesSelected"
+	MessageNoNodesSelected = "No node is selected for the device class"
+
+	MessageDeviceClassVGsReady      = "The VGs are ready on %d of %d nodes"
+	MessageDeviceClassVGsNotHealthy = "The VGs are not healthy on nodes: %s"
+
+	ReasonAllNodes              = "AllNodes"
+	MessageConditionOnAllNodes  = "The condition is True on all %d nodes"
+	MessageConditionOnNodes     = "The condition is %s on nodes: %s"
+	MessageNoVGReportedByNodes  = "No node has reported the VG yet"
+	MessageVGsProgressingOnNode = "The VG is being set up, updated or torn down on nodes: %s"
+	MessageVGsNotProgressing    = "The VG is up to date on all nodes"
+	MessageVGsFailedOnNodes     = "The VG is failed on nodes: %s"
+	MessageVGsNotFailed         = "The VG is not failed on any node"
 )
 
+func setPausedConditionTrue(instance *lvmv1alpha1.LVMCluster) {
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.Paused,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonReconciliationPaused,
+		Message: MessageReconciliationPaused,
+	})
+}
+
+func removePausedCondition(instance *lvmv1alpha1.LVMCluster) {
+	meta.RemoveStatusCondition(&instance.Status.Conditions, lvmv1alpha1.Paused)
+}
+
 func setResourcesAvailableConditionTrue(instance *lvmv1alpha1.LVMCluster) {
 	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
 		Type:    lvmv1alpha1.ResourcesAvailable,
@@ -94,6 +136,15 @@ func setVolumeGroupsReadyConditionDegraded(instance *lvmv1alpha1.LVMCluster) {
 	})
 }
 
+func setVolumeGroupsReadyConditionPaused(instance *lvmv1alpha1.LVMCluster) {
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeGroupsReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonVGsPaused,
+		Message: MessageVGsPaused,
+	})
+}
+
 func setVolumeGroupsReadyConditionInProgress(instance *lvmv1alpha1.LVMCluster) {
 	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
 		Type:    lvmv1alpha1.VolumeGroupsReady,
@@ -103,6 +154,14 @@ func setVolumeGroupsReadyConditionInProgress(instance *lvmv1alpha1.LVMCluster) {
 	})
 }
 
+func setVolumeGroupsReadyConditionOutdated(instance *lvmv1alpha1.LVMCluster, outdated []string) {
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeGroupsReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonVGReadinessInProgress,
+		Message: fmt.Sprintf(MessageVGsOutdated, strings.Join(outdated, ", ")),
+	})
+}
 func setVolumeGroupsReadyConditionUnmanaged(instance *lvmv1alpha1.LVMCluster) {
 	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
 		Type:    lvmv1alpha1.VolumeGroupsReady,
@@ -112,24 +171,30 @@ func setVolumeGroupsReadyConditionUnmanaged(instance *lvmv1alpha1.LVMCluster) {
 	})
 }
 
-func computeDeviceClassStatuses(vgNodeStatusList *lvmv1alpha1.LVMVolumeGroupNodeStatusList) []lvmv1alpha1.DeviceClassStatus {
+// computeDeviceClassStatuses collects the status of the volume groups of the device classes of the LVMCluster
+// on all nodes. Volume groups of other LVMClusters are ignored.
+func computeDeviceClassStatuses(instance *lvmv1alpha1.LVMCluster, vgNodeStatusList *lvmv1alpha1.LVMVolumeGroupNodeStatusList) []lvmv1alpha1.DeviceClassStatus {
 	vgNodeMap := make(map[string][]lvmv1alpha1.NodeStatus)
 	for _, nodeItem := range vgNodeStatusList.Items {
-		for _, item := range nodeItem.Spec.LVMVGStatus {
+		for _, item := range nodeItem.Status.VolumeGroups {
 			vgNodeMap[item.Name] = append(vgNodeMap[item.Name],
 				lvmv1alpha1.NodeStatus{
 					Node:     nodeItem.Name,
-					VGStatus: *item.DeepCopy(),
+					VGStatus: *item.VGStatus.DeepCopy(),
 				},
 			)
 		}
 	}
 	var allVgStatuses []lvmv1alpha1.DeviceClassStatus
-	for key, val := range vgNodeMap {
+	for _, deviceClass := range instance.Spec.Storage.DeviceClasses {
+		nodeStatus, ok := vgNodeMap[instance.VolumeGroupName(deviceClass.Name)]
+		if !ok {
+			continue
+		}
 		allVgStatuses = append(allVgStatuses,
 			lvmv1alpha1.DeviceClassStatus{
-				Name:       key,
-				NodeStatus: val,
+				Name:       deviceClass.Name,
+				NodeStatus: nodeStatus,
 			},
 		)
 	}
@@ -137,6 +202,266 @@ func computeDeviceClassStatuses(vgNodeStatusList *lvmv1alpha1.LVMVolumeGroupNode
 	return allVgStatuses
 }
 
+// aggregateDeviceClassStatuses sets the conditions, the node counts and the capacity of every device class
+// based on the volume group statuses reported by vg-manager. Device classes without any reported volume group
+// are added, so that every device class of the LVMCluster reports its conditions.
+func aggregateDeviceClassStatuses(
+	instance *lvmv1alpha1.LVMCluster,
+	nodes *corev1.NodeList,
+	vgNodeStatusList *lvmv1alpha1.LVMVolumeGroupNodeStatusList,
+	statuses []lvmv1alpha1.DeviceClassStatus,
+) ([]lvmv1alpha1.DeviceClassStatus, error) {
+	var aggregated []lvmv1alpha1.DeviceClassStatus
+	for _, deviceClass := range instance.Spec.Storage.DeviceClasses {
+		status := lvmv1alpha1.DeviceClassStatus{Name: deviceClass.Name}
+		for _, existing := range statuses {
+			if existing.Name == deviceClass.Name {
+				status = existing
+			}
+		}
+		// keep the previous conditions to preserve their last transition time
+		for _, previous := range instance.Status.DeviceClassStatuses {
+			if previous.Name == deviceClass.Name {
+				status.Conditions = previous.Conditions
+			}
+		}
+
+		status.ExpectedNodes = 0
+		for _, node := range nodes.Items {
+			valid, err := isNodeValid(&node, instance, &deviceClass)
+			if err != nil {
+				return nil, err
+			}
+			if valid {
+				status.ExpectedNodes++
+			}
+		}
+
+		status.ReadyNodes, status.FailedNodes = 0, 0
+		status.Capacity = nil
+		paused := false
+		var unhealthy []string
+		for _, nodeStatus := range status.NodeStatus {
+			switch nodeStatus.Status {
+			case lvmv1alpha1.VGStatusReady:
+				status.ReadyNodes++
+			case lvmv1alpha1.VGStatusFailed:
+				status.FailedNodes++
+				unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", nodeStatus.Node, nodeStatus.Status))
+			case lvmv1alpha1.VGStatusDegraded:
+				unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", nodeStatus.Node, nodeStatus.Status))
+			case lvmv1alpha1.VGStatusPaused:
+				paused = true
+			}
+		}
+		for _, nodeItem := range vgNodeStatusList.Items {
+			if vgStatus := nodeItem.VolumeGroupStatus(instance.VolumeGroupName(deviceClass.Name)); vgStatus != nil {
+				addCapacity(&status, vgStatus.Capacity)
+			}
+		}
+
+		setDeviceClassConditions(&status, unhealthy, paused)
+		aggregated = append(aggregated, status)
+	}
+	return aggregated, nil
+}
+
+// setDeviceClassConditions sets the Ready and Degraded conditions of the device class.
+func setDeviceClassConditions(status *lvmv1alpha1.DeviceClassStatus, unhealthy []string, paused bool) {
+	readyMessage := fmt.Sprintf(MessageDeviceClassVGsReady, status.ReadyNodes, status.ExpectedNodes)
+	ready := metav1.Condition{
+		Type:    lvmv1alpha1.DeviceClassReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonVGReadinessInProgress,
+		Message: readyMessage,
+	}
+	switch {
+	case status.ExpectedNodes == 0:
+		ready.Reason, ready.Message = ReasonNoNodesSelected, MessageNoNodesSelected
+	case status.FailedNodes > 0:
+		ready.Reason = ReasonVGsFailed
+	case len(unhealthy) > 0:
+		ready.Reason = ReasonVGsDegraded
+	case paused:
+		ready.Reason = ReasonVGsPaused
+	case status.ReadyNodes >= status.ExpectedNodes:
+		ready.Status, ready.Reason = metav1.ConditionTrue, ReasonVGsReady
+	}
+	meta.SetStatusCondition(&status.Conditions, ready)
+
+	degraded := metav1.Condition{
+		Type:    lvmv1alpha1.DeviceClassDegraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonVGsHealthy,
+		Message: MessageVGsHealthy,
+	}
+	if len(unhealthy) > 0 {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = ReasonVGsDegraded
+		if status.FailedNodes > 0 {
+			degraded.Reason = ReasonVGsFailed
+		}
+		degraded.Message = fmt.Sprintf(MessageDeviceClassVGsNotHealthy, strings.Join(unhealthy, ", "))
+	}
+	meta.SetStatusCondition(&status.Conditions, degraded)
+}
+
+// addCapacity adds the capacity of the volume group on a node to the capacity of the device class.
+func addCapacity(status *lvmv1alpha1.DeviceClassStatus, capacity *lvmv1alpha1.VGCapacity) {
+	if capacity == nil {
+		return
+	}
+	if status.Capacity == nil {
+		status.Capacity = &lvmv1alpha1.VGCapacity{
+			Size: *resource.NewQuantity(0, resource.BinarySI),
+			Free: *resource.NewQuantity(0, resource.BinarySI),
+		}
+	}
+	status.Capacity.Size.Add(capacity.Size)
+	status.Capacity.Free.Add(capacity.Free)
+	if capacity.ThinPoolSize != nil {
+		if status.Capacity.ThinPoolSize == nil {
+			status.Capacity.ThinPoolSize = resource.NewQuantity(0, resource.BinarySI)
+		}
+		status.Capacity.ThinPoolSize.Add(*capacity.ThinPoolSize)
+	}
+	if capacity.ThinPoolUsed != nil {
+		if status.Capacity.ThinPoolUsed == nil {
+			status.Capacity.ThinPoolUsed = resource.NewQuantity(0, resource.BinarySI)
+		}
+		status.Capacity.ThinPoolUsed.Add(*capacity.ThinPoolUsed)
+	}
+}
+
+// setVolumeGroupConditions aggregates the conditions reported by vg-manager for the volume group on every node
+// into the conditions of the LVMVolumeGroup. A condition is only True if it is True on every node that reports it,
+// it is False if it is False on any node and Unknown otherwise. Reconciling and Stalled are derived from the
+// status of the volume group on the nodes so that the LVMVolumeGroup can be assessed by kstatus. A node that did not apply
+// the latest generation of the LVMVolumeGroup yet counts as reconciling.
+func setVolumeGroupConditions(volumeGroup *lvmv1alpha1.LVMVolumeGroup, vgNodeStatusList *lvmv1alpha1.LVMVolumeGroupNodeStatusList) {
+	nodeConditions := make(map[string][]metav1.Condition)
+	var progressing, failed []string
+	reported := 0
+	for _, nodeStatus := range vgNodeStatusList.Items {
+		vgStatus := nodeStatus.VolumeGroupStatus(volumeGroup.GetName())
+		if vgStatus == nil {
+			continue
+		}
+		reported++
+		switch {
+		case vgStatus.Status == lvmv1alpha1.VGStatusFailed:
+			failed = append(failed, nodeStatus.GetName())
+		case vgStatus.Status == lvmv1alpha1.VGStatusProgressing,
+			vgStatus.AppliedGeneration < volumeGroup.GetGeneration():
+			progressing = append(progressing, nodeStatus.GetName())
+		}
+		nodeConditions[nodeStatus.GetName()] = vgStatus.Conditions
+	}
+
+	conditions := &volumeGroup.Status.Conditions
+	for _, conditionType := range []string{
+		lvmv1alpha1.VGConditionReady,
+		lvmv1alpha1.VGConditionDevicesReady,
+		lvmv1alpha1.VGConditionThinPoolHealthy,
+		lvmv1alpha1.VGConditionLVMDConfigured,
+		lvmv1alpha1.VGConditionRAIDHealthy,
+	} {
+		condition, ok := aggregateCondition(conditionType, nodeConditions)
+		if !ok {
+			meta.RemoveStatusCondition(conditions, conditionType)
+			continue
+		}
+		condition.ObservedGeneration = volumeGroup.GetGeneration()
+		meta.SetStatusCondition(conditions, condition)
+	}
+	if reported == 0 {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               lvmv1alpha1.VGConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             ReasonVGReadinessInProgress,
+			Message:            MessageNoVGReportedByNodes,
+			ObservedGeneration: volumeGroup.GetGeneration(),
+		})
+	}
+
+	reconciling := metav1.Condition{
+		Type:               lvmv1alpha1.VGConditionReconciling,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonVGsReady,
+		Message:            MessageVGsNotProgressing,
+		ObservedGeneration: volumeGroup.GetGeneration(),
+	}
+	if reported == 0 {
+		reconciling.Status, reconciling.Reason, reconciling.Message =
+			metav1.ConditionTrue, ReasonVGReadinessInProgress, MessageNoVGReportedByNodes
+	} else if len(progressing) > 0 {
+		reconciling.Status, reconciling.Reason = metav1.ConditionTrue, ReasonVGReadinessInProgress
+		reconciling.Message = fmt.Sprintf(MessageVGsProgressingOnNode, strings.Join(progressing, ", "))
+	}
+	meta.SetStatusCondition(conditions, reconciling)
+
+	stalled := metav1.Condition{
+		Type:               lvmv1alpha1.VGConditionStalled,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonVGsHealthy,
+		Message:            MessageVGsNotFailed,
+		ObservedGeneration: volumeGroup.GetGeneration(),
+	}
+	if len(failed) > 0 {
+		stalled.Status, stalled.Reason = metav1.ConditionTrue, ReasonVGsFailed
+		stalled.Message = fmt.Sprintf(MessageVGsFailedOnNodes, strings.Join(failed, ", "))
+	}
+	meta.SetStatusCondition(conditions, stalled)
+}
+
+// aggregateCondition aggregates the condition of the given type over all nodes.
+// It returns false if no node reports the condition.
+func aggregateCondition(conditionType string, nodeConditions map[string][]metav1.Condition) (metav1.Condition, bool) {
+	var nodes []string
+	for node := range nodeConditions {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+
+	var reporting int
+	notTrue := make(map[metav1.ConditionStatus][]string)
+	reasons := make(map[metav1.ConditionStatus]string)
+	for _, node := range nodes {
+		condition := meta.FindStatusCondition(nodeConditions[node], conditionType)
+		if condition == nil {
+			continue
+		}
+		reporting++
+		if condition.Status == metav1.ConditionTrue {
+			continue
+		}
+		notTrue[condition.Status] = append(notTrue[condition.Status], node)
+		if _, ok := reasons[condition.Status]; !ok {
+			reasons[condition.Status] = condition.Reason
+		}
+	}
+	if reporting == 0 {
+		return metav1.Condition{}, false
+	}
+
+	for _, status := range []metav1.ConditionStatus{metav1.ConditionFalse, metav1.ConditionUnknown} {
+		if len(notTrue[status]) > 0 {
+			return metav1.Condition{
+				Type:    conditionType,
+				Status:  status,
+				Reason:  reasons[status],
+				Message: fmt.Sprintf(MessageConditionOnNodes, status, strings.Join(notTrue[status], ", ")),
+			}, true
+		}
+	}
+	return metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonAllNodes,
+		Message: fmt.Sprintf(MessageConditionOnAllNodes, reporting),
+	}, true
+}
+
 func computeLVMClusterReadiness(conditions []metav1.Condition) (lvmv1alpha1.LVMStateType, bool) {
 	state := lvmv1alpha1.LVMStatusUnknown
 	for _, c := range conditions {
@@ -152,7 +477,7 @@ func translateReasonToState(reason string, currentState lvmv1alpha1.LVMStateType
 	switch reason {
 	case ReasonVGsFailed:
 		return lvmv1alpha1.LVMStatusFailed
-	case ReasonVGsDegraded:
+	case ReasonVGsDegraded, ReasonVGsPaused:
 		if currentState != lvmv1alpha1.LVMStatusFailed {
 			return lvmv1alpha1.LVMStatusDegraded
 		}
@@ -160,6 +485,9 @@ func translateReasonToState(reason string, currentState lvmv1alpha1.LVMStateType
 		if currentState != lvmv1alpha1.LVMStatusFailed && currentState != lvmv1alpha1.LVMStatusDegraded {
 			return lvmv1alpha1.LVMStatusProgressing
 		}
+	case ReasonReconciliationPaused:
+		// pausing does not change the state, it is derived from the last observed conditions
+		return currentState
 	case ReasonResourcesAvailable, ReasonVGsReady, ReasonVGsUnmanaged:
 		transitionToReadyAcceptable := true
 		// if at least one other state was signalling Failed, Degraded or Progressing State,
@@ -184,37 +512,74 @@ func translateReasonToState(reason string, currentState lvmv1alpha1.LVMStateType
 	return currentState
 }
 
-func setVolumeGroupsReadyCondition(ctx context.Context, instance *lvmv1alpha1.LVMCluster, nodes *corev1.NodeList, vgNodeStatusList *lvmv1alpha1.LVMVolumeGroupNodeStatusList) {
+func setVolumeGroupsReadyCondition(ctx context.Context, instance *lvmv1alpha1.LVMCluster, nodes *corev1.NodeList, vgNodeStatusList *lvmv1alpha1.LVMVolumeGroupNodeStatusList, volumeGroups []lvmv1alpha1.LVMVolumeGroup) {
 	logger := log.FromContext(ctx)
 
 	err := validateDeviceClassSetup(instance, nodes, vgNodeStatusList)
 	if err == nil {
+		// the volume groups are only ready once every node applied the latest spec of the LVMVolumeGroups
+		if outdated := outdatedVolumeGroups(volumeGroups, vgNodeStatusList); len(outdated) > 0 {
+			setVolumeGroupsReadyConditionOutdated(instance, outdated)
+			return
+		}
 		setVolumeGroupsReadyConditionTrue(instance)
 		return
 	} else {
 		logger.Error(err, "failed to validate device class setup")
 	}
 
-	degraded := false
+	ownVGs := make(map[string]struct{})
+	for _, deviceClass := range instance.Spec.Storage.DeviceClasses {
+		ownVGs[instance.VolumeGroupName(deviceClass.Name)] = struct{}{}
+	}
+
+	degraded, paused := false, false
 	for _, nodeItem := range vgNodeStatusList.Items {
-		for _, vgStatus := range nodeItem.Spec.LVMVGStatus {
+		for _, vgStatus := range nodeItem.Status.VolumeGroups {
+			// volume groups of other LVMClusters on the same node do not affect this LVMCluster
+			if _, ok := ownVGs[vgStatus.Name]; !ok {
+				continue
+			}
 			switch vgStatus.Status {
 			case lvmv1alpha1.VGStatusFailed:
 				setVolumeGroupsReadyConditionFailed(instance)
 				return
 			case lvmv1alpha1.VGStatusDegraded:
 				degraded = true
+			case lvmv1alpha1.VGStatusPaused:
+				paused = true
 			}
 		}
 	}
 
 	if degraded {
 		setVolumeGroupsReadyConditionDegraded(instance)
+	} else if paused {
+		setVolumeGroupsReadyConditionPaused(instance)
 	}
 }
 
+// outdatedVolumeGroups lists the volume groups whose applied generation on a node is behind the generation
+// of their LVMVolumeGroup, formatted as "<node> (<volume group>)".
+func outdatedVolumeGroups(volumeGroups []lvmv1alpha1.LVMVolumeGroup, vgNodeStatusList *lvmv1alpha1.LVMVolumeGroupNodeStatusList) []string {
+	generations := make(map[string]int64, len(volumeGroups))
+	for _, volumeGroup := range volumeGroups {
+		generations[volumeGroup.GetName()] = volumeGroup.GetGeneration()
+	}
+	var outdated []string
+	for _, nodeItem := range vgNodeStatusList.Items {
+		for _, vgStatus := range nodeItem.Status.VolumeGroups {
+			if generation, ok := generations[vgStatus.Name]; ok && vgStatus.AppliedGeneration < generation {
+				outdated = append(outdated, fmt.Sprintf("%s (%s)", nodeItem.GetName(), vgStatus.Name))
+			}
+		}
+	}
+	return outdated
+}
+
 func validateDeviceClassSetup(cluster *lvmv1alpha1.LVMCluster, nodes *corev1.NodeList, nodeStatusList *lvmv1alpha1.LVMVolumeGroupNodeStatusList) error {
 	for _, deviceClass := range cluster.Spec.Storage.DeviceClasses {
+		vgName := cluster.VolumeGroupName(deviceClass.Name)
 		validNodeExists := false
 		for _, node := range nodes.Items {
 			valid, err := isNodeValid(&node, cluster, &deviceClass)
@@ -244,26 +609,20 @@ func validateDeviceClassSetup(cluster *lvmv1alpha1.LVMCluster, nodes *corev1.Nod
 			}
 
 			// Check if the VGStatus for the device class is present in the NodeStatus
-			var relatedVGStatus *lvmv1alpha1.VGStatus
-			for _, vgStatus := range relatedNodeStatus.Spec.LVMVGStatus {
-				if vgStatus.Name == deviceClass.Name {
-					relatedVGStatus = &vgStatus
-					break
-				}
-			}
+			relatedVGStatus := relatedNodeStatus.VolumeGroupStatus(vgName)
 
 			// If no VGStatus is found, return an error, we assume it should have been
 			// created by the vgmanager
 			if relatedVGStatus == nil {
 				return fmt.Errorf("no VGStatus found for VG %s on node %s,"+
 					"that is part of the expected nodes for device class %s",
-					deviceClass.Name, node.Name, deviceClass.Name)
+					vgName, node.Name, deviceClass.Name)
 			}
 
 			if relatedVGStatus.Status != lvmv1alpha1.VGStatusReady {
 				return fmt.Errorf("VG %s on node %s is not in ready state (%s),"+
 					"that is part of the expected nodes for device class %s",
-					deviceClass.Name, relatedVGStatus.Status, node.Name, deviceClass.Name)
+					vgName, node.Name, relatedVGStatus.Status, deviceClass.Name)
 			}
 		}
 		if !validNodeExists {
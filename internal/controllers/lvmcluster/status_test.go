@@ -2,12 +2,15 @@ package lvmcluster
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
 	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,6 +27,12 @@ var (
 		Reason:  ReasonVGsDegraded,
 		Message: MessageVGsDegraded,
 	}
+	vgPausedCondition = metav1.Condition{
+		Type:    lvmv1alpha1.VolumeGroupsReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonVGsPaused,
+		Message: MessageVGsPaused,
+	}
 	vgFailedCondition = metav1.Condition{
 		Type:    lvmv1alpha1.VolumeGroupsReady,
 		Status:  metav1.ConditionFalse,
@@ -204,6 +213,7 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 		deviceClasses     []lvmv1alpha1.DeviceClass
 		nodes             *corev1.NodeList
 		vgNodeStatusList  *lvmv1alpha1.LVMVolumeGroupNodeStatusList
+		volumeGroups      []lvmv1alpha1.LVMVolumeGroup
 		expectedCondition metav1.Condition
 	}{
 		{
@@ -224,11 +234,13 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node1",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusReady,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
 								},
 							},
 						},
@@ -237,6 +249,87 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 			},
 			expectedCondition: vgReadyCondition,
 		},
+		{
+			desc: "ready vg with an outdated generation should return in progress condition",
+			deviceClasses: []lvmv1alpha1.DeviceClass{
+				{
+					Name: "vg1",
+				},
+			},
+			nodes: &corev1.NodeList{
+				Items: []corev1.Node{
+					{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+				},
+			},
+			vgNodeStatusList: &lvmv1alpha1.LVMVolumeGroupNodeStatusList{
+				Items: []lvmv1alpha1.LVMVolumeGroupNodeStatus{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "node1",
+						},
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
+								{
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
+									AppliedGeneration: 1,
+								},
+							},
+						},
+					},
+				},
+			},
+			volumeGroups: []lvmv1alpha1.LVMVolumeGroup{
+				{ObjectMeta: metav1.ObjectMeta{Name: "vg1", Generation: 2}},
+			},
+			expectedCondition: metav1.Condition{
+				Type:    lvmv1alpha1.VolumeGroupsReady,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonVGReadinessInProgress,
+				Message: fmt.Sprintf(MessageVGsOutdated, "node1 (vg1)"),
+			},
+		},
+		{
+			desc: "failed vg of another LVMCluster should not fail the condition",
+			deviceClasses: []lvmv1alpha1.DeviceClass{
+				{
+					Name: "vg1",
+				},
+			},
+			nodes: &corev1.NodeList{
+				Items: []corev1.Node{
+					{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+				},
+			},
+			vgNodeStatusList: &lvmv1alpha1.LVMVolumeGroupNodeStatusList{
+				Items: []lvmv1alpha1.LVMVolumeGroupNodeStatus{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "node1",
+						},
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
+								{
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusProgressing,
+									},
+								},
+								{
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "other-vg1",
+										Status: lvmv1alpha1.VGStatusFailed,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedCondition: vgProgressingCondition,
+		},
 		{
 			desc: "no node status is found should return in progress condition",
 			deviceClasses: []lvmv1alpha1.DeviceClass{
@@ -270,11 +363,13 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node1",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg2",
-									Status: lvmv1alpha1.VGStatusReady,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg2",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
 								},
 							},
 						},
@@ -301,11 +396,13 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node1",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusProgressing,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusProgressing,
+									},
 								},
 							},
 						},
@@ -332,11 +429,13 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node1",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusDegraded,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusDegraded,
+									},
 								},
 							},
 						},
@@ -345,6 +444,55 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 			},
 			expectedCondition: vgDegradedCondition,
 		},
+		{
+			desc: "paused vg should return paused condition",
+			deviceClasses: []lvmv1alpha1.DeviceClass{
+				{
+					Name: "vg1",
+				},
+			},
+			nodes: &corev1.NodeList{
+				Items: []corev1.Node{
+					{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+				},
+			},
+			vgNodeStatusList: &lvmv1alpha1.LVMVolumeGroupNodeStatusList{
+				Items: []lvmv1alpha1.LVMVolumeGroupNodeStatus{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "node1",
+						},
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
+								{
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusPaused,
+									},
+								},
+							},
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "node2",
+						},
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
+								{
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedCondition: vgPausedCondition,
+		},
 		{
 			desc: "failed vg should return failed condition",
 			deviceClasses: []lvmv1alpha1.DeviceClass{
@@ -363,11 +511,13 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node1",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusFailed,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusFailed,
+									},
 								},
 							},
 						},
@@ -395,11 +545,13 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node1",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusFailed,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusFailed,
+									},
 								},
 							},
 						},
@@ -408,11 +560,13 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node2",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusDegraded,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusDegraded,
+									},
 								},
 							},
 						},
@@ -440,11 +594,13 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node1",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusProgressing,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusProgressing,
+									},
 								},
 							},
 						},
@@ -453,11 +609,13 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node2",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusDegraded,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusDegraded,
+									},
 								},
 							},
 						},
@@ -488,15 +646,19 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node1",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusProgressing,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusProgressing,
+									},
 								},
 								{
-									Name:   "vg2",
-									Status: lvmv1alpha1.VGStatusProgressing,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg2",
+										Status: lvmv1alpha1.VGStatusProgressing,
+									},
 								},
 							},
 						},
@@ -505,15 +667,19 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node2",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusReady,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
 								},
 								{
-									Name:   "vg2",
-									Status: lvmv1alpha1.VGStatusReady,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg2",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
 								},
 							},
 						},
@@ -563,7 +729,7 @@ func TestSetVolumeGroupsReadyCondition(t *testing.T) {
 			}
 
 			setVolumeGroupsReadyConditionInProgress(cluster)
-			setVolumeGroupsReadyCondition(context.TODO(), cluster, testCase.nodes, testCase.vgNodeStatusList)
+			setVolumeGroupsReadyCondition(context.TODO(), cluster, testCase.nodes, testCase.vgNodeStatusList, testCase.volumeGroups)
 			exists := false
 			for _, cond := range cluster.Status.Conditions {
 				if cond.Type == testCase.expectedCondition.Type {
@@ -592,15 +758,19 @@ func TestComputeDeviceClassStatuses(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node1",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusReady,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
 								},
 								{
-									Name:   "vg2",
-									Status: lvmv1alpha1.VGStatusReady,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg2",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
 								},
 							},
 						},
@@ -609,15 +779,19 @@ func TestComputeDeviceClassStatuses(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node2",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusReady,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
 								},
 								{
-									Name:   "vg2",
-									Status: lvmv1alpha1.VGStatusReady,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg2",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
 								},
 							},
 						},
@@ -673,15 +847,19 @@ func TestComputeDeviceClassStatuses(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node1",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusFailed,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusFailed,
+									},
 								},
 								{
-									Name:   "vg2",
-									Status: lvmv1alpha1.VGStatusReady,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg2",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
 								},
 							},
 						},
@@ -690,15 +868,19 @@ func TestComputeDeviceClassStatuses(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node2",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusReady,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
 								},
 								{
-									Name:   "vg2",
-									Status: lvmv1alpha1.VGStatusReady,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg2",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
 								},
 							},
 						},
@@ -754,15 +936,19 @@ func TestComputeDeviceClassStatuses(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node1",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusDegraded,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusDegraded,
+									},
 								},
 								{
-									Name:   "vg2",
-									Status: lvmv1alpha1.VGStatusProgressing,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg2",
+										Status: lvmv1alpha1.VGStatusProgressing,
+									},
 								},
 							},
 						},
@@ -771,15 +957,19 @@ func TestComputeDeviceClassStatuses(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "node2",
 						},
-						Spec: lvmv1alpha1.LVMVolumeGroupNodeStatusSpec{
-							LVMVGStatus: []lvmv1alpha1.VGStatus{
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
 								{
-									Name:   "vg1",
-									Status: lvmv1alpha1.VGStatusReady,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
 								},
 								{
-									Name:   "vg2",
-									Status: lvmv1alpha1.VGStatusFailed,
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg2",
+										Status: lvmv1alpha1.VGStatusFailed,
+									},
 								},
 							},
 						},
@@ -827,16 +1017,264 @@ func TestComputeDeviceClassStatuses(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "vgs of other LVMClusters are ignored",
+			vgNodeStatusList: &lvmv1alpha1.LVMVolumeGroupNodeStatusList{
+				Items: []lvmv1alpha1.LVMVolumeGroupNodeStatus{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "node1",
+						},
+						Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+							VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
+								{
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "vg1",
+										Status: lvmv1alpha1.VGStatusReady,
+									},
+								},
+								{
+									VGStatus: lvmv1alpha1.VGStatus{
+										Name:   "other-vg1",
+										Status: lvmv1alpha1.VGStatusFailed,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedDeviceClassStatuses: []lvmv1alpha1.DeviceClassStatus{
+				{
+					Name: "vg1",
+					NodeStatus: []lvmv1alpha1.NodeStatus{
+						{
+							Node: "node1",
+							VGStatus: lvmv1alpha1.VGStatus{
+								Name:   "vg1",
+								Status: lvmv1alpha1.VGStatusReady,
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, testCase := range testTable {
 		t.Run(testCase.desc, func(t *testing.T) {
-			deviceClassStatuses := computeDeviceClassStatuses(testCase.vgNodeStatusList)
+			cluster := &lvmv1alpha1.LVMCluster{
+				Spec: lvmv1alpha1.LVMClusterSpec{
+					Storage: lvmv1alpha1.Storage{
+						DeviceClasses: []lvmv1alpha1.DeviceClass{{Name: "vg1"}, {Name: "vg2"}},
+					},
+				},
+			}
+			deviceClassStatuses := computeDeviceClassStatuses(cluster, testCase.vgNodeStatusList)
 			assert.ElementsMatch(t, testCase.expectedDeviceClassStatuses, deviceClassStatuses)
 		})
 	}
 }
 
+func TestAggregateDeviceClassStatuses(t *testing.T) {
+	fastSelector := &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+		MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "disk", Operator: corev1.NodeSelectorOpIn, Values: []string{"fast"}}},
+	}}}
+	nodes := &corev1.NodeList{Items: []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"disk": "fast"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{"disk": "fast"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node3"}},
+	}}
+	capacity := func(size, free string) *lvmv1alpha1.VGCapacity {
+		return &lvmv1alpha1.VGCapacity{Size: resource.MustParse(size), Free: resource.MustParse(free)}
+	}
+	vgStatus := func(name string, status lvmv1alpha1.VGStatusType, c *lvmv1alpha1.VGCapacity) lvmv1alpha1.VolumeGroupStatus {
+		return lvmv1alpha1.VolumeGroupStatus{VGStatus: lvmv1alpha1.VGStatus{Name: name, Status: status}, Capacity: c}
+	}
+	nodeStatus := func(node string, vgStatuses ...lvmv1alpha1.VolumeGroupStatus) lvmv1alpha1.LVMVolumeGroupNodeStatus {
+		return lvmv1alpha1.LVMVolumeGroupNodeStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: node},
+			Status:     lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{VolumeGroups: vgStatuses},
+		}
+	}
+
+	cluster := &lvmv1alpha1.LVMCluster{
+		Spec: lvmv1alpha1.LVMClusterSpec{
+			Storage: lvmv1alpha1.Storage{
+				DeviceClasses: []lvmv1alpha1.DeviceClass{
+					{Name: "fast", NodeSelector: fastSelector},
+					{Name: "bulk"},
+					{Name: "new"},
+				},
+			},
+		},
+	}
+	vgNodeStatusList := &lvmv1alpha1.LVMVolumeGroupNodeStatusList{Items: []lvmv1alpha1.LVMVolumeGroupNodeStatus{
+		nodeStatus("node1",
+			vgStatus("fast", lvmv1alpha1.VGStatusFailed, nil),
+			vgStatus("bulk", lvmv1alpha1.VGStatusReady, capacity("10Gi", "4Gi"))),
+		nodeStatus("node2",
+			vgStatus("fast", lvmv1alpha1.VGStatusReady, capacity("10Gi", "4Gi")),
+			vgStatus("bulk", lvmv1alpha1.VGStatusReady, capacity("20Gi", "6Gi"))),
+		nodeStatus("node3",
+			vgStatus("bulk", lvmv1alpha1.VGStatusReady, capacity("30Gi", "10Gi"))),
+	}}
+	statuses := computeDeviceClassStatuses(cluster, vgNodeStatusList)
+
+	aggregated, err := aggregateDeviceClassStatuses(cluster, nodes, vgNodeStatusList, statuses)
+	assert.NoError(t, err)
+	assert.Len(t, aggregated, 3)
+
+	fast := aggregated[0]
+	assert.Equal(t, 2, fast.ExpectedNodes)
+	assert.Equal(t, 1, fast.ReadyNodes)
+	assert.Equal(t, 1, fast.FailedNodes)
+	assert.True(t, meta.IsStatusConditionFalse(fast.Conditions, lvmv1alpha1.DeviceClassReady))
+	assert.True(t, meta.IsStatusConditionTrue(fast.Conditions, lvmv1alpha1.DeviceClassDegraded))
+	assert.Equal(t, ReasonVGsFailed, meta.FindStatusCondition(fast.Conditions, lvmv1alpha1.DeviceClassDegraded).Reason)
+	assert.Contains(t, meta.FindStatusCondition(fast.Conditions, lvmv1alpha1.DeviceClassDegraded).Message, "node1")
+	assert.Zero(t, fast.Capacity.Size.Cmp(resource.MustParse("10Gi")))
+
+	bulk := aggregated[1]
+	assert.Equal(t, 3, bulk.ExpectedNodes)
+	assert.Equal(t, 3, bulk.ReadyNodes)
+	assert.Equal(t, 0, bulk.FailedNodes)
+	assert.True(t, meta.IsStatusConditionTrue(bulk.Conditions, lvmv1alpha1.DeviceClassReady))
+	assert.True(t, meta.IsStatusConditionFalse(bulk.Conditions, lvmv1alpha1.DeviceClassDegraded))
+	assert.Zero(t, bulk.Capacity.Size.Cmp(resource.MustParse("60Gi")))
+	assert.Zero(t, bulk.Capacity.Free.Cmp(resource.MustParse("20Gi")))
+	assert.Nil(t, bulk.Capacity.ThinPoolSize)
+
+	newClass := aggregated[2]
+	assert.Equal(t, "new", newClass.Name)
+	assert.Equal(t, 3, newClass.ExpectedNodes)
+	assert.Nil(t, newClass.Capacity)
+	assert.Equal(t, ReasonVGReadinessInProgress, meta.FindStatusCondition(newClass.Conditions, lvmv1alpha1.DeviceClassReady).Reason)
+}
+
+func TestSetVolumeGroupConditions(t *testing.T) {
+	condition := func(conditionType string, status metav1.ConditionStatus, reason string) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status, Reason: reason}
+	}
+	nodeStatus := func(node string, vgStatus lvmv1alpha1.VGStatusType, conditions ...metav1.Condition) lvmv1alpha1.LVMVolumeGroupNodeStatus {
+		return lvmv1alpha1.LVMVolumeGroupNodeStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: node},
+			Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+				VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{{
+					VGStatus:          lvmv1alpha1.VGStatus{Name: "vg1", Status: vgStatus},
+					AppliedGeneration: 2,
+					Conditions:        conditions,
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		nodeStatuses []lvmv1alpha1.LVMVolumeGroupNodeStatus
+		expected     map[string]metav1.ConditionStatus
+		absent       []string
+		message      string
+	}{
+		{
+			name: "no node reported the volume group",
+			expected: map[string]metav1.ConditionStatus{
+				lvmv1alpha1.VGConditionReady:       metav1.ConditionFalse,
+				lvmv1alpha1.VGConditionReconciling: metav1.ConditionTrue,
+				lvmv1alpha1.VGConditionStalled:     metav1.ConditionFalse,
+			},
+			absent: []string{lvmv1alpha1.VGConditionDevicesReady, lvmv1alpha1.VGConditionThinPoolHealthy},
+		},
+		{
+			name: "ready on all nodes",
+			nodeStatuses: []lvmv1alpha1.LVMVolumeGroupNodeStatus{
+				nodeStatus("node1", lvmv1alpha1.VGStatusReady,
+					condition(lvmv1alpha1.VGConditionReady, metav1.ConditionTrue, "Ready"),
+					condition(lvmv1alpha1.VGConditionDevicesReady, metav1.ConditionTrue, "DevicesAttached")),
+				nodeStatus("node2", lvmv1alpha1.VGStatusReady,
+					condition(lvmv1alpha1.VGConditionReady, metav1.ConditionTrue, "Ready"),
+					condition(lvmv1alpha1.VGConditionDevicesReady, metav1.ConditionTrue, "DevicesAttached")),
+			},
+			expected: map[string]metav1.ConditionStatus{
+				lvmv1alpha1.VGConditionReady:        metav1.ConditionTrue,
+				lvmv1alpha1.VGConditionDevicesReady: metav1.ConditionTrue,
+				lvmv1alpha1.VGConditionReconciling:  metav1.ConditionFalse,
+				lvmv1alpha1.VGConditionStalled:      metav1.ConditionFalse,
+			},
+			absent: []string{lvmv1alpha1.VGConditionThinPoolHealthy, lvmv1alpha1.VGConditionRAIDHealthy},
+		},
+		{
+			name: "failed on one node",
+			nodeStatuses: []lvmv1alpha1.LVMVolumeGroupNodeStatus{
+				nodeStatus("node1", lvmv1alpha1.VGStatusReady,
+					condition(lvmv1alpha1.VGConditionReady, metav1.ConditionTrue, "Ready"),
+					condition(lvmv1alpha1.VGConditionThinPoolHealthy, metav1.ConditionTrue, "ThinPoolHealthy")),
+				nodeStatus("node2", lvmv1alpha1.VGStatusFailed,
+					condition(lvmv1alpha1.VGConditionReady, metav1.ConditionFalse, "Failed"),
+					condition(lvmv1alpha1.VGConditionThinPoolHealthy, metav1.ConditionFalse, "Failed")),
+			},
+			expected: map[string]metav1.ConditionStatus{
+				lvmv1alpha1.VGConditionReady:           metav1.ConditionFalse,
+				lvmv1alpha1.VGConditionThinPoolHealthy: metav1.ConditionFalse,
+				lvmv1alpha1.VGConditionReconciling:     metav1.ConditionFalse,
+				lvmv1alpha1.VGConditionStalled:         metav1.ConditionTrue,
+			},
+			message: "node2",
+		},
+		{
+			name: "previous generation applied on one node",
+			nodeStatuses: []lvmv1alpha1.LVMVolumeGroupNodeStatus{
+				nodeStatus("node1", lvmv1alpha1.VGStatusReady,
+					condition(lvmv1alpha1.VGConditionReady, metav1.ConditionTrue, "Ready")),
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "node2"},
+					Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+						VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{{VGStatus: lvmv1alpha1.VGStatus{Name: "vg1", Status: lvmv1alpha1.VGStatusReady}, AppliedGeneration: 1}},
+					},
+				},
+			},
+			expected: map[string]metav1.ConditionStatus{
+				lvmv1alpha1.VGConditionReady:       metav1.ConditionTrue,
+				lvmv1alpha1.VGConditionReconciling: metav1.ConditionTrue,
+				lvmv1alpha1.VGConditionStalled:     metav1.ConditionFalse,
+			},
+		},
+		{
+			name: "progressing on one node",
+			nodeStatuses: []lvmv1alpha1.LVMVolumeGroupNodeStatus{
+				nodeStatus("node1", lvmv1alpha1.VGStatusProgressing,
+					condition(lvmv1alpha1.VGConditionReady, metav1.ConditionFalse, "Progressing"),
+					condition(lvmv1alpha1.VGConditionDevicesReady, metav1.ConditionFalse, "DevicesPending")),
+			},
+			expected: map[string]metav1.ConditionStatus{
+				lvmv1alpha1.VGConditionReady:        metav1.ConditionFalse,
+				lvmv1alpha1.VGConditionDevicesReady: metav1.ConditionFalse,
+				lvmv1alpha1.VGConditionReconciling:  metav1.ConditionTrue,
+				lvmv1alpha1.VGConditionStalled:      metav1.ConditionFalse,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			volumeGroup := &lvmv1alpha1.LVMVolumeGroup{ObjectMeta: metav1.ObjectMeta{Name: "vg1", Generation: 2}}
+			setVolumeGroupConditions(volumeGroup, &lvmv1alpha1.LVMVolumeGroupNodeStatusList{Items: tt.nodeStatuses})
+			for conditionType, status := range tt.expected {
+				c := meta.FindStatusCondition(volumeGroup.Status.Conditions, conditionType)
+				if assert.NotNil(t, c, conditionType) {
+					assert.Equal(t, status, c.Status, conditionType)
+					assert.Equal(t, int64(2), c.ObservedGeneration, conditionType)
+				}
+			}
+			for _, conditionType := range tt.absent {
+				assert.Nil(t, meta.FindStatusCondition(volumeGroup.Status.Conditions, conditionType), conditionType)
+			}
+			if tt.message != "" {
+				assert.Contains(t, meta.FindStatusCondition(volumeGroup.Status.Conditions, lvmv1alpha1.VGConditionReady).Message, tt.message)
+			}
+		})
+	}
+}
+
 func TestComputeReadiness(t *testing.T) {
 	testTable := []struct {
 		desc          string
@@ -918,6 +1356,50 @@ func TestComputeReadiness(t *testing.T) {
 			expectedState: lvmv1alpha1.LVMStatusDegraded,
 			expectedReady: false,
 		},
+		{
+			desc: "paused with ready conditions",
+			conditions: []metav1.Condition{
+				{
+					Type:    lvmv1alpha1.ResourcesAvailable,
+					Status:  metav1.ConditionTrue,
+					Reason:  ReasonResourcesAvailable,
+					Message: MessageResourcesAvailable,
+				},
+				{
+					Type:    lvmv1alpha1.VolumeGroupsReady,
+					Status:  metav1.ConditionTrue,
+					Reason:  ReasonVGsReady,
+					Message: MessageVGsReady,
+				},
+				{
+					Type:    lvmv1alpha1.Paused,
+					Status:  metav1.ConditionTrue,
+					Reason:  ReasonReconciliationPaused,
+					Message: MessageReconciliationPaused,
+				},
+			},
+			expectedState: lvmv1alpha1.LVMStatusReady,
+			expectedReady: true,
+		},
+		{
+			desc: "one ready, one paused",
+			conditions: []metav1.Condition{
+				{
+					Type:    lvmv1alpha1.ResourcesAvailable,
+					Status:  metav1.ConditionTrue,
+					Reason:  ReasonResourcesAvailable,
+					Message: MessageResourcesAvailable,
+				},
+				{
+					Type:    lvmv1alpha1.VolumeGroupsReady,
+					Status:  metav1.ConditionFalse,
+					Reason:  ReasonVGsPaused,
+					Message: MessageVGsPaused,
+				},
+			},
+			expectedState: lvmv1alpha1.LVMStatusDegraded,
+			expectedReady: false,
+		},
 		{
 			desc: "both failing",
 			conditions: []metav1.Condition{
@@ -122,14 +122,17 @@ func removeDeleteProtectionFinalizer(status *lvmv1alpha1.LVMVolumeGroupNodeStatu
 	return false
 }
 
-// cleanupVolumeGroupsForNode removes the node cleanup finalizer and wipe annotation
+// cleanupVolumeGroupsForNode removes the node cleanup finalizer and wipe annotations
 // for the given node from all LVMVolumeGroup objects.
 // This prevents stale finalizers from blocking VolumeGroup deletion
 // after a node is removed from the cluster.
 func (r *Reconciler) cleanupVolumeGroupsForNode(ctx context.Context, nodeName string) error {
 	logger := log.FromContext(ctx)
 	finalizer := fmt.Sprintf("%s/%s", vgmanager.NodeCleanupFinalizer, nodeName)
-	annotationKey := constants.DevicesWipedAnnotationPrefix + nodeName
+	annotationKeys := []string{
+		constants.DevicesWipedAnnotationPrefix + nodeName,
+		constants.WipedDevicePathsAnnotationPrefix + nodeName,
+	}
 
 	volumeGroups := &lvmv1alpha1.LVMVolumeGroupList{}
 	if err := r.List(ctx, volumeGroups, client.InNamespace(r.Namespace)); err != nil {
@@ -139,9 +142,11 @@ func (r *Reconciler) cleanupVolumeGroupsForNode(ctx context.Context, nodeName st
 	for _, vg := range volumeGroups.Items {
 		needsUpdate := controllerutil.RemoveFinalizer(&vg, finalizer)
 
-		if _, exists := vg.Annotations[annotationKey]; exists {
-			delete(vg.Annotations, annotationKey)
-			needsUpdate = true
+		for _, annotationKey := range annotationKeys {
+			if _, exists := vg.Annotations[annotationKey]; exists {
+				delete(vg.Annotations, annotationKey)
+				needsUpdate = true
+			}
 		}
 
 		if !needsUpdate {
@@ -195,6 +195,7 @@ func TestNodeRemovalController_CleansUpVolumeGroupsOnNodeDeletion(t *testing.T)
 	otherNodeFinalizer := vgmanager.NodeCleanupFinalizer + "/" + otherNodeName
 	nodeWipeAnnotation := "wiped.devices.lvms.openshift.io/" + nodeName
 	otherNodeWipeAnnotation := "wiped.devices.lvms.openshift.io/" + otherNodeName
+	nodeWipedPathsAnnotation := "wiped-paths.devices.lvms.openshift.io/" + nodeName
 
 	req := controllerruntime.Request{NamespacedName: types.NamespacedName{
 		Name:      nodeName,
@@ -212,8 +213,9 @@ func TestNodeRemovalController_CleansUpVolumeGroupsOnNodeDeletion(t *testing.T)
 				Namespace:  namespace,
 				Finalizers: []string{nodeFinalizer, otherNodeFinalizer},
 				Annotations: map[string]string{
-					nodeWipeAnnotation:      "true",
-					otherNodeWipeAnnotation: "true",
+					nodeWipeAnnotation:       "true",
+					otherNodeWipeAnnotation:  "true",
+					nodeWipedPathsAnnotation: `["/dev/sda"]`,
 				},
 			},
 		},
@@ -251,6 +253,7 @@ func TestNodeRemovalController_CleansUpVolumeGroupsOnNodeDeletion(t *testing.T)
 	assert.NotContains(t, vgBoth.Finalizers, nodeFinalizer)
 	assert.Contains(t, vgBoth.Finalizers, otherNodeFinalizer)
 	assert.NotContains(t, vgBoth.Annotations, nodeWipeAnnotation)
+	assert.NotContains(t, vgBoth.Annotations, nodeWipedPathsAnnotation)
 	assert.Contains(t, vgBoth.Annotations, otherNodeWipeAnnotation)
 
 	// VG unrelated to the deleted node: should be untouched
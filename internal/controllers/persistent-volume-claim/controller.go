@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 	"math"
-	"sort"
-	"strings"
 	"time"
 
 	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
@@ -13,7 +11,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -43,8 +40,12 @@ func NewReconciler(client client.Client, eventRecorder events.EventRecorder) *Re
 }
 
 //+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims/status,verbs=patch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=node,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=events,verbs=create;update;patch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumegroupnodestatuses,verbs=get;list;watch
 
 // Reconcile PVC
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -88,49 +89,49 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	// Skip if the PVC is not in Pending state.
 	if pvc.Status.Phase != corev1.ClaimPending {
+		if err := r.updateCondition(ctx, pvc, nil); err != nil {
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{}, nil
 	}
 
-	// List the nodes
-	nodeList := &corev1.NodeList{}
-	err = r.Client.List(ctx, nodeList)
+	// Check why the PVC cannot be provisioned on any node
+	d, err := r.diagnose(ctx, pvc, &sc, deviceClass)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Check if there is any node that has enough storage capacity for the PVC
-	found := false
-	requestedStorage := pvc.Spec.Resources.Requests.Storage()
-	var nodeMessage []string
-	for _, node := range nodeList.Items {
-		capacity, ok := node.Annotations[CapacityAnnotation+deviceClass]
-		if !ok {
-			continue
-		}
+	if err := r.updateCondition(ctx, pvc, d); err != nil {
+		return ctrl.Result{}, err
+	}
 
-		capacityQuantity, err := resource.ParseQuantity(capacity)
-		if err != nil {
-			logger.Error(fmt.Errorf("failed to parse capacity for node %s: %w", node.Name, err), "failed to parse capacity")
-			return ctrl.Result{}, nil
-		}
-		if requestedStorage.Cmp(capacityQuantity) < 0 {
-			found = true
-			break
-		}
-		nodeMessage = append(nodeMessage, fmt.Sprintf("node %s has %s free storage", node.Name, prettyByteSize(capacityQuantity.Value())))
+	// Publish an event if no node can provision the PVC
+	if d.Status == corev1.ConditionFalse {
+		r.Recorder.Eventf(pvc, nil, "Warning", d.Reason, "CheckCapacity", d.Message)
+		logger.V(7).Info(d.Message)
 	}
 
-	// sort the message alphabetically so we always publish the same event
-	sort.Strings(nodeMessage)
+	return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+}
+
+// updateCondition sets the StorageAvailable condition of the PVC to the diagnosis, or removes it if the diagnosis is nil.
+func (r *Reconciler) updateCondition(ctx context.Context, pvc *corev1.PersistentVolumeClaim, d *diagnosis) error {
+	original := pvc.DeepCopy()
 
-	// Publish an event if the requested storage is greater than the available capacity
-	if !found {
-		msg := fmt.Sprintf("Requested storage (%s) is greater than available capacity on any node (%s).", requestedStorage.String(), strings.Join(nodeMessage, ","))
-		r.Recorder.Eventf(pvc, nil, "Warning", "NotEnoughCapacity", "CheckCapacity", msg)
-		logger.V(7).Info(msg)
+	var changed bool
+	if d == nil {
+		changed = removeStorageAvailableCondition(pvc)
+	} else {
+		changed = setStorageAvailableCondition(pvc, d)
+	}
+	if !changed {
+		return nil
 	}
 
-	return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+	if err := r.Client.Status().Patch(ctx, pvc, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("failed to update the %s condition of the pvc: %w", StorageAvailableCondition, err)
+	}
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -7,9 +7,11 @@ import (
 	"testing"
 	"time"
 
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
 	persistentvolumeclaim "github.com/openshift/lvm-operator/v4/internal/controllers/persistent-volume-claim"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
@@ -18,7 +20,9 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	controllerruntime "sigs.k8s.io/controller-runtime"
@@ -26,6 +30,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, lvmv1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
 func TestPersistentVolumeClaimReconciler_SetupWithManager(t *testing.T) {
 	mgr, err := controllerruntime.NewManager(&rest.Config{}, controllerruntime.Options{})
 	assert.NoError(t, err)
@@ -305,7 +316,8 @@ func TestPersistentVolumeClaimReconciler_Reconcile(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			recorder := events.NewFakeRecorder(1)
 			r := persistentvolumeclaim.NewReconciler(
-				fake.NewClientBuilder().WithObjects(tt.objs...).
+				fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(tt.objs...).
+					WithStatusSubresource(&v1.PersistentVolumeClaim{}).
 					WithInterceptorFuncs(interceptor.Funcs{
 						Get: func(ctx context.Context, client client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
 							if tt.clientGetErr != nil {
@@ -353,3 +365,203 @@ func TestPersistentVolumeClaimReconciler_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestPersistentVolumeClaimReconciler_Diagnosis(t *testing.T) {
+	defaultNamespace := "openshift-lvm-storage"
+	deviceClass := "vg1"
+	request := resource.MustParse("10Gi")
+
+	newPVC := func(annotations map[string]string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "test-pvc", Annotations: annotations},
+			Spec: v1.PersistentVolumeClaimSpec{
+				StorageClassName: ptr.To(constants.StorageClassPrefix + deviceClass),
+				Resources:        v1.VolumeResourceRequirements{Requests: v1.ResourceList{v1.ResourceStorage: request}},
+			},
+			Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+		}
+	}
+	newStorageClass := func(bindingMode storagev1.VolumeBindingMode) *storagev1.StorageClass {
+		return &storagev1.StorageClass{
+			ObjectMeta:        metav1.ObjectMeta{Name: constants.StorageClassPrefix + deviceClass},
+			Provisioner:       constants.TopolvmCSIDriverName,
+			Parameters:        map[string]string{constants.DeviceClassKey: deviceClass},
+			VolumeBindingMode: ptr.To(bindingMode),
+		}
+	}
+	newNode := func(name, capacity string, labels map[string]string, taints ...v1.Taint) *v1.Node {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: map[string]string{}},
+			Spec:       v1.NodeSpec{Taints: taints},
+		}
+		if capacity != "" {
+			node.Annotations[persistentvolumeclaim.CapacityAnnotation+deviceClass] = capacity
+		}
+		return node
+	}
+	newCluster := func(dc lvmv1alpha1.DeviceClass) *lvmv1alpha1.LVMCluster {
+		dc.Name = deviceClass
+		return &lvmv1alpha1.LVMCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "lvmcluster"},
+			Spec:       lvmv1alpha1.LVMClusterSpec{Storage: lvmv1alpha1.Storage{DeviceClasses: []lvmv1alpha1.DeviceClass{dc}}},
+		}
+	}
+	newConsumer := func(nodeSelector map[string]string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "consumer"},
+			Spec: v1.PodSpec{
+				NodeSelector: nodeSelector,
+				Volumes: []v1.Volume{{Name: "data", VolumeSource: v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "test-pvc"},
+				}}},
+			},
+		}
+	}
+	storageNodes := &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{MatchExpressions: []v1.NodeSelectorRequirement{{
+		Key: "storage", Operator: v1.NodeSelectorOpIn, Values: []string{"true"},
+	}}}}}
+
+	tests := []struct {
+		name           string
+		objs           []client.Object
+		expectStatus   v1.ConditionStatus
+		expectReason   string
+		expectMessages []string
+	}{
+		{
+			name: "capacity available",
+			objs: []client.Object{
+				newPVC(nil), newStorageClass(storagev1.VolumeBindingImmediate),
+				newCluster(lvmv1alpha1.DeviceClass{}),
+				newNode("node-a", "20Gi", nil),
+			},
+			expectStatus:   v1.ConditionTrue,
+			expectReason:   persistentvolumeclaim.ReasonCapacityAvailable,
+			expectMessages: []string{"node-a"},
+		},
+		{
+			name: "thin pool overprovisioned",
+			objs: []client.Object{
+				newPVC(nil), newStorageClass(storagev1.VolumeBindingImmediate),
+				newCluster(lvmv1alpha1.DeviceClass{ThinPoolConfig: &lvmv1alpha1.ThinPoolConfig{Name: "thin-pool-1", OverprovisionRatio: 10}}),
+				newNode("node-a", "5Gi", nil),
+			},
+			expectStatus:   v1.ConditionFalse,
+			expectReason:   persistentvolumeclaim.ReasonThinPoolOverprovisioned,
+			expectMessages: []string{"thin pool thin-pool-1 with overprovisionRatio 10"},
+		},
+		{
+			name: "raid overhead exceeds capacity",
+			objs: []client.Object{
+				newPVC(nil), newStorageClass(storagev1.VolumeBindingImmediate),
+				newCluster(lvmv1alpha1.DeviceClass{RAIDConfig: &lvmv1alpha1.RAIDConfig{Type: lvmv1alpha1.RAIDTypeRAID1}}),
+				newNode("node-a", "15Gi", nil),
+			},
+			expectStatus:   v1.ConditionFalse,
+			expectReason:   persistentvolumeclaim.ReasonNotEnoughCapacity,
+			expectMessages: []string{"20.0GiB is required with raid1 overhead"},
+		},
+		{
+			name: "node selector and taints exclude all nodes",
+			objs: []client.Object{
+				newPVC(nil), newStorageClass(storagev1.VolumeBindingImmediate),
+				newCluster(lvmv1alpha1.DeviceClass{NodeSelector: storageNodes}),
+				newNode("node-a", "", nil),
+				newNode("node-b", "", map[string]string{"storage": "true"}, v1.Taint{Key: "dedicated", Value: "db", Effect: v1.TaintEffectNoSchedule}),
+			},
+			expectStatus: v1.ConditionFalse,
+			expectReason: persistentvolumeclaim.ReasonTaintNotTolerated,
+			expectMessages: []string{
+				"1 node(s) do not match the nodeSelector of the device class (node-a)",
+				"1 node(s) have a taint that is not tolerated by the LVMCluster (node-b: dedicated=db:NoSchedule)",
+			},
+		},
+		{
+			name: "capacity not reported",
+			objs: []client.Object{
+				newPVC(nil), newStorageClass(storagev1.VolumeBindingImmediate),
+				newCluster(lvmv1alpha1.DeviceClass{}),
+				newNode("node-a", "", nil),
+			},
+			expectStatus: v1.ConditionFalse,
+			expectReason: persistentvolumeclaim.ReasonCapacityNotReported,
+		},
+		{
+			name: "waiting for first consumer",
+			objs: []client.Object{
+				newPVC(nil), newStorageClass(storagev1.VolumeBindingWaitForFirstConsumer),
+				newCluster(lvmv1alpha1.DeviceClass{}),
+				newNode("node-a", "20Gi", nil),
+			},
+			expectStatus: v1.ConditionUnknown,
+			expectReason: persistentvolumeclaim.ReasonWaitForFirstConsumer,
+		},
+		{
+			name: "consumer node affinity excludes the nodes with capacity",
+			objs: []client.Object{
+				newPVC(nil), newStorageClass(storagev1.VolumeBindingWaitForFirstConsumer),
+				newCluster(lvmv1alpha1.DeviceClass{}),
+				newNode("node-a", "20Gi", nil),
+				newNode("node-b", "5Gi", map[string]string{"zone": "b"}),
+				newConsumer(map[string]string{"zone": "b"}),
+			},
+			expectStatus: v1.ConditionFalse,
+			expectReason: persistentvolumeclaim.ReasonNotEnoughCapacity,
+			expectMessages: []string{
+				"1 node(s) do not match the node affinity or tolerations of the pods using the PVC (node-a)",
+				"node node-b has 5.0GiB free storage",
+			},
+		},
+		{
+			name: "selected node has no capacity",
+			objs: []client.Object{
+				newPVC(map[string]string{"volume.kubernetes.io/selected-node": "node-b"}), newStorageClass(storagev1.VolumeBindingWaitForFirstConsumer),
+				newCluster(lvmv1alpha1.DeviceClass{NodeSelector: storageNodes}),
+				newNode("node-a", "20Gi", map[string]string{"storage": "true"}),
+				newNode("node-b", "", nil),
+			},
+			expectStatus:   v1.ConditionFalse,
+			expectReason:   persistentvolumeclaim.ReasonConsumerNodeMismatch,
+			expectMessages: []string{"are not the node node-b selected for the PVC (node-a)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			recorder := events.NewFakeRecorder(1)
+			fakeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(tt.objs...).
+				WithStatusSubresource(&v1.PersistentVolumeClaim{}).Build()
+			r := persistentvolumeclaim.NewReconciler(fakeClient, recorder)
+
+			req := controllerruntime.Request{NamespacedName: types.NamespacedName{Namespace: defaultNamespace, Name: "test-pvc"}}
+			_, err := r.Reconcile(ctx, req)
+			require.NoError(t, err)
+
+			pvc := &v1.PersistentVolumeClaim{}
+			require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, pvc))
+			require.Len(t, pvc.Status.Conditions, 1)
+			condition := pvc.Status.Conditions[0]
+			assert.Equal(t, persistentvolumeclaim.StorageAvailableCondition, condition.Type)
+			assert.Equal(t, tt.expectStatus, condition.Status)
+			assert.Equal(t, tt.expectReason, condition.Reason)
+			for _, msg := range tt.expectMessages {
+				assert.Contains(t, condition.Message, msg)
+			}
+
+			if tt.expectStatus == v1.ConditionFalse {
+				require.Len(t, recorder.Events, 1)
+				assert.Contains(t, <-recorder.Events, tt.expectReason)
+			} else {
+				assert.Empty(t, recorder.Events)
+			}
+
+			// The condition is removed once the PVC is no longer pending.
+			pvc.Status.Phase = v1.ClaimBound
+			require.NoError(t, fakeClient.Status().Update(ctx, pvc))
+			_, err = r.Reconcile(ctx, req)
+			require.NoError(t, err)
+			require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, pvc))
+			assert.Empty(t, pvc.Status.Conditions)
+		})
+	}
+}
@@ -0,0 +1,426 @@
+package persistent_volume_claim
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/topolvm/topolvm"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1helper "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	volumehelpers "k8s.io/component-helpers/storage/volume"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// StorageAvailableCondition is the PVC condition that explains whether a pending PVC can be provisioned by LVMS.
+const StorageAvailableCondition corev1.PersistentVolumeClaimConditionType = "StorageAvailable"
+
+const (
+	ReasonCapacityAvailable       = "CapacityAvailable"
+	ReasonWaitForFirstConsumer    = "WaitForFirstConsumer"
+	ReasonNodeSelectorMismatch    = "NodeSelectorMismatch"
+	ReasonTaintNotTolerated       = "TaintNotTolerated"
+	ReasonConsumerNodeMismatch    = "ConsumerNodeMismatch"
+	ReasonCapacityNotReported     = "CapacityNotReported"
+	ReasonNotEnoughCapacity       = "NotEnoughCapacity"
+	ReasonThinPoolOverprovisioned = "ThinPoolOverprovisioned"
+)
+
+// reasonOrder ranks the node reasons by how far a node got in the evaluation.
+// If no node can provision the PVC, the reason of the node that got the furthest is reported for the PVC.
+var reasonOrder = map[string]int{
+	ReasonNodeSelectorMismatch:    0,
+	ReasonTaintNotTolerated:       1,
+	ReasonConsumerNodeMismatch:    2,
+	ReasonCapacityNotReported:     3,
+	ReasonNotEnoughCapacity:       4,
+	ReasonThinPoolOverprovisioned: 4,
+}
+
+// maxNodeDetails limits the number of nodes listed per reason in the condition message.
+const maxNodeDetails = 5
+
+// diagnosis is the result of checking why a pending PVC is not provisioned.
+type diagnosis struct {
+	Status  corev1.ConditionStatus
+	Reason  string
+	Message string
+}
+
+// nodeResult is the outcome of checking a single node for a pending PVC.
+type nodeResult struct {
+	Node   string
+	Reason string
+	Detail string
+}
+
+// consumer describes the constraints of the pods waiting for a PVC with WaitForFirstConsumer binding.
+type consumer struct {
+	// SelectedNode is the node chosen by the scheduler for the PVC.
+	SelectedNode string
+	// Pods are the unscheduled pods that use the PVC.
+	Pods []corev1.Pod
+	// Waiting is true if no pod uses the PVC yet.
+	Waiting bool
+}
+
+// diagnose checks all nodes for the reasons a PVC cannot be provisioned in the given device class.
+func (r *Reconciler) diagnose(ctx context.Context, pvc *corev1.PersistentVolumeClaim, sc *v1.StorageClass, deviceClassName string) (*diagnosis, error) {
+	logger := log.FromContext(ctx)
+
+	cluster, deviceClass, err := r.getDeviceClass(ctx, deviceClassName)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer, err := r.getConsumer(ctx, pvc, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := r.Client.List(ctx, nodeList); err != nil {
+		return nil, err
+	}
+
+	deviceCounts, err := r.getDeviceCounts(ctx, deviceClass)
+	if err != nil {
+		return nil, err
+	}
+
+	requestedStorage := pvc.Spec.Resources.Requests.Storage()
+
+	var results []nodeResult
+	var fits []string
+	for _, node := range nodeList.Items {
+		capacity, hasCapacity := node.Annotations[CapacityAnnotation+deviceClassName]
+
+		// Without the device class configuration, only the nodes reporting capacity can be checked.
+		if deviceClass == nil && !hasCapacity {
+			continue
+		}
+
+		if deviceClass != nil {
+			if result, ok := checkDeviceClassScheduling(&node, cluster, deviceClass); !ok {
+				results = append(results, result)
+				continue
+			}
+		}
+
+		if result, ok := checkConsumer(&node, consumer); !ok {
+			results = append(results, result)
+			continue
+		}
+
+		if !hasCapacity {
+			results = append(results, nodeResult{Node: node.Name, Reason: ReasonCapacityNotReported})
+			continue
+		}
+
+		capacityQuantity, err := resource.ParseQuantity(capacity)
+		if err != nil {
+			logger.Error(fmt.Errorf("failed to parse capacity for node %s: %w", node.Name, err), "failed to parse capacity")
+			continue
+		}
+
+		if result, ok := checkCapacity(&node, deviceClass, deviceCounts[node.Name], requestedStorage, capacityQuantity); !ok {
+			results = append(results, result)
+			continue
+		}
+
+		fits = append(fits, node.Name)
+	}
+	sort.Strings(fits)
+
+	if len(fits) > 0 {
+		nodes := strings.Join(limit(fits), ", ")
+		if consumer.Waiting {
+			return &diagnosis{
+				Status: corev1.ConditionUnknown,
+				Reason: ReasonWaitForFirstConsumer,
+				Message: fmt.Sprintf("Waiting for a pod using the PVC to be scheduled, %d node(s) can provision %s in device class %s: %s.",
+					len(fits), requestedStorage.String(), deviceClassName, nodes),
+			}, nil
+		}
+		return &diagnosis{
+			Status: corev1.ConditionTrue,
+			Reason: ReasonCapacityAvailable,
+			Message: fmt.Sprintf("%d node(s) can provision %s in device class %s: %s.",
+				len(fits), requestedStorage.String(), deviceClassName, nodes),
+		}, nil
+	}
+
+	return &diagnosis{
+		Status:  corev1.ConditionFalse,
+		Reason:  blockingReason(results),
+		Message: summarize(results, requestedStorage, deviceClassName, len(nodeList.Items), consumer),
+	}, nil
+}
+
+// getDeviceClass returns the LVMCluster and its device class whose volume group has the given name.
+// Nil is returned if no LVMCluster contains the device class.
+func (r *Reconciler) getDeviceClass(ctx context.Context, name string) (*lvmv1alpha1.LVMCluster, *lvmv1alpha1.DeviceClass, error) {
+	clusters := &lvmv1alpha1.LVMClusterList{}
+	if err := r.Client.List(ctx, clusters); err != nil {
+		return nil, nil, fmt.Errorf("failed to list LVMClusters: %w", err)
+	}
+
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		deviceClasses := cluster.Spec.Storage.DeviceClasses
+		for j := range deviceClasses {
+			deviceClass := &deviceClasses[j]
+			if cluster.VolumeGroupName(deviceClass.Name) == name {
+				return cluster, deviceClass, nil
+			}
+			if name == topolvm.DefaultDeviceClassAnnotationName && cluster.IsDefaultDeviceClass(deviceClass) {
+				return cluster, deviceClass, nil
+			}
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// getConsumer returns the constraints of the pods that will consume the PVC.
+// Only PVCs with WaitForFirstConsumer binding are provisioned on the node of their consumer.
+func (r *Reconciler) getConsumer(ctx context.Context, pvc *corev1.PersistentVolumeClaim, sc *v1.StorageClass) (*consumer, error) {
+	if selectedNode, ok := pvc.Annotations[volumehelpers.AnnSelectedNode]; ok && selectedNode != "" {
+		return &consumer{SelectedNode: selectedNode}, nil
+	}
+
+	if sc.VolumeBindingMode == nil || *sc.VolumeBindingMode != v1.VolumeBindingWaitForFirstConsumer {
+		return &consumer{}, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods, client.InNamespace(pvc.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	c := &consumer{}
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Spec.NodeName != "" {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == pvc.Name {
+				c.Pods = append(c.Pods, pod)
+				break
+			}
+		}
+	}
+	c.Waiting = len(c.Pods) == 0
+
+	return c, nil
+}
+
+// getDeviceCounts returns the number of devices in the volume group of a RAID device class by node.
+func (r *Reconciler) getDeviceCounts(ctx context.Context, deviceClass *lvmv1alpha1.DeviceClass) (map[string]int, error) {
+	deviceCounts := make(map[string]int)
+	if deviceClass == nil || deviceClass.RAIDConfig == nil {
+		return deviceCounts, nil
+	}
+
+	nodeStatuses := &lvmv1alpha1.LVMVolumeGroupNodeStatusList{}
+	if err := r.Client.List(ctx, nodeStatuses); err != nil {
+		return nil, fmt.Errorf("failed to list LVMVolumeGroupNodeStatuses: %w", err)
+	}
+
+	for _, nodeStatus := range nodeStatuses.Items {
+		if vgStatus := nodeStatus.VolumeGroupStatus(deviceClass.Name); vgStatus != nil {
+			deviceCounts[nodeStatus.Name] = len(vgStatus.Devices)
+		}
+	}
+
+	return deviceCounts, nil
+}
+
+// checkDeviceClassScheduling checks if vg-manager and TopoLVM can run on the node for the device class.
+func checkDeviceClassScheduling(node *corev1.Node, cluster *lvmv1alpha1.LVMCluster, deviceClass *lvmv1alpha1.DeviceClass) (nodeResult, bool) {
+	if deviceClass.NodeSelector != nil {
+		if matches, err := corev1helper.MatchNodeSelectorTerms(node, deviceClass.NodeSelector); err != nil || !matches {
+			return nodeResult{Node: node.Name, Reason: ReasonNodeSelectorMismatch}, false
+		}
+	}
+
+	if taint, untolerated := corev1helper.FindMatchingUntoleratedTaint(klog.Background(), node.Spec.Taints, cluster.Spec.Tolerations, nil, true); untolerated {
+		return nodeResult{Node: node.Name, Reason: ReasonTaintNotTolerated, Detail: fmt.Sprintf("%s: %s", node.Name, taint.ToString())}, false
+	}
+
+	return nodeResult{}, true
+}
+
+// checkConsumer checks if the pods consuming the PVC can be scheduled to the node.
+func checkConsumer(node *corev1.Node, consumer *consumer) (nodeResult, bool) {
+	if consumer.SelectedNode != "" && consumer.SelectedNode != node.Name {
+		return nodeResult{Node: node.Name, Reason: ReasonConsumerNodeMismatch}, false
+	}
+
+	for i := range consumer.Pods {
+		pod := &consumer.Pods[i]
+		if matches, err := nodeaffinity.GetRequiredNodeAffinity(pod).Match(node); err != nil || !matches {
+			return nodeResult{Node: node.Name, Reason: ReasonConsumerNodeMismatch}, false
+		}
+		_, untolerated := corev1helper.FindMatchingUntoleratedTaint(klog.Background(), node.Spec.Taints, pod.Spec.Tolerations, func(t *corev1.Taint) bool {
+			return t.Effect == corev1.TaintEffectNoSchedule || t.Effect == corev1.TaintEffectNoExecute
+		}, true)
+		if untolerated {
+			return nodeResult{Node: node.Name, Reason: ReasonConsumerNodeMismatch}, false
+		}
+	}
+
+	return nodeResult{}, true
+}
+
+// checkCapacity checks if the capacity reported for the node fits the requested storage.
+// For RAID device classes the request is multiplied by the RAID overhead, as TopoLVM reports the free space of the volume group.
+func checkCapacity(node *corev1.Node, deviceClass *lvmv1alpha1.DeviceClass, deviceCount int, requested *resource.Quantity, capacity resource.Quantity) (nodeResult, bool) {
+	required := requested.Value()
+	if deviceClass != nil && deviceClass.RAIDConfig != nil {
+		required = int64(math.Ceil(float64(required) * deviceClass.RAIDConfig.OverheadFactor(deviceCount)))
+	}
+
+	if required < capacity.Value() {
+		return nodeResult{}, true
+	}
+
+	switch {
+	case deviceClass != nil && deviceClass.ThinPoolConfig != nil:
+		return nodeResult{Node: node.Name, Reason: ReasonThinPoolOverprovisioned, Detail: fmt.Sprintf(
+			"node %s has %s free in thin pool %s with overprovisionRatio %d",
+			node.Name, prettyByteSize(capacity.Value()), deviceClass.ThinPoolConfig.Name, deviceClass.ThinPoolConfig.OverprovisionRatio,
+		)}, false
+	case deviceClass != nil && deviceClass.RAIDConfig != nil:
+		return nodeResult{Node: node.Name, Reason: ReasonNotEnoughCapacity, Detail: fmt.Sprintf(
+			"node %s has %s free storage, %s is required with %s overhead",
+			node.Name, prettyByteSize(capacity.Value()), prettyByteSize(required), deviceClass.RAIDConfig.Type,
+		)}, false
+	default:
+		return nodeResult{Node: node.Name, Reason: ReasonNotEnoughCapacity, Detail: fmt.Sprintf(
+			"node %s has %s free storage", node.Name, prettyByteSize(capacity.Value()),
+		)}, false
+	}
+}
+
+// blockingReason returns the reason of the node that got the furthest in the evaluation.
+func blockingReason(results []nodeResult) string {
+	reason := ReasonNotEnoughCapacity
+	order := -1
+	for _, result := range results {
+		if reasonOrder[result.Reason] > order {
+			reason = result.Reason
+			order = reasonOrder[result.Reason]
+		}
+	}
+	return reason
+}
+
+// summarize builds a message that groups the nodes by the reason they cannot provision the PVC.
+func summarize(results []nodeResult, requested *resource.Quantity, deviceClassName string, nodeCount int, consumer *consumer) string {
+	if len(results) == 0 {
+		return fmt.Sprintf("Requested storage (%s) is greater than available capacity on any node, no node reports capacity for device class %s.",
+			requested.String(), deviceClassName)
+	}
+
+	nodesByReason := make(map[string][]nodeResult)
+	for _, result := range results {
+		nodesByReason[result.Reason] = append(nodesByReason[result.Reason], result)
+	}
+
+	var parts []string
+	for reason, nodes := range nodesByReason {
+		var details []string
+		for _, node := range nodes {
+			if node.Detail != "" {
+				details = append(details, node.Detail)
+			} else {
+				details = append(details, node.Node)
+			}
+		}
+		sort.Strings(details)
+		parts = append(parts, fmt.Sprintf("%d node(s) %s (%s)", len(nodes), describeReason(reason, consumer), strings.Join(limit(details), ", ")))
+	}
+	sort.Strings(parts)
+
+	return fmt.Sprintf("0/%d nodes can provision %s in device class %s: %s.", nodeCount, requested.String(), deviceClassName, strings.Join(parts, ", "))
+}
+
+func describeReason(reason string, consumer *consumer) string {
+	switch reason {
+	case ReasonNodeSelectorMismatch:
+		return "do not match the nodeSelector of the device class"
+	case ReasonTaintNotTolerated:
+		return "have a taint that is not tolerated by the LVMCluster"
+	case ReasonConsumerNodeMismatch:
+		if consumer.SelectedNode != "" {
+			return fmt.Sprintf("are not the node %s selected for the PVC", consumer.SelectedNode)
+		}
+		return "do not match the node affinity or tolerations of the pods using the PVC"
+	case ReasonCapacityNotReported:
+		return "do not report capacity for the device class"
+	case ReasonThinPoolOverprovisioned:
+		return "have not enough overprovisioned capacity in the thin pool"
+	default:
+		return "have not enough capacity"
+	}
+}
+
+// limit shortens a list of details to maxNodeDetails entries.
+func limit(details []string) []string {
+	if len(details) <= maxNodeDetails {
+		return details
+	}
+	return append(details[:maxNodeDetails:maxNodeDetails], fmt.Sprintf("and %d more", len(details)-maxNodeDetails))
+}
+
+// setStorageAvailableCondition sets the condition on the PVC and returns true if it changed.
+func setStorageAvailableCondition(pvc *corev1.PersistentVolumeClaim, d *diagnosis) bool {
+	for i := range pvc.Status.Conditions {
+		condition := &pvc.Status.Conditions[i]
+		if condition.Type != StorageAvailableCondition {
+			continue
+		}
+		if condition.Status == d.Status && condition.Reason == d.Reason && condition.Message == d.Message {
+			return false
+		}
+		if condition.Status != d.Status {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		condition.Status = d.Status
+		condition.Reason = d.Reason
+		condition.Message = d.Message
+		condition.LastProbeTime = metav1.Now()
+		return true
+	}
+
+	pvc.Status.Conditions = append(pvc.Status.Conditions, corev1.PersistentVolumeClaimCondition{
+		Type:               StorageAvailableCondition,
+		Status:             d.Status,
+		Reason:             d.Reason,
+		Message:            d.Message,
+		LastProbeTime:      metav1.Now(),
+		LastTransitionTime: metav1.Now(),
+	})
+	return true
+}
+
+// removeStorageAvailableCondition removes the condition from the PVC and returns true if it was present.
+func removeStorageAvailableCondition(pvc *corev1.PersistentVolumeClaim) bool {
+	for i, condition := range pvc.Status.Conditions {
+		if condition.Type == StorageAvailableCondition {
+			pvc.Status.Conditions = append(pvc.Status.Conditions[:i], pvc.Status.Conditions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
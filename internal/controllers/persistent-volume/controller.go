@@ -2,17 +2,21 @@ package persistent_volume
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
@@ -33,8 +37,12 @@ func NewReconciler(client client.Client, eventRecorder events.EventRecorder) *Re
 	}
 }
 
-//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;update;delete
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=events,verbs=create;update;patch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumegroupnodestatuses,verbs=get;list;watch
 
 // Reconcile PV
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -65,9 +73,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	var nodeName string
 	for _, pvNodeSelectorTerms := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
 		for _, v := range pvNodeSelectorTerms.MatchExpressions {
 			if v.Key == "topology.topolvm.io/node" && v.Operator == corev1.NodeSelectorOpIn {
+				nodeName = v.Values[0]
 				if pv.Labels == nil {
 					pv.Labels = make(map[string]string)
 				}
@@ -83,18 +93,66 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}
 
+	if nodeName == "" {
+		return ctrl.Result{}, nil
+	}
+
+	// Check if the volume is stranded on a node or volume group that is no longer available
+	reason, message, err := r.getStrandedReason(ctx, pv, nodeName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	changed, err := r.updateStrandedAnnotations(ctx, pv, reason)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if reason == "" {
+		if changed {
+			logger.Info("PV is no longer stranded", "PV", req.NamespacedName)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Publish an event on the PV and the bound PVC once the volume is stranded
+	if changed {
+		msg := fmt.Sprintf("Volume is stranded as the %s. The data of the volume is not available until the node or volume group is recovered.", message)
+		r.recorder.Eventf(pv, nil, "Warning", "VolumeStranded", "CheckNode", msg)
+		if pv.Spec.ClaimRef != nil && pv.Status.Phase == corev1.VolumeBound {
+			r.recorder.Eventf(pv.Spec.ClaimRef, pv, "Warning", "VolumeStranded", "CheckNode", msg)
+		}
+		logger.Info("PV is stranded", "PV", req.NamespacedName, "reason", reason)
+	}
+
+	if reason == ReasonNodeDeleted {
+		return r.deleteStrandedVolume(ctx, pv)
+	}
+
 	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		WithEventFilter(r.Predicates()).
-		For(&corev1.PersistentVolume{}).
+		For(&corev1.PersistentVolume{}, builder.WithPredicates(r.Predicates())).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.getPersistentVolumesForNode),
+			builder.WithPredicates(nodeAvailabilityPredicate())).
+		Watches(&lvmv1alpha1.LVMVolumeGroupNodeStatus{}, handler.EnqueueRequestsFromMapFunc(r.getPersistentVolumesForNode)).
 		WithOptions(controller.Options{SkipNameValidation: ptr.To(true)}).
 		Complete(r)
 }
 
+// nodeAvailabilityPredicate filters for nodes being added or removed.
+func nodeAvailabilityPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return true },
+		DeleteFunc:  func(event.DeleteEvent) bool { return true },
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
 func (r *Reconciler) Predicates() predicate.Funcs {
 	return predicate.Funcs{
 		CreateFunc:  func(event.CreateEvent) bool { return true },
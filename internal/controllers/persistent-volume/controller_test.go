@@ -5,13 +5,19 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
 	persistentvolume "github.com/openshift/lvm-operator/v4/internal/controllers/persistent-volume"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
@@ -22,6 +28,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, lvmv1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
 func TestPersistentVolumeReconciler_SetupWithManager(t *testing.T) {
 	mgr, err := controllerruntime.NewManager(&rest.Config{}, controllerruntime.Options{})
 	assert.NoError(t, err)
@@ -155,6 +168,15 @@ func TestPersistentVolumeReconciler_Reconcile(t *testing.T) {
 						},
 					},
 				},
+				&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}},
+				&lvmv1alpha1.LVMVolumeGroupNodeStatus{
+					ObjectMeta: metav1.ObjectMeta{Namespace: defaultRequest.Namespace, Name: "test-node"},
+					Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+						VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
+							{VGStatus: lvmv1alpha1.VGStatus{Name: "example", Status: lvmv1alpha1.VGStatusReady}},
+						},
+					},
+				},
 			},
 		},
 	}
@@ -162,7 +184,7 @@ func TestPersistentVolumeReconciler_Reconcile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			recorder := events.NewFakeRecorder(1)
-			clnt := fake.NewClientBuilder().WithObjects(tt.objs...).
+			clnt := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(tt.objs...).
 				WithInterceptorFuncs(interceptor.Funcs{Get: func(ctx context.Context, client client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
 					if tt.clientErr != nil {
 						return tt.clientErr
@@ -194,3 +216,157 @@ func TestPersistentVolumeReconciler_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestPersistentVolumeReconciler_StrandedVolumes(t *testing.T) {
+	const nodeName = "test-node"
+	namespace := "openshift-lvm-storage"
+
+	newPV := func(phase v1.PersistentVolumePhase, annotations map[string]string) *v1.PersistentVolume {
+		return &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-pv", Annotations: annotations},
+			Spec: v1.PersistentVolumeSpec{
+				StorageClassName:              constants.StorageClassPrefix + "vg1",
+				PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimRetain,
+				ClaimRef:                      &v1.ObjectReference{Kind: "PersistentVolumeClaim", Namespace: "app", Name: "data"},
+				NodeAffinity: &v1.VolumeNodeAffinity{Required: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{
+					MatchExpressions: []v1.NodeSelectorRequirement{{
+						Key: "topology.topolvm.io/node", Operator: v1.NodeSelectorOpIn, Values: []string{nodeName},
+					}},
+				}}}},
+			},
+			Status: v1.PersistentVolumeStatus{Phase: phase},
+		}
+	}
+	newNodeStatus := func(status lvmv1alpha1.VGStatusType) *lvmv1alpha1.LVMVolumeGroupNodeStatus {
+		return &lvmv1alpha1.LVMVolumeGroupNodeStatus{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: nodeName},
+			Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+				VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
+					{VGStatus: lvmv1alpha1.VGStatus{Name: "vg1", Status: status, Reason: "no devices found"}},
+				},
+			},
+		}
+	}
+	newCluster := func(name string, prefixed bool, policy lvmv1alpha1.StrandedVolumePolicy) *lvmv1alpha1.LVMCluster {
+		cluster := &lvmv1alpha1.LVMCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: lvmv1alpha1.LVMClusterSpec{
+				StrandedVolumePolicy: policy,
+				Storage:              lvmv1alpha1.Storage{DeviceClasses: []lvmv1alpha1.DeviceClass{{Name: "vg1"}}},
+			},
+		}
+		if prefixed {
+			cluster.Annotations = map[string]string{constants.PrefixedVolumeGroupsAnnotation: "true"}
+		}
+		return cluster
+	}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+	expiredSince := map[string]string{
+		constants.StrandedVolumeAnnotation: persistentvolume.ReasonNodeDeleted,
+		constants.StrandedSinceAnnotation:  metav1.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+	}
+
+	tests := []struct {
+		name         string
+		objs         []client.Object
+		expectReason string
+		expectEvents int
+		expectDelete bool
+	}{
+		{
+			name:         "node deleted",
+			objs:         []client.Object{newPV(v1.VolumeBound, nil)},
+			expectReason: persistentvolume.ReasonNodeDeleted,
+			expectEvents: 2,
+		},
+		{
+			name:         "volume group failed",
+			objs:         []client.Object{newPV(v1.VolumeBound, nil), node, newNodeStatus(lvmv1alpha1.VGStatusFailed)},
+			expectReason: persistentvolume.ReasonVolumeGroupFailed,
+			expectEvents: 2,
+		},
+		{
+			name:         "volume group missing",
+			objs:         []client.Object{newPV(v1.VolumeBound, nil), node},
+			expectReason: persistentvolume.ReasonVolumeGroupMissing,
+			expectEvents: 2,
+		},
+		{
+			name: "volume group recovered",
+			objs: []client.Object{newPV(v1.VolumeBound, map[string]string{
+				constants.StrandedVolumeAnnotation: persistentvolume.ReasonVolumeGroupFailed,
+			}), node, newNodeStatus(lvmv1alpha1.VGStatusReady)},
+		},
+		{
+			name:         "released volume of removed node is retained by default",
+			objs:         []client.Object{newPV(v1.VolumeReleased, expiredSince), newCluster("lvmcluster", false, lvmv1alpha1.StrandedVolumePolicyRetain)},
+			expectReason: persistentvolume.ReasonNodeDeleted,
+		},
+		{
+			name:         "bound volume of removed node is not deleted",
+			objs:         []client.Object{newPV(v1.VolumeBound, expiredSince), newCluster("lvmcluster", false, lvmv1alpha1.StrandedVolumePolicyDelete)},
+			expectReason: persistentvolume.ReasonNodeDeleted,
+		},
+		{
+			name:         "released volume of removed node is deleted",
+			objs:         []client.Object{newPV(v1.VolumeReleased, expiredSince), newCluster("lvmcluster", false, lvmv1alpha1.StrandedVolumePolicyDelete)},
+			expectEvents: 1,
+			expectDelete: true,
+		},
+		{
+			name: "released volume of removed node uses the policy of the LVMCluster owning its device class",
+			objs: []client.Object{
+				newPV(v1.VolumeReleased, expiredSince),
+				newCluster("lvmcluster", false, lvmv1alpha1.StrandedVolumePolicyRetain),
+				newCluster("other", true, lvmv1alpha1.StrandedVolumePolicyDelete),
+			},
+			expectReason: persistentvolume.ReasonNodeDeleted,
+		},
+		{
+			name: "released volume of removed node is deleted by the LVMCluster owning its device class",
+			objs: []client.Object{
+				newPV(v1.VolumeReleased, expiredSince),
+				newCluster("lvmcluster", false, lvmv1alpha1.StrandedVolumePolicyDelete),
+				newCluster("other", true, lvmv1alpha1.StrandedVolumePolicyRetain),
+			},
+			expectEvents: 1,
+			expectDelete: true,
+		},
+		{
+			name: "released volume of removed node is not deleted by the policy of another LVMCluster",
+			objs: []client.Object{
+				newPV(v1.VolumeReleased, expiredSince),
+				newCluster("other", true, lvmv1alpha1.StrandedVolumePolicyDelete),
+			},
+			expectReason: persistentvolume.ReasonNodeDeleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			recorder := events.NewFakeRecorder(2)
+			clnt := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(tt.objs...).Build()
+			r := persistentvolume.NewReconciler(clnt, recorder)
+
+			req := controllerruntime.Request{NamespacedName: types.NamespacedName{Name: "test-pv"}}
+			_, err := r.Reconcile(ctx, req)
+			require.NoError(t, err)
+			assert.Len(t, recorder.Events, tt.expectEvents)
+
+			pv := &v1.PersistentVolume{}
+			err = clnt.Get(ctx, req.NamespacedName, pv)
+			if tt.expectDelete {
+				assert.True(t, apierrors.IsNotFound(err), "stranded volume should be deleted")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectReason, pv.Annotations[constants.StrandedVolumeAnnotation])
+			if tt.expectReason == "" {
+				assert.NotContains(t, pv.Annotations, constants.StrandedSinceAnnotation)
+			} else {
+				assert.Contains(t, pv.Annotations, constants.StrandedSinceAnnotation)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package persistent_volume
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// ReasonNodeDeleted is set if the node of the PersistentVolume no longer exists.
+	ReasonNodeDeleted = "NodeDeleted"
+	// ReasonVolumeGroupMissing is set if the volume group of the PersistentVolume is not reported for its node.
+	ReasonVolumeGroupMissing = "VolumeGroupMissing"
+	// ReasonVolumeGroupFailed is set if the volume group of the PersistentVolume is reported as failed on its node.
+	ReasonVolumeGroupFailed = "VolumeGroupFailed"
+
+	// strandedVolumeGracePeriod is the time the node of a PersistentVolume has to be removed
+	// before the PersistentVolume is deleted with the Delete StrandedVolumePolicy.
+	strandedVolumeGracePeriod = 10 * time.Minute
+)
+
+// getStrandedReason checks if the node and the volume group of the PersistentVolume are still available.
+// An empty reason is returned if they are.
+func (r *Reconciler) getStrandedReason(ctx context.Context, pv *corev1.PersistentVolume, nodeName string) (string, string, error) {
+	node := &corev1.Node{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: nodeName}, node); apierrors.IsNotFound(err) {
+		return ReasonNodeDeleted, fmt.Sprintf("node %s of the volume no longer exists", nodeName), nil
+	} else if err != nil {
+		return "", "", fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+
+	deviceClass, err := r.getDeviceClass(ctx, pv)
+	if err != nil {
+		return "", "", err
+	}
+
+	nodeStatuses := &lvmv1alpha1.LVMVolumeGroupNodeStatusList{}
+	if err := r.client.List(ctx, nodeStatuses); err != nil {
+		return "", "", fmt.Errorf("failed to list LVMVolumeGroupNodeStatuses: %w", err)
+	}
+	for _, nodeStatus := range nodeStatuses.Items {
+		if nodeStatus.Name != nodeName {
+			continue
+		}
+		if vgStatus := nodeStatus.VolumeGroupStatus(deviceClass); vgStatus != nil {
+			if vgStatus.Status == lvmv1alpha1.VGStatusFailed {
+				return ReasonVolumeGroupFailed, fmt.Sprintf("volume group %s on node %s has failed: %s", deviceClass, nodeName, vgStatus.Reason), nil
+			}
+			return "", "", nil
+		}
+	}
+
+	return ReasonVolumeGroupMissing, fmt.Sprintf("volume group %s is not reported for node %s", deviceClass, nodeName), nil
+}
+
+// getDeviceClass returns the device class of the PersistentVolume from its StorageClass.
+// If the StorageClass no longer exists, the device class is derived from the StorageClass name.
+func (r *Reconciler) getDeviceClass(ctx context.Context, pv *corev1.PersistentVolume) (string, error) {
+	sc := &storagev1.StorageClass{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: pv.Spec.StorageClassName}, sc); err == nil {
+		if deviceClass, ok := sc.Parameters[constants.DeviceClassKey]; ok {
+			return deviceClass, nil
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get storage class %s: %w", pv.Spec.StorageClassName, err)
+	}
+	return strings.TrimPrefix(pv.Spec.StorageClassName, constants.StorageClassPrefix), nil
+}
+
+// updateStrandedAnnotations marks or unmarks the PersistentVolume as stranded and returns true if the reason changed.
+func (r *Reconciler) updateStrandedAnnotations(ctx context.Context, pv *corev1.PersistentVolume, reason string) (bool, error) {
+	current, marked := pv.Annotations[constants.StrandedVolumeAnnotation]
+	if current == reason {
+		return false, nil
+	}
+
+	if reason == "" {
+		delete(pv.Annotations, constants.StrandedVolumeAnnotation)
+		delete(pv.Annotations, constants.StrandedSinceAnnotation)
+	} else {
+		if pv.Annotations == nil {
+			pv.Annotations = make(map[string]string)
+		}
+		pv.Annotations[constants.StrandedVolumeAnnotation] = reason
+		if !marked {
+			pv.Annotations[constants.StrandedSinceAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		}
+	}
+
+	if err := r.client.Update(ctx, pv); err != nil {
+		return false, fmt.Errorf("failed to update stranded annotations: %w", err)
+	}
+	return true, nil
+}
+
+// getStrandedVolumePolicy returns the StrandedVolumePolicy of the LVMCluster that owns the device class of the
+// PersistentVolume, which is the LVMCluster with a device class whose volume group name matches it. Volumes of
+// device classes that are owned by no LVMCluster, or by more than one, are retained.
+func (r *Reconciler) getStrandedVolumePolicy(ctx context.Context, pv *corev1.PersistentVolume) (lvmv1alpha1.StrandedVolumePolicy, error) {
+	clusters := &lvmv1alpha1.LVMClusterList{}
+	if err := r.client.List(ctx, clusters); err != nil {
+		return "", fmt.Errorf("failed to list LVMClusters: %w", err)
+	}
+
+	volumeGroup, err := r.getDeviceClass(ctx, pv)
+	if err != nil {
+		return "", err
+	}
+	var owners []*lvmv1alpha1.LVMCluster
+	for i := range clusters.Items {
+		for _, deviceClass := range clusters.Items[i].Spec.Storage.DeviceClasses {
+			if clusters.Items[i].VolumeGroupName(deviceClass.Name) == volumeGroup {
+				owners = append(owners, &clusters.Items[i])
+				break
+			}
+		}
+	}
+
+	if len(owners) != 1 {
+		log.FromContext(ctx).V(1).Info("retaining stranded PersistentVolume as its device class has no unique LVMCluster",
+			"volumeGroup", volumeGroup, "owners", len(owners))
+		return lvmv1alpha1.StrandedVolumePolicyRetain, nil
+	}
+	return owners[0].Spec.StrandedVolumePolicy, nil
+}
+
+// deleteStrandedVolume deletes an unbound PersistentVolume with the Retain reclaim policy whose node was removed,
+// once the grace period has passed.
+func (r *Reconciler) deleteStrandedVolume(ctx context.Context, pv *corev1.PersistentVolume) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if pv.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimRetain ||
+		(pv.Status.Phase != corev1.VolumeReleased && pv.Status.Phase != corev1.VolumeAvailable) {
+		return ctrl.Result{}, nil
+	}
+
+	policy, err := r.getStrandedVolumePolicy(ctx, pv)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if policy != lvmv1alpha1.StrandedVolumePolicyDelete {
+		return ctrl.Result{}, nil
+	}
+
+	since, err := time.Parse(time.RFC3339, pv.Annotations[constants.StrandedSinceAnnotation])
+	if err != nil {
+		since = time.Now()
+	}
+	if remaining := strandedVolumeGracePeriod - time.Since(since); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	if err := r.client.Delete(ctx, pv); client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to delete stranded PersistentVolume: %w", err)
+	}
+	logger.Info("deleted stranded PersistentVolume of removed node")
+	r.recorder.Eventf(pv, nil, "Normal", "StrandedVolumeDeleted", "DeleteStrandedVolume",
+		"PersistentVolume was deleted as its node was removed and it was no longer bound to a claim.")
+
+	return ctrl.Result{}, nil
+}
+
+// getPersistentVolumesForNode enqueues the PersistentVolumes on the node of the object.
+// It is used for Node and LVMVolumeGroupNodeStatus objects, which are both named after the node.
+func (r *Reconciler) getPersistentVolumesForNode(ctx context.Context, obj client.Object) []reconcile.Request {
+	pvs := &corev1.PersistentVolumeList{}
+	if err := r.client.List(ctx, pvs, client.MatchingLabels{pvLabel: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "getPersistentVolumesForNode: Failed to get PersistentVolume objs")
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0, len(pvs.Items))
+	for _, pv := range pvs.Items {
+		if strings.HasPrefix(pv.Spec.StorageClassName, constants.StorageClassPrefix) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&pv)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,523 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot_schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	snapapi "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/topolvm/topolvm"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// SnapshotScheduleLabel is set on the VolumeSnapshots created by an LVMSnapshotSchedule.
+	// Its value is the name of the LVMSnapshotSchedule.
+	SnapshotScheduleLabel = "lvm.topolvm.io/snapshot-schedule"
+	// SnapshotScheduleNamespaceLabel is set on the VolumeSnapshots created by an LVMSnapshotSchedule.
+	// Its value is the namespace of the LVMSnapshotSchedule.
+	SnapshotScheduleNamespaceLabel = "lvm.topolvm.io/snapshot-schedule-namespace"
+	// ScheduledAtAnnotation is set on the VolumeSnapshots created by an LVMSnapshotSchedule to the time
+	// they were scheduled at. It is used to order the VolumeSnapshots for the retention.
+	ScheduledAtAnnotation = "lvm.topolvm.io/scheduled-at"
+
+	// statusRefreshInterval is the maximum time between two reconciliations of a schedule,
+	// so that its status reflects PersistentVolumeClaims that were created or changed in the meantime.
+	statusRefreshInterval = 5 * time.Minute
+
+	// maxMissedSchedules is the number of missed schedules that are looked at to determine the latest one.
+	// If more schedules were missed, the VolumeSnapshots are named after the current time instead.
+	maxMissedSchedules = 100
+)
+
+type (
+	EventReasonInfo  string
+	EventReasonError string
+)
+
+const (
+	EventReasonErrorSnapshotFailed  EventReasonError = "SnapshotFailed"
+	EventReasonErrorInvalidSchedule EventReasonError = "InvalidSchedule"
+	EventReasonSnapshotsCreated     EventReasonInfo  = "SnapshotsCreated"
+	EventReasonSnapshotsDeleted     EventReasonInfo  = "SnapshotsDeleted"
+)
+
+const (
+	ReasonScheduled       = "Scheduled"
+	ReasonPaused          = "Paused"
+	ReasonInvalidSchedule = "InvalidSchedule"
+	ReasonSnapshotFailed  = "SnapshotFailed"
+
+	// SkipReasonThickDeviceClass is set for PersistentVolumeClaims of thick device classes without snapshot support.
+	SkipReasonThickDeviceClass = "ThickDeviceClass"
+	// SkipReasonDeviceClassNotFound is set for PersistentVolumeClaims whose device class is not part of any LVMCluster.
+	SkipReasonDeviceClassNotFound = "DeviceClassNotFound"
+	// SkipReasonNotBound is set for PersistentVolumeClaims that are not bound to a PersistentVolume yet.
+	SkipReasonNotBound = "NotBound"
+)
+
+// Reconciler reconciles LVMSnapshotSchedule objects.
+// It creates VolumeSnapshots of the selected PersistentVolumeClaims whenever the schedule is due
+// and deletes the VolumeSnapshots that exceed the retention of the schedule.
+type Reconciler struct {
+	client.Client
+	events.EventRecorder
+
+	// now returns the current time and is replaced in tests.
+	now func() time.Time
+}
+
+// NewReconciler returns Reconciler.
+func NewReconciler(client client.Client, eventRecorder events.EventRecorder) *Reconciler {
+	return &Reconciler{
+		Client:        client,
+		EventRecorder: eventRecorder,
+		now:           time.Now,
+	}
+}
+
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmsnapshotschedules,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmsnapshotschedules/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;update;patch
+
+// Reconcile creates the VolumeSnapshots of the LVMSnapshotSchedule if it is due and applies its retention.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	schedule := &lvmv1alpha1.LVMSnapshotSchedule{}
+	if err := r.Get(ctx, req.NamespacedName, schedule); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !schedule.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	original := schedule.DeepCopy()
+	now := r.now().UTC()
+
+	cron, err := parseCronSchedule(schedule.Spec.Schedule)
+	if err != nil {
+		r.Eventf(schedule, nil, corev1.EventTypeWarning, string(EventReasonErrorInvalidSchedule), "ParseSchedule", err.Error())
+		schedule.Status.NextScheduleTime = nil
+		setReadyCondition(schedule, metav1.ConditionFalse, ReasonInvalidSchedule, err.Error())
+		return ctrl.Result{}, r.updateStatus(ctx, original, schedule)
+	}
+
+	pvcs, skipped, err := r.selectPersistentVolumeClaims(ctx, schedule)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	schedule.Status.PersistentVolumeClaims = int32(len(pvcs))
+	schedule.Status.Skipped = skipped
+
+	if schedule.Spec.Paused {
+		logger.V(1).Info("skipping snapshot schedule as it is paused")
+		schedule.Status.NextScheduleTime = nil
+		setReadyCondition(schedule, metav1.ConditionFalse, ReasonPaused, "the schedule is paused, no VolumeSnapshots are created or deleted")
+		return ctrl.Result{}, r.updateStatus(ctx, original, schedule)
+	}
+
+	// The schedule is due once the previously determined next schedule time has passed. It is not set for new
+	// schedules or while paused, so schedules missed before are not caught up.
+	var errs []error
+	next := cron.next(now)
+	if due := schedule.Status.NextScheduleTime; due != nil && !due.After(now) {
+		scheduled := latestSchedule(cron, due.Time, now)
+		if err := r.createSnapshots(ctx, schedule, pvcs, scheduled); err != nil {
+			// keep the next schedule time so that the remaining VolumeSnapshots are created on retry
+			errs = append(errs, err)
+			next = due.Time
+		} else {
+			schedule.Status.LastScheduleTime = ptr.To(metav1.NewTime(scheduled))
+		}
+	}
+
+	count, err := r.applyRetention(ctx, schedule, now)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	schedule.Status.VolumeSnapshots = count
+
+	if next.IsZero() {
+		schedule.Status.NextScheduleTime = nil
+	} else {
+		schedule.Status.NextScheduleTime = ptr.To(metav1.NewTime(next))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		r.Eventf(schedule, nil, corev1.EventTypeWarning, string(EventReasonErrorSnapshotFailed), "CreateSnapshots", err.Error())
+		setReadyCondition(schedule, metav1.ConditionFalse, ReasonSnapshotFailed, err.Error())
+		return ctrl.Result{}, errors.Join(err, r.updateStatus(ctx, original, schedule))
+	}
+
+	setReadyCondition(schedule, metav1.ConditionTrue, ReasonScheduled,
+		fmt.Sprintf("%d PersistentVolumeClaims are snapshotted, %d are skipped", len(pvcs), len(skipped)))
+	if err := r.updateStatus(ctx, original, schedule); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	requeueAfter := statusRefreshInterval
+	if !next.IsZero() && next.Sub(now) < requeueAfter {
+		requeueAfter = next.Sub(now)
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// selectPersistentVolumeClaims returns the PersistentVolumeClaims that are snapshotted by the schedule,
+// together with the selected PersistentVolumeClaims that are skipped.
+// PersistentVolumeClaims that are not provisioned by LVMS are ignored.
+func (r *Reconciler) selectPersistentVolumeClaims(ctx context.Context, schedule *lvmv1alpha1.LVMSnapshotSchedule) (
+	[]corev1.PersistentVolumeClaim, []lvmv1alpha1.SkippedPersistentVolumeClaim, error,
+) {
+	namespaces, err := r.selectNamespaces(ctx, schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pvcSelector := labels.Everything()
+	if schedule.Spec.PersistentVolumeClaimSelector != nil {
+		if pvcSelector, err = metav1.LabelSelectorAsSelector(schedule.Spec.PersistentVolumeClaimSelector); err != nil {
+			return nil, nil, fmt.Errorf("invalid persistentVolumeClaimSelector: %w", err)
+		}
+	}
+
+	clusters := &lvmv1alpha1.LVMClusterList{}
+	if err := r.List(ctx, clusters); err != nil {
+		return nil, nil, fmt.Errorf("failed to list LVMClusters: %w", err)
+	}
+
+	var selected []corev1.PersistentVolumeClaim
+	var skipped []lvmv1alpha1.SkippedPersistentVolumeClaim
+	for _, namespace := range namespaces {
+		pvcs := &corev1.PersistentVolumeClaimList{}
+		if err := r.List(ctx, pvcs, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: pvcSelector}); err != nil {
+			return nil, nil, fmt.Errorf("failed to list PersistentVolumeClaims in namespace %s: %w", namespace, err)
+		}
+
+		for _, pvc := range pvcs.Items {
+			if !pvc.DeletionTimestamp.IsZero() || pvc.Spec.StorageClassName == nil {
+				continue
+			}
+
+			sc := &storagev1.StorageClass{}
+			if err := r.Get(ctx, client.ObjectKey{Name: *pvc.Spec.StorageClassName}, sc); apierrors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return nil, nil, fmt.Errorf("failed to get StorageClass %s: %w", *pvc.Spec.StorageClassName, err)
+			}
+			if sc.Provisioner != constants.TopolvmCSIDriverName {
+				continue
+			}
+
+			if reason, message := skipReason(&pvc, sc, clusters.Items); reason != "" {
+				skipped = append(skipped, lvmv1alpha1.SkippedPersistentVolumeClaim{
+					Namespace: pvc.Namespace,
+					Name:      pvc.Name,
+					Reason:    reason,
+					Message:   message,
+				})
+				continue
+			}
+			selected = append(selected, pvc)
+		}
+	}
+
+	sort.Slice(skipped, func(i, j int) bool {
+		if skipped[i].Namespace != skipped[j].Namespace {
+			return skipped[i].Namespace < skipped[j].Namespace
+		}
+		return skipped[i].Name < skipped[j].Name
+	})
+
+	return selected, skipped, nil
+}
+
+// selectNamespaces returns the namespaces selected by the namespaceSelector of the schedule,
+// or the namespace of the schedule if no namespaceSelector is set.
+func (r *Reconciler) selectNamespaces(ctx context.Context, schedule *lvmv1alpha1.LVMSnapshotSchedule) ([]string, error) {
+	if schedule.Spec.NamespaceSelector == nil {
+		return []string{schedule.Namespace}, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(schedule.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespaceSelector: %w", err)
+	}
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	names := make([]string, 0, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		names = append(names, namespace.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// skipReason returns why the PersistentVolumeClaim can not be snapshotted, or an empty reason if it can.
+func skipReason(pvc *corev1.PersistentVolumeClaim, sc *storagev1.StorageClass, clusters []lvmv1alpha1.LVMCluster) (string, string) {
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return SkipReasonNotBound, "the PersistentVolumeClaim is not bound to a PersistentVolume"
+	}
+
+	name, ok := sc.Parameters[constants.DeviceClassKey]
+	if !ok {
+		name = topolvm.DefaultDeviceClassAnnotationName
+	}
+	deviceClass := findDeviceClass(clusters, name)
+	if deviceClass == nil {
+		return SkipReasonDeviceClassNotFound, fmt.Sprintf("device class %s of StorageClass %s is not part of any LVMCluster", name, sc.Name)
+	}
+	if deviceClass.ThinPoolConfig == nil && deviceClass.ThickSnapshotConfig == nil {
+		return SkipReasonThickDeviceClass, fmt.Sprintf("device class %s has no thin pool and no thickSnapshotConfig and does not support snapshots", deviceClass.Name)
+	}
+	return "", ""
+}
+
+func findDeviceClass(clusters []lvmv1alpha1.LVMCluster, name string) *lvmv1alpha1.DeviceClass {
+	for i := range clusters {
+		deviceClasses := clusters[i].Spec.Storage.DeviceClasses
+		for j := range deviceClasses {
+			deviceClass := &deviceClasses[j]
+			if clusters[i].VolumeGroupName(deviceClass.Name) == name {
+				return deviceClass
+			}
+			if name == topolvm.DefaultDeviceClassAnnotationName && clusters[i].IsDefaultDeviceClass(deviceClass) {
+				return deviceClass
+			}
+		}
+	}
+	return nil
+}
+
+// createSnapshots creates a VolumeSnapshot for every PersistentVolumeClaim at the scheduled time.
+// The names of the VolumeSnapshots are derived from the scheduled time so that retries do not create duplicates.
+func (r *Reconciler) createSnapshots(ctx context.Context, schedule *lvmv1alpha1.LVMSnapshotSchedule, pvcs []corev1.PersistentVolumeClaim, scheduled time.Time) error {
+	logger := log.FromContext(ctx)
+
+	var errs []error
+	created := 0
+	for _, pvc := range pvcs {
+		snapshot, err := r.volumeSnapshot(ctx, schedule, &pvc, scheduled)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := r.Create(ctx, snapshot); apierrors.IsAlreadyExists(err) {
+			continue
+		} else if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create VolumeSnapshot %s/%s: %w", snapshot.Namespace, snapshot.Name, err))
+			continue
+		}
+		logger.V(1).Info("created VolumeSnapshot", "VolumeSnapshot", client.ObjectKeyFromObject(snapshot))
+		created++
+	}
+
+	if created > 0 {
+		r.Eventf(schedule, nil, corev1.EventTypeNormal, string(EventReasonSnapshotsCreated), "CreateSnapshots",
+			fmt.Sprintf("created %d VolumeSnapshots scheduled at %s", created, scheduled.Format(time.RFC3339)))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Reconciler) volumeSnapshot(ctx context.Context, schedule *lvmv1alpha1.LVMSnapshotSchedule, pvc *corev1.PersistentVolumeClaim, scheduled time.Time) (*snapapi.VolumeSnapshot, error) {
+	snapshotClassName := schedule.Spec.VolumeSnapshotClassName
+	if snapshotClassName == "" {
+		sc := &storagev1.StorageClass{}
+		if err := r.Get(ctx, client.ObjectKey{Name: *pvc.Spec.StorageClassName}, sc); err != nil {
+			return nil, fmt.Errorf("failed to get StorageClass %s: %w", *pvc.Spec.StorageClassName, err)
+		}
+		deviceClass, ok := sc.Parameters[constants.DeviceClassKey]
+		if !ok {
+			return nil, fmt.Errorf("StorageClass %s has no device class, set volumeSnapshotClassName explicitly", sc.Name)
+		}
+		snapshotClassName = constants.VolumeSnapshotClassPrefix + deviceClass
+	}
+
+	return &snapapi.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotName(pvc.Name, schedule.Name, scheduled),
+			Namespace: pvc.Namespace,
+			Labels: map[string]string{
+				SnapshotScheduleLabel:          schedule.Name,
+				SnapshotScheduleNamespaceLabel: schedule.Namespace,
+			},
+			Annotations: map[string]string{
+				ScheduledAtAnnotation: scheduled.UTC().Format(time.RFC3339),
+			},
+		},
+		Spec: snapapi.VolumeSnapshotSpec{
+			Source: snapapi.VolumeSnapshotSource{
+				PersistentVolumeClaimName: ptr.To(pvc.Name),
+			},
+			VolumeSnapshotClassName: ptr.To(snapshotClassName),
+		},
+	}, nil
+}
+
+// snapshotName returns the name of the VolumeSnapshot of the PersistentVolumeClaim at the scheduled time.
+// The name of the PersistentVolumeClaim is shortened if the name would be too long.
+func snapshotName(pvcName, scheduleName string, scheduled time.Time) string {
+	suffix := fmt.Sprintf("-%s-%s", scheduleName, scheduled.UTC().Format("200601021504"))
+	if maxLength := 253 - len(suffix); len(pvcName) > maxLength {
+		pvcName = pvcName[:maxLength]
+	}
+	return pvcName + suffix
+}
+
+// applyRetention deletes the VolumeSnapshots of the schedule that exceed its retention
+// and returns the number of remaining VolumeSnapshots.
+func (r *Reconciler) applyRetention(ctx context.Context, schedule *lvmv1alpha1.LVMSnapshotSchedule, now time.Time) (int32, error) {
+	snapshots := &snapapi.VolumeSnapshotList{}
+	if err := r.List(ctx, snapshots, client.MatchingLabels{
+		SnapshotScheduleLabel:          schedule.Name,
+		SnapshotScheduleNamespaceLabel: schedule.Namespace,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to list VolumeSnapshots: %w", err)
+	}
+
+	// group the snapshots by their PersistentVolumeClaim, newest first
+	bySource := make(map[string][]*snapapi.VolumeSnapshot)
+	for i := range snapshots.Items {
+		snapshot := &snapshots.Items[i]
+		if !snapshot.DeletionTimestamp.IsZero() || snapshot.Spec.Source.PersistentVolumeClaimName == nil {
+			continue
+		}
+		key := snapshot.Namespace + "/" + *snapshot.Spec.Source.PersistentVolumeClaimName
+		bySource[key] = append(bySource[key], snapshot)
+	}
+
+	var count int32
+	var errs []error
+	deleted := 0
+	for _, group := range bySource {
+		sort.Slice(group, func(i, j int) bool {
+			return scheduledAt(group[j]).Before(scheduledAt(group[i]))
+		})
+		for i, snapshot := range group {
+			if !exceedsRetention(schedule.Spec.Retention, i, scheduledAt(snapshot), now) {
+				count++
+				continue
+			}
+			if err := r.Delete(ctx, snapshot); client.IgnoreNotFound(err) != nil {
+				errs = append(errs, fmt.Errorf("failed to delete VolumeSnapshot %s/%s: %w", snapshot.Namespace, snapshot.Name, err))
+				count++
+				continue
+			}
+			deleted++
+		}
+	}
+
+	if deleted > 0 {
+		r.Eventf(schedule, nil, corev1.EventTypeNormal, string(EventReasonSnapshotsDeleted), "ApplyRetention",
+			fmt.Sprintf("deleted %d VolumeSnapshots exceeding the retention", deleted))
+	}
+
+	return count, errors.Join(errs...)
+}
+
+// scheduledAt returns the time the VolumeSnapshot was scheduled at, or its creation time if it is unknown.
+func scheduledAt(snapshot *snapapi.VolumeSnapshot) time.Time {
+	if scheduled, err := time.Parse(time.RFC3339, snapshot.Annotations[ScheduledAtAnnotation]); err == nil {
+		return scheduled
+	}
+	return snapshot.CreationTimestamp.Time
+}
+
+// exceedsRetention returns true if the VolumeSnapshot at the given position (newest first) should be deleted.
+func exceedsRetention(retention *lvmv1alpha1.SnapshotRetention, position int, created, now time.Time) bool {
+	if retention == nil {
+		return false
+	}
+	if retention.MaxCount != nil && position >= int(*retention.MaxCount) {
+		return true
+	}
+	if retention.MaxAge != nil && now.Sub(created) > retention.MaxAge.Duration {
+		return true
+	}
+	return false
+}
+
+// latestSchedule returns the latest time the schedule was due, starting at the first missed schedule.
+func latestSchedule(cron *cronSchedule, first, now time.Time) time.Time {
+	scheduled := first
+	for i := 0; i < maxMissedSchedules; i++ {
+		next := cron.next(scheduled)
+		if next.IsZero() || next.After(now) {
+			return scheduled
+		}
+		scheduled = next
+	}
+	return now.Truncate(time.Minute)
+}
+
+func setReadyCondition(schedule *lvmv1alpha1.LVMSnapshotSchedule, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&schedule.Status.Conditions, metav1.Condition{
+		Type:               lvmv1alpha1.SnapshotScheduleReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: schedule.Generation,
+	})
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, original, schedule *lvmv1alpha1.LVMSnapshotSchedule) error {
+	if equality.Semantic.DeepEqual(original.Status, schedule.Status) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, schedule); err != nil {
+		return fmt.Errorf("failed to update status of LVMSnapshotSchedule %s: %w", schedule.GetName(), err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&lvmv1alpha1.LVMSnapshotSchedule{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controller.Options{SkipNameValidation: ptr.To(true)}).
+		Named("lvms_snapshotschedule").
+		Complete(r)
+}
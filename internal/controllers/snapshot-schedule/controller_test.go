@@ -0,0 +1,241 @@
+package snapshot_schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	snapapi "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace = "test-ns"
+	testSchedule  = "hourly"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, lvmv1alpha1.AddToScheme(scheme))
+	require.NoError(t, snapapi.AddToScheme(scheme))
+	return scheme
+}
+
+func testObjects() []client.Object {
+	cluster := &lvmv1alpha1.LVMCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "lvmcluster", Namespace: "openshift-storage"},
+		Spec: lvmv1alpha1.LVMClusterSpec{Storage: lvmv1alpha1.Storage{DeviceClasses: []lvmv1alpha1.DeviceClass{
+			{Name: "thin", ThinPoolConfig: &lvmv1alpha1.ThinPoolConfig{Name: "thin-pool-1"}},
+			{Name: "thick"},
+		}}},
+	}
+	storageClass := func(deviceClass string) *storagev1.StorageClass {
+		return &storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: constants.StorageClassPrefix + deviceClass},
+			Provisioner: constants.TopolvmCSIDriverName,
+			Parameters:  map[string]string{constants.DeviceClassKey: deviceClass},
+		}
+	}
+	pvc := func(name, storageClassName string, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: map[string]string{"backup": "true"}},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: ptr.To(storageClassName)},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+	return []client.Object{
+		cluster,
+		storageClass("thin"),
+		storageClass("thick"),
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "other"}, Provisioner: "other.csi.io"},
+		pvc("thin-pvc", "lvms-thin", corev1.ClaimBound),
+		pvc("thick-pvc", "lvms-thick", corev1.ClaimBound),
+		pvc("pending-pvc", "lvms-thin", corev1.ClaimPending),
+		pvc("other-pvc", "other", corev1.ClaimBound),
+	}
+}
+
+func testScheduledSnapshot(pvcName string, created time.Time) *snapapi.VolumeSnapshot {
+	return &snapapi.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              snapshotName(pvcName, testSchedule, created),
+			Namespace:         testNamespace,
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				SnapshotScheduleLabel:          testSchedule,
+				SnapshotScheduleNamespaceLabel: testNamespace,
+			},
+		},
+		Spec: snapapi.VolumeSnapshotSpec{Source: snapapi.VolumeSnapshotSource{PersistentVolumeClaimName: ptr.To(pvcName)}},
+	}
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 30, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		spec              lvmv1alpha1.LVMSnapshotScheduleSpec
+		status            lvmv1alpha1.LVMSnapshotScheduleStatus
+		objs              []client.Object
+		expectedReason    string
+		expectedSnapshots []string
+		expectedNext      *time.Time
+		expectedLast      *time.Time
+		expectedRequeue   time.Duration
+	}{
+		{
+			name:              "new schedule is not due",
+			spec:              lvmv1alpha1.LVMSnapshotScheduleSpec{Schedule: "@hourly"},
+			expectedReason:    ReasonScheduled,
+			expectedSnapshots: []string{},
+			expectedNext:      ptr.To(time.Date(2024, time.March, 1, 13, 0, 0, 0, time.UTC)),
+			expectedRequeue:   statusRefreshInterval,
+		},
+		{
+			name:              "due schedule creates snapshots of thin volumes",
+			spec:              lvmv1alpha1.LVMSnapshotScheduleSpec{Schedule: "@hourly"},
+			status:            lvmv1alpha1.LVMSnapshotScheduleStatus{NextScheduleTime: ptr.To(metav1.NewTime(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)))},
+			expectedReason:    ReasonScheduled,
+			expectedSnapshots: []string{"thin-pvc-hourly-202403011200"},
+			expectedNext:      ptr.To(time.Date(2024, time.March, 1, 13, 0, 0, 0, time.UTC)),
+			expectedLast:      ptr.To(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)),
+			expectedRequeue:   statusRefreshInterval,
+		},
+		{
+			name:              "missed schedules create snapshots only once",
+			spec:              lvmv1alpha1.LVMSnapshotScheduleSpec{Schedule: "*/2 * * * *"},
+			status:            lvmv1alpha1.LVMSnapshotScheduleStatus{NextScheduleTime: ptr.To(metav1.NewTime(time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)))},
+			expectedReason:    ReasonScheduled,
+			expectedSnapshots: []string{"thin-pvc-hourly-202403011200"},
+			expectedNext:      ptr.To(time.Date(2024, time.March, 1, 12, 2, 0, 0, time.UTC)),
+			expectedLast:      ptr.To(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)),
+			expectedRequeue:   90 * time.Second,
+		},
+		{
+			name: "retention deletes snapshots exceeding max count and max age",
+			spec: lvmv1alpha1.LVMSnapshotScheduleSpec{
+				Schedule:  "@hourly",
+				Retention: &lvmv1alpha1.SnapshotRetention{MaxCount: ptr.To(int32(2)), MaxAge: &metav1.Duration{Duration: 3 * time.Hour}},
+			},
+			status: lvmv1alpha1.LVMSnapshotScheduleStatus{NextScheduleTime: ptr.To(metav1.NewTime(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)))},
+			objs: []client.Object{
+				testScheduledSnapshot("thin-pvc", now.Add(-time.Hour)),
+				testScheduledSnapshot("thin-pvc", now.Add(-2*time.Hour)),
+				testScheduledSnapshot("removed-pvc", now.Add(-2*time.Hour)),
+				testScheduledSnapshot("removed-pvc", now.Add(-4*time.Hour)),
+			},
+			expectedReason: ReasonScheduled,
+			expectedSnapshots: []string{
+				"removed-pvc-hourly-202403011000",
+				"thin-pvc-hourly-202403011100",
+				"thin-pvc-hourly-202403011200",
+			},
+			expectedNext:    ptr.To(time.Date(2024, time.March, 1, 13, 0, 0, 0, time.UTC)),
+			expectedLast:    ptr.To(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)),
+			expectedRequeue: statusRefreshInterval,
+		},
+		{
+			name:              "paused schedule does not create snapshots",
+			spec:              lvmv1alpha1.LVMSnapshotScheduleSpec{Schedule: "@hourly", Paused: true},
+			status:            lvmv1alpha1.LVMSnapshotScheduleStatus{NextScheduleTime: ptr.To(metav1.NewTime(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)))},
+			expectedReason:    ReasonPaused,
+			expectedSnapshots: []string{},
+		},
+		{
+			name:              "invalid schedule",
+			spec:              lvmv1alpha1.LVMSnapshotScheduleSpec{Schedule: "every hour"},
+			expectedReason:    ReasonInvalidSchedule,
+			expectedSnapshots: []string{},
+		},
+		{
+			name: "pvc selector",
+			spec: lvmv1alpha1.LVMSnapshotScheduleSpec{
+				Schedule:                      "@hourly",
+				PersistentVolumeClaimSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"backup": "false"}},
+			},
+			status:            lvmv1alpha1.LVMSnapshotScheduleStatus{NextScheduleTime: ptr.To(metav1.NewTime(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)))},
+			expectedReason:    ReasonScheduled,
+			expectedSnapshots: []string{},
+			expectedNext:      ptr.To(time.Date(2024, time.March, 1, 13, 0, 0, 0, time.UTC)),
+			expectedLast:      ptr.To(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)),
+			expectedRequeue:   statusRefreshInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			schedule := &lvmv1alpha1.LVMSnapshotSchedule{
+				ObjectMeta: metav1.ObjectMeta{Name: testSchedule, Namespace: testNamespace},
+				Spec:       tt.spec,
+				Status:     tt.status,
+			}
+			objs := append(testObjects(), tt.objs...)
+			objs = append(objs, schedule)
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(newScheme(t)).
+				WithObjects(objs...).
+				WithStatusSubresource(&lvmv1alpha1.LVMSnapshotSchedule{}).
+				Build()
+
+			r := NewReconciler(fakeClient, events.NewFakeRecorder(10))
+			r.now = func() time.Time { return now }
+
+			result, err := r.Reconcile(ctx, controllerruntime.Request{NamespacedName: client.ObjectKeyFromObject(schedule)})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRequeue, result.RequeueAfter)
+
+			updated := &lvmv1alpha1.LVMSnapshotSchedule{}
+			require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(schedule), updated))
+			condition := meta.FindStatusCondition(updated.Status.Conditions, lvmv1alpha1.SnapshotScheduleReady)
+			require.NotNil(t, condition)
+			assert.Equal(t, tt.expectedReason, condition.Reason)
+
+			if tt.expectedNext == nil {
+				assert.Nil(t, updated.Status.NextScheduleTime)
+			} else if assert.NotNil(t, updated.Status.NextScheduleTime) {
+				assert.True(t, tt.expectedNext.Equal(updated.Status.NextScheduleTime.Time), "next schedule %s", updated.Status.NextScheduleTime)
+			}
+			if tt.expectedLast == nil {
+				assert.Nil(t, updated.Status.LastScheduleTime)
+			} else if assert.NotNil(t, updated.Status.LastScheduleTime) {
+				assert.True(t, tt.expectedLast.Equal(updated.Status.LastScheduleTime.Time), "last schedule %s", updated.Status.LastScheduleTime)
+			}
+
+			snapshots := &snapapi.VolumeSnapshotList{}
+			require.NoError(t, fakeClient.List(ctx, snapshots))
+			names := []string{}
+			for _, snapshot := range snapshots.Items {
+				names = append(names, snapshot.Name)
+				assert.Equal(t, constants.VolumeSnapshotClassPrefix+"thin", ptr.Deref(snapshot.Spec.VolumeSnapshotClassName, constants.VolumeSnapshotClassPrefix+"thin"))
+			}
+			assert.ElementsMatch(t, tt.expectedSnapshots, names)
+
+			if tt.expectedReason == ReasonScheduled && tt.spec.PersistentVolumeClaimSelector == nil {
+				assert.Equal(t, int32(1), updated.Status.PersistentVolumeClaims)
+				assert.Equal(t, []lvmv1alpha1.SkippedPersistentVolumeClaim{
+					{Namespace: testNamespace, Name: "pending-pvc", Reason: SkipReasonNotBound, Message: "the PersistentVolumeClaim is not bound to a PersistentVolume"},
+					{Namespace: testNamespace, Name: "thick-pvc", Reason: SkipReasonThickDeviceClass, Message: "device class thick has no thin pool and no thickSnapshotConfig and does not support snapshots"},
+				}, updated.Status.Skipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,458 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	ControllerName = "lvms-lv-consistency"
+
+	// DefaultInterval is the interval in which the logical volumes of a volume group are checked.
+	DefaultInterval = 5 * time.Minute
+
+	// DefaultMinOrphanAge is the time for which a logical volume has to be found orphaned before it is deleted.
+	DefaultMinOrphanAge = 30 * time.Minute
+
+	// stuckFinalizerTimeout is the time after which a LogicalVolume pending deletion is reported as stuck.
+	stuckFinalizerTimeout = 10 * time.Minute
+
+	// maxReportedFindings limits the number of findings listed in the LVMVolumeGroupNodeStatus.
+	maxReportedFindings = 50
+)
+
+type (
+	EventReasonInfo  string
+	EventReasonError string
+)
+
+const (
+	EventReasonErrorInconsistentLogicalVolumes    EventReasonError = "InconsistentLogicalVolumes"
+	EventReasonErrorOrphanedLogicalVolumeNotFreed EventReasonError = "OrphanedLogicalVolumeNotFreed"
+	EventReasonOrphanedLogicalVolumeDeleted       EventReasonInfo  = "OrphanedLogicalVolumeDeleted"
+)
+
+// Reconciler periodically compares the TopoLVM LogicalVolumes of the node it runs on with the logical volumes
+// in the volume groups on the node and reports the inconsistencies in the LVMVolumeGroupNodeStatus.
+// Orphaned logical volumes are deleted if the OrphanedLogicalVolumePolicy of the volume group is Delete.
+type Reconciler struct {
+	client.Client
+	events.EventRecorder
+	lvm.LVM
+	NodeName string
+	Interval time.Duration
+	// MinOrphanAge is the time for which a logical volume has to be found orphaned before it is deleted.
+	MinOrphanAge time.Duration
+
+	// orphans contains the orphaned logical volumes found in the last check per volume group with the time
+	// they were first found. Orphans are only deleted if they were already found in the previous check and
+	// are older than MinOrphanAge, so that logical volumes that are just being provisioned by TopoLVM are never deleted.
+	orphans map[string]map[string]time.Time
+}
+
+// NewReconciler returns Reconciler.
+func NewReconciler(client client.Client, eventRecorder events.EventRecorder, lvm lvm.LVM, nodeName string) *Reconciler {
+	return &Reconciler{
+		Client:        client,
+		EventRecorder: eventRecorder,
+		LVM:           lvm,
+		NodeName:      nodeName,
+		Interval:      DefaultInterval,
+		MinOrphanAge:  DefaultMinOrphanAge,
+		orphans:       make(map[string]map[string]time.Time),
+	}
+}
+
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumegroups,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumegroupnodestatuses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumegroupnodestatuses/status,verbs=get;update
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;update;patch
+
+// Reconcile checks the consistency of the logical volumes in the volume group if it exists on this node.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	volumeGroup := &lvmv1alpha1.LVMVolumeGroup{}
+	if err := r.Get(ctx, req.NamespacedName, volumeGroup); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			r.forget(req.Name)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !volumeGroup.DeletionTimestamp.IsZero() {
+		r.forget(volumeGroup.GetName())
+		return ctrl.Result{}, nil
+	}
+
+	vgs, err := r.ListVGs(ctx, true)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list volume groups: %w", err)
+	}
+	if !containsVolumeGroup(vgs, volumeGroup.GetName()) {
+		logger.V(1).Info("volume group does not exist on this node, skipping consistency check")
+		r.forget(volumeGroup.GetName())
+		return ctrl.Result{RequeueAfter: r.Interval}, nil
+	}
+
+	lvReport, err := r.ListLVs(ctx, volumeGroup.GetName())
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list logical volumes in volume group %s: %w", volumeGroup.GetName(), err)
+	}
+	var lvs []lvm.LogicalVolume
+	for _, report := range lvReport.Report {
+		lvs = append(lvs, report.Lv...)
+	}
+
+	logicalVolumeList := &topolvmv1.LogicalVolumeList{}
+	if err := r.List(ctx, logicalVolumeList); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list TopoLVM LogicalVolumes: %w", err)
+	}
+	var logicalVolumes []topolvmv1.LogicalVolume
+	for _, logicalVolume := range logicalVolumeList.Items {
+		if logicalVolume.Spec.NodeName == r.NodeName && logicalVolume.Spec.DeviceClass == volumeGroup.GetName() {
+			logicalVolumes = append(logicalVolumes, logicalVolume)
+		}
+	}
+
+	now := time.Now()
+	findings, orphans, err := check(volumeGroup, lvs, logicalVolumes, now)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if volumeGroup.Spec.OrphanedLogicalVolumePolicy == lvmv1alpha1.OrphanedLogicalVolumePolicyDelete {
+		if len(logicalVolumes) == 0 {
+			// Without any LogicalVolume of the volume group on this node, every logical volume looks orphaned,
+			// which is more likely caused by lost or not yet restored LogicalVolumes than by actual orphans.
+			if len(orphans) > 0 {
+				logger.Info("skipping deletion of orphaned logical volumes as no LogicalVolume exists for the volume group on this node",
+					"orphans", len(orphans))
+			}
+		} else if findings, orphans, err = r.deleteOrphans(ctx, volumeGroup, findings, orphans, now); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	r.remember(volumeGroup.GetName(), orphans, now)
+
+	updateMetrics(r.NodeName, volumeGroup.GetName(), findings, orphans)
+
+	consistency := &lvmv1alpha1.LogicalVolumeConsistency{FindingCount: len(findings)}
+	if len(findings) > 0 {
+		consistency.Findings = findings[:min(len(findings), maxReportedFindings)]
+	}
+	updated, err := r.setConsistencyStatus(ctx, volumeGroup, consistency)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update logical volume consistency status: %w", err)
+	}
+	if updated && len(findings) > 0 {
+		r.Eventf(volumeGroup, nil, corev1.EventTypeWarning, string(EventReasonErrorInconsistentLogicalVolumes), "CheckLogicalVolumes",
+			fmt.Sprintf("found %d inconsistencies between LogicalVolumes and logical volumes on node %s", len(findings), r.NodeName))
+	}
+
+	return ctrl.Result{RequeueAfter: r.Interval}, nil
+}
+
+// check compares the logical volumes in the volume group with the LogicalVolumes of the device class.
+// It returns the inconsistencies found, as well as the orphaned logical volumes.
+func check(
+	volumeGroup *lvmv1alpha1.LVMVolumeGroup,
+	lvs []lvm.LogicalVolume,
+	logicalVolumes []topolvmv1.LogicalVolume,
+	now time.Time,
+) ([]lvmv1alpha1.LogicalVolumeFinding, []lvm.LogicalVolume, error) {
+	var findings []lvmv1alpha1.LogicalVolumeFinding
+	var orphans []lvm.LogicalVolume
+
+	// TopoLVM names the logical volume after the UID of the LogicalVolume and sets it as the VolumeID once it
+	// is provisioned, so both are considered references to avoid reporting volumes that are still being created.
+	referenced := make(map[string]*topolvmv1.LogicalVolume)
+	for i := range logicalVolumes {
+		if logicalVolumes[i].Status.VolumeID != "" {
+			referenced[logicalVolumes[i].Status.VolumeID] = &logicalVolumes[i]
+		}
+		referenced[string(logicalVolumes[i].GetUID())] = &logicalVolumes[i]
+	}
+
+	existing := make(map[string]struct{}, len(lvs))
+	for _, lv := range lvs {
+		existing[lv.Name] = struct{}{}
+
+		lvAttr, err := vgmanager.ParsedLvAttr(lv.LvAttr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not parse lv_attr from logical volume %s: %w", lv.Name, err)
+		}
+		if lvAttr.VolumeType == vgmanager.VolumeTypeThinPool ||
+			(volumeGroup.Spec.ThinPoolConfig != nil && lv.Name == volumeGroup.Spec.ThinPoolConfig.Name) {
+			continue
+		}
+
+		if logicalVolume, ok := referenced[lv.Name]; ok {
+			if logicalVolume.Status.CurrentSize == nil {
+				continue
+			}
+			size, err := strconv.ParseInt(lv.LvSize, 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not parse lv_size from logical volume %s: %w", lv.Name, err)
+			}
+			if size != logicalVolume.Status.CurrentSize.Value() {
+				findings = append(findings, lvmv1alpha1.LogicalVolumeFinding{
+					Type:          lvmv1alpha1.LogicalVolumeFindingSizeDrift,
+					Name:          lv.Name,
+					LogicalVolume: logicalVolume.GetName(),
+					Message: fmt.Sprintf("logical volume has a size of %d bytes, but the LogicalVolume reports %d bytes",
+						size, logicalVolume.Status.CurrentSize.Value()),
+				})
+			}
+			continue
+		}
+
+		if isSnapshotArtifact(lv.Name, lvAttr) {
+			findings = append(findings, lvmv1alpha1.LogicalVolumeFinding{
+				Type:    lvmv1alpha1.LogicalVolumeFindingSnapshotArtifact,
+				Name:    lv.Name,
+				Message: "snapshot logical volume is not referenced by any LogicalVolume",
+			})
+			continue
+		}
+
+		// logical volumes not named by TopoLVM were created manually and are not managed by LVMS
+		if !lvm.IsTopoLVMVolumeName(lv.Name) {
+			continue
+		}
+
+		findings = append(findings, lvmv1alpha1.LogicalVolumeFinding{
+			Type:    lvmv1alpha1.LogicalVolumeFindingOrphaned,
+			Name:    lv.Name,
+			Message: fmt.Sprintf("logical volume with a size of %s bytes is not referenced by any LogicalVolume", lv.LvSize),
+		})
+		orphans = append(orphans, lv)
+	}
+
+	for _, logicalVolume := range logicalVolumes {
+		if !logicalVolume.DeletionTimestamp.IsZero() {
+			if len(logicalVolume.GetFinalizers()) > 0 && now.Sub(logicalVolume.DeletionTimestamp.Time) > stuckFinalizerTimeout {
+				findings = append(findings, lvmv1alpha1.LogicalVolumeFinding{
+					Type:          lvmv1alpha1.LogicalVolumeFindingStuckFinalizer,
+					Name:          logicalVolume.Status.VolumeID,
+					LogicalVolume: logicalVolume.GetName(),
+					Message: fmt.Sprintf("LogicalVolume is pending deletion since %s with finalizers %v",
+						logicalVolume.DeletionTimestamp.UTC().Format(time.RFC3339), logicalVolume.GetFinalizers()),
+				})
+			}
+			continue
+		}
+		if logicalVolume.Status.VolumeID == "" || logicalVolume.GetAnnotations()[topolvm.GetLVPendingDeletionKey()] == "true" {
+			continue
+		}
+		if _, ok := existing[logicalVolume.Status.VolumeID]; !ok {
+			findings = append(findings, lvmv1alpha1.LogicalVolumeFinding{
+				Type:          lvmv1alpha1.LogicalVolumeFindingMissing,
+				Name:          logicalVolume.Status.VolumeID,
+				LogicalVolume: logicalVolume.GetName(),
+				Message:       "logical volume referenced by the LogicalVolume does not exist in the volume group",
+			})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Type != findings[j].Type {
+			return findings[i].Type < findings[j].Type
+		}
+		return findings[i].Name < findings[j].Name
+	})
+
+	return findings, orphans, nil
+}
+
+// isSnapshotArtifact checks if the logical volume is a thick snapshot or a leftover of a snapshot operation.
+func isSnapshotArtifact(lvName string, lvAttr vgmanager.LvAttr) bool {
+	return lvAttr.VolumeType == vgmanager.VolumeTypeSnapshot ||
+		lvAttr.VolumeType == vgmanager.VolumeTypeMergingSnapshot ||
+		strings.HasSuffix(lvName, "-real") ||
+		strings.HasSuffix(lvName, "-cow")
+}
+
+// deleteOrphans deletes the orphaned logical volumes that were already found in the previous check,
+// were first found at least MinOrphanAge ago and are not open.
+// Nothing is deleted while the node is in maintenance or the LVMCluster is paused.
+func (r *Reconciler) deleteOrphans(
+	ctx context.Context,
+	volumeGroup *lvmv1alpha1.LVMVolumeGroup,
+	findings []lvmv1alpha1.LogicalVolumeFinding,
+	orphans []lvm.LogicalVolume,
+	now time.Time,
+) ([]lvmv1alpha1.LogicalVolumeFinding, []lvm.LogicalVolume, error) {
+	logger := log.FromContext(ctx)
+
+	if len(orphans) == 0 {
+		return findings, orphans, nil
+	}
+
+	paused, err := r.isPaused(ctx, volumeGroup)
+	if err != nil {
+		return nil, nil, err
+	}
+	if paused {
+		logger.Info("skipping deletion of orphaned logical volumes as reconciliation is paused")
+		return findings, orphans, nil
+	}
+
+	deleted := make(map[string]struct{})
+	var remaining []lvm.LogicalVolume
+	for _, orphan := range orphans {
+		if firstSeen, seen := r.orphans[volumeGroup.GetName()][orphan.Name]; !seen || now.Sub(firstSeen) < r.MinOrphanAge {
+			remaining = append(remaining, orphan)
+			continue
+		}
+		if lvAttr, err := vgmanager.ParsedLvAttr(orphan.LvAttr); err == nil && lvAttr.Open == vgmanager.OpenTrue {
+			logger.Info("orphaned logical volume is open and is not deleted", "LV", orphan.Name)
+			remaining = append(remaining, orphan)
+			continue
+		}
+
+		if err := r.DeleteLV(ctx, orphan.Name, volumeGroup.GetName()); err != nil {
+			r.Eventf(volumeGroup, nil, corev1.EventTypeWarning, string(EventReasonErrorOrphanedLogicalVolumeNotFreed), "DeleteOrphanedLogicalVolume",
+				fmt.Sprintf("failed to delete orphaned logical volume %s on node %s: %v", orphan.Name, r.NodeName, err))
+			remaining = append(remaining, orphan)
+			continue
+		}
+
+		logger.Info("deleted orphaned logical volume", "LV", orphan.Name, "size", orphan.LvSize)
+		r.Eventf(volumeGroup, nil, corev1.EventTypeNormal, string(EventReasonOrphanedLogicalVolumeDeleted), "DeleteOrphanedLogicalVolume",
+			fmt.Sprintf("deleted orphaned logical volume %s with a size of %s bytes on node %s", orphan.Name, orphan.LvSize, r.NodeName))
+		orphanedLogicalVolumesDeleted.WithLabelValues(r.NodeName, volumeGroup.GetName()).Inc()
+		deleted[orphan.Name] = struct{}{}
+	}
+
+	var remainingFindings []lvmv1alpha1.LogicalVolumeFinding
+	for _, finding := range findings {
+		if _, ok := deleted[finding.Name]; ok && finding.Type == lvmv1alpha1.LogicalVolumeFindingOrphaned {
+			continue
+		}
+		remainingFindings = append(remainingFindings, finding)
+	}
+
+	return remainingFindings, remaining, nil
+}
+
+// isPaused checks if the node is in maintenance or the LVMCluster controlling the volume group is paused.
+func (r *Reconciler) isPaused(ctx context.Context, volumeGroup *lvmv1alpha1.LVMVolumeGroup) (bool, error) {
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: r.NodeName}, node); err != nil {
+		return false, fmt.Errorf("failed to get node %s: %w", r.NodeName, err)
+	}
+	if node.GetAnnotations()[constants.MaintenanceAnnotation] == "true" {
+		return true, nil
+	}
+
+	owner := metav1.GetControllerOf(volumeGroup)
+	if owner == nil || owner.Kind != "LVMCluster" {
+		return false, nil
+	}
+	lvmCluster := &lvmv1alpha1.LVMCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: volumeGroup.GetNamespace()}, lvmCluster); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return lvmCluster.Spec.Paused, nil
+}
+
+// setConsistencyStatus sets the consistency in the status of the volume group in the LVMVolumeGroupNodeStatus.
+// The status of the volume group is owned by the vgmanager, so nothing is done if it does not exist yet.
+func (r *Reconciler) setConsistencyStatus(
+	ctx context.Context,
+	volumeGroup *lvmv1alpha1.LVMVolumeGroup,
+	consistency *lvmv1alpha1.LogicalVolumeConsistency,
+) (bool, error) {
+	nodeStatus := &lvmv1alpha1.LVMVolumeGroupNodeStatus{}
+	if err := r.Get(ctx, types.NamespacedName{Name: r.NodeName, Namespace: volumeGroup.GetNamespace()}, nodeStatus); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	vgStatus := nodeStatus.VolumeGroupStatus(volumeGroup.GetName())
+	if vgStatus == nil || equality.Semantic.DeepEqual(vgStatus.LogicalVolumeConsistency, consistency) {
+		return false, nil
+	}
+	vgStatus.LogicalVolumeConsistency = consistency
+	if err := r.Status().Update(ctx, nodeStatus); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *Reconciler) remember(vgName string, orphans []lvm.LogicalVolume, now time.Time) {
+	firstSeen := make(map[string]time.Time, len(orphans))
+	for _, orphan := range orphans {
+		if previous, ok := r.orphans[vgName][orphan.Name]; ok {
+			firstSeen[orphan.Name] = previous
+		} else {
+			firstSeen[orphan.Name] = now
+		}
+	}
+	r.orphans[vgName] = firstSeen
+}
+
+func (r *Reconciler) forget(vgName string) {
+	delete(r.orphans, vgName)
+	deleteMetrics(r.NodeName, vgName)
+}
+
+func containsVolumeGroup(vgs []lvm.VolumeGroup, name string) bool {
+	for _, vg := range vgs {
+		if vg.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&lvmv1alpha1.LVMVolumeGroup{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controller.Options{SkipNameValidation: ptr.To(true), MaxConcurrentReconciles: 1}).
+		Named("lvms_lv_consistency").
+		Complete(r)
+}
@@ -0,0 +1,259 @@
+package consistency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	lvmmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"k8s.io/client-go/tools/events"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	testNode      = "test-node"
+	testNamespace = "openshift-lvm-storage"
+	testVG        = "vg1"
+	referencedLV  = "0d2b1c3e-6a1f-4e4c-8d43-2b9a7a3c1f10"
+	driftedLV     = "1e3c2d4f-7b2a-4f5d-9e54-3cab8b4d2a21"
+	missingLV     = "2f4d3e5a-8c3b-4a6e-8f65-4dbc9c5e3b32"
+	orphanedLV    = "7e6f4f7c-2f5c-4a0d-9a0e-5f3c2b1d0e9a"
+	openOrphanLV  = "9c1f0e2d-3b4a-4c5d-8e6f-7a8b9c0d1e2f"
+	creatingLVUID = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, topolvmv1.AddToScheme(scheme))
+	require.NoError(t, lvmv1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+func logicalVolume(name, uid, volumeID string, size int64) topolvmv1.LogicalVolume {
+	logicalVolume := topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(uid)},
+		Spec:       topolvmv1.LogicalVolumeSpec{NodeName: testNode, DeviceClass: testVG},
+		Status:     topolvmv1.LogicalVolumeStatus{VolumeID: volumeID},
+	}
+	if size > 0 {
+		logicalVolume.Status.CurrentSize = resource.NewQuantity(size, resource.BinarySI)
+	}
+	return logicalVolume
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	volumeGroup := &lvmv1alpha1.LVMVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: testVG},
+		Spec:       lvmv1alpha1.LVMVolumeGroupSpec{ThinPoolConfig: &lvmv1alpha1.ThinPoolConfig{Name: "thin-pool-1"}},
+	}
+
+	stuck := logicalVolume("pvc-stuck", "b2c3d4e5-f6a7-4b8c-9d0e-1f2a3b4c5d6e", "", 0)
+	stuck.DeletionTimestamp = &metav1.Time{Time: now.Add(-time.Hour)}
+	stuck.Finalizers = []string{"topolvm.io/logicalvolume"}
+
+	deleting := logicalVolume("pvc-deleting", "c3d4e5f6-a7b8-4c9d-8e1f-2a3b4c5d6e7f", "", 0)
+	deleting.DeletionTimestamp = &metav1.Time{Time: now.Add(-time.Minute)}
+	deleting.Finalizers = []string{"topolvm.io/logicalvolume"}
+
+	logicalVolumes := []topolvmv1.LogicalVolume{
+		logicalVolume("pvc-referenced", referencedLV, referencedLV, 1073741824),
+		logicalVolume("pvc-drifted", driftedLV, driftedLV, 1073741824),
+		logicalVolume("pvc-missing", missingLV, missingLV, 1073741824),
+		logicalVolume("pvc-creating", creatingLVUID, "", 0),
+		stuck,
+		deleting,
+	}
+	lvs := []lvm.LogicalVolume{
+		{Name: "thin-pool-1", VgName: testVG, LvAttr: "twi-a-tz--", LvSize: "10737418240"},
+		{Name: referencedLV, VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-aotz--", LvSize: "1073741824"},
+		{Name: driftedLV, VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-aotz--", LvSize: "2147483648"},
+		{Name: creatingLVUID, VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-a-tz--", LvSize: "1073741824"},
+		{Name: orphanedLV, VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-a-tz--", LvSize: "1073741824"},
+		{Name: "snap-leftover", VgName: testVG, LvAttr: "swi-a-s---", LvSize: "1073741824"},
+		{Name: "origin-real", VgName: testVG, LvAttr: "-wi-a-----", LvSize: "1073741824"},
+		{Name: "manually-created", VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-a-tz--", LvSize: "1073741824"},
+	}
+
+	findings, orphans, err := check(volumeGroup, lvs, logicalVolumes, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, []lvmv1alpha1.LogicalVolumeFinding{
+		{Type: lvmv1alpha1.LogicalVolumeFindingMissing, Name: missingLV, LogicalVolume: "pvc-missing",
+			Message: "logical volume referenced by the LogicalVolume does not exist in the volume group"},
+		{Type: lvmv1alpha1.LogicalVolumeFindingOrphaned, Name: orphanedLV,
+			Message: "logical volume with a size of 1073741824 bytes is not referenced by any LogicalVolume"},
+		{Type: lvmv1alpha1.LogicalVolumeFindingSizeDrift, Name: driftedLV, LogicalVolume: "pvc-drifted",
+			Message: "logical volume has a size of 2147483648 bytes, but the LogicalVolume reports 1073741824 bytes"},
+		{Type: lvmv1alpha1.LogicalVolumeFindingSnapshotArtifact, Name: "origin-real",
+			Message: "snapshot logical volume is not referenced by any LogicalVolume"},
+		{Type: lvmv1alpha1.LogicalVolumeFindingSnapshotArtifact, Name: "snap-leftover",
+			Message: "snapshot logical volume is not referenced by any LogicalVolume"},
+		{Type: lvmv1alpha1.LogicalVolumeFindingStuckFinalizer, Name: "", LogicalVolume: "pvc-stuck",
+			Message: "LogicalVolume is pending deletion since " + stuck.DeletionTimestamp.UTC().Format(time.RFC3339) +
+				" with finalizers [topolvm.io/logicalvolume]"},
+	}, findings)
+
+	require.Len(t, orphans, 1)
+	assert.Equal(t, orphanedLV, orphans[0].Name)
+}
+
+func TestReconcileDeletesOrphans(t *testing.T) {
+	ctx := context.Background()
+
+	volumeGroup := &lvmv1alpha1.LVMVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: testVG, Namespace: testNamespace},
+		Spec: lvmv1alpha1.LVMVolumeGroupSpec{
+			ThinPoolConfig:              &lvmv1alpha1.ThinPoolConfig{Name: "thin-pool-1"},
+			OrphanedLogicalVolumePolicy: lvmv1alpha1.OrphanedLogicalVolumePolicyDelete,
+		},
+	}
+	nodeStatus := &lvmv1alpha1.LVMVolumeGroupNodeStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: testNode, Namespace: testNamespace},
+		Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+			VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
+				{VGStatus: lvmv1alpha1.VGStatus{Name: testVG, Status: lvmv1alpha1.VGStatusReady}},
+			},
+		},
+	}
+	referenced := logicalVolume("pvc-referenced", referencedLV, referencedLV, 1073741824)
+
+	clnt := fake.NewClientBuilder().
+		WithScheme(newScheme(t)).
+		WithObjects(
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode}},
+			volumeGroup,
+			nodeStatus,
+			&referenced,
+		).
+		WithStatusSubresource(nodeStatus).
+		Build()
+
+	lvReport := &lvm.LVReport{Report: []lvm.LVReportItem{{Lv: []lvm.LogicalVolume{
+		{Name: "thin-pool-1", VgName: testVG, LvAttr: "twi-a-tz--", LvSize: "10737418240"},
+		{Name: referencedLV, VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-aotz--", LvSize: "1073741824"},
+		{Name: orphanedLV, VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-a-tz--", LvSize: "1073741824"},
+		{Name: openOrphanLV, VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-aotz--", LvSize: "1073741824"},
+	}}}}
+
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListVGs(mock.Anything, true).Return([]lvm.VolumeGroup{{Name: testVG}}, nil).Times(3)
+	mockLVM.EXPECT().ListLVs(mock.Anything, testVG).Return(lvReport, nil).Times(3)
+
+	r := NewReconciler(clnt, events.NewFakeRecorder(10), mockLVM, testNode)
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(volumeGroup)}
+
+	// orphans are not deleted before they were found in two consecutive checks and for at least MinOrphanAge
+	for range 2 {
+		res, err := r.Reconcile(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultInterval, res.RequeueAfter)
+	}
+
+	require.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus))
+	consistency := nodeStatus.Status.VolumeGroups[0].LogicalVolumeConsistency
+	require.NotNil(t, consistency, "findings should be reported in the node status")
+	assert.Equal(t, 2, consistency.FindingCount)
+
+	// open logical volumes are never deleted
+	for name := range r.orphans[testVG] {
+		r.orphans[testVG][name] = time.Now().Add(-DefaultMinOrphanAge)
+	}
+	mockLVM.EXPECT().DeleteLV(mock.Anything, orphanedLV, testVG).Return(nil).Once()
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	require.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus))
+	consistency = nodeStatus.Status.VolumeGroups[0].LogicalVolumeConsistency
+	require.NotNil(t, consistency)
+	assert.Equal(t, 1, consistency.FindingCount)
+	assert.Equal(t, openOrphanLV, consistency.Findings[0].Name)
+}
+
+func TestReconcileSkipsDeletionWhilePaused(t *testing.T) {
+	ctx := context.Background()
+
+	volumeGroup := &lvmv1alpha1.LVMVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: testVG, Namespace: testNamespace},
+		Spec: lvmv1alpha1.LVMVolumeGroupSpec{
+			OrphanedLogicalVolumePolicy: lvmv1alpha1.OrphanedLogicalVolumePolicyDelete,
+		},
+	}
+	referenced := logicalVolume("pvc-referenced", referencedLV, referencedLV, 1073741824)
+	clnt := fake.NewClientBuilder().
+		WithScheme(newScheme(t)).
+		WithObjects(
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode, Annotations: map[string]string{
+				constants.MaintenanceAnnotation: "true",
+			}}},
+			volumeGroup,
+			&referenced,
+		).
+		Build()
+
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListVGs(mock.Anything, true).Return([]lvm.VolumeGroup{{Name: testVG}}, nil).Times(2)
+	mockLVM.EXPECT().ListLVs(mock.Anything, testVG).Return(&lvm.LVReport{Report: []lvm.LVReportItem{{Lv: []lvm.LogicalVolume{
+		{Name: orphanedLV, VgName: testVG, LvAttr: "-wi-a-----", LvSize: "1073741824"},
+	}}}}, nil).Times(2)
+
+	r := NewReconciler(clnt, events.NewFakeRecorder(10), mockLVM, testNode)
+	r.Interval = time.Minute
+	r.MinOrphanAge = 0
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(volumeGroup)}
+
+	for range 2 {
+		res, err := r.Reconcile(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, res.RequeueAfter)
+	}
+}
+
+func TestReconcileSkipsDeletionWithoutLogicalVolumes(t *testing.T) {
+	ctx := context.Background()
+
+	volumeGroup := &lvmv1alpha1.LVMVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: testVG, Namespace: testNamespace},
+		Spec: lvmv1alpha1.LVMVolumeGroupSpec{
+			OrphanedLogicalVolumePolicy: lvmv1alpha1.OrphanedLogicalVolumePolicyDelete,
+		},
+	}
+	clnt := fake.NewClientBuilder().
+		WithScheme(newScheme(t)).
+		WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode}}, volumeGroup).
+		Build()
+
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListVGs(mock.Anything, true).Return([]lvm.VolumeGroup{{Name: testVG}}, nil).Times(2)
+	mockLVM.EXPECT().ListLVs(mock.Anything, testVG).Return(&lvm.LVReport{Report: []lvm.LVReportItem{{Lv: []lvm.LogicalVolume{
+		{Name: orphanedLV, VgName: testVG, LvAttr: "-wi-a-----", LvSize: "1073741824"},
+	}}}}, nil).Times(2)
+
+	r := NewReconciler(clnt, events.NewFakeRecorder(10), mockLVM, testNode)
+	r.MinOrphanAge = 0
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(volumeGroup)}
+
+	// no DeleteLV is expected by the mock, as every logical volume looks orphaned without any LogicalVolume
+	for range 2 {
+		_, err := r.Reconcile(ctx, req)
+		require.NoError(t, err)
+	}
+	assert.Contains(t, r.orphans[testVG], orphanedLV, "the orphan should still be reported")
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consistency
+
+import (
+	"strconv"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	logicalVolumeFindings = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "lvms_logical_volume_consistency_findings",
+			Help: "Number of inconsistencies between TopoLVM LogicalVolumes and the logical volumes in a device class by type.",
+		},
+		[]string{"node", "device_class", "type"},
+	)
+
+	orphanedLogicalVolumeBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "lvms_orphaned_logical_volume_bytes",
+			Help: "Total size in bytes of the logical volumes in a device class that are not referenced by any LogicalVolume. For thin volumes this is the virtual size.",
+		},
+		[]string{"node", "device_class"},
+	)
+
+	orphanedLogicalVolumesDeleted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "lvms_orphaned_logical_volumes_deleted_total",
+			Help: "Total number of orphaned logical volumes deleted in a device class.",
+		},
+		[]string{"node", "device_class"},
+	)
+)
+
+var findingTypes = []lvmv1alpha1.LogicalVolumeFindingType{
+	lvmv1alpha1.LogicalVolumeFindingOrphaned,
+	lvmv1alpha1.LogicalVolumeFindingMissing,
+	lvmv1alpha1.LogicalVolumeFindingSizeDrift,
+	lvmv1alpha1.LogicalVolumeFindingSnapshotArtifact,
+	lvmv1alpha1.LogicalVolumeFindingStuckFinalizer,
+}
+
+// Metrics returns the Prometheus collectors for the logical volume consistency check.
+func Metrics() []prometheus.Collector {
+	return []prometheus.Collector{
+		logicalVolumeFindings,
+		orphanedLogicalVolumeBytes,
+		orphanedLogicalVolumesDeleted,
+	}
+}
+
+// updateMetrics sets the consistency gauges for a device class on a node.
+func updateMetrics(nodeName, deviceClassName string, findings []lvmv1alpha1.LogicalVolumeFinding, orphans []lvm.LogicalVolume) {
+	counts := make(map[lvmv1alpha1.LogicalVolumeFindingType]int)
+	for _, finding := range findings {
+		counts[finding.Type]++
+	}
+	for _, findingType := range findingTypes {
+		logicalVolumeFindings.WithLabelValues(nodeName, deviceClassName, string(findingType)).Set(float64(counts[findingType]))
+	}
+
+	var orphanedBytes int64
+	for _, orphan := range orphans {
+		if size, err := strconv.ParseInt(orphan.LvSize, 10, 64); err == nil {
+			orphanedBytes += size
+		}
+	}
+	orphanedLogicalVolumeBytes.WithLabelValues(nodeName, deviceClassName).Set(float64(orphanedBytes))
+}
+
+// deleteMetrics removes all consistency metric series for a device class on a node.
+func deleteMetrics(nodeName, deviceClassName string) {
+	labels := prometheus.Labels{"node": nodeName, "device_class": deviceClassName}
+	logicalVolumeFindings.DeletePartialMatch(labels)
+	orphanedLogicalVolumeBytes.DeletePartialMatch(labels)
+	orphanedLogicalVolumesDeleted.DeletePartialMatch(labels)
+}
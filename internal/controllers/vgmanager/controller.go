@@ -18,9 +18,12 @@ package vgmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -44,13 +47,17 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	corev1helper "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -78,21 +85,41 @@ const (
 	EventReasonErrorDevicePathCheckFailed        EventReasonError = "DevicePathCheckFailed"
 	EventReasonErrorRAIDHealthCheckFailed        EventReasonError = "RAIDHealthCheckFailed"
 	EventReasonErrorDeviceRemovalFailed          EventReasonError = "DeviceRemovalFailed"
+	EventReasonErrorLVMDConfigReadFailed         EventReasonError = "LVMDConfigReadFailed"
+	EventReasonErrorLVMDConfigWriteFailed        EventReasonError = "LVMDConfigWriteFailed"
+	EventReasonErrorThinPoolDeletionFailed       EventReasonError = "ThinPoolDeletionFailed"
+	EventReasonErrorVGDeletionFailed             EventReasonError = "VGDeletionFailed"
 	EventReasonLVMDConfigMissing                 EventReasonInfo  = "LVMDConfigMissing"
 	EventReasonLVMDConfigUpdated                 EventReasonInfo  = "LVMDConfigUpdated"
 	EventReasonLVMDConfigDeleted                 EventReasonInfo  = "LVMDConfigDeleted"
 	EventReasonVolumeGroupReady                  EventReasonInfo  = "VolumeGroupReady"
+	EventReasonVolumeGroupPaused                 EventReasonInfo  = "VolumeGroupPaused"
+	EventReasonVolumeGroupLeaving                EventReasonInfo  = "VolumeGroupLeaving"
+	EventReasonVolumeGroupRetained               EventReasonInfo  = "VolumeGroupRetained"
 	EventReasonDeviceRemoved                     EventReasonInfo  = "DeviceRemoved"
 	EventReasonErrorManualCleanupRequired        EventReasonError = "ManualCleanupRequired"
 )
 
 var reconcileAgain = ctrl.Result{Requeue: true, RequeueAfter: reconcileInterval}
 
+// ErrThinPoolMetadataFull is returned when the metadata of a thin pool is filled over metadataWarningPercentage.
+var ErrThinPoolMetadataFull = errors.New("thin pool metadata is full")
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&lvmv1alpha1.LVMVolumeGroup{}).
 		Owns(&lvmv1alpha1.LVMVolumeGroupNodeStatus{}, builder.MatchEveryOwner, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.getVolumeGroupsForNode),
+			builder.WithPredicates(r.nodeChangedPredicate()),
+		).
+		Watches(
+			&lvmv1alpha1.LVMCluster{},
+			handler.EnqueueRequestsFromMapFunc(r.getVolumeGroupsForLVMCluster),
+			builder.WithPredicates(pausedChangedPredicate()),
+		).
 		WithOptions(controller.Options{SkipNameValidation: ptr.To(true)}).
 		Complete(r)
 }
@@ -112,6 +139,69 @@ type Reconciler struct {
 	SymlinkResolveFn symlinkResolver.ResolveFn
 }
 
+// nodeChangedPredicate filters for changes of the maintenance annotation or the labels on this node.
+// Label changes can make the node join or leave volume groups.
+func (r *Reconciler) nodeChangedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectNew.GetName() == r.NodeName &&
+				(isMaintenanceEnabled(e.ObjectOld) != isMaintenanceEnabled(e.ObjectNew) ||
+					!maps.Equal(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()))
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
+
+// getVolumeGroupsForNode enqueues all LVMVolumeGroups so that a change of the maintenance state or the labels
+// of the node is picked up immediately.
+func (r *Reconciler) getVolumeGroupsForNode(ctx context.Context, _ client.Object) []reconcile.Request {
+	volumeGroups := &lvmv1alpha1.LVMVolumeGroupList{}
+	if err := r.List(ctx, volumeGroups, client.InNamespace(r.Namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "getVolumeGroupsForNode: Failed to get LVMVolumeGroup objs")
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0, len(volumeGroups.Items))
+	for _, volumeGroup := range volumeGroups.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&volumeGroup)})
+	}
+	return requests
+}
+
+// pausedChangedPredicate filters for changes of spec.paused on the LVMCluster.
+func pausedChangedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCluster, okOld := e.ObjectOld.(*lvmv1alpha1.LVMCluster)
+			newCluster, okNew := e.ObjectNew.(*lvmv1alpha1.LVMCluster)
+			return okOld && okNew && oldCluster.Spec.Paused != newCluster.Spec.Paused
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
+
+// getVolumeGroupsForLVMCluster enqueues all LVMVolumeGroups controlled by the LVMCluster so that pausing or
+// unpausing the LVMCluster is picked up immediately.
+func (r *Reconciler) getVolumeGroupsForLVMCluster(ctx context.Context, obj client.Object) []reconcile.Request {
+	volumeGroups := &lvmv1alpha1.LVMVolumeGroupList{}
+	if err := r.List(ctx, volumeGroups, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "getVolumeGroupsForLVMCluster: Failed to get LVMVolumeGroup objs")
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for _, volumeGroup := range volumeGroups.Items {
+		if v1.IsControlledBy(&volumeGroup, obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&volumeGroup)})
+		}
+	}
+	return requests
+}
+
 func (r *Reconciler) getFinalizer() string {
 	return fmt.Sprintf("%s/%s", NodeCleanupFinalizer, r.NodeName)
 }
@@ -134,9 +224,31 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, fmt.Errorf("failed to match nodeSelector to node labels: %w", err)
 	}
 	if !nodeMatches {
-		// Nothing to be done on this node for the VG.
-		logger.Info("node labels do not match the selector", "VGName", volumeGroup.Name)
-		return ctrl.Result{}, nil
+		if !controllerutil.ContainsFinalizer(volumeGroup, r.getFinalizer()) {
+			// Nothing to be done on this node for the VG.
+			logger.Info("node labels do not match the selector", "VGName", volumeGroup.Name)
+			return ctrl.Result{}, nil
+		}
+		// The VG was set up on this node before, but the node is no longer selected.
+		return r.processNodeLeave(ctx, volumeGroup)
+	}
+
+	// Check if the LVMCluster of the VG tolerates the taints on this node before setting up the VG for the first time.
+	// A VG that was set up on this node already is still reconciled and cleaned up after the node was tainted.
+	if !controllerutil.ContainsFinalizer(volumeGroup, r.getFinalizer()) {
+		tolerated, err := r.toleratesThisNode(ctx, volumeGroup)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to match tolerations to node taints: %w", err)
+		}
+		if !tolerated {
+			logger.Info("node taints are not tolerated by the LVMCluster", "VGName", volumeGroup.Name)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Use the node override of the VG matching this node, if any
+	if err := r.applyNodeOverride(ctx, volumeGroup); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to apply node override: %w", err)
 	}
 
 	nodeStatus := r.getLVMVolumeGroupNodeStatus()
@@ -144,9 +256,50 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, fmt.Errorf("could not get LVMVolumeGroupNodeStatus: %w", err)
 	}
 
+	inMaintenance, err := r.isNodeInMaintenance(ctx)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to check maintenance state of node: %w", err)
+	}
+	if inMaintenance {
+		return r.reconcilePaused(ctx, volumeGroup,
+			fmt.Sprintf("node is in maintenance (%s=true)", constants.MaintenanceAnnotation))
+	}
+
+	paused, err := r.isLVMClusterPaused(ctx, volumeGroup)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to check if LVMCluster is paused: %w", err)
+	}
+	if paused {
+		return r.reconcilePaused(ctx, volumeGroup, "LVMCluster is paused")
+	}
+
 	return r.reconcile(ctx, volumeGroup, resolver)
 }
 
+// reconcilePaused only reports the state of the volume group while the node is in maintenance or the LVMCluster is paused.
+// None of the operations modifying the node (wiping, creating, extending or reducing volume groups,
+// extending thin pools, or editing the lvmd config) are run, so that manual changes are not raced.
+// This includes the cleanup of deleted volume groups, which resumes once the maintenance has ended.
+func (r *Reconciler) reconcilePaused(ctx context.Context, volumeGroup *lvmv1alpha1.LVMVolumeGroup, reason string) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("skipping volume group operations", "reason", reason)
+
+	msg := fmt.Sprintf("%s, volume group operations are paused", reason)
+
+	vgs, err := r.ListVGs(ctx, true)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list volume groups: %w", err)
+	}
+
+	if updated, err := r.setVolumeGroupPausedStatus(ctx, volumeGroup, vgs, msg); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to set status for volume group %s to paused: %w", volumeGroup.Name, err)
+	} else if updated {
+		r.NormalEvent(ctx, volumeGroup, EventReasonVolumeGroupPaused, msg)
+	}
+
+	return ctrl.Result{RequeueAfter: reconcileInterval}, nil
+}
+
 func (r *Reconciler) reconcile(
 	ctx context.Context,
 	volumeGroup *lvmv1alpha1.LVMVolumeGroup,
@@ -157,9 +310,10 @@ func (r *Reconciler) reconcile(
 	if !volumeGroup.DeletionTimestamp.IsZero() {
 		return ctrl.Result{}, r.processDelete(ctx, volumeGroup)
 	} else {
+		base := volumeGroup.DeepCopy()
 		if added := controllerutil.AddFinalizer(volumeGroup, r.getFinalizer()); added {
 			logger.Info("adding finalizer")
-			return ctrl.Result{}, r.Update(ctx, volumeGroup)
+			return ctrl.Result{}, r.patchVolumeGroup(ctx, volumeGroup, base)
 		}
 	}
 
@@ -175,7 +329,7 @@ func (r *Reconciler) reconcile(
 
 	if err := r.checkRAIDVGHealth(vgs, volumeGroup); err != nil {
 		r.WarningEvent(ctx, volumeGroup, EventReasonErrorRAIDHealthCheckFailed, err)
-		if _, statusErr := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, FilteredBlockDevices{}, err); statusErr != nil {
+		if _, statusErr := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, FilteredBlockDevices{}, EventReasonErrorRAIDHealthCheckFailed, err); statusErr != nil {
 			logger.Error(statusErr, "failed to set status to failed")
 		}
 		return ctrl.Result{RequeueAfter: raidReconcileInterval}, nil
@@ -183,10 +337,11 @@ func (r *Reconciler) reconcile(
 
 	logger.V(1).Info("block devices", "blockDevices", blockDevices)
 
+	base := volumeGroup.DeepCopy()
 	if updated, err := r.wipeDevices(ctx, volumeGroup, blockDevices, resolver); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to wipe devices: %w", err)
 	} else if updated {
-		return ctrl.Result{}, r.Update(ctx, volumeGroup)
+		return ctrl.Result{}, r.patchVolumeGroup(ctx, volumeGroup, base)
 	}
 
 	pvs, err := r.ListPVs(ctx, "")
@@ -209,7 +364,7 @@ func (r *Reconciler) reconcile(
 	if volumeGroup.Spec.DeviceSelector != nil {
 		if err := VerifyMandatoryDevicePaths(devices, resolver, volumeGroup.Spec.DeviceSelector.Paths); err != nil {
 			r.WarningEvent(ctx, volumeGroup, EventReasonErrorDevicePathCheckFailed, err)
-			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, err); err != nil {
+			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, EventReasonErrorDevicePathCheckFailed, err); err != nil {
 				logger.Error(err, "failed to set status to failed")
 			}
 			return ctrl.Result{}, err
@@ -260,7 +415,7 @@ func (r *Reconciler) reconcile(
 			err := fmt.Errorf("the volume group %s does not exist (or was not tagged properly with %q), "+
 				"and there were no available devices to create it", volumeGroup.GetName(), lvm.DefaultTag)
 			r.WarningEvent(ctx, volumeGroup, EventReasonErrorNoAvailableDevicesForVG, err)
-			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, err); err != nil {
+			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, EventReasonErrorNoAvailableDevicesForVG, err); err != nil {
 				logger.Error(err, "failed to set status to failed")
 			}
 			return ctrl.Result{}, err
@@ -268,7 +423,7 @@ func (r *Reconciler) reconcile(
 
 		deleted, err := r.deleteRemovedDevices(ctx, lvmVG, volumeGroup, resolver)
 		if err != nil {
-			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, err); err != nil {
+			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, EventReasonErrorDeviceRemovalFailed, err); err != nil {
 				logger.Error(err, "failed to set status to failed")
 			}
 			return ctrl.Result{}, fmt.Errorf("failed to remove devices: %w", err)
@@ -290,7 +445,7 @@ func (r *Reconciler) reconcile(
 			if err := r.validateLVs(ctx, volumeGroup); err != nil {
 				err := fmt.Errorf("error while validating logical volumes in existing volume group: %w", err)
 				r.WarningEvent(ctx, volumeGroup, EventReasonErrorInconsistentLVs, err)
-				if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, err); err != nil {
+				if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, EventReasonErrorInconsistentLVs, err); err != nil {
 					logger.Error(err, "failed to set status to failed")
 				}
 				return ctrl.Result{}, err
@@ -331,7 +486,7 @@ func (r *Reconciler) reconcile(
 		}
 		if err := validateRAIDDeviceCount(volumeGroup.Spec.RAIDConfig, totalDevices); err != nil {
 			r.WarningEvent(ctx, volumeGroup, EventReasonErrorNoAvailableDevicesForVG, err)
-			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, err); err != nil {
+			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, EventReasonErrorNoAvailableDevicesForVG, err); err != nil {
 				logger.Error(err, "failed to set status to failed")
 			}
 			return ctrl.Result{}, err
@@ -342,7 +497,7 @@ func (r *Reconciler) reconcile(
 	if err = r.addDevicesToVG(ctx, vgs, volumeGroup.Name, devices.Available, r.shouldWipeDevicesOnVolumeGroup(volumeGroup)); err != nil {
 		err = fmt.Errorf("failed to create/extend volume group %s: %w", volumeGroup.Name, err)
 		r.WarningEvent(ctx, volumeGroup, EventReasonErrorVGCreateOrExtendFailed, err)
-		if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, err); err != nil {
+		if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, EventReasonErrorVGCreateOrExtendFailed, err); err != nil {
 			logger.Error(err, "failed to set status to failed")
 		}
 		return ctrl.Result{}, err
@@ -353,7 +508,7 @@ func (r *Reconciler) reconcile(
 		if err = r.addThinPoolToVG(ctx, volumeGroup.Name, volumeGroup.Spec.ThinPoolConfig); err != nil {
 			err := fmt.Errorf("failed to create thin pool %s for volume group %s: %w", volumeGroup.Spec.ThinPoolConfig.Name, volumeGroup.Name, err)
 			r.WarningEvent(ctx, volumeGroup, EventReasonErrorThinPoolCreateOrExtendFailed, err)
-			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, err); err != nil {
+			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, EventReasonErrorThinPoolCreateOrExtendFailed, err); err != nil {
 				logger.Error(err, "failed to set status to failed")
 			}
 			return ctrl.Result{}, err
@@ -362,7 +517,7 @@ func (r *Reconciler) reconcile(
 		if err := r.validateLVs(ctx, volumeGroup); err != nil {
 			err := fmt.Errorf("error while validating logical volumes in existing volume group: %w", err)
 			r.WarningEvent(ctx, volumeGroup, EventReasonErrorInconsistentLVs, err)
-			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, err); err != nil {
+			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, EventReasonErrorInconsistentLVs, err); err != nil {
 				logger.Error(err, "failed to set status to failed")
 			}
 			return ctrl.Result{}, err
@@ -428,7 +583,7 @@ func (r *Reconciler) applyLVMDConfig(ctx context.Context, volumeGroup *lvmv1alph
 	lvmdConfig, err := r.LVMD.Load(ctx)
 	if err != nil {
 		err = fmt.Errorf("failed to read the lvmd config file: %w", err)
-		if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, err); err != nil {
+		if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, EventReasonErrorLVMDConfigReadFailed, err); err != nil {
 			logger.Error(err, "failed to set status to failed")
 		}
 		return err
@@ -481,7 +636,7 @@ func (r *Reconciler) applyLVMDConfig(ctx context.Context, volumeGroup *lvmv1alph
 	}
 
 	if err := r.updateLVMDConfigAfterReconcile(ctx, volumeGroup, oldConfig, lvmdConfig, lvmdConfigWasMissing); err != nil {
-		if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, err); err != nil {
+		if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, devices, EventReasonErrorLVMDConfigWriteFailed, err); err != nil {
 			logger.Error(err, "failed to set status to failed")
 		}
 		return err
@@ -535,32 +690,141 @@ func (r *Reconciler) isRetainPolicy(ctx context.Context, volumeGroup *lvmv1alpha
 	return false, nil
 }
 
-func (r *Reconciler) processDelete(ctx context.Context, volumeGroup *lvmv1alpha1.LVMVolumeGroup) error {
+// processNodeLeave tears down the volume group on a node that is no longer selected by the node selector of the
+// volume group, either because the node selector or the labels of the node were changed.
+// With the Retain policy the volume group is kept with its logical volumes and only removed from the lvmd config.
+// With the Remove policy the volume group is deleted, but only once it contains no logical volumes anymore.
+func (r *Reconciler) processNodeLeave(ctx context.Context, volumeGroup *lvmv1alpha1.LVMVolumeGroup) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("VGName", volumeGroup.Name)
+
+	inMaintenance, err := r.isNodeInMaintenance(ctx)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to check maintenance state of node: %w", err)
+	}
+	paused, err := r.isLVMClusterPaused(ctx, volumeGroup)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to check if LVMCluster is paused: %w", err)
+	}
+	if inMaintenance || paused {
+		logger.Info("node is no longer selected, but volume group operations are paused")
+		return ctrl.Result{RequeueAfter: reconcileInterval}, nil
+	}
+
+	if volumeGroup.Spec.NodeRemovalPolicy == lvmv1alpha1.NodeRemovalPolicyRemove {
+		vgs, err := r.ListVGs(ctx, true)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to list volume groups: %w", err)
+		}
+		if slices.ContainsFunc(vgs, func(vg lvm.VolumeGroup) bool { return vg.Name == volumeGroup.Name }) {
+			userLVs, err := r.listUserLVs(ctx, volumeGroup)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if len(userLVs) > 0 {
+				msg := fmt.Sprintf("node is no longer selected, waiting for logical volumes %v to be removed before removing the volume group", userLVs)
+				logger.Info(msg)
+				if updated, err := r.setVolumeGroupLeavingStatus(ctx, volumeGroup, vgs, msg); err != nil {
+					return ctrl.Result{}, fmt.Errorf("failed to set status for volume group %s to leaving: %w", volumeGroup.Name, err)
+				} else if updated {
+					r.NormalEvent(ctx, volumeGroup, EventReasonVolumeGroupLeaving, msg)
+				}
+				return ctrl.Result{RequeueAfter: reconcileInterval}, nil
+			}
+		}
+		logger.Info("node is no longer selected, removing the volume group")
+		if err := r.processDelete(ctx, volumeGroup); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to remove volume group from node that is no longer selected: %w", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	logger.Info("node is no longer selected, retaining the volume group and no longer managing it")
+	if err := r.removeFromLVMDConfig(ctx, volumeGroup); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.removeVolumeGroupStatus(ctx, volumeGroup); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to remove status for volume group %s: %w", volumeGroup.Name, err)
+	}
+	r.NormalEvent(ctx, volumeGroup, EventReasonVolumeGroupRetained,
+		"node is no longer selected, the volume group was retained and is no longer managed")
+
+	base := volumeGroup.DeepCopy()
+	if removed := controllerutil.RemoveFinalizer(volumeGroup, r.getFinalizer()); removed {
+		logger.Info("removing finalizer")
+		return ctrl.Result{}, r.patchVolumeGroup(ctx, volumeGroup, base)
+	}
+	return ctrl.Result{}, nil
+}
+
+// removeFromLVMDConfig removes the device class of the volume group from the lvmd config.
+// If it was the last device class, the lvmd config is deleted.
+func (r *Reconciler) removeFromLVMDConfig(ctx context.Context, volumeGroup *lvmv1alpha1.LVMVolumeGroup) error {
 	logger := log.FromContext(ctx).WithValues("VGName", volumeGroup.Name)
-	logger.Info("deleting")
 
-	// Read the lvmd config file
 	lvmdConfig, err := r.LVMD.Load(ctx)
 	if err != nil {
 		// Failed to read lvmdconfig file. Reconcile again
 		return fmt.Errorf("failed to read the lvmd config file: %w", err)
 	}
 	if lvmdConfig == nil {
+		// if there was no config file in the first place, nothing has to be removed.
 		logger.Info("lvmd config file does not exist, assuming deleted")
+		return nil
+	}
+
+	found := false
+	for i, deviceClass := range lvmdConfig.DeviceClasses {
+		if deviceClass.Name == volumeGroup.Name {
+			// Remove this vg from the lvmdconf file
+			lvmdConfig.DeviceClasses = append(lvmdConfig.DeviceClasses[:i], lvmdConfig.DeviceClasses[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if !found {
+		logger.Info("could not find volume group in lvmd deviceclasses list, assuming deleted")
+	}
+
+	// we either need to update the config if there are still deviceClasses remaining
+	// or delete it, if we are dealing with the last deviceClass that is about to be removed.
+	if len(lvmdConfig.DeviceClasses) > 0 {
+		if err = r.LVMD.Save(ctx, lvmdConfig); err != nil {
+			return fmt.Errorf("failed to update lvmd.conf file for volume group %s: %w", volumeGroup.GetName(), err)
+		}
+		msg := "updated lvmd config after deviceClass was removed"
+		logger.Info(msg)
+		r.NormalEvent(ctx, volumeGroup, EventReasonLVMDConfigUpdated, msg)
 	} else {
-		found := false
-		for i, deviceClass := range lvmdConfig.DeviceClasses {
-			if deviceClass.Name == volumeGroup.Name {
-				// Remove this vg from the lvmdconf file
-				lvmdConfig.DeviceClasses = append(lvmdConfig.DeviceClasses[:i], lvmdConfig.DeviceClasses[i+1:]...)
-				found = true
-				break
-			}
+		if err = r.LVMD.Delete(ctx); err != nil {
+			return fmt.Errorf("failed to delete lvmd.conf file for volume group %s: %w", volumeGroup.GetName(), err)
 		}
-		if !found {
-			logger.Info("could not find volume group in lvmd deviceclasses list, assuming deleted")
+		msg := "removed lvmd config after last deviceClass was removed"
+		logger.Info(msg)
+		r.NormalEvent(ctx, volumeGroup, EventReasonLVMDConfigDeleted, msg)
+	}
+	return nil
+}
+
+// listUserLVs lists the logical volumes in the volume group that hold user data, which excludes the thin pool.
+func (r *Reconciler) listUserLVs(ctx context.Context, volumeGroup *lvmv1alpha1.LVMVolumeGroup) ([]string, error) {
+	lvs, err := r.ListLVsByName(ctx, volumeGroup.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LVs in volume group %s: %w", volumeGroup.Name, err)
+	}
+	// Filter out the LVMS-managed thin pool — it is not user data
+	var userLVs []string
+	for _, lv := range lvs {
+		if volumeGroup.Spec.ThinPoolConfig != nil && lv == volumeGroup.Spec.ThinPoolConfig.Name {
+			continue
 		}
+		userLVs = append(userLVs, lv)
 	}
+	return userLVs, nil
+}
+
+func (r *Reconciler) processDelete(ctx context.Context, volumeGroup *lvmv1alpha1.LVMVolumeGroup) error {
+	logger := log.FromContext(ctx).WithValues("VGName", volumeGroup.Name)
+	logger.Info("deleting")
 
 	// Check if volume group exists
 	vgs, err := r.ListVGs(ctx, true)
@@ -586,17 +850,9 @@ func (r *Reconciler) processDelete(ctx context.Context, volumeGroup *lvmv1alpha1
 		}
 
 		if retain {
-			lvs, err := r.ListLVsByName(ctx, volumeGroup.Name)
+			userLVs, err := r.listUserLVs(ctx, volumeGroup)
 			if err != nil {
-				return fmt.Errorf("failed to list LVs in volume group %s: %w", volumeGroup.Name, err)
-			}
-			// Filter out the LVMS-managed thin pool — it is not user data
-			var userLVs []string
-			for _, lv := range lvs {
-				if volumeGroup.Spec.ThinPoolConfig != nil && lv == volumeGroup.Spec.ThinPoolConfig.Name {
-					continue
-				}
-				userLVs = append(userLVs, lv)
+				return err
 			}
 			if len(userLVs) > 0 {
 				err := fmt.Errorf("volume group %s has retained logical volumes %v; manual cleanup required before deletion can proceed", volumeGroup.Name, userLVs)
@@ -622,7 +878,7 @@ func (r *Reconciler) processDelete(ctx context.Context, volumeGroup *lvmv1alpha1
 			if thinPoolExists {
 				if err := r.DeleteLV(ctx, thinPoolName, volumeGroup.Name); err != nil {
 					err := fmt.Errorf("failed to delete thin pool %s in volume group %s: %w", thinPoolName, volumeGroup.Name, err)
-					if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, FilteredBlockDevices{}, err); err != nil {
+					if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, FilteredBlockDevices{}, EventReasonErrorThinPoolDeletionFailed, err); err != nil {
 						logger.Error(err, "failed to set status to failed")
 					}
 					return err
@@ -635,7 +891,7 @@ func (r *Reconciler) processDelete(ctx context.Context, volumeGroup *lvmv1alpha1
 
 		if err = r.DeleteVG(ctx, existingVG); err != nil {
 			err := fmt.Errorf("failed to delete volume group %s: %w", volumeGroup.Name, err)
-			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, FilteredBlockDevices{}, err); err != nil {
+			if _, err := r.setVolumeGroupFailedStatus(ctx, volumeGroup, vgs, FilteredBlockDevices{}, EventReasonErrorVGDeletionFailed, err); err != nil {
 				logger.Error(err, "failed to set status to failed", "VGName", volumeGroup.GetName())
 			}
 			return err
@@ -643,34 +899,18 @@ func (r *Reconciler) processDelete(ctx context.Context, volumeGroup *lvmv1alpha1
 		logger.Info("volume group deleted")
 	}
 
-	// in case we have an existing LVMDConfig, we either need to update it if there are still deviceClasses remaining
-	// or delete it, if we are dealing with the last deviceClass that is about to be removed.
-	// if there was no config file in the first place, nothing has to be removed.
-	if lvmdConfig != nil {
-		if len(lvmdConfig.DeviceClasses) > 0 {
-			if err = r.LVMD.Save(ctx, lvmdConfig); err != nil {
-				return fmt.Errorf("failed to update lvmd.conf file for volume group %s: %w", volumeGroup.GetName(), err)
-			}
-			msg := "updated lvmd config after deviceClass was removed"
-			logger.Info(msg)
-			r.NormalEvent(ctx, volumeGroup, EventReasonLVMDConfigUpdated, msg)
-		} else {
-			if err = r.LVMD.Delete(ctx); err != nil {
-				return fmt.Errorf("failed to delete lvmd.conf file for volume group %s: %w", volumeGroup.GetName(), err)
-			}
-			msg := "removed lvmd config after last deviceClass was removed"
-			logger.Info(msg)
-			r.NormalEvent(ctx, volumeGroup, EventReasonLVMDConfigDeleted, msg)
-		}
+	if err := r.removeFromLVMDConfig(ctx, volumeGroup); err != nil {
+		return err
 	}
 
 	if err := r.removeVolumeGroupStatus(ctx, volumeGroup); err != nil {
 		return fmt.Errorf("failed to remove status for volume group %s: %w", volumeGroup.Name, err)
 	}
 
+	base := volumeGroup.DeepCopy()
 	if removed := controllerutil.RemoveFinalizer(volumeGroup, r.getFinalizer()); removed {
 		logger.Info("removing finalizer")
-		return r.Update(ctx, volumeGroup)
+		return r.patchVolumeGroup(ctx, volumeGroup, base)
 	}
 	return nil
 }
@@ -729,8 +969,8 @@ func (r *Reconciler) validateLVs(ctx context.Context, volumeGroup *lvmv1alpha1.L
 				return fmt.Errorf("could not ensure metadata percentage of LV due to a parsing error: %w", err)
 			}
 			if metadataPercentage > metadataWarningPercentage {
-				return fmt.Errorf("metadata partition is over %v percent filled and LVM Metadata Overflows cannot be recovered"+
-					"you should manually extend the metadata_partition or you will risk data loss: metadata_percent: %v", metadataPercentage, lv.MetadataPercent)
+				return fmt.Errorf("%w: metadata partition is over %v percent filled and LVM Metadata Overflows cannot be recovered"+
+					"you should manually extend the metadata_partition or you will risk data loss: metadata_percent: %v", ErrThinPoolMetadataFull, metadataPercentage, lv.MetadataPercent)
 			}
 
 			if err := verifyChunkSizeForPolicy(volumeGroup.Spec.ThinPoolConfig, lv); err != nil {
@@ -994,6 +1234,95 @@ func (r *Reconciler) matchesThisNode(ctx context.Context, selector *corev1.NodeS
 	return matches, err
 }
 
+// applyNodeOverride replaces the device selector and the thin pool size of the volume group with the ones of the
+// first node override matching this node. The spec is only changed in memory, which is why changes to the volume group
+// have to be written with patchVolumeGroup.
+func (r *Reconciler) applyNodeOverride(ctx context.Context, volumeGroup *lvmv1alpha1.LVMVolumeGroup) error {
+	for _, override := range volumeGroup.Spec.NodeOverrides {
+		matches, err := r.matchesThisNode(ctx, override.NodeSelector)
+		if err != nil {
+			return fmt.Errorf("failed to match nodeSelector of node override to node labels: %w", err)
+		}
+		if !matches {
+			continue
+		}
+		if override.DeviceSelector != nil {
+			volumeGroup.Spec.DeviceSelector = override.DeviceSelector.DeepCopy()
+		}
+		if override.ThinPoolSizePercent != nil && volumeGroup.Spec.ThinPoolConfig != nil {
+			volumeGroup.Spec.ThinPoolConfig.SizePercent = *override.ThinPoolSizePercent
+		}
+		return nil
+	}
+	return nil
+}
+
+// patchVolumeGroup writes the metadata changes of the volume group compared to base.
+// As base carries the same spec, a node override applied to the spec is never written back.
+func (r *Reconciler) patchVolumeGroup(ctx context.Context, volumeGroup, base *lvmv1alpha1.LVMVolumeGroup) error {
+	return r.Patch(ctx, volumeGroup, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+}
+
+// toleratesThisNode checks if the LVMCluster controlling the volume group tolerates the taints of this node.
+// The vg-manager runs with the tolerations of all LVMClusters, so it can be scheduled on nodes that only
+// some of them are allowed on. Taints for node conditions are ignored as every DaemonSet tolerates them,
+// and PreferNoSchedule taints are ignored as they do not prevent scheduling on the node.
+func (r *Reconciler) toleratesThisNode(ctx context.Context, volumeGroup *lvmv1alpha1.LVMVolumeGroup) (bool, error) {
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: r.NodeName}, node); err != nil {
+		return false, err
+	}
+	if len(node.Spec.Taints) == 0 {
+		return true, nil
+	}
+
+	owner := v1.GetControllerOf(volumeGroup)
+	if owner == nil || owner.Kind != "LVMCluster" {
+		return true, nil
+	}
+	lvmCluster := &lvmv1alpha1.LVMCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: volumeGroup.GetNamespace()}, lvmCluster); err != nil {
+		// without the LVMCluster the volume group can only be cleaned up
+		return true, client.IgnoreNotFound(err)
+	}
+
+	for _, taint := range node.Spec.Taints {
+		if strings.HasPrefix(taint.Key, "node.kubernetes.io/") || taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !corev1helper.TolerationsTolerateTaint(klog.Background(), lvmCluster.Spec.Tolerations, &taint, true) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// isNodeInMaintenance checks whether the node was put into maintenance with the maintenance annotation.
+func (r *Reconciler) isNodeInMaintenance(ctx context.Context) (bool, error) {
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: r.NodeName}, node); err != nil {
+		return false, err
+	}
+	return isMaintenanceEnabled(node), nil
+}
+
+func isMaintenanceEnabled(obj client.Object) bool {
+	return obj.GetAnnotations()[constants.MaintenanceAnnotation] == "true"
+}
+
+// isLVMClusterPaused checks if the LVMCluster controlling the volume group has set spec.paused.
+func (r *Reconciler) isLVMClusterPaused(ctx context.Context, volumeGroup *lvmv1alpha1.LVMVolumeGroup) (bool, error) {
+	owner := v1.GetControllerOf(volumeGroup)
+	if owner == nil || owner.Kind != "LVMCluster" {
+		return false, nil
+	}
+	lvmCluster := &lvmv1alpha1.LVMCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: volumeGroup.GetNamespace()}, lvmCluster); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return lvmCluster.Spec.Paused, nil
+}
+
 // WarningEvent sends an event to both the nodeStatus, and the affected processed volumeGroup as well as the owning LVMCluster if present
 func (r *Reconciler) WarningEvent(ctx context.Context, obj *lvmv1alpha1.LVMVolumeGroup, reason EventReasonError, errMsg error) {
 	nodeStatus := &lvmv1alpha1.LVMVolumeGroupNodeStatus{}
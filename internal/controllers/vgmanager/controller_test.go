@@ -17,6 +17,7 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	secv1 "github.com/openshift/api/security/v1"
 	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/filter"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lsblk"
 	lsblkmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lsblk/mocks"
@@ -27,6 +28,8 @@ import (
 	wipefsmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/wipefs/mocks"
 	"github.com/stretchr/testify/mock"
 	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
@@ -40,6 +43,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -100,6 +105,10 @@ var _ = Describe("vgmanager controller", func() {
 			It("should handle thin pool creation correctly", testThinPoolCreation)
 			It("should handle thin pool extension cases correctly", testThinPoolExtension)
 			It("should handle metadata size extension correctly", testMetadataSizeExtension)
+			It("should pause reconciliation when node is in maintenance", testMaintenance)
+			It("should pause reconciliation when LVMCluster is paused", testLVMClusterPaused)
+			It("should skip volume groups of LVMClusters that do not tolerate the node", testUntoleratedNodeTaint)
+			It("should use the node override matching the node without writing it back", testNodeOverride)
 		})
 		Context("event tests", func() {
 			It("should correctly emit events", testEvents)
@@ -322,6 +331,75 @@ var _ = Describe("vgmanager controller", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+	Context("node no longer selected by the volume group", func() {
+		leavingVolumeGroup := func(ctx context.Context, instances testInstances, policy lvmv1alpha1.NodeRemovalPolicy) *lvmv1alpha1.LVMVolumeGroup {
+			vg := &lvmv1alpha1.LVMVolumeGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "vg1",
+					Namespace:  instances.namespace.Name,
+					Finalizers: []string{instances.Reconciler.getFinalizer()},
+				},
+				Spec: lvmv1alpha1.LVMVolumeGroupSpec{
+					NodeSelector: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      "hardware",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"nvme"},
+						}},
+					}}},
+					NodeRemovalPolicy: policy,
+				},
+			}
+			Expect(instances.client.Create(ctx, vg)).To(Succeed())
+			return vg
+		}
+
+		It("should retain the volume group and stop managing it with the Retain policy", func(ctx SpecContext) {
+			logCtx := log.IntoContext(ctx, zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+			instances := setupInstances()
+			vg := leavingVolumeGroup(ctx, instances, lvmv1alpha1.NodeRemovalPolicyRetain)
+
+			res, err := instances.Reconciler.Reconcile(logCtx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vg)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(reconcile.Result{}))
+
+			Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(vg), vg)).To(Succeed())
+			Expect(vg.GetFinalizers()).To(BeEmpty(), "should release the volume group on this node")
+		})
+
+		It("should remove the volume group once it has no logical volumes with the Remove policy", func(ctx SpecContext) {
+			logCtx := log.IntoContext(ctx, zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+			instances := setupInstances()
+			vg := leavingVolumeGroup(ctx, instances, lvmv1alpha1.NodeRemovalPolicyRemove)
+
+			existingVG := lvm.VolumeGroup{Name: "vg1", PVs: []lvm.PhysicalVolume{{PvName: "/dev/sda", VgName: "vg1"}}}
+
+			By("waiting for the logical volumes to be removed")
+			instances.LVM.EXPECT().ListVGs(mock.Anything, true).Return([]lvm.VolumeGroup{existingVG}, nil).Once()
+			instances.LVM.EXPECT().ListLVsByName(mock.Anything, "vg1").Return([]string{"user-data-lv"}, nil).Once()
+			res, err := instances.Reconciler.Reconcile(logCtx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vg)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(Equal(reconcileInterval))
+
+			nodeStatus := instances.Reconciler.getLVMVolumeGroupNodeStatus()
+			Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
+			Expect(nodeStatus.Status.VolumeGroups).To(HaveLen(1))
+			Expect(nodeStatus.Status.VolumeGroups[0].Transition).To(Equal(lvmv1alpha1.VGTransitionLeaving))
+			Expect(nodeStatus.Status.VolumeGroups[0].Reason).To(ContainSubstring("user-data-lv"))
+
+			By("removing the volume group without logical volumes")
+			instances.LVM.EXPECT().ListVGs(mock.Anything, true).Return([]lvm.VolumeGroup{existingVG}, nil).Twice()
+			instances.LVM.EXPECT().ListLVsByName(mock.Anything, "vg1").Return([]string{}, nil).Once()
+			instances.LVM.EXPECT().DeleteVG(mock.Anything, existingVG).Return(nil).Once()
+			_, err = instances.Reconciler.Reconcile(logCtx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vg)})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(vg), vg)).To(Succeed())
+			Expect(vg.GetFinalizers()).To(BeEmpty())
+			Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
+			Expect(nodeStatus.Status.VolumeGroups).To(BeEmpty())
+		})
+	})
 })
 
 func init() {
@@ -383,6 +461,7 @@ func setupInstances() testInstances {
 
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).
 		WithObjects(node, namespace).
+		WithStatusSubresource(&lvmv1alpha1.LVMVolumeGroupNodeStatus{}).
 		Build()
 	fakeRecorder := events.NewFakeRecorder(100)
 
@@ -479,7 +558,7 @@ func testVGWithLocalDevice(ctx context.Context, vgTemplate lvmv1alpha1.LVMVolume
 		nodeStatus.SetName(instances.node.GetName())
 		nodeStatus.SetNamespace(instances.namespace.GetName())
 		Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
-		Expect(nodeStatus.Spec.LVMVGStatus).To(BeEmpty())
+		Expect(nodeStatus.Status.VolumeGroups).To(BeEmpty())
 	})
 
 	checkDistributedEvent := func(eventType, msg string) {
@@ -509,12 +588,18 @@ func testVGWithLocalDevice(ctx context.Context, vgTemplate lvmv1alpha1.LVMVolume
 
 	By("ensuring the VGStatus was set to progressing after picking up new devices", func() {
 		Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
-		Expect(nodeStatus.Spec.LVMVGStatus).ToNot(BeEmpty())
-		Expect(nodeStatus.Spec.LVMVGStatus).To(ContainElement(lvmv1alpha1.VGStatus{
+		Expect(nodeStatus.Status.VolumeGroups).ToNot(BeEmpty())
+		Expect(nodeStatus.Status.VolumeGroups).To(ContainElement(HaveField("VGStatus", lvmv1alpha1.VGStatus{
 			Name:                  vg.GetName(),
 			Status:                lvmv1alpha1.VGStatusProgressing,
 			DeviceDiscoveryPolicy: lvmv1alpha1.DeviceDiscoveryPolicyPreconfigured,
-		}))
+		})))
+		Expect(nodeStatus.Status.VolumeGroups).To(HaveLen(1))
+		Expect(nodeStatus.Status.VolumeGroups[0].Transition).To(Equal(lvmv1alpha1.VGTransitionJoining))
+		conditions := nodeStatus.Status.VolumeGroups[0].Conditions
+		Expect(meta.IsStatusConditionFalse(conditions, lvmv1alpha1.VGConditionDevicesReady)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(conditions, lvmv1alpha1.VGConditionReady)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(nodeStatus.Status.Conditions, lvmv1alpha1.VGConditionReconciling)).To(BeTrue())
 	})
 
 	// Requeue effects
@@ -620,13 +705,23 @@ func testVGWithLocalDevice(ctx context.Context, vgTemplate lvmv1alpha1.LVMVolume
 	By("verifying the VGStatus is now ready", func() {
 		checkDistributedEvent(corev1.EventTypeNormal, "all the available devices are attached to the volume group")
 		Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
-		Expect(nodeStatus.Spec.LVMVGStatus).ToNot(BeEmpty())
-		Expect(nodeStatus.Spec.LVMVGStatus).To(ContainElement(lvmv1alpha1.VGStatus{
+		Expect(nodeStatus.Status.VolumeGroups).ToNot(BeEmpty())
+		Expect(nodeStatus.Status.VolumeGroups).To(ContainElement(HaveField("VGStatus", lvmv1alpha1.VGStatus{
 			Name:                  vg.GetName(),
 			Status:                lvmv1alpha1.VGStatusReady,
 			Devices:               []string{device.Unresolved()},
 			DeviceDiscoveryPolicy: lvmv1alpha1.DeviceDiscoveryPolicyPreconfigured,
-		}))
+		})))
+		Expect(nodeStatus.Status.VolumeGroups).To(HaveLen(1))
+		conditions := nodeStatus.Status.VolumeGroups[0].Conditions
+		Expect(meta.IsStatusConditionTrue(conditions, lvmv1alpha1.VGConditionReady)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(conditions, lvmv1alpha1.VGConditionDevicesReady)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(conditions, lvmv1alpha1.VGConditionLVMDConfigured)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(conditions, lvmv1alpha1.VGConditionThinPoolHealthy)).To(Equal(vg.Spec.ThinPoolConfig != nil))
+		Expect(meta.IsStatusConditionTrue(nodeStatus.Status.Conditions, lvmv1alpha1.VGConditionReady)).To(BeTrue())
+		Expect(nodeStatus.Status.VolumeGroups[0].AppliedGeneration).To(Equal(vg.GetGeneration()))
+		Expect(nodeStatus.Spec.LVMVGStatus).To(ConsistOf(nodeStatus.Status.VolumeGroups[0].VGStatus))
+		Expect(meta.IsStatusConditionFalse(nodeStatus.Status.Conditions, lvmv1alpha1.VGConditionReconciling)).To(BeTrue())
 		oldReadyGeneration = nodeStatus.GetGeneration()
 	})
 
@@ -683,7 +778,7 @@ func testVGWithLocalDevice(ctx context.Context, vgTemplate lvmv1alpha1.LVMVolume
 
 	By("verifying the state did not change", func() {
 		Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
-		Expect(nodeStatus.Spec.LVMVGStatus).ToNot(BeEmpty())
+		Expect(nodeStatus.Status.VolumeGroups).ToNot(BeEmpty())
 		var excluded []lvmv1alpha1.ExcludedDevice
 		if vg.Spec.ThinPoolConfig != nil {
 			excluded = append(excluded, []lvmv1alpha1.ExcludedDevice{
@@ -711,13 +806,13 @@ func testVGWithLocalDevice(ctx context.Context, vgTemplate lvmv1alpha1.LVMVolume
 				},
 			}...)
 		}
-		Expect(nodeStatus.Spec.LVMVGStatus).To(ContainElement(lvmv1alpha1.VGStatus{
+		Expect(nodeStatus.Status.VolumeGroups).To(ContainElement(HaveField("VGStatus", lvmv1alpha1.VGStatus{
 			Name:                  vg.GetName(),
 			Status:                lvmv1alpha1.VGStatusReady,
 			Devices:               []string{device.Unresolved()},
 			Excluded:              excluded,
 			DeviceDiscoveryPolicy: lvmv1alpha1.DeviceDiscoveryPolicyPreconfigured,
-		}))
+		})))
 		Expect(oldReadyGeneration).To(Equal(nodeStatus.GetGeneration()))
 	})
 
@@ -906,7 +1001,8 @@ func testEvents(ctx context.Context) {
 	gvk, _ := apiutil.GVKForObject(nodeStatus, scheme.Scheme)
 	nodeStatus.SetGroupVersionKind(gvk)
 
-	clnt := fake.NewClientBuilder().WithObjects(vg, nodeStatus).WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+	clnt := fake.NewClientBuilder().WithObjects(vg, nodeStatus).WithScheme(scheme.Scheme).
+		WithStatusSubresource(&lvmv1alpha1.LVMVolumeGroupNodeStatus{}).WithInterceptorFuncs(interceptor.Funcs{
 		Get: func(ctx context.Context, client client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
 			err := client.Get(ctx, key, obj, opts...)
 			if err == nil {
@@ -948,7 +1044,8 @@ func testLVMD(ctx context.Context) {
 	vg := &lvmv1alpha1.LVMVolumeGroup{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
 	devices := FilteredBlockDevices{}
 
-	r.Client = fake.NewClientBuilder().WithObjects(vg).WithScheme(scheme.Scheme).Build()
+	r.Client = fake.NewClientBuilder().WithObjects(vg).WithScheme(scheme.Scheme).
+		WithStatusSubresource(&lvmv1alpha1.LVMVolumeGroupNodeStatus{}).Build()
 	mockLVMD := lvmdmocks.NewMockConfigurator(GinkgoT())
 	r.LVMD = mockLVMD
 	mockLVM := lvmmocks.NewMockLVM(GinkgoT())
@@ -1163,8 +1260,8 @@ func testReconcileFailure(ctx context.Context) {
 			Namespace: instances.namespace.GetName(),
 			Name:      instances.node.GetName(),
 		}, nodeStatus)).To(Succeed())
-		Expect(nodeStatus.Spec.LVMVGStatus).To(HaveLen(1))
-		Expect(nodeStatus.Spec.LVMVGStatus[0].Status).To(Equal(lvmv1alpha1.VGStatusFailed))
+		Expect(nodeStatus.Status.VolumeGroups).To(HaveLen(1))
+		Expect(nodeStatus.Status.VolumeGroups[0].Status).To(Equal(lvmv1alpha1.VGStatusFailed))
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(And(
 			ContainSubstring(fmt.Sprintf("mandatory device path %q cannot be used", "/dev/sda")),
@@ -1241,7 +1338,8 @@ func testLVMDConfigChange(ctx context.Context) {
 		},
 	}
 
-	r.Client = fake.NewClientBuilder().WithObjects(vg).WithScheme(scheme.Scheme).Build()
+	r.Client = fake.NewClientBuilder().WithObjects(vg).WithScheme(scheme.Scheme).
+		WithStatusSubresource(&lvmv1alpha1.LVMVolumeGroupNodeStatus{}).Build()
 
 	err := r.applyLVMDConfig(ctx, vg, nil, FilteredBlockDevices{})
 	Expect(err).NotTo(HaveOccurred())
@@ -1389,9 +1487,9 @@ func testStaticModeExcludesNewDevices(ctx context.Context) {
 
 	By("verifying the VG status shows excluded device with static reason")
 	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
-	Expect(nodeStatus.Spec.LVMVGStatus).ToNot(BeEmpty())
+	Expect(nodeStatus.Status.VolumeGroups).ToNot(BeEmpty())
 	found := false
-	for _, status := range nodeStatus.Spec.LVMVGStatus {
+	for _, status := range nodeStatus.Status.VolumeGroups {
 		if status.Name == "vg1" {
 			found = true
 			Expect(status.Status).To(Equal(lvmv1alpha1.VGStatusReady))
@@ -1460,9 +1558,9 @@ func testDynamicModeAddsNewDevices(ctx context.Context) {
 
 	By("verifying the VG status is progressing (new devices discovered)")
 	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
-	Expect(nodeStatus.Spec.LVMVGStatus).ToNot(BeEmpty())
+	Expect(nodeStatus.Status.VolumeGroups).ToNot(BeEmpty())
 	found := false
-	for _, status := range nodeStatus.Spec.LVMVGStatus {
+	for _, status := range nodeStatus.Status.VolumeGroups {
 		if status.Name == "vg1" {
 			found = true
 			Expect(status.Status).To(Equal(lvmv1alpha1.VGStatusProgressing))
@@ -1526,9 +1624,9 @@ func testStaticModeIgnoredWithExplicitPaths(ctx context.Context) {
 
 	By("verifying the VG status is progressing (device from Paths is being added)")
 	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
-	Expect(nodeStatus.Spec.LVMVGStatus).ToNot(BeEmpty())
+	Expect(nodeStatus.Status.VolumeGroups).ToNot(BeEmpty())
 	found := false
-	for _, status := range nodeStatus.Spec.LVMVGStatus {
+	for _, status := range nodeStatus.Status.VolumeGroups {
 		if status.Name == "vg1" {
 			found = true
 			Expect(status.Status).To(Equal(lvmv1alpha1.VGStatusProgressing))
@@ -1590,9 +1688,9 @@ func testNilPolicyDefaultsToDynamic(ctx context.Context) {
 
 	By("verifying the VG status is progressing (new devices discovered, not excluded)")
 	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
-	Expect(nodeStatus.Spec.LVMVGStatus).ToNot(BeEmpty())
+	Expect(nodeStatus.Status.VolumeGroups).ToNot(BeEmpty())
 	found := false
-	for _, status := range nodeStatus.Spec.LVMVGStatus {
+	for _, status := range nodeStatus.Status.VolumeGroups {
 		if status.Name == "vg1" {
 			found = true
 			Expect(status.Status).To(Equal(lvmv1alpha1.VGStatusProgressing))
@@ -1727,8 +1825,8 @@ func testRAIDVGWithLocalDevices(ctx context.Context) {
 
 	By("verifying the VGStatus is Ready with RAID member info")
 	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
-	Expect(nodeStatus.Spec.LVMVGStatus).ToNot(BeEmpty())
-	Expect(nodeStatus.Spec.LVMVGStatus).To(ContainElement(lvmv1alpha1.VGStatus{
+	Expect(nodeStatus.Status.VolumeGroups).ToNot(BeEmpty())
+	Expect(nodeStatus.Status.VolumeGroups).To(ContainElement(HaveField("VGStatus", lvmv1alpha1.VGStatus{
 		Name:                  vg.GetName(),
 		Status:                lvmv1alpha1.VGStatusReady,
 		Devices:               []string{device1.Unresolved(), device2.Unresolved()},
@@ -1737,7 +1835,7 @@ func testRAIDVGWithLocalDevices(ctx context.Context) {
 			Status:      lvmv1alpha1.RAIDHealthStatusHealthy,
 			MemberCount: 2,
 		},
-	}))
+	})))
 
 	By("triggering the delete of the RAID VolumeGroup")
 	deletePolicy := corev1.PersistentVolumeReclaimDelete
@@ -1819,9 +1917,9 @@ func testRAIDVGInsufficientDevices(ctx context.Context) {
 
 	By("verifying progressing status was set")
 	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
-	Expect(nodeStatus.Spec.LVMVGStatus).ToNot(BeEmpty())
+	Expect(nodeStatus.Status.VolumeGroups).ToNot(BeEmpty())
 	progressingFound := false
-	for _, status := range nodeStatus.Spec.LVMVGStatus {
+	for _, status := range nodeStatus.Status.VolumeGroups {
 		if status.Name == "vg1" {
 			progressingFound = true
 			Expect(status.Status).To(Equal(lvmv1alpha1.VGStatusProgressing))
@@ -1842,7 +1940,7 @@ func testRAIDVGInsufficientDevices(ctx context.Context) {
 	By("verifying the VGStatus is failed")
 	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
 	found := false
-	for _, status := range nodeStatus.Spec.LVMVGStatus {
+	for _, status := range nodeStatus.Status.VolumeGroups {
 		if status.Name == "vg1" {
 			found = true
 			Expect(status.Status).To(Equal(lvmv1alpha1.VGStatusFailed))
@@ -2002,7 +2100,7 @@ func testRAIDHealthyAfterRepair(ctx context.Context) {
 	By("verifying the VGStatus is Ready")
 	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed(), "should fetch LVMVolumeGroupNodeStatus")
 	found := false
-	for _, status := range nodeStatus.Spec.LVMVGStatus {
+	for _, status := range nodeStatus.Status.VolumeGroups {
 		if status.Name == vg.GetName() {
 			found = true
 			Expect(status.Status).To(Equal(lvmv1alpha1.VGStatusReady), "VG status should be Ready after repair")
@@ -2010,3 +2108,253 @@ func testRAIDHealthyAfterRepair(ctx context.Context) {
 	}
 	Expect(found).To(BeTrue(), "VG status should exist and be ready")
 }
+
+func testMaintenance(ctx context.Context) {
+	logger := zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true))
+	ctx = log.IntoContext(ctx, logger)
+
+	instances := setupInstances()
+
+	vg := &lvmv1alpha1.LVMVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vg1",
+			Namespace: instances.namespace.GetName(),
+		},
+		Spec: lvmv1alpha1.LVMVolumeGroupSpec{
+			NodeSelector: instances.nodeSelector.DeepCopy(),
+		},
+	}
+
+	By("putting the node into maintenance")
+	node := instances.node.DeepCopy()
+	node.SetAnnotations(map[string]string{constants.MaintenanceAnnotation: "true"})
+	Expect(instances.client.Update(ctx, node)).To(Succeed(), "should annotate node")
+
+	By("verifying the node predicate only reacts to maintenance changes of this node")
+	predicate := instances.Reconciler.nodeChangedPredicate()
+	Expect(predicate.Update(event.UpdateEvent{ObjectOld: instances.node, ObjectNew: node})).To(BeTrue())
+	Expect(predicate.Update(event.UpdateEvent{ObjectOld: node, ObjectNew: node})).To(BeFalse())
+	relabeled := node.DeepCopy()
+	relabeled.Labels["hardware"] = "nvme"
+	Expect(predicate.Update(event.UpdateEvent{ObjectOld: node, ObjectNew: relabeled})).To(BeTrue())
+	otherNode := node.DeepCopy()
+	otherNode.SetName("other-node")
+	Expect(predicate.Update(event.UpdateEvent{ObjectOld: instances.node, ObjectNew: otherNode})).To(BeFalse())
+
+	By("creating LVMVolumeGroup and LVMVolumeGroupNodeStatus")
+	Expect(instances.client.Create(ctx, vg)).To(Succeed(), "should create LVMVolumeGroup")
+	nodeStatus := &lvmv1alpha1.LVMVolumeGroupNodeStatus{}
+	nodeStatus.SetName(instances.node.GetName())
+	nodeStatus.SetNamespace(instances.namespace.GetName())
+	Expect(instances.client.Create(ctx, nodeStatus)).To(Succeed(), "should create LVMVolumeGroupNodeStatus")
+
+	By("reconciling without any mutating LVM operation")
+	existingVG := lvm.VolumeGroup{
+		Name: "vg1",
+		PVs:  []lvm.PhysicalVolume{{PvName: "/dev/sda", VgName: "vg1"}},
+	}
+	instances.LVM.EXPECT().ListVGs(ctx, true).Return([]lvm.VolumeGroup{existingVG}, nil).Once()
+	res, err := instances.Reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vg)})
+	Expect(err).ToNot(HaveOccurred(), "reconciliation in maintenance should succeed")
+	Expect(res.RequeueAfter).To(Equal(reconcileInterval), "should requeue to keep reporting status")
+
+	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(vg), vg)).To(Succeed())
+	Expect(vg.GetFinalizers()).To(BeEmpty(), "should not add finalizer while in maintenance")
+
+	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
+	Expect(nodeStatus.Status.VolumeGroups).To(HaveLen(1))
+	Expect(nodeStatus.Status.VolumeGroups[0].Status).To(Equal(lvmv1alpha1.VGStatusPaused))
+	Expect(nodeStatus.Status.VolumeGroups[0].Devices).To(ConsistOf("/dev/sda"))
+}
+
+func testLVMClusterPaused(ctx context.Context) {
+	logger := zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true))
+	ctx = log.IntoContext(ctx, logger)
+
+	instances := setupInstances()
+
+	lvmCluster := &lvmv1alpha1.LVMCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lvmcluster",
+			Namespace: instances.namespace.GetName(),
+			UID:       "test-lvmcluster-uid",
+		},
+		Spec: lvmv1alpha1.LVMClusterSpec{Paused: true},
+	}
+	Expect(instances.client.Create(ctx, lvmCluster)).To(Succeed(), "should create LVMCluster")
+
+	vg := &lvmv1alpha1.LVMVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vg1",
+			Namespace: instances.namespace.GetName(),
+		},
+		Spec: lvmv1alpha1.LVMVolumeGroupSpec{
+			NodeSelector: instances.nodeSelector.DeepCopy(),
+		},
+	}
+	Expect(ctrl.SetControllerReference(lvmCluster, vg, scheme.Scheme)).To(Succeed())
+
+	By("verifying the LVMCluster is mapped to the volume groups it controls")
+	Expect(instances.client.Create(ctx, vg)).To(Succeed(), "should create LVMVolumeGroup")
+	Expect(instances.Reconciler.getVolumeGroupsForLVMCluster(ctx, lvmCluster)).To(ConsistOf(
+		reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vg)},
+	))
+
+	By("verifying the paused predicate only reacts to changes of spec.paused")
+	unpaused := lvmCluster.DeepCopy()
+	unpaused.Spec.Paused = false
+	Expect(pausedChangedPredicate().Update(event.UpdateEvent{ObjectOld: unpaused, ObjectNew: lvmCluster})).To(BeTrue())
+	Expect(pausedChangedPredicate().Update(event.UpdateEvent{ObjectOld: lvmCluster, ObjectNew: lvmCluster})).To(BeFalse())
+
+	nodeStatus := &lvmv1alpha1.LVMVolumeGroupNodeStatus{}
+	nodeStatus.SetName(instances.node.GetName())
+	nodeStatus.SetNamespace(instances.namespace.GetName())
+	Expect(instances.client.Create(ctx, nodeStatus)).To(Succeed(), "should create LVMVolumeGroupNodeStatus")
+
+	By("reconciling without any mutating LVM operation")
+	instances.LVM.EXPECT().ListVGs(ctx, true).Return(nil, nil).Once()
+	res, err := instances.Reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vg)})
+	Expect(err).ToNot(HaveOccurred(), "reconciliation of paused LVMCluster should succeed")
+	Expect(res.RequeueAfter).To(Equal(reconcileInterval), "should requeue to keep reporting status")
+
+	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(vg), vg)).To(Succeed())
+	Expect(vg.GetFinalizers()).To(BeEmpty(), "should not add finalizer while paused")
+
+	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(nodeStatus), nodeStatus)).To(Succeed())
+	Expect(nodeStatus.Status.VolumeGroups).To(HaveLen(1))
+	Expect(nodeStatus.Status.VolumeGroups[0].Status).To(Equal(lvmv1alpha1.VGStatusPaused))
+	Expect(nodeStatus.Status.VolumeGroups[0].Reason).To(ContainSubstring("LVMCluster is paused"))
+
+	By("unpausing the LVMCluster")
+	lvmCluster.Spec.Paused = false
+	Expect(instances.client.Update(ctx, lvmCluster)).To(Succeed())
+	_, err = instances.Reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vg)})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(vg), vg)).To(Succeed())
+	Expect(vg.GetFinalizers()).ToNot(BeEmpty(), "should resume reconciliation and add finalizer")
+}
+
+func testUntoleratedNodeTaint(ctx context.Context) {
+	logger := zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true))
+	ctx = log.IntoContext(ctx, logger)
+
+	instances := setupInstances()
+
+	taint := corev1.Taint{Key: "storage-pool", Value: "team-a", Effect: corev1.TaintEffectNoSchedule}
+	instances.node.Spec.Taints = append(instances.node.Spec.Taints, taint)
+	Expect(instances.client.Update(ctx, instances.node)).To(Succeed(), "should taint the node")
+
+	lvmCluster := &lvmv1alpha1.LVMCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lvmcluster",
+			Namespace: instances.namespace.GetName(),
+			UID:       "test-lvmcluster-uid",
+		},
+	}
+	Expect(instances.client.Create(ctx, lvmCluster)).To(Succeed(), "should create LVMCluster")
+
+	vg := &lvmv1alpha1.LVMVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vg1",
+			Namespace: instances.namespace.GetName(),
+		},
+		Spec: lvmv1alpha1.LVMVolumeGroupSpec{
+			NodeSelector: instances.nodeSelector.DeepCopy(),
+		},
+	}
+	Expect(ctrl.SetControllerReference(lvmCluster, vg, scheme.Scheme)).To(Succeed())
+	Expect(instances.client.Create(ctx, vg)).To(Succeed(), "should create LVMVolumeGroup")
+
+	By("skipping the volume group as the taint is not tolerated")
+	res, err := instances.Reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vg)})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(res).To(Equal(reconcile.Result{}))
+	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(vg), vg)).To(Succeed())
+	Expect(vg.GetFinalizers()).To(BeEmpty(), "should not process the volume group")
+
+	By("processing the volume group once the taint is tolerated")
+	lvmCluster.Spec.Tolerations = []corev1.Toleration{{
+		Key:      taint.Key,
+		Operator: corev1.TolerationOpEqual,
+		Value:    taint.Value,
+		Effect:   taint.Effect,
+	}}
+	Expect(instances.client.Update(ctx, lvmCluster)).To(Succeed())
+	Expect(instances.Reconciler.toleratesThisNode(ctx, vg)).To(BeTrue())
+
+	By("ignoring taints that only prefer not to schedule on the node")
+	lvmCluster.Spec.Tolerations = nil
+	Expect(instances.client.Update(ctx, lvmCluster)).To(Succeed())
+	instances.node.Spec.Taints = []corev1.Taint{{Key: "storage-pool", Value: "team-a", Effect: corev1.TaintEffectPreferNoSchedule}}
+	Expect(instances.client.Update(ctx, instances.node)).To(Succeed())
+	Expect(instances.Reconciler.toleratesThisNode(ctx, vg)).To(BeTrue())
+
+	By("deleting a volume group that was set up before the taint was added")
+	instances.node.Spec.Taints = []corev1.Taint{taint}
+	Expect(instances.client.Update(ctx, instances.node)).To(Succeed())
+	Expect(instances.Reconciler.toleratesThisNode(ctx, vg)).To(BeFalse())
+	nodeStatus := instances.Reconciler.getLVMVolumeGroupNodeStatus()
+	Expect(instances.client.Create(ctx, nodeStatus)).To(Succeed())
+	controllerutil.AddFinalizer(vg, instances.Reconciler.getFinalizer())
+	Expect(instances.client.Update(ctx, vg)).To(Succeed())
+	Expect(instances.client.Delete(ctx, vg)).To(Succeed())
+	instances.LVM.EXPECT().ListVGs(mock.Anything, true).Return(nil, nil).Once()
+	_, err = instances.Reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vg)})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(vg), vg)).To(Satisfy(apierrors.IsNotFound),
+		"should remove the finalizer of the node")
+}
+
+func testNodeOverride(ctx context.Context) {
+	logger := zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true))
+	ctx = log.IntoContext(ctx, logger)
+
+	instances := setupInstances()
+
+	vg := &lvmv1alpha1.LVMVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vg1",
+			Namespace: instances.namespace.GetName(),
+		},
+		Spec: lvmv1alpha1.LVMVolumeGroupSpec{
+			DeviceSelector: &lvmv1alpha1.DeviceSelector{Paths: []lvmv1alpha1.DevicePath{"/dev/sda"}},
+			ThinPoolConfig: &lvmv1alpha1.ThinPoolConfig{Name: "thin-pool-1", SizePercent: 90, OverprovisionRatio: 10},
+			NodeOverrides: []lvmv1alpha1.DeviceClassNodeOverride{
+				{
+					Name: "nvme",
+					NodeSelector: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      "hardware",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"nvme"},
+						}},
+					}}},
+					DeviceSelector: &lvmv1alpha1.DeviceSelector{Paths: []lvmv1alpha1.DevicePath{"/dev/nvme0n1"}},
+				},
+				{
+					Name:                "sd",
+					NodeSelector:        instances.nodeSelector.DeepCopy(),
+					DeviceSelector:      &lvmv1alpha1.DeviceSelector{Paths: []lvmv1alpha1.DevicePath{"/dev/sdb", "/dev/sdc"}},
+					ThinPoolSizePercent: ptr.To(80),
+				},
+			},
+		},
+	}
+	Expect(instances.client.Create(ctx, vg)).To(Succeed(), "should create LVMVolumeGroup")
+
+	By("applying the override matching the node")
+	Expect(instances.Reconciler.applyNodeOverride(ctx, vg)).To(Succeed())
+	Expect(vg.Spec.DeviceSelector.Paths).To(Equal([]lvmv1alpha1.DevicePath{"/dev/sdb", "/dev/sdc"}))
+	Expect(vg.Spec.ThinPoolConfig.SizePercent).To(Equal(80))
+
+	By("only writing back the metadata of the volume group")
+	base := vg.DeepCopy()
+	controllerutil.AddFinalizer(vg, instances.Reconciler.getFinalizer())
+	Expect(instances.Reconciler.patchVolumeGroup(ctx, vg, base)).To(Succeed())
+
+	stored := &lvmv1alpha1.LVMVolumeGroup{}
+	Expect(instances.client.Get(ctx, client.ObjectKeyFromObject(vg), stored)).To(Succeed())
+	Expect(stored.GetFinalizers()).To(ContainElement(instances.Reconciler.getFinalizer()))
+	Expect(stored.Spec.DeviceSelector.Paths).To(Equal([]lvmv1alpha1.DevicePath{"/dev/sda"}))
+	Expect(stored.Spec.ThinPoolConfig.SizePercent).To(Equal(90))
+}
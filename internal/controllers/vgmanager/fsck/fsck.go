@@ -0,0 +1,190 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	vgmanagerexec "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/exec"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var (
+	DefaultXFSRepair = "/usr/sbin/xfs_repair"
+	DefaultE2fsck    = "/usr/sbin/e2fsck"
+	DefaultBlkid     = "/usr/sbin/blkid"
+)
+
+// MaxOutputLength is the number of bytes of the end of the output of a check that are kept in the Result.
+const MaxOutputLength = 4096
+
+var (
+	// ErrUnsupportedFilesystem is returned for devices without a filesystem that can be checked.
+	ErrUnsupportedFilesystem = errors.New("unsupported filesystem")
+	// ErrLogReplayRequired is returned by xfs_repair if the log of the filesystem is dirty and has to be
+	// replayed by mounting the filesystem before it can be repaired.
+	ErrLogReplayRequired = errors.New("the filesystem log has to be replayed by mounting the filesystem before it can be repaired")
+)
+
+// Result is the outcome of a filesystem check.
+type Result struct {
+	// ErrorsFound is set if the filesystem has errors that were not repaired.
+	ErrorsFound bool
+	// Repaired is set if errors were found and repaired.
+	Repaired bool
+	// Output is the end of the combined output of the check.
+	Output string
+}
+
+type Fsck interface {
+	// FilesystemType returns the type of the filesystem on the device, or an empty string if there is none.
+	FilesystemType(ctx context.Context, device string) (string, error)
+	// Check checks the filesystem of the type on the device without modifying it.
+	Check(ctx context.Context, device, fsType string) (Result, error)
+	// Repair checks the filesystem of the type on the device and repairs all errors found.
+	Repair(ctx context.Context, device, fsType string) (Result, error)
+}
+
+type HostFsck struct {
+	vgmanagerexec.Executor
+	xfsRepair string
+	e2fsck    string
+	blkid     string
+}
+
+func NewDefaultHostFsck() *HostFsck {
+	return NewHostFsck(&vgmanagerexec.CommandExecutor{}, DefaultXFSRepair, DefaultE2fsck, DefaultBlkid)
+}
+
+func NewHostFsck(executor vgmanagerexec.Executor, xfsRepair, e2fsck, blkid string) *HostFsck {
+	return &HostFsck{
+		Executor:  executor,
+		xfsRepair: xfsRepair,
+		e2fsck:    e2fsck,
+		blkid:     blkid,
+	}
+}
+
+func (f *HostFsck) FilesystemType(ctx context.Context, device string) (string, error) {
+	output, err := f.CombinedOutputCommandAsHost(ctx, f.blkid, "--probe", "--output", "value", "--match-tag", "TYPE", device)
+	// blkid exits with 2 if no signature was found on the device
+	if exitCode(err) == 2 {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to probe the device %q: %s: %w", device, strings.TrimSpace(string(output)), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (f *HostFsck) Check(ctx context.Context, device, fsType string) (Result, error) {
+	switch fsType {
+	case "xfs":
+		return f.checkXFS(ctx, device)
+	case "ext2", "ext3", "ext4":
+		return f.runE2fsck(ctx, "-n", device)
+	}
+	return Result{}, fmt.Errorf("%w %q on device %q", ErrUnsupportedFilesystem, fsType, device)
+}
+
+func (f *HostFsck) Repair(ctx context.Context, device, fsType string) (Result, error) {
+	switch fsType {
+	case "xfs":
+		return f.repairXFS(ctx, device)
+	case "ext2", "ext3", "ext4":
+		return f.runE2fsck(ctx, "-y", device)
+	}
+	return Result{}, fmt.Errorf("%w %q on device %q", ErrUnsupportedFilesystem, fsType, device)
+}
+
+// checkXFS runs xfs_repair in no modify mode, which exits with 1 if errors were found.
+func (f *HostFsck) checkXFS(ctx context.Context, device string) (Result, error) {
+	output, err := f.CombinedOutputCommandAsHost(ctx, f.xfsRepair, "-n", device)
+	result := Result{Output: tail(output)}
+	switch exitCode(err) {
+	case 0:
+	case 1:
+		result.ErrorsFound = true
+	default:
+		return result, fmt.Errorf("failed to run xfs_repair: %w", err)
+	}
+	log.FromContext(ctx).Info("xfs_repair finished", "mode", "check", "device", device, "errorsFound", result.ErrorsFound)
+	return result, nil
+}
+
+// repairXFS only repairs a filesystem if errors were found by a check first, as xfs_repair does not report
+// whether it repaired anything. xfs_repair exits with 2 if the log is dirty.
+func (f *HostFsck) repairXFS(ctx context.Context, device string) (Result, error) {
+	result, err := f.checkXFS(ctx, device)
+	if err != nil || !result.ErrorsFound {
+		return result, err
+	}
+
+	output, err := f.CombinedOutputCommandAsHost(ctx, f.xfsRepair, device)
+	result = Result{Output: tail(output)}
+	switch exitCode(err) {
+	case 0:
+		result.Repaired = true
+	case 2:
+		result.ErrorsFound = true
+		return result, ErrLogReplayRequired
+	default:
+		return result, fmt.Errorf("failed to run xfs_repair: %w", err)
+	}
+	log.FromContext(ctx).Info("xfs_repair finished", "mode", "repair", "device", device)
+	return result, nil
+}
+
+// runE2fsck runs a forced e2fsck, which reports the outcome in the bits of its exit code:
+// 1 and 2 mean that errors were corrected and 4 that errors were left uncorrected.
+func (f *HostFsck) runE2fsck(ctx context.Context, mode, device string) (Result, error) {
+	output, err := f.CombinedOutputCommandAsHost(ctx, f.e2fsck, "-f", mode, device)
+	result := Result{Output: tail(output)}
+	code := exitCode(err)
+	if err != nil && (code < 0 || code >= 8) {
+		return result, fmt.Errorf("failed to run e2fsck: %w", err)
+	}
+	if code > 0 {
+		result.Repaired = code&3 != 0
+		result.ErrorsFound = code&4 != 0
+	}
+	log.FromContext(ctx).Info("e2fsck finished", "mode", mode, "device", device, "exitCode", max(code, 0))
+	return result, nil
+}
+
+// exitCode returns the exit code of the command that failed with the error,
+// 0 if there is no error and -1 if the command did not exit.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+func tail(output []byte) string {
+	if len(output) > MaxOutputLength {
+		output = output[len(output)-MaxOutputLength:]
+	}
+	return strings.TrimSpace(strings.ToValidUTF8(string(output), ""))
+}
@@ -0,0 +1,155 @@
+package fsck
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	mockExec "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type exitError int
+
+func (e exitError) Error() string { return "exit status" }
+func (e exitError) ExitCode() int { return int(e) }
+
+const testDevice = "/dev/mapper/vg1-lv1"
+
+// command is the expected invocation of a command and its outcome
+type command struct {
+	name   string
+	args   []string
+	output string
+	err    error
+}
+
+func newExecutor(t *testing.T, commands ...command) *mockExec.MockExecutor {
+	return &mockExec.MockExecutor{
+		MockCombinedOutputCommandAsHost: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			if len(commands) == 0 {
+				t.Fatalf("unexpected command %s %v", name, args)
+			}
+			next := commands[0]
+			commands = commands[1:]
+			assert.Equal(t, next.name, name)
+			assert.Equal(t, next.args, args)
+			return []byte(next.output), next.err
+		},
+	}
+}
+
+func TestFilesystemType(t *testing.T) {
+	ctx := log.IntoContext(context.Background(), testr.New(t))
+	args := []string{"--probe", "--output", "value", "--match-tag", "TYPE", testDevice}
+
+	fsType, err := NewHostFsck(newExecutor(t, command{DefaultBlkid, args, "xfs\n", nil}), DefaultXFSRepair, DefaultE2fsck, DefaultBlkid).
+		FilesystemType(ctx, testDevice)
+	require.NoError(t, err)
+	assert.Equal(t, "xfs", fsType)
+
+	fsType, err = NewHostFsck(newExecutor(t, command{DefaultBlkid, args, "", exitError(2)}), DefaultXFSRepair, DefaultE2fsck, DefaultBlkid).
+		FilesystemType(ctx, testDevice)
+	require.NoError(t, err)
+	assert.Empty(t, fsType)
+}
+
+func TestCheckAndRepair(t *testing.T) {
+	tests := []struct {
+		name     string
+		fsType   string
+		repair   bool
+		commands []command
+		want     Result
+		wantErr  error
+	}{
+		{
+			name:     "clean xfs",
+			fsType:   "xfs",
+			commands: []command{{DefaultXFSRepair, []string{"-n", testDevice}, "No modify flag set", nil}},
+			want:     Result{Output: "No modify flag set"},
+		},
+		{
+			name:     "xfs with errors",
+			fsType:   "xfs",
+			commands: []command{{DefaultXFSRepair, []string{"-n", testDevice}, "would fix", exitError(1)}},
+			want:     Result{ErrorsFound: true, Output: "would fix"},
+		},
+		{
+			name:   "clean xfs is not repaired",
+			fsType: "xfs",
+			repair: true,
+			commands: []command{
+				{DefaultXFSRepair, []string{"-n", testDevice}, "", nil},
+			},
+			want: Result{},
+		},
+		{
+			name:   "xfs repaired",
+			fsType: "xfs",
+			repair: true,
+			commands: []command{
+				{DefaultXFSRepair, []string{"-n", testDevice}, "would fix", exitError(1)},
+				{DefaultXFSRepair, []string{testDevice}, "done", nil},
+			},
+			want: Result{Repaired: true, Output: "done"},
+		},
+		{
+			name:   "xfs with dirty log",
+			fsType: "xfs",
+			repair: true,
+			commands: []command{
+				{DefaultXFSRepair, []string{"-n", testDevice}, "would fix", exitError(1)},
+				{DefaultXFSRepair, []string{testDevice}, "log", exitError(2)},
+			},
+			wantErr: ErrLogReplayRequired,
+		},
+		{
+			name:     "ext4 with errors",
+			fsType:   "ext4",
+			commands: []command{{DefaultE2fsck, []string{"-f", "-n", testDevice}, "", exitError(4)}},
+			want:     Result{ErrorsFound: true},
+		},
+		{
+			name:     "ext4 repaired",
+			fsType:   "ext4",
+			repair:   true,
+			commands: []command{{DefaultE2fsck, []string{"-f", "-y", testDevice}, "", exitError(1)}},
+			want:     Result{Repaired: true},
+		},
+		{
+			name:    "unsupported filesystem",
+			fsType:  "btrfs",
+			wantErr: ErrUnsupportedFilesystem,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := log.IntoContext(context.Background(), testr.New(t))
+			fsck := NewHostFsck(newExecutor(t, tt.commands...), DefaultXFSRepair, DefaultE2fsck, DefaultBlkid)
+
+			var result Result
+			var err error
+			if tt.repair {
+				result, err = fsck.Repair(ctx, testDevice, tt.fsType)
+			} else {
+				result, err = fsck.Check(ctx, testDevice, tt.fsType)
+			}
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestTail(t *testing.T) {
+	output := strings.Repeat("a", MaxOutputLength) + "end"
+	assert.Equal(t, strings.Repeat("a", MaxOutputLength-3)+"end", tail([]byte(output)))
+}
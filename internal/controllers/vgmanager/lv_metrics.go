@@ -0,0 +1,275 @@
+package vgmanager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/thindelta"
+	"github.com/prometheus/client_golang/prometheus"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// DefaultLogicalVolumeMetricsInterval is the interval in which the logical volume metrics are collected.
+const DefaultLogicalVolumeMetricsInterval = time.Minute
+
+var (
+	logicalVolumeLabels = []string{"node", "device_class", "logical_volume", "namespace", "persistentvolumeclaim"}
+
+	logicalVolumeSizeBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "lvms_logical_volume_size_bytes",
+			Help: "Size of a logical volume in bytes. For thin volumes this is the virtual size.",
+		},
+		logicalVolumeLabels,
+	)
+
+	logicalVolumeDataPercent = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "lvms_logical_volume_data_percent",
+			Help: "Percentage of a thin logical volume that is mapped in the thin pool (0-100).",
+		},
+		logicalVolumeLabels,
+	)
+
+	logicalVolumeMappedBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "lvms_logical_volume_mapped_bytes",
+			Help: "Bytes of a thin logical volume that are mapped in the thin pool.",
+		},
+		logicalVolumeLabels,
+	)
+
+	snapshotExclusiveBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "lvms_logical_volume_snapshot_exclusive_bytes",
+			Help: "Bytes used only by a snapshot. For thin snapshots these are the bytes mapped in the thin pool that are not shared with the origin " +
+				"or any other thin volume. For thick snapshots this is the used copy-on-write space. " +
+				"The namespace and persistentvolumeclaim labels refer to the origin volume.",
+		},
+		[]string{"node", "device_class", "logical_volume", "origin", "namespace", "persistentvolumeclaim"},
+	)
+)
+
+// LogicalVolumeMetrics returns the Prometheus collectors for the size and usage of logical volumes.
+func LogicalVolumeMetrics() []prometheus.Collector {
+	return []prometheus.Collector{
+		logicalVolumeSizeBytes,
+		logicalVolumeDataPercent,
+		logicalVolumeMappedBytes,
+		snapshotExclusiveBytes,
+	}
+}
+
+// LogicalVolumeMetricsExporter periodically exports the usage of the logical volumes in the volume groups of a node
+// together with the PersistentVolumeClaim owning them.
+type LogicalVolumeMetricsExporter struct {
+	client.Client
+	lvm.LVM
+	thindelta.ThinDelta
+
+	NodeName  string
+	Namespace string
+	Interval  time.Duration
+
+	// series holds the label values exported in the last collection for each metric,
+	// so that series of deleted logical volumes can be removed.
+	series map[*prometheus.GaugeVec]map[string][]string
+}
+
+var _ manager.Runnable = &LogicalVolumeMetricsExporter{}
+var _ manager.LeaderElectionRunnable = &LogicalVolumeMetricsExporter{}
+
+// NewLogicalVolumeMetricsExporter returns a LogicalVolumeMetricsExporter.
+func NewLogicalVolumeMetricsExporter(client client.Client, lvm lvm.LVM, thinDelta thindelta.ThinDelta, nodeName, namespace string) *LogicalVolumeMetricsExporter {
+	return &LogicalVolumeMetricsExporter{
+		Client:    client,
+		LVM:       lvm,
+		ThinDelta: thinDelta,
+		NodeName:  nodeName,
+		Namespace: namespace,
+		Interval:  DefaultLogicalVolumeMetricsInterval,
+		series:    make(map[*prometheus.GaugeVec]map[string][]string),
+	}
+}
+
+func (e *LogicalVolumeMetricsExporter) NeedLeaderElection() bool {
+	return false
+}
+
+func (e *LogicalVolumeMetricsExporter) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("lv-metrics")
+
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.collect(ctx); err != nil {
+			logger.Error(err, "failed to collect logical volume metrics")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// logicalVolumeOwner is the PersistentVolumeClaim a logical volume belongs to.
+type logicalVolumeOwner struct {
+	Namespace string
+	Name      string
+}
+
+// collect updates the metrics for all logical volumes in the volume groups of the node.
+func (e *LogicalVolumeMetricsExporter) collect(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	volumeGroups := &lvmv1alpha1.LVMVolumeGroupList{}
+	if err := e.List(ctx, volumeGroups, client.InNamespace(e.Namespace)); err != nil {
+		return fmt.Errorf("failed to list LVMVolumeGroups: %w", err)
+	}
+
+	vgs, err := e.ListVGs(ctx, true)
+	if err != nil {
+		return fmt.Errorf("failed to list volume groups: %w", err)
+	}
+	vgsOnNode := make(map[string]struct{}, len(vgs))
+	for _, vg := range vgs {
+		vgsOnNode[vg.Name] = struct{}{}
+	}
+
+	owners, err := e.getOwners(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[*prometheus.GaugeVec]map[string][]string)
+	set := func(vec *prometheus.GaugeVec, value float64, labelValues ...string) {
+		if current[vec] == nil {
+			current[vec] = make(map[string][]string)
+		}
+		current[vec][strings.Join(labelValues, "/")] = labelValues
+		vec.WithLabelValues(labelValues...).Set(value)
+	}
+
+	for _, volumeGroup := range volumeGroups.Items {
+		if _, ok := vgsOnNode[volumeGroup.Name]; !ok {
+			continue
+		}
+
+		report, err := e.ListLVs(ctx, volumeGroup.Name)
+		if err != nil {
+			return fmt.Errorf("failed to list logical volumes in volume group %s: %w", volumeGroup.Name, err)
+		}
+		// the exclusive bytes of the thin volumes per thin pool, only read for pools with thin snapshots
+		exclusiveBytes := make(map[string]map[int]int64)
+
+		for _, item := range report.Report {
+			for _, lv := range item.Lv {
+				lvAttr, err := ParsedLvAttr(lv.LvAttr)
+				if err != nil || lvAttr.VolumeType == VolumeTypeThinPool {
+					continue
+				}
+
+				size, err := strconv.ParseFloat(lv.LvSize, 64)
+				if err != nil {
+					continue
+				}
+
+				owner := owners[lv.Name]
+				labelValues := []string{e.NodeName, volumeGroup.Name, lv.Name, owner.Namespace, owner.Name}
+				set(logicalVolumeSizeBytes, size, labelValues...)
+
+				dataPercent, err := strconv.ParseFloat(lv.DataPercent, 64)
+				if err != nil {
+					continue
+				}
+				mapped := size * dataPercent / 100
+
+				if lvAttr.VolumeType == VolumeTypeThinVolume {
+					set(logicalVolumeDataPercent, dataPercent, labelValues...)
+					set(logicalVolumeMappedBytes, mapped, labelValues...)
+				}
+
+				if lv.Origin == "" {
+					continue
+				}
+				origin := owners[lv.Origin]
+				snapshotLabelValues := []string{e.NodeName, volumeGroup.Name, lv.Name, lv.Origin, origin.Namespace, origin.Name}
+				switch lvAttr.VolumeType {
+				case VolumeTypeThinVolume:
+					// The mapped bytes of a thin snapshot include the blocks shared with its origin,
+					// so the blocks exclusive to the snapshot are read from the thin pool metadata.
+					if _, ok := exclusiveBytes[lv.PoolName]; !ok {
+						exclusive, err := e.ExclusiveBytes(ctx, volumeGroup.Name, lv.PoolName)
+						if err != nil {
+							logger.Error(err, "failed to get exclusive bytes of thin volumes", "VGName", volumeGroup.Name, "pool", lv.PoolName)
+						}
+						exclusiveBytes[lv.PoolName] = exclusive
+					}
+					thinID, err := strconv.Atoi(lv.ThinID)
+					if err != nil {
+						continue
+					}
+					if exclusive, ok := exclusiveBytes[lv.PoolName][thinID]; ok {
+						set(snapshotExclusiveBytes, float64(exclusive), snapshotLabelValues...)
+					}
+				case VolumeTypeSnapshot:
+					// The used copy-on-write space of a thick snapshot only holds the blocks exclusive to it.
+					set(snapshotExclusiveBytes, mapped, snapshotLabelValues...)
+				}
+			}
+		}
+	}
+
+	// remove the series of logical volumes that no longer exist
+	for _, vec := range []*prometheus.GaugeVec{logicalVolumeSizeBytes, logicalVolumeDataPercent, logicalVolumeMappedBytes, snapshotExclusiveBytes} {
+		for key, labelValues := range e.series[vec] {
+			if _, ok := current[vec][key]; !ok {
+				vec.DeleteLabelValues(labelValues...)
+			}
+		}
+	}
+	e.series = current
+
+	return nil
+}
+
+// getOwners maps the names of the logical volumes on the node to the PersistentVolumeClaims they belong to.
+func (e *LogicalVolumeMetricsExporter) getOwners(ctx context.Context) (map[string]logicalVolumeOwner, error) {
+	logicalVolumes := &topolvmv1.LogicalVolumeList{}
+	if err := e.List(ctx, logicalVolumes); err != nil {
+		return nil, fmt.Errorf("failed to list LogicalVolumes: %w", err)
+	}
+
+	pvs := &corev1.PersistentVolumeList{}
+	if err := e.List(ctx, pvs); err != nil {
+		return nil, fmt.Errorf("failed to list PersistentVolumes: %w", err)
+	}
+	claims := make(map[string]logicalVolumeOwner, len(pvs.Items))
+	for _, pv := range pvs.Items {
+		if pv.Spec.ClaimRef != nil {
+			claims[pv.Name] = logicalVolumeOwner{Namespace: pv.Spec.ClaimRef.Namespace, Name: pv.Spec.ClaimRef.Name}
+		}
+	}
+
+	owners := make(map[string]logicalVolumeOwner)
+	for _, logicalVolume := range logicalVolumes.Items {
+		if logicalVolume.Spec.NodeName != e.NodeName || logicalVolume.Status.VolumeID == "" {
+			continue
+		}
+		owners[logicalVolume.Status.VolumeID] = claims[logicalVolume.Spec.Name]
+	}
+
+	return owners, nil
+}
@@ -0,0 +1,81 @@
+package vgmanager
+
+import (
+	"context"
+	"testing"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	lvmmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm/mocks"
+	thindeltamocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/thindelta/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLogicalVolumeMetricsExporter(t *testing.T) {
+	for _, c := range LogicalVolumeMetrics() {
+		c.(interface{ Reset() }).Reset()
+	}
+
+	const (
+		nodeName   = "test-node"
+		namespace  = "openshift-lvm-storage"
+		volumeID   = "0d2b1c3e-6a1f-4e4c-8d43-2b9a7a3c1f10"
+		snapshotID = "7e6f4f7c-2f5c-4a0d-9a0e-5f3c2b1d0e9a"
+	)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, lvmv1alpha1.AddToScheme(scheme))
+	require.NoError(t, topolvmv1.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&lvmv1alpha1.LVMVolumeGroup{ObjectMeta: metav1.ObjectMeta{Name: "vg1", Namespace: namespace}},
+		&topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+			Spec:       topolvmv1.LogicalVolumeSpec{Name: "pvc-1", NodeName: nodeName, DeviceClass: "vg1"},
+			Status:     topolvmv1.LogicalVolumeStatus{VolumeID: volumeID},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+			Spec:       corev1.PersistentVolumeSpec{ClaimRef: &corev1.ObjectReference{Namespace: "app", Name: "data"}},
+		},
+	).Build()
+
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListVGs(mock.Anything, true).Return([]lvm.VolumeGroup{{Name: "vg1"}}, nil)
+	lvs := []lvm.LogicalVolume{
+		{Name: "thin-pool-1", VgName: "vg1", LvAttr: "twi-a-tz--", LvSize: "10737418240", DataPercent: "30.00"},
+		{Name: volumeID, VgName: "vg1", PoolName: "thin-pool-1", LvAttr: "Vwi-aotz--", LvSize: "1073741824", DataPercent: "50.00", ThinID: "1"},
+		{Name: snapshotID, VgName: "vg1", PoolName: "thin-pool-1", Origin: volumeID, LvAttr: "Vwi---tz-k", LvSize: "1073741824", DataPercent: "45.00", ThinID: "2"},
+	}
+	mockLVM.EXPECT().ListLVs(mock.Anything, "vg1").RunAndReturn(func(ctx context.Context, s string) (*lvm.LVReport, error) {
+		return &lvm.LVReport{Report: []lvm.LVReportItem{{Lv: lvs}}}, nil
+	})
+
+	// the snapshot maps 45% of its size, but only 64MiB of them are not shared with the origin
+	mockThinDelta := thindeltamocks.NewMockThinDelta(t)
+	mockThinDelta.EXPECT().ExclusiveBytes(mock.Anything, "vg1", "thin-pool-1").Return(map[int]int64{1: 134217728, 2: 67108864}, nil).Once()
+
+	e := NewLogicalVolumeMetricsExporter(fakeClient, mockLVM, mockThinDelta, nodeName, namespace)
+	require.NoError(t, e.collect(context.Background()))
+
+	assert.Equal(t, 2, collectMetricCount(logicalVolumeSizeBytes), "thin pool should not be exported")
+	assert.Equal(t, float64(1073741824), getGaugeValue(logicalVolumeSizeBytes, nodeName, "vg1", volumeID, "app", "data"))
+	assert.Equal(t, float64(50), getGaugeValue(logicalVolumeDataPercent, nodeName, "vg1", volumeID, "app", "data"))
+	assert.Equal(t, float64(536870912), getGaugeValue(logicalVolumeMappedBytes, nodeName, "vg1", volumeID, "app", "data"))
+	assert.Equal(t, float64(67108864), getGaugeValue(snapshotExclusiveBytes, nodeName, "vg1", snapshotID, volumeID, "app", "data"))
+
+	// the series of deleted logical volumes are removed
+	lvs = lvs[:2]
+	require.NoError(t, e.collect(context.Background()))
+	assert.Equal(t, 1, collectMetricCount(logicalVolumeSizeBytes))
+	assert.Equal(t, 0, collectMetricCount(snapshotExclusiveBytes))
+}
@@ -19,6 +19,7 @@ package lvm
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -29,6 +30,10 @@ var (
 	ErrVolumeGroupNotFound = fmt.Errorf("volume group not found")
 )
 
+// topoLVMVolumeName matches the names TopoLVM gives to the logical volumes it provisions,
+// which is the UID of the owning LogicalVolume.
+var topoLVMVolumeName = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
 type ExitError interface {
 	ExitCode() int
 	error
@@ -48,7 +53,10 @@ const (
 	lvExtendCmd   = "/usr/sbin/lvextend"
 	lvRemoveCmd   = "/usr/sbin/lvremove"
 	lvChangeCmd   = "/usr/sbin/lvchange"
+	lvRenameCmd   = "/usr/sbin/lvrename"
+	lvConvertCmd  = "/usr/sbin/lvconvert"
 	lvmDevicesCmd = "/usr/sbin/lvmdevices"
+	ddCmd         = "/usr/bin/dd"
 
 	DefaultTag = "@lvms"
 )
@@ -60,6 +68,8 @@ var (
 		"pool_lv",
 		"lv_attr",
 		"lv_size",
+		"origin",
+		"data_percent",
 		"metadata_percent",
 		"chunk_size",
 		"lv_metadata_size",
@@ -67,6 +77,7 @@ var (
 		"lv_health_status",
 		"lv_layout",
 		"raid_sync_action",
+		"thin_id",
 	}
 )
 
@@ -76,6 +87,7 @@ type VGReport struct {
 		Vg []struct {
 			Name   string `json:"vg_name"`
 			VgSize string `json:"vg_size"`
+			VgFree string `json:"vg_free"`
 			Tags   string `json:"vg_tags"`
 		} `json:"vg"`
 	} `json:"report"`
@@ -103,6 +115,8 @@ type LogicalVolume struct {
 	PoolName        string `json:"pool_lv"`
 	LvAttr          string `json:"lv_attr"`
 	LvSize          string `json:"lv_size"`
+	Origin          string `json:"origin"`
+	DataPercent     string `json:"data_percent"`
 	MetadataPercent string `json:"metadata_percent"`
 	ChunkSize       string `json:"chunk_size"`
 	MetadataSize    string `json:"lv_metadata_size"`
@@ -110,6 +124,18 @@ type LogicalVolume struct {
 	LVHealthStatus  string `json:"lv_health_status"`
 	LVLayout        string `json:"lv_layout"`
 	RAIDSyncAction  string `json:"raid_sync_action"`
+	ThinID          string `json:"thin_id"`
+}
+
+// DeviceMapperPath returns the device mapper path of a logical volume, escaping dashes
+// in the same way as LVM does.
+func DeviceMapperPath(vgName, lvName string) string {
+	return fmt.Sprintf("/dev/mapper/%s-%s", strings.ReplaceAll(vgName, "-", "--"), strings.ReplaceAll(lvName, "-", "--"))
+}
+
+// IsTopoLVMVolumeName checks if the name of a logical volume is one given by TopoLVM.
+func IsTopoLVMVolumeName(lvName string) bool {
+	return topoLVMVolumeName.MatchString(lvName)
 }
 
 type LVM interface {
@@ -130,7 +156,14 @@ type LVM interface {
 	CreateLV(ctx context.Context, lvName, vgName string, sizePercent int, chunkSizeBytes, metadataSizeBytes int64) error
 	ExtendLV(ctx context.Context, lvName, vgName string, sizePercent int) error
 	ExtendThinPoolMetadata(ctx context.Context, lvName, vgName string, metadataSizeBytes int64) error
+	CreateSnapshotLV(ctx context.Context, lvName, vgName, originName string, sizeBytes int64, tags []string, readOnly bool) error
+	CreateThinSnapshotLV(ctx context.Context, lvName, vgName, originName string) error
+	ExtendSnapshotLV(ctx context.Context, lvName, vgName string, sizePercent int) error
+	CopyLV(ctx context.Context, vgName, sourceName, targetName string) error
 	ActivateLV(ctx context.Context, lvName, vgName string) error
+	DeactivateLV(ctx context.Context, lvName, vgName string) error
+	MergeSnapshotLV(ctx context.Context, lvName, vgName string) error
+	RenameLV(ctx context.Context, lvName, vgName, newName string) error
 	DeleteLV(ctx context.Context, lvName, vgName string) error
 }
 
@@ -154,6 +187,9 @@ type VolumeGroup struct {
 	// VgSize is the size of the volume group
 	VgSize string `json:"vg_size"`
 
+	// VgFree is the size of the volume group that is not allocated to any logical volume
+	VgFree string `json:"vg_free"`
+
 	// PVs is the list of physical volumes associated with the volume group
 	PVs []PhysicalVolume `json:"pvs"`
 
@@ -323,6 +359,7 @@ func (hlvm *HostLVM) GetVG(ctx context.Context, name string) (VolumeGroup, error
 			if vg.Name == name {
 				volumeGroup.Name = vg.Name
 				volumeGroup.VgSize = vg.VgSize
+				volumeGroup.VgFree = vg.VgFree
 				vgFound = true
 				break
 			}
@@ -380,7 +417,7 @@ func (hlvm *HostLVM) ListVGs(ctx context.Context, tagged bool) ([]VolumeGroup, e
 	res := new(VGReport)
 
 	args := []string{
-		"-o", "vg_name,vg_size,vg_tags", "--units", "b", "--nosuffix", "--reportformat", "json",
+		"-o", "vg_name,vg_size,vg_free,vg_tags", "--units", "b", "--nosuffix", "--reportformat", "json",
 	}
 	if tagged {
 		args = append(args, DefaultTag)
@@ -396,6 +433,7 @@ func (hlvm *HostLVM) ListVGs(ctx context.Context, tagged bool) ([]VolumeGroup, e
 			vgList = append(vgList, VolumeGroup{
 				Name:   vg.Name,
 				VgSize: vg.VgSize,
+				VgFree: vg.VgFree,
 				PVs:    []PhysicalVolume{},
 				Tags:   strings.Split(vg.Tags, ","),
 			})
@@ -573,6 +611,104 @@ func (hlvm *HostLVM) ExtendThinPoolMetadata(ctx context.Context, lvName, vgName
 	return nil
 }
 
+// CreateSnapshotLV creates a copy-on-write snapshot of a thick logical volume. sizeBytes is the size reserved for
+// the changes of the origin after the snapshot was taken.
+func (hlvm *HostLVM) CreateSnapshotLV(ctx context.Context, lvName, vgName, originName string, sizeBytes int64, tags []string, readOnly bool) error {
+	if vgName == "" {
+		return fmt.Errorf("failed to create snapshot logical volume in volume group: volume group name is empty")
+	}
+	if lvName == "" || originName == "" {
+		return fmt.Errorf("failed to create snapshot logical volume in volume group: logical volume name is empty")
+	}
+	if sizeBytes <= 0 {
+		return fmt.Errorf("failed to create snapshot logical volume in volume group: size should be greater than 0")
+	}
+
+	args := []string{"-s", "-n", lvName, "-L", fmt.Sprintf("%vb", sizeBytes)}
+	for _, tag := range tags {
+		args = append(args, "--addtag", tag)
+	}
+	if readOnly {
+		args = append(args, "-p", "r")
+	}
+	args = append(args, fmt.Sprintf("%s/%s", vgName, originName))
+
+	if err := hlvm.RunCommandAsHost(ctx, lvCreateCmd, args...); err != nil {
+		return fmt.Errorf("failed to create snapshot logical volume %q of %q in the volume group %q using command '%s': %w",
+			lvName, originName, vgName, fmt.Sprintf("%s %s", lvCreateCmd, strings.Join(args, " ")), err)
+	}
+
+	return nil
+}
+
+// CreateThinSnapshotLV creates a read-only snapshot of a thin logical volume. Unlike the snapshots created by TopoLVM,
+// the snapshot does not skip activation, so its device can be read right away.
+func (hlvm *HostLVM) CreateThinSnapshotLV(ctx context.Context, lvName, vgName, originName string) error {
+	if vgName == "" {
+		return fmt.Errorf("failed to create thin snapshot logical volume in volume group: volume group name is empty")
+	}
+	if lvName == "" || originName == "" {
+		return fmt.Errorf("failed to create thin snapshot logical volume in volume group: logical volume name is empty")
+	}
+
+	args := []string{"-s", "-kn", "-p", "r", "-n", lvName, fmt.Sprintf("%s/%s", vgName, originName)}
+
+	if err := hlvm.RunCommandAsHost(ctx, lvCreateCmd, args...); err != nil {
+		return fmt.Errorf("failed to create thin snapshot logical volume %q of %q in the volume group %q using command '%s': %w",
+			lvName, originName, vgName, fmt.Sprintf("%s %s", lvCreateCmd, strings.Join(args, " ")), err)
+	}
+
+	return nil
+}
+
+// ExtendSnapshotLV extends the copy-on-write space of a snapshot logical volume by sizePercent of its current size.
+func (hlvm *HostLVM) ExtendSnapshotLV(ctx context.Context, lvName, vgName string, sizePercent int) error {
+	if vgName == "" {
+		return fmt.Errorf("failed to extend snapshot logical volume in volume group: volume group name is empty")
+	}
+	if lvName == "" {
+		return fmt.Errorf("failed to extend snapshot logical volume in volume group: logical volume name is empty")
+	}
+	if sizePercent <= 0 {
+		return fmt.Errorf("failed to extend snapshot logical volume in volume group: size percent should be greater than 0")
+	}
+
+	args := []string{"-l", fmt.Sprintf("+%d%%LV", sizePercent), fmt.Sprintf("%s/%s", vgName, lvName)}
+
+	if err := hlvm.RunCommandAsHost(ctx, lvExtendCmd, args...); err != nil {
+		return fmt.Errorf("failed to extend snapshot logical volume %q in the volume group %q using command '%s': %w",
+			lvName, vgName, fmt.Sprintf("%s %s", lvExtendCmd, strings.Join(args, " ")), err)
+	}
+
+	return nil
+}
+
+// CopyLV copies the content of a logical volume block by block into another logical volume of at least the same size.
+func (hlvm *HostLVM) CopyLV(ctx context.Context, vgName, sourceName, targetName string) error {
+	if vgName == "" {
+		return fmt.Errorf("failed to copy logical volume in volume group: volume group name is empty")
+	}
+	if sourceName == "" || targetName == "" {
+		return fmt.Errorf("failed to copy logical volume in volume group: logical volume name is empty")
+	}
+
+	args := []string{
+		fmt.Sprintf("if=%s", DeviceMapperPath(vgName, sourceName)),
+		fmt.Sprintf("of=%s", DeviceMapperPath(vgName, targetName)),
+		"bs=4M",
+		"iflag=direct",
+		"oflag=direct",
+		"conv=fsync",
+	}
+
+	if err := hlvm.RunCommandAsHost(ctx, ddCmd, args...); err != nil {
+		return fmt.Errorf("failed to copy logical volume %q to %q in the volume group %q using command '%s': %w",
+			sourceName, targetName, vgName, fmt.Sprintf("%s %s", ddCmd, strings.Join(args, " ")), err)
+	}
+
+	return nil
+}
+
 // ActivateLV activates the logical volume
 func (hlvm *HostLVM) ActivateLV(ctx context.Context, lvName, vgName string) error {
 	if vgName == "" {
@@ -592,6 +728,59 @@ func (hlvm *HostLVM) ActivateLV(ctx context.Context, lvName, vgName string) erro
 	return nil
 }
 
+// DeactivateLV deactivates the logical volume
+func (hlvm *HostLVM) DeactivateLV(ctx context.Context, lvName, vgName string) error {
+	if vgName == "" {
+		return fmt.Errorf("failed to deactivate logical volume in volume group: volume group name is empty")
+	}
+	if lvName == "" {
+		return fmt.Errorf("failed to deactivate logical volume in volume group: logical volume name is empty")
+	}
+
+	if err := hlvm.RunCommandAsHost(ctx, lvChangeCmd, "-an", fmt.Sprintf("%s/%s", vgName, lvName)); err != nil {
+		return fmt.Errorf("failed to deactivate logical volume %q in volume group %q. %w", lvName, vgName, err)
+	}
+
+	return nil
+}
+
+// MergeSnapshotLV merges the snapshot logical volume into its origin and removes the snapshot afterward.
+// The merge is started in the background. If the origin or the snapshot are open, it is deferred until
+// the origin is activated the next time.
+func (hlvm *HostLVM) MergeSnapshotLV(ctx context.Context, lvName, vgName string) error {
+	if vgName == "" {
+		return fmt.Errorf("failed to merge snapshot logical volume in volume group: volume group name is empty")
+	}
+	if lvName == "" {
+		return fmt.Errorf("failed to merge snapshot logical volume in volume group: logical volume name is empty")
+	}
+
+	args := []string{"--merge", "--background", fmt.Sprintf("%s/%s", vgName, lvName)}
+
+	if err := hlvm.RunCommandAsHost(ctx, lvConvertCmd, args...); err != nil {
+		return fmt.Errorf("failed to merge snapshot logical volume %q in the volume group %q using command '%s': %w",
+			lvName, vgName, fmt.Sprintf("%s %s", lvConvertCmd, strings.Join(args, " ")), err)
+	}
+
+	return nil
+}
+
+// RenameLV renames the logical volume inside the volume group
+func (hlvm *HostLVM) RenameLV(ctx context.Context, lvName, vgName, newName string) error {
+	if vgName == "" {
+		return fmt.Errorf("failed to rename logical volume in volume group: volume group name is empty")
+	}
+	if lvName == "" || newName == "" {
+		return fmt.Errorf("failed to rename logical volume in volume group: logical volume name is empty")
+	}
+
+	if err := hlvm.RunCommandAsHost(ctx, lvRenameCmd, vgName, lvName, newName); err != nil {
+		return fmt.Errorf("failed to rename logical volume %q to %q in volume group %q. %w", lvName, newName, vgName, err)
+	}
+
+	return nil
+}
+
 // ReduceVG removes a physical volume from a volume group using vgreduce.
 func (hlvm *HostLVM) ReduceVG(ctx context.Context, vgName string, device string) error {
 	args := []string{vgName, device}
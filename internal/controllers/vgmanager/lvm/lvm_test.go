@@ -503,6 +503,164 @@ func TestHostLVM_ExtendLV(t *testing.T) {
 	}
 }
 
+func TestHostLVM_CreateSnapshotLV(t *testing.T) {
+	tests := []struct {
+		name       string
+		lvName     string
+		vgName     string
+		originName string
+		sizeBytes  int64
+		readOnly   bool
+		wantArgs   []string
+		wantErr    bool
+		execErr    bool
+	}{
+		{"Empty Volume Group Name", "snap1", "", "lv1", 1024, false, nil, true, false},
+		{"Empty Origin Name", "snap1", "vg1", "", 1024, false, nil, true, false},
+		{"Invalid Size", "snap1", "vg1", "lv1", 0, false, nil, true, false},
+		{"Error on Exec", "snap1", "vg1", "lv1", 1024, false, nil, true, true},
+		{"Snapshot created successfully", "snap1", "vg1", "lv1", 1024, false,
+			[]string{"-s", "-n", "snap1", "-L", "1024b", "--addtag", "tag1", "vg1/lv1"}, false, false},
+		{"Read-only snapshot created successfully", "snap1", "vg1", "lv1", 1024, true,
+			[]string{"-s", "-n", "snap1", "-L", "1024b", "--addtag", "tag1", "-p", "r", "vg1/lv1"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := log.IntoContext(context.Background(), testr.New(t))
+			executor := &test.MockExecutor{MockRunCommandAsHost: func(ctx context.Context, command string, args ...string) error {
+				if tt.execErr {
+					return fmt.Errorf("mocked error")
+				}
+
+				assert.Equal(t, tt.wantArgs, args)
+				return nil
+			}}
+
+			err := NewHostLVM(executor).CreateSnapshotLV(ctx, tt.lvName, tt.vgName, tt.originName, tt.sizeBytes, []string{"tag1"}, tt.readOnly)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHostLVM_CreateThinSnapshotLV(t *testing.T) {
+	tests := []struct {
+		name       string
+		lvName     string
+		vgName     string
+		originName string
+		wantArgs   []string
+		wantErr    bool
+		execErr    bool
+	}{
+		{"Empty Volume Group Name", "snap1", "", "lv1", nil, true, false},
+		{"Empty Origin Name", "snap1", "vg1", "", nil, true, false},
+		{"Error on Exec", "snap1", "vg1", "lv1", nil, true, true},
+		{"Snapshot created successfully", "snap1", "vg1", "lv1",
+			[]string{"-s", "-kn", "-p", "r", "-n", "snap1", "vg1/lv1"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := log.IntoContext(context.Background(), testr.New(t))
+			executor := &test.MockExecutor{MockRunCommandAsHost: func(ctx context.Context, command string, args ...string) error {
+				if tt.execErr {
+					return fmt.Errorf("mocked error")
+				}
+
+				assert.Equal(t, tt.wantArgs, args)
+				return nil
+			}}
+
+			err := NewHostLVM(executor).CreateThinSnapshotLV(ctx, tt.lvName, tt.vgName, tt.originName)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHostLVM_ExtendSnapshotLV(t *testing.T) {
+	tests := []struct {
+		name        string
+		lvName      string
+		vgName      string
+		sizePercent int
+		wantErr     bool
+		execErr     bool
+	}{
+		{"Empty Volume Group Name", "snap1", "", 20, true, false},
+		{"Empty Logical Volume Name", "", "vg1", 20, true, false},
+		{"Invalid SizePercent", "snap1", "vg1", 0, true, false},
+		{"Error on Exec", "snap1", "vg1", 20, true, true},
+		{"Snapshot extended successfully", "snap1", "vg1", 20, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := log.IntoContext(context.Background(), testr.New(t))
+			executor := &test.MockExecutor{MockRunCommandAsHost: func(ctx context.Context, command string, args ...string) error {
+				if tt.execErr {
+					return fmt.Errorf("mocked error")
+				}
+
+				assert.Equal(t, []string{"-l", fmt.Sprintf("+%d%%LV", tt.sizePercent), fmt.Sprintf("%s/%s", tt.vgName, tt.lvName)}, args)
+				return nil
+			}}
+
+			err := NewHostLVM(executor).ExtendSnapshotLV(ctx, tt.lvName, tt.vgName, tt.sizePercent)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHostLVM_MergeSnapshotLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		lvName  string
+		vgName  string
+		wantErr bool
+		execErr bool
+	}{
+		{"Empty Volume Group Name", "snap1", "", true, false},
+		{"Empty Logical Volume Name", "", "vg1", true, false},
+		{"Error on Exec", "snap1", "vg1", true, true},
+		{"Merge started successfully", "snap1", "vg1", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := log.IntoContext(context.Background(), testr.New(t))
+			executor := &test.MockExecutor{MockRunCommandAsHost: func(ctx context.Context, command string, args ...string) error {
+				if tt.execErr {
+					return fmt.Errorf("mocked error")
+				}
+
+				assert.Equal(t, lvConvertCmd, command)
+				assert.Equal(t, []string{"--merge", "--background", fmt.Sprintf("%s/%s", tt.vgName, tt.lvName)}, args)
+				return nil
+			}}
+
+			err := NewHostLVM(executor).MergeSnapshotLV(ctx, tt.lvName, tt.vgName)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestHostLVM_DeleteLV(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -583,6 +741,44 @@ func TestHostLVM_ActivateLV(t *testing.T) {
 	}
 }
 
+func TestHostLVM_RenameLV(t *testing.T) {
+	tests := []struct {
+		name        string
+		lvName      string
+		vgName      string
+		newName     string
+		wantErr     bool
+		lvRenameErr bool
+	}{
+		{"Empty Volume Group Name", "lv1", "", "lv2", true, false},
+		{"Empty Logical Volume Name", "", "vg1", "lv2", true, false},
+		{"Empty New Logical Volume Name", "lv1", "vg1", "", true, false},
+		{"Error on LV Rename", "lv1", "vg1", "lv2", true, true},
+		{"LV renamed successfully", "lv1", "vg1", "lv2", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := log.IntoContext(context.Background(), testr.New(t))
+			executor := &test.MockExecutor{MockRunCommandAsHost: func(ctx context.Context, command string, args ...string) error {
+				if tt.lvRenameErr {
+					return fmt.Errorf("mocked error")
+				}
+				assert.Equal(t, lvRenameCmd, command)
+				assert.Equal(t, []string{tt.vgName, tt.lvName, tt.newName}, args)
+				return nil
+			}}
+
+			err := NewHostLVM(executor).RenameLV(ctx, tt.lvName, tt.vgName, tt.newName)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestNewDefaultHostLVM(t *testing.T) {
 	lvm := NewDefaultHostLVM()
 	assert.NotNilf(t, lvm, "lvm should not be nil")
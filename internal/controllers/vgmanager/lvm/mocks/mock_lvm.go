@@ -158,6 +158,75 @@ func (_c *MockLVM_AddTagToVG_Call) RunAndReturn(run func(ctx context.Context, vg
 	return _c
 }
 
+// CopyLV provides a mock function for the type MockLVM
+func (_mock *MockLVM) CopyLV(ctx context.Context, vgName string, sourceName string, targetName string) error {
+	ret := _mock.Called(ctx, vgName, sourceName, targetName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CopyLV")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = returnFunc(ctx, vgName, sourceName, targetName)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLVM_CopyLV_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CopyLV'
+type MockLVM_CopyLV_Call struct {
+	*mock.Call
+}
+
+// CopyLV is a helper method to define mock.On call
+//   - ctx context.Context
+//   - vgName string
+//   - sourceName string
+//   - targetName string
+func (_e *MockLVM_Expecter) CopyLV(ctx interface{}, vgName interface{}, sourceName interface{}, targetName interface{}) *MockLVM_CopyLV_Call {
+	return &MockLVM_CopyLV_Call{Call: _e.mock.On("CopyLV", ctx, vgName, sourceName, targetName)}
+}
+
+func (_c *MockLVM_CopyLV_Call) Run(run func(ctx context.Context, vgName string, sourceName string, targetName string)) *MockLVM_CopyLV_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLVM_CopyLV_Call) Return(err error) *MockLVM_CopyLV_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLVM_CopyLV_Call) RunAndReturn(run func(ctx context.Context, vgName string, sourceName string, targetName string) error) *MockLVM_CopyLV_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateLV provides a mock function for the type MockLVM
 func (_mock *MockLVM) CreateLV(ctx context.Context, lvName string, vgName string, sizePercent int, chunkSizeBytes int64, metadataSizeBytes int64) error {
 	ret := _mock.Called(ctx, lvName, vgName, sizePercent, chunkSizeBytes, metadataSizeBytes)
@@ -239,6 +308,162 @@ func (_c *MockLVM_CreateLV_Call) RunAndReturn(run func(ctx context.Context, lvNa
 	return _c
 }
 
+// CreateSnapshotLV provides a mock function for the type MockLVM
+func (_mock *MockLVM) CreateSnapshotLV(ctx context.Context, lvName string, vgName string, originName string, sizeBytes int64, tags []string, readOnly bool) error {
+	ret := _mock.Called(ctx, lvName, vgName, originName, sizeBytes, tags, readOnly)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSnapshotLV")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, int64, []string, bool) error); ok {
+		r0 = returnFunc(ctx, lvName, vgName, originName, sizeBytes, tags, readOnly)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLVM_CreateSnapshotLV_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSnapshotLV'
+type MockLVM_CreateSnapshotLV_Call struct {
+	*mock.Call
+}
+
+// CreateSnapshotLV is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lvName string
+//   - vgName string
+//   - originName string
+//   - sizeBytes int64
+//   - tags []string
+//   - readOnly bool
+func (_e *MockLVM_Expecter) CreateSnapshotLV(ctx interface{}, lvName interface{}, vgName interface{}, originName interface{}, sizeBytes interface{}, tags interface{}, readOnly interface{}) *MockLVM_CreateSnapshotLV_Call {
+	return &MockLVM_CreateSnapshotLV_Call{Call: _e.mock.On("CreateSnapshotLV", ctx, lvName, vgName, originName, sizeBytes, tags, readOnly)}
+}
+
+func (_c *MockLVM_CreateSnapshotLV_Call) Run(run func(ctx context.Context, lvName string, vgName string, originName string, sizeBytes int64, tags []string, readOnly bool)) *MockLVM_CreateSnapshotLV_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 int64
+		if args[4] != nil {
+			arg4 = args[4].(int64)
+		}
+		var arg5 []string
+		if args[5] != nil {
+			arg5 = args[5].([]string)
+		}
+		var arg6 bool
+		if args[6] != nil {
+			arg6 = args[6].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+			arg6,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLVM_CreateSnapshotLV_Call) Return(err error) *MockLVM_CreateSnapshotLV_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLVM_CreateSnapshotLV_Call) RunAndReturn(run func(ctx context.Context, lvName string, vgName string, originName string, sizeBytes int64, tags []string, readOnly bool) error) *MockLVM_CreateSnapshotLV_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateThinSnapshotLV provides a mock function for the type MockLVM
+func (_mock *MockLVM) CreateThinSnapshotLV(ctx context.Context, lvName string, vgName string, originName string) error {
+	ret := _mock.Called(ctx, lvName, vgName, originName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateThinSnapshotLV")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = returnFunc(ctx, lvName, vgName, originName)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLVM_CreateThinSnapshotLV_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateThinSnapshotLV'
+type MockLVM_CreateThinSnapshotLV_Call struct {
+	*mock.Call
+}
+
+// CreateThinSnapshotLV is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lvName string
+//   - vgName string
+//   - originName string
+func (_e *MockLVM_Expecter) CreateThinSnapshotLV(ctx interface{}, lvName interface{}, vgName interface{}, originName interface{}) *MockLVM_CreateThinSnapshotLV_Call {
+	return &MockLVM_CreateThinSnapshotLV_Call{Call: _e.mock.On("CreateThinSnapshotLV", ctx, lvName, vgName, originName)}
+}
+
+func (_c *MockLVM_CreateThinSnapshotLV_Call) Run(run func(ctx context.Context, lvName string, vgName string, originName string)) *MockLVM_CreateThinSnapshotLV_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLVM_CreateThinSnapshotLV_Call) Return(err error) *MockLVM_CreateThinSnapshotLV_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLVM_CreateThinSnapshotLV_Call) RunAndReturn(run func(ctx context.Context, lvName string, vgName string, originName string) error) *MockLVM_CreateThinSnapshotLV_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateVG provides a mock function for the type MockLVM
 func (_mock *MockLVM) CreateVG(ctx context.Context, vg lvm.VolumeGroup, isWiped bool) error {
 	ret := _mock.Called(ctx, vg, isWiped)
@@ -302,6 +527,69 @@ func (_c *MockLVM_CreateVG_Call) RunAndReturn(run func(ctx context.Context, vg l
 	return _c
 }
 
+// DeactivateLV provides a mock function for the type MockLVM
+func (_mock *MockLVM) DeactivateLV(ctx context.Context, lvName string, vgName string) error {
+	ret := _mock.Called(ctx, lvName, vgName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeactivateLV")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, lvName, vgName)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLVM_DeactivateLV_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeactivateLV'
+type MockLVM_DeactivateLV_Call struct {
+	*mock.Call
+}
+
+// DeactivateLV is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lvName string
+//   - vgName string
+func (_e *MockLVM_Expecter) DeactivateLV(ctx interface{}, lvName interface{}, vgName interface{}) *MockLVM_DeactivateLV_Call {
+	return &MockLVM_DeactivateLV_Call{Call: _e.mock.On("DeactivateLV", ctx, lvName, vgName)}
+}
+
+func (_c *MockLVM_DeactivateLV_Call) Run(run func(ctx context.Context, lvName string, vgName string)) *MockLVM_DeactivateLV_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLVM_DeactivateLV_Call) Return(err error) *MockLVM_DeactivateLV_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLVM_DeactivateLV_Call) RunAndReturn(run func(ctx context.Context, lvName string, vgName string) error) *MockLVM_DeactivateLV_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteLV provides a mock function for the type MockLVM
 func (_mock *MockLVM) DeleteLV(ctx context.Context, lvName string, vgName string) error {
 	ret := _mock.Called(ctx, lvName, vgName)
@@ -491,6 +779,75 @@ func (_c *MockLVM_ExtendLV_Call) RunAndReturn(run func(ctx context.Context, lvNa
 	return _c
 }
 
+// ExtendSnapshotLV provides a mock function for the type MockLVM
+func (_mock *MockLVM) ExtendSnapshotLV(ctx context.Context, lvName string, vgName string, sizePercent int) error {
+	ret := _mock.Called(ctx, lvName, vgName, sizePercent)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExtendSnapshotLV")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int) error); ok {
+		r0 = returnFunc(ctx, lvName, vgName, sizePercent)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLVM_ExtendSnapshotLV_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExtendSnapshotLV'
+type MockLVM_ExtendSnapshotLV_Call struct {
+	*mock.Call
+}
+
+// ExtendSnapshotLV is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lvName string
+//   - vgName string
+//   - sizePercent int
+func (_e *MockLVM_Expecter) ExtendSnapshotLV(ctx interface{}, lvName interface{}, vgName interface{}, sizePercent interface{}) *MockLVM_ExtendSnapshotLV_Call {
+	return &MockLVM_ExtendSnapshotLV_Call{Call: _e.mock.On("ExtendSnapshotLV", ctx, lvName, vgName, sizePercent)}
+}
+
+func (_c *MockLVM_ExtendSnapshotLV_Call) Run(run func(ctx context.Context, lvName string, vgName string, sizePercent int)) *MockLVM_ExtendSnapshotLV_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLVM_ExtendSnapshotLV_Call) Return(err error) *MockLVM_ExtendSnapshotLV_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLVM_ExtendSnapshotLV_Call) RunAndReturn(run func(ctx context.Context, lvName string, vgName string, sizePercent int) error) *MockLVM_ExtendSnapshotLV_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ExtendThinPoolMetadata provides a mock function for the type MockLVM
 func (_mock *MockLVM) ExtendThinPoolMetadata(ctx context.Context, lvName string, vgName string, metadataSizeBytes int64) error {
 	ret := _mock.Called(ctx, lvName, vgName, metadataSizeBytes)
@@ -1042,6 +1399,69 @@ func (_c *MockLVM_ListVGs_Call) RunAndReturn(run func(ctx context.Context, tagge
 	return _c
 }
 
+// MergeSnapshotLV provides a mock function for the type MockLVM
+func (_mock *MockLVM) MergeSnapshotLV(ctx context.Context, lvName string, vgName string) error {
+	ret := _mock.Called(ctx, lvName, vgName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergeSnapshotLV")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, lvName, vgName)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLVM_MergeSnapshotLV_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MergeSnapshotLV'
+type MockLVM_MergeSnapshotLV_Call struct {
+	*mock.Call
+}
+
+// MergeSnapshotLV is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lvName string
+//   - vgName string
+func (_e *MockLVM_Expecter) MergeSnapshotLV(ctx interface{}, lvName interface{}, vgName interface{}) *MockLVM_MergeSnapshotLV_Call {
+	return &MockLVM_MergeSnapshotLV_Call{Call: _e.mock.On("MergeSnapshotLV", ctx, lvName, vgName)}
+}
+
+func (_c *MockLVM_MergeSnapshotLV_Call) Run(run func(ctx context.Context, lvName string, vgName string)) *MockLVM_MergeSnapshotLV_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLVM_MergeSnapshotLV_Call) Return(err error) *MockLVM_MergeSnapshotLV_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLVM_MergeSnapshotLV_Call) RunAndReturn(run func(ctx context.Context, lvName string, vgName string) error) *MockLVM_MergeSnapshotLV_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ReduceVG provides a mock function for the type MockLVM
 func (_mock *MockLVM) ReduceVG(ctx context.Context, vgName string, devices string) error {
 	ret := _mock.Called(ctx, vgName, devices)
@@ -1161,3 +1581,72 @@ func (_c *MockLVM_RemovePV_Call) RunAndReturn(run func(ctx context.Context, devi
 	_c.Call.Return(run)
 	return _c
 }
+
+// RenameLV provides a mock function for the type MockLVM
+func (_mock *MockLVM) RenameLV(ctx context.Context, lvName string, vgName string, newName string) error {
+	ret := _mock.Called(ctx, lvName, vgName, newName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenameLV")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = returnFunc(ctx, lvName, vgName, newName)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLVM_RenameLV_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenameLV'
+type MockLVM_RenameLV_Call struct {
+	*mock.Call
+}
+
+// RenameLV is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lvName string
+//   - vgName string
+//   - newName string
+func (_e *MockLVM_Expecter) RenameLV(ctx interface{}, lvName interface{}, vgName interface{}, newName interface{}) *MockLVM_RenameLV_Call {
+	return &MockLVM_RenameLV_Call{Call: _e.mock.On("RenameLV", ctx, lvName, vgName, newName)}
+}
+
+func (_c *MockLVM_RenameLV_Call) Run(run func(ctx context.Context, lvName string, vgName string, newName string)) *MockLVM_RenameLV_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLVM_RenameLV_Call) Return(err error) *MockLVM_RenameLV_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLVM_RenameLV_Call) RunAndReturn(run func(ctx context.Context, lvName string, vgName string, newName string) error) *MockLVM_RenameLV_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mkfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	vgmanagerexec "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/exec"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var (
+	DefaultMkfs  = "/usr/sbin/mkfs"
+	DefaultBlkid = "/usr/sbin/blkid"
+)
+
+// blkidNotFound is the exit code of blkid if no signature was found on the device
+const blkidNotFound = 2
+
+type Mkfs interface {
+	// Format creates a filesystem of the type with the options on the device, unless the device
+	// already contains a signature. It returns whether a filesystem was created.
+	Format(ctx context.Context, device, fsType string, options []string) (bool, error)
+}
+
+type HostMkfs struct {
+	vgmanagerexec.Executor
+	mkfs  string
+	blkid string
+}
+
+func NewDefaultHostMkfs() *HostMkfs {
+	return NewHostMkfs(&vgmanagerexec.CommandExecutor{}, DefaultMkfs, DefaultBlkid)
+}
+
+func NewHostMkfs(executor vgmanagerexec.Executor, mkfs, blkid string) *HostMkfs {
+	return &HostMkfs{
+		Executor: executor,
+		mkfs:     mkfs,
+		blkid:    blkid,
+	}
+}
+
+func (m *HostMkfs) Format(ctx context.Context, device, fsType string, options []string) (bool, error) {
+	if device == "" || fsType == "" {
+		return false, errors.New("failed to format the device: device or filesystem type is empty")
+	}
+
+	output, err := m.CombinedOutputCommandAsHost(ctx, m.blkid, "--probe", "--output", "export", device)
+	var exitErr interface{ ExitCode() int }
+	switch {
+	case err == nil:
+		log.FromContext(ctx).V(1).Info("device is already formatted", "device", device, "signature", strings.TrimSpace(string(output)))
+		return false, nil
+	case !errors.As(err, &exitErr) || exitErr.ExitCode() != blkidNotFound:
+		return false, fmt.Errorf("failed to probe the device %q: %s: %w", device, strings.TrimSpace(string(output)), err)
+	}
+
+	args := append([]string{"-t", fsType}, defaultOptions(fsType)...)
+	args = append(args, options...)
+	args = append(args, device)
+	if output, err := m.CombinedOutputCommandAsHost(ctx, m.mkfs, args...); err != nil {
+		return false, fmt.Errorf("failed to create %s filesystem on the device %q: %s: %w", fsType, device, strings.TrimSpace(string(output)), err)
+	}
+	log.FromContext(ctx).Info("created filesystem", "device", device, "fsType", fsType, "options", options)
+	return true, nil
+}
+
+// defaultOptions returns the options the node server of TopoLVM formats a device with,
+// so that the options of the device class are applied on top of the same defaults.
+func defaultOptions(fsType string) []string {
+	switch fsType {
+	case "ext3", "ext4":
+		return []string{"-F", "-m0"}
+	case "xfs":
+		return []string{"-f"}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,87 @@
+package mkfs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	mockExec "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type exitError int
+
+func (e exitError) Error() string { return "exit status" }
+func (e exitError) ExitCode() int { return int(e) }
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name          string
+		fsType        string
+		options       []string
+		probeOutput   string
+		probeErr      error
+		wantFormatted bool
+		wantArgs      []string
+		wantErr       bool
+	}{
+		{
+			name:          "xfs with options",
+			fsType:        "xfs",
+			options:       []string{"-m", "reflink=1"},
+			probeErr:      exitError(blkidNotFound),
+			wantFormatted: true,
+			wantArgs:      []string{"-t", "xfs", "-f", "-m", "reflink=1", "/dev/mapper/vg1-lv1"},
+		},
+		{
+			name:          "ext4 with options",
+			fsType:        "ext4",
+			options:       []string{"-E", "lazy_itable_init=1"},
+			probeErr:      exitError(blkidNotFound),
+			wantFormatted: true,
+			wantArgs:      []string{"-t", "ext4", "-F", "-m0", "-E", "lazy_itable_init=1", "/dev/mapper/vg1-lv1"},
+		},
+		{
+			name:        "already formatted",
+			fsType:      "xfs",
+			probeOutput: "DEVNAME=/dev/mapper/vg1-lv1\nTYPE=xfs\n",
+		},
+		{
+			name:     "probe failed",
+			fsType:   "xfs",
+			probeErr: exitError(4),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := log.IntoContext(context.Background(), testr.New(t))
+			var mkfsArgs []string
+			executor := &mockExec.MockExecutor{
+				MockCombinedOutputCommandAsHost: func(ctx context.Context, command string, args ...string) ([]byte, error) {
+					switch command {
+					case DefaultBlkid:
+						assert.Equal(t, []string{"--probe", "--output", "export", "/dev/mapper/vg1-lv1"}, args)
+						return []byte(tt.probeOutput), tt.probeErr
+					case DefaultMkfs:
+						mkfsArgs = args
+						return nil, nil
+					}
+					return nil, errors.New("unexpected command")
+				},
+			}
+
+			formatted, err := NewHostMkfs(executor, DefaultMkfs, DefaultBlkid).Format(ctx, "/dev/mapper/vg1-lv1", tt.fsType, tt.options)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFormatted, formatted)
+			assert.Equal(t, tt.wantArgs, mkfsArgs)
+		})
+	}
+}
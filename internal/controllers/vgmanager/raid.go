@@ -57,36 +57,6 @@ func validateRAIDDeviceCount(rc *lvmv1alpha1.RAIDConfig, deviceCount int) error
 	return nil
 }
 
-// computeOverheadFactor returns the raw-to-usable space ratio for the given RAID level and device count.
-func computeOverheadFactor(rc *lvmv1alpha1.RAIDConfig, deviceCount int) float64 {
-	mirrors := rc.EffectiveMirrors()
-
-	switch rc.Type {
-	case lvmv1alpha1.RAIDTypeRAID1:
-		return float64(mirrors + 1)
-	case lvmv1alpha1.RAIDTypeRAID4, lvmv1alpha1.RAIDTypeRAID5:
-		if rc.Stripes != nil {
-			return float64(*rc.Stripes+1) / float64(*rc.Stripes)
-		}
-		if deviceCount <= 1 {
-			return 1.0
-		}
-		return float64(deviceCount) / float64(deviceCount-1)
-	case lvmv1alpha1.RAIDTypeRAID6:
-		if rc.Stripes != nil {
-			return float64(*rc.Stripes+2) / float64(*rc.Stripes)
-		}
-		if deviceCount <= 2 {
-			return 1.0
-		}
-		return float64(deviceCount) / float64(deviceCount-2)
-	case lvmv1alpha1.RAIDTypeRAID10:
-		return float64(mirrors + 1)
-	default:
-		return 1.0
-	}
-}
-
 // buildRAIDStatus inspects logical volumes and physical volumes to build an aggregate RAIDStatus.
 // Returns nil only when both lvs and pvs are empty.
 func buildRAIDStatus(lvs []lvm.LogicalVolume, pvs []lvm.PhysicalVolume, raidType lvmv1alpha1.RAIDType) *lvmv1alpha1.RAIDStatus {
@@ -291,7 +291,7 @@ func TestComputeOverheadFactor(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := computeOverheadFactor(tt.config, tt.deviceCount)
+			got := tt.config.OverheadFactor(tt.deviceCount)
 			if got != tt.expected {
 				t.Errorf("expected %f, got %f", tt.expected, got)
 			}
@@ -0,0 +1,431 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lsblk"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"k8s.io/utils/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+// RecoveredLabel is set on every LogicalVolume and PersistentVolume that was reconstructed from node state.
+const RecoveredLabel = "lvms.topolvm.io/recovered"
+
+// provisionedByAnnotation marks the PersistentVolume as provisioned by TopoLVM so that the
+// external-provisioner handles it on deletion when the reclaim policy is Delete.
+const provisionedByAnnotation = "pv.kubernetes.io/provisioned-by"
+
+// Volume is a logical volume provisioned by TopoLVM whose API objects are missing,
+// together with the objects that have to be created to recover it.
+// The volume ID of the planned objects is the current name of the logical volume. On Apply, the logical
+// volume is renamed to the UID of the created LogicalVolume, as TopoLVM addresses logical volumes by the
+// UID of their LogicalVolume, and the volume ID of the objects is updated accordingly.
+type Volume struct {
+	LogicalVolume    *topolvmv1.LogicalVolume
+	PersistentVolume *corev1.PersistentVolume
+	// ReplacesPersistentVolume is set if a PersistentVolume for the logical volume still exists.
+	// Its volume handle can not be changed to the new volume ID, so it is replaced by PersistentVolume,
+	// which keeps its name, claim and reclaim policy.
+	ReplacesPersistentVolume bool
+}
+
+// Recovery reconstructs TopoLVM LogicalVolumes and PersistentVolumes for the logical volumes in
+// the LVMS volume groups of a node, e.g. after etcd was restored from an outdated backup or the
+// cluster was rebuilt on top of the same disks.
+type Recovery struct {
+	client   client.Client
+	lvm      lvm.LVM
+	lsblk    lsblk.LSBLK
+	nodeName string
+}
+
+func NewRecovery(client client.Client, lvm lvm.LVM, lsblk lsblk.LSBLK, nodeName string) *Recovery {
+	return &Recovery{
+		client:   client,
+		lvm:      lvm,
+		lsblk:    lsblk,
+		nodeName: nodeName,
+	}
+}
+
+// Plan scans all volume groups tagged by LVMS and returns the logical volumes provisioned by TopoLVM
+// that are no longer referenced by a LogicalVolume.
+// Logical volumes that were not provisioned by TopoLVM can be adopted with an LVMVolumeImport instead.
+func (r *Recovery) Plan(ctx context.Context) ([]Volume, error) {
+	logger := log.FromContext(ctx)
+
+	vgs, err := r.lvm.ListVGs(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume groups: %w", err)
+	}
+
+	referenced, err := r.referencedVolumeIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	persistentVolumes, err := r.persistentVolumesByVolumeHandle(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blockDevices, err := r.lsblk.ListBlockDevices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list block devices: %w", err)
+	}
+	fsTypes := make(map[string]string)
+	for _, device := range lsblk.FlattenedBlockDevices(blockDevices) {
+		if device.FSType != "" {
+			fsTypes[device.Name] = device.FSType
+		}
+	}
+
+	var volumes []Volume
+	for _, vg := range vgs {
+		lvReport, err := r.lvm.ListLVs(ctx, vg.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list logical volumes in volume group %s: %w", vg.Name, err)
+		}
+		for _, item := range lvReport.Report {
+			for _, lv := range item.Lv {
+				if !lvm.IsTopoLVMVolumeName(lv.Name) {
+					logger.V(1).Info("skipping logical volume not provisioned by TopoLVM", "LV", lv.Name, "VG", vg.Name)
+					continue
+				}
+				if name, ok := referenced[lv.Name]; ok {
+					logger.V(1).Info("skipping logical volume that is referenced by a LogicalVolume",
+						"LV", lv.Name, "VG", vg.Name, "LogicalVolume", name)
+					continue
+				}
+				lvAttr, err := vgmanager.ParsedLvAttr(lv.LvAttr)
+				if err != nil {
+					return nil, fmt.Errorf("could not parse lv_attr from logical volume %s: %w", lv.Name, err)
+				}
+				if lvAttr.VolumeType == vgmanager.VolumeTypeThinPool {
+					continue
+				}
+				// snapshots can only be restored together with their VolumeSnapshotContent, which can not
+				// be derived from the node, so they are not recovered as standalone volumes
+				if lv.Origin != "" {
+					logger.V(1).Info("skipping snapshot logical volume", "LV", lv.Name, "VG", vg.Name, "Origin", lv.Origin)
+					continue
+				}
+				size, err := strconv.ParseInt(lv.LvSize, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("could not parse lv_size from logical volume %s: %w", lv.Name, err)
+				}
+
+				volume := Volume{LogicalVolume: r.logicalVolume(lv, *resource.NewQuantity(size, resource.BinarySI))}
+				if existing, ok := persistentVolumes[lv.Name]; ok {
+					volume.PersistentVolume = replacementPersistentVolume(existing)
+					volume.ReplacesPersistentVolume = true
+				} else {
+					volume.PersistentVolume = r.persistentVolume(volume.LogicalVolume, fsTypes[lvm.DeviceMapperPath(lv.VgName, lv.Name)])
+				}
+				volumes = append(volumes, volume)
+			}
+		}
+	}
+
+	return volumes, nil
+}
+
+// Apply creates the objects of the planned volumes.
+// The LogicalVolume is created with the pending deletion annotation of TopoLVM so that TopoLVM does not
+// provision a new logical volume for it before the existing logical volume is renamed to its UID and
+// its status references it.
+func (r *Recovery) Apply(ctx context.Context, volumes []Volume) error {
+	for _, volume := range volumes {
+		volumeID, err := r.applyLogicalVolume(ctx, volume.LogicalVolume)
+		if err != nil {
+			return err
+		}
+		if err := r.applyPersistentVolume(ctx, volume, volumeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyLogicalVolume creates the LogicalVolume, renames the logical volume to its UID and returns the new volume ID.
+// A LogicalVolume left behind by an interrupted Apply is picked up again.
+func (r *Recovery) applyLogicalVolume(ctx context.Context, planned *topolvmv1.LogicalVolume) (string, error) {
+	logger := log.FromContext(ctx).WithValues("LogicalVolume", planned.GetName())
+
+	logicalVolume := planned.DeepCopy()
+	logicalVolume.Status = topolvmv1.LogicalVolumeStatus{}
+	logicalVolume.Annotations = map[string]string{topolvm.GetLVPendingDeletionKey(): "true"}
+	if err := r.client.Create(ctx, logicalVolume); err != nil {
+		if !k8serrors.IsAlreadyExists(err) {
+			return "", fmt.Errorf("failed to create LogicalVolume %s: %w", logicalVolume.GetName(), err)
+		}
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(logicalVolume), logicalVolume); err != nil {
+			return "", fmt.Errorf("failed to get LogicalVolume %s: %w", logicalVolume.GetName(), err)
+		}
+		if logicalVolume.GetLabels()[RecoveredLabel] != "true" || logicalVolume.Spec.NodeName != r.nodeName {
+			return "", fmt.Errorf("LogicalVolume %s already exists and was not created by a previous recovery", logicalVolume.GetName())
+		}
+	}
+
+	vgName := planned.Spec.DeviceClass
+	volumeID := string(logicalVolume.GetUID())
+	renamed, err := r.lvm.LVExists(ctx, volumeID, vgName)
+	if err != nil {
+		return "", fmt.Errorf("failed to check existence of logical volume %s in volume group %s: %w", volumeID, vgName, err)
+	}
+	if !renamed {
+		if err := r.lvm.RenameLV(ctx, planned.Status.VolumeID, vgName, volumeID); err != nil {
+			return "", fmt.Errorf("failed to rename logical volume for LogicalVolume %s: %w", logicalVolume.GetName(), err)
+		}
+		logger.Info("renamed logical volume to LogicalVolume UID", "from", planned.Status.VolumeID, "to", volumeID)
+	}
+
+	logicalVolume.Status = planned.Status
+	logicalVolume.Status.VolumeID = volumeID
+	if err := r.client.Status().Update(ctx, logicalVolume); err != nil {
+		return "", fmt.Errorf("failed to update status of LogicalVolume %s: %w", logicalVolume.GetName(), err)
+	}
+
+	patch := client.MergeFrom(logicalVolume.DeepCopy())
+	delete(logicalVolume.Annotations, topolvm.GetLVPendingDeletionKey())
+	if err := r.client.Patch(ctx, logicalVolume, patch); err != nil {
+		return "", fmt.Errorf("failed to remove pending deletion annotation from LogicalVolume %s: %w", logicalVolume.GetName(), err)
+	}
+	logger.Info("recovered LogicalVolume", "VolumeID", volumeID)
+	return volumeID, nil
+}
+
+// applyPersistentVolume creates the PersistentVolume with the new volume ID. An existing PersistentVolume
+// is switched to the Retain reclaim policy, so that its deletion does not delete the logical volume,
+// and is deleted before it is recreated with the same name.
+func (r *Recovery) applyPersistentVolume(ctx context.Context, volume Volume, volumeID string) error {
+	logger := log.FromContext(ctx).WithValues("PersistentVolume", volume.PersistentVolume.GetName())
+
+	pv := volume.PersistentVolume.DeepCopy()
+	pv.Spec.CSI.VolumeHandle = volumeID
+
+	if volume.ReplacesPersistentVolume {
+		existing := &corev1.PersistentVolume{}
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(pv), existing); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get PersistentVolume %s: %w", pv.GetName(), err)
+		} else if err == nil {
+			if existing.Spec.CSI != nil && existing.Spec.CSI.VolumeHandle == volumeID {
+				return nil
+			}
+			patch := client.MergeFrom(existing.DeepCopy())
+			existing.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+			existing.Finalizers = nil
+			if err := r.client.Patch(ctx, existing, patch); err != nil {
+				return fmt.Errorf("failed to retain PersistentVolume %s before replacing it: %w", pv.GetName(), err)
+			}
+			if err := r.client.Delete(ctx, existing); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete PersistentVolume %s before replacing it: %w", pv.GetName(), err)
+			}
+			logger.Info("deleted PersistentVolume with outdated volume handle", "VolumeHandle", existing.Spec.CSI.VolumeHandle)
+		}
+	}
+
+	if err := r.client.Create(ctx, pv); err != nil {
+		return fmt.Errorf("failed to create PersistentVolume %s: %w", pv.GetName(), err)
+	}
+	logger.Info("recovered PersistentVolume", "VolumeHandle", volumeID)
+	return nil
+}
+
+// WriteYAML writes the objects of the planned volumes as a multi-document YAML stream for review.
+// The volume IDs in the output are the current names of the logical volumes, which are replaced by
+// the UIDs of the LogicalVolumes on Apply.
+func WriteYAML(w io.Writer, volumes []Volume) error {
+	for _, volume := range volumes {
+		objs := []client.Object{volume.LogicalVolume}
+		if volume.PersistentVolume != nil {
+			objs = append(objs, volume.PersistentVolume)
+		}
+		for _, obj := range objs {
+			out, err := yaml.Marshal(obj)
+			if err != nil {
+				return fmt.Errorf("failed to marshal %s: %w", obj.GetName(), err)
+			}
+			if _, err := fmt.Fprintf(w, "---\n%s", out); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// referencedVolumeIDs returns the names of all logical volumes on this node that are owned by a LogicalVolume.
+func (r *Recovery) referencedVolumeIDs(ctx context.Context) (map[string]string, error) {
+	logicalVolumes := &topolvmv1.LogicalVolumeList{}
+	if err := r.client.List(ctx, logicalVolumes); err != nil {
+		return nil, fmt.Errorf("failed to list TopoLVM LogicalVolumes: %w", err)
+	}
+
+	referenced := make(map[string]string)
+	for _, logicalVolume := range logicalVolumes.Items {
+		if logicalVolume.Spec.NodeName != r.nodeName {
+			continue
+		}
+		if logicalVolume.Status.VolumeID != "" {
+			referenced[logicalVolume.Status.VolumeID] = logicalVolume.GetName()
+		}
+		referenced[string(logicalVolume.GetUID())] = logicalVolume.GetName()
+	}
+	return referenced, nil
+}
+
+func (r *Recovery) persistentVolumesByVolumeHandle(ctx context.Context) (map[string]*corev1.PersistentVolume, error) {
+	pvs := &corev1.PersistentVolumeList{}
+	if err := r.client.List(ctx, pvs); err != nil {
+		return nil, fmt.Errorf("failed to list PersistentVolumes: %w", err)
+	}
+
+	byHandle := make(map[string]*corev1.PersistentVolume)
+	for i, pv := range pvs.Items {
+		if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != constants.TopolvmCSIDriverName {
+			continue
+		}
+		byHandle[pv.Spec.CSI.VolumeHandle] = &pvs.Items[i]
+	}
+	return byHandle, nil
+}
+
+// replacementPersistentVolume returns a copy of an existing PersistentVolume that can be created once the
+// existing one is deleted. The claim is kept without its UID, so that the PersistentVolumeClaim binds again.
+func replacementPersistentVolume(existing *corev1.PersistentVolume) *corev1.PersistentVolume {
+	labels := map[string]string{RecoveredLabel: "true"}
+	for k, v := range existing.GetLabels() {
+		labels[k] = v
+	}
+	pv := &corev1.PersistentVolume{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "PersistentVolume",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        existing.GetName(),
+			Labels:      labels,
+			Annotations: existing.GetAnnotations(),
+		},
+		Spec: *existing.Spec.DeepCopy(),
+	}
+	if pv.Spec.ClaimRef != nil {
+		pv.Spec.ClaimRef = &corev1.ObjectReference{
+			Kind:       pv.Spec.ClaimRef.Kind,
+			APIVersion: pv.Spec.ClaimRef.APIVersion,
+			Namespace:  pv.Spec.ClaimRef.Namespace,
+			Name:       pv.Spec.ClaimRef.Name,
+		}
+	}
+	return pv
+}
+
+// logicalVolume returns the LogicalVolume for a logical volume. Its name is the name of the logical volume
+// as the name of the original LogicalVolume and PersistentVolume cannot be derived from the node.
+func (r *Recovery) logicalVolume(lv lvm.LogicalVolume, size resource.Quantity) *topolvmv1.LogicalVolume {
+	return &topolvmv1.LogicalVolume{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: topolvmv1.GroupVersion.String(),
+			Kind:       "LogicalVolume",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   lv.Name,
+			Labels: map[string]string{RecoveredLabel: "true"},
+		},
+		Spec: topolvmv1.LogicalVolumeSpec{
+			Name:        lv.Name,
+			NodeName:    r.nodeName,
+			DeviceClass: lv.VgName,
+			Size:        size,
+		},
+		Status: topolvmv1.LogicalVolumeStatus{
+			VolumeID:    lv.Name,
+			Code:        codes.OK,
+			CurrentSize: ptr.To(size),
+		},
+	}
+}
+
+// persistentVolume returns an unbound PersistentVolume for the LogicalVolume with the Retain reclaim policy.
+// It can be claimed by a PersistentVolumeClaim that sets spec.volumeName.
+// Logical volumes without a detected filesystem are recovered as block volumes, as a filesystem volume
+// would get formatted on its first mount.
+func (r *Recovery) persistentVolume(logicalVolume *topolvmv1.LogicalVolume, fsType string) *corev1.PersistentVolume {
+	volumeMode := corev1.PersistentVolumeFilesystem
+	if fsType == "" {
+		volumeMode = corev1.PersistentVolumeBlock
+	}
+	return &corev1.PersistentVolume{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "PersistentVolume",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        logicalVolume.GetName(),
+			Labels:      map[string]string{RecoveredLabel: "true"},
+			Annotations: map[string]string{provisionedByAnnotation: constants.TopolvmCSIDriverName},
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: logicalVolume.Spec.Size,
+			},
+			AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+			StorageClassName:              constants.StorageClassPrefix + logicalVolume.Spec.DeviceClass,
+			VolumeMode:                    &volumeMode,
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:       constants.TopolvmCSIDriverName,
+					VolumeHandle: logicalVolume.Status.VolumeID,
+					FSType:       fsType,
+					VolumeAttributes: map[string]string{
+						constants.DeviceClassKey: logicalVolume.Spec.DeviceClass,
+					},
+				},
+			},
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      topolvm.GetTopologyNodeKey(),
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{logicalVolume.Spec.NodeName},
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
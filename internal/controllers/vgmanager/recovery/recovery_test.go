@@ -0,0 +1,151 @@
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lsblk"
+	lsblkmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lsblk/mocks"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	lvmmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const (
+	testNode         = "test-node"
+	testVG           = "vg1"
+	referencedLV     = "0d2b1c3e-6a1f-4e4c-8d43-2b9a7a3c1f10"
+	unreferencedLV   = "7e6f4f7c-2f5c-4a0d-9a0e-5f3c2b1d0e9a"
+	unreferencedBlk  = "9c1f0e2d-3b4a-4c5d-8e6f-7a8b9c0d1e2f"
+	pvWithoutLVObjLV = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+	snapshotLV       = "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, topolvmv1.AddToScheme(scheme))
+	return scheme
+}
+
+func TestRecovery(t *testing.T) {
+	ctx := context.Background()
+
+	clnt := fake.NewClientBuilder().
+		WithScheme(newScheme(t)).
+		WithObjects(
+			&topolvmv1.LogicalVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pvc-referenced"},
+				Spec:       topolvmv1.LogicalVolumeSpec{NodeName: testNode, DeviceClass: testVG},
+				Status:     topolvmv1.LogicalVolumeStatus{VolumeID: referencedLV},
+			},
+			&corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pvc-existing", Finalizers: []string{"kubernetes.io/pv-protection"}},
+				Spec: corev1.PersistentVolumeSpec{
+					PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+					ClaimRef:                      &corev1.ObjectReference{Namespace: "default", Name: "data", UID: "old-claim-uid"},
+					PersistentVolumeSource: corev1.PersistentVolumeSource{
+						CSI: &corev1.CSIPersistentVolumeSource{Driver: constants.TopolvmCSIDriverName, VolumeHandle: pvWithoutLVObjLV},
+					},
+				},
+			},
+		).
+		WithStatusSubresource(&topolvmv1.LogicalVolume{}).
+		WithInterceptorFuncs(interceptor.Funcs{Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if _, ok := obj.(*topolvmv1.LogicalVolume); ok {
+				obj.SetUID(types.UID("uid-" + obj.GetName()))
+			}
+			return client.Create(ctx, obj, opts...)
+		}}).
+		Build()
+
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListVGs(mock.Anything, true).Return([]lvm.VolumeGroup{{Name: testVG}}, nil)
+	mockLVM.EXPECT().ListLVs(mock.Anything, testVG).Return(&lvm.LVReport{Report: []lvm.LVReportItem{{Lv: []lvm.LogicalVolume{
+		{Name: "thin-pool-1", VgName: testVG, LvAttr: "twi-a-tz--", LvSize: "10737418240"},
+		{Name: referencedLV, VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-aotz--", LvSize: "1073741824"},
+		{Name: unreferencedLV, VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-a-tz--", LvSize: "1073741824"},
+		{Name: unreferencedBlk, VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-a-tz--", LvSize: "2147483648"},
+		{Name: pvWithoutLVObjLV, VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-a-tz--", LvSize: "1073741824"},
+		{Name: "manually-created", VgName: testVG, PoolName: "thin-pool-1", LvAttr: "Vwi-a-tz--", LvSize: "1073741824"},
+		{Name: snapshotLV, VgName: testVG, PoolName: "thin-pool-1", Origin: unreferencedLV, LvAttr: "Vwi---tz-k", LvSize: "1073741824"},
+	}}}}, nil)
+	for _, lv := range []string{unreferencedLV, unreferencedBlk, pvWithoutLVObjLV} {
+		mockLVM.EXPECT().LVExists(mock.Anything, "uid-"+lv, testVG).Return(false, nil).Once()
+		mockLVM.EXPECT().RenameLV(mock.Anything, lv, testVG, "uid-"+lv).Return(nil).Once()
+	}
+	mockLSBLK := lsblkmocks.NewMockLSBLK(t)
+	mockLSBLK.EXPECT().ListBlockDevices(mock.Anything).Return([]lsblk.BlockDevice{{
+		Name: "/dev/sda", KName: "/dev/sda", Type: "disk", FSType: "LVM2_member",
+		Children: []lsblk.BlockDevice{
+			{Name: lvm.DeviceMapperPath(testVG, unreferencedLV), KName: "/dev/dm-3", Type: "lvm", FSType: "xfs"},
+		},
+	}}, nil)
+
+	r := NewRecovery(clnt, mockLVM, mockLSBLK, testNode)
+
+	volumes, err := r.Plan(ctx)
+	require.NoError(t, err)
+	require.Len(t, volumes, 3)
+
+	assert.Equal(t, unreferencedLV, volumes[0].LogicalVolume.Status.VolumeID)
+	assert.Equal(t, int64(1073741824), volumes[0].LogicalVolume.Spec.Size.Value())
+	require.NotNil(t, volumes[0].PersistentVolume)
+	assert.Equal(t, "xfs", volumes[0].PersistentVolume.Spec.CSI.FSType)
+	assert.Equal(t, corev1.PersistentVolumeFilesystem, *volumes[0].PersistentVolume.Spec.VolumeMode)
+
+	assert.Equal(t, unreferencedBlk, volumes[1].LogicalVolume.Status.VolumeID)
+	require.NotNil(t, volumes[1].PersistentVolume)
+	assert.Empty(t, volumes[1].PersistentVolume.Spec.CSI.FSType)
+	assert.Equal(t, corev1.PersistentVolumeBlock, *volumes[1].PersistentVolume.Spec.VolumeMode)
+
+	assert.Equal(t, pvWithoutLVObjLV, volumes[2].LogicalVolume.Status.VolumeID)
+	assert.True(t, volumes[2].ReplacesPersistentVolume, "existing PersistentVolume should be replaced")
+	require.NotNil(t, volumes[2].PersistentVolume)
+	assert.Equal(t, "pvc-existing", volumes[2].PersistentVolume.GetName())
+	assert.Empty(t, volumes[2].PersistentVolume.Spec.ClaimRef.UID)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, WriteYAML(buf, volumes))
+	assert.Contains(t, buf.String(), "kind: LogicalVolume")
+	assert.Contains(t, buf.String(), "kind: PersistentVolume")
+	assert.Contains(t, buf.String(), "volumeID: "+unreferencedLV)
+
+	require.NoError(t, r.Apply(ctx, volumes))
+
+	for _, volume := range volumes {
+		logicalVolume := &topolvmv1.LogicalVolume{}
+		require.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(volume.LogicalVolume), logicalVolume))
+		assert.Equal(t, "uid-"+volume.LogicalVolume.Status.VolumeID, logicalVolume.Status.VolumeID,
+			"the logical volume should be renamed to the UID of the LogicalVolume")
+		assert.True(t, volume.LogicalVolume.Spec.Size.Equal(*logicalVolume.Status.CurrentSize))
+		assert.NotContains(t, logicalVolume.GetAnnotations(), topolvm.GetLVPendingDeletionKey())
+
+		if volume.PersistentVolume != nil {
+			pv := &corev1.PersistentVolume{}
+			require.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(volume.PersistentVolume), pv))
+			assert.Equal(t, logicalVolume.Status.VolumeID, pv.Spec.CSI.VolumeHandle)
+			if volume.ReplacesPersistentVolume {
+				assert.Equal(t, corev1.PersistentVolumeReclaimDelete, pv.Spec.PersistentVolumeReclaimPolicy,
+					"the replacement should keep the reclaim policy")
+				assert.Equal(t, "data", pv.Spec.ClaimRef.Name)
+			} else {
+				assert.Equal(t, corev1.PersistentVolumeReclaimRetain, pv.Spec.PersistentVolumeReclaimPolicy)
+			}
+		}
+	}
+}
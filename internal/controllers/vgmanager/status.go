@@ -18,8 +18,12 @@ package vgmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 
 	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/filter"
@@ -27,21 +31,41 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+const (
+	ReasonDevicesPending       = "DevicesPending"
+	ReasonDevicesAttached      = "DevicesAttached"
+	ReasonLVMDConfigured       = "LVMDConfigured"
+	ReasonThinPoolHealthy      = "ThinPoolHealthy"
+	ReasonLeaving              = "Leaving"
+	ReasonFailed               = "Failed"
+	ReasonVolumeGroupsReady    = "VolumeGroupsReady"
+	ReasonVolumeGroupsNotReady = "VolumeGroupsNotReady"
+	ReasonReconciled           = "Reconciled"
+	ReasonNotStalled           = "NotStalled"
+)
+
 func (r *Reconciler) setVolumeGroupProgressingStatus(ctx context.Context, vg *lvmv1alpha1.LVMVolumeGroup, vgs []lvm.VolumeGroup, devices FilteredBlockDevices) (bool, error) {
-	status := &lvmv1alpha1.VGStatus{
+	status := &lvmv1alpha1.VolumeGroupStatus{VGStatus: lvmv1alpha1.VGStatus{
 		Name:   vg.GetName(),
 		Status: lvmv1alpha1.VGStatusProgressing,
-	}
+	}}
 
 	// Set devices for the VGStatus.
-	if _, err := r.setDevices(status, vgs, devices); err != nil {
+	if devicesExist, err := r.setDevices(status, vgs, devices); err != nil {
 		return false, err
+	} else if !devicesExist {
+		// the VG was not created on this node yet
+		status.Transition = lvmv1alpha1.VGTransitionJoining
 	}
+	setCapacity(status, vgs)
 
 	if vg.Spec.RAIDConfig != nil {
 		if err := r.applyRAIDStatus(ctx, vg, vgs, status); err != nil {
@@ -49,18 +73,32 @@ func (r *Reconciler) setVolumeGroupProgressingStatus(ctx context.Context, vg *lv
 		}
 	}
 
-	return r.setVolumeGroupStatus(ctx, vg, status)
+	return r.setVolumeGroupStatus(ctx, vg, status, metav1.Condition{
+		Type:    lvmv1alpha1.VGConditionDevicesReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonDevicesPending,
+		Message: "new devices are being added to the volume group",
+	})
 }
 
 func (r *Reconciler) setVolumeGroupReadyStatus(ctx context.Context, vg *lvmv1alpha1.LVMVolumeGroup, vgs []lvm.VolumeGroup, devices FilteredBlockDevices) (bool, error) {
-	status := &lvmv1alpha1.VGStatus{
-		Name:   vg.GetName(),
-		Status: lvmv1alpha1.VGStatusReady,
+	status := &lvmv1alpha1.VolumeGroupStatus{
+		VGStatus: lvmv1alpha1.VGStatus{
+			Name:   vg.GetName(),
+			Status: lvmv1alpha1.VGStatusReady,
+		},
+		AppliedGeneration: vg.GetGeneration(),
 	}
 
 	if _, err := r.setDevices(status, vgs, devices); err != nil {
 		return false, err
 	}
+	setCapacity(status, vgs)
+	if vg.Spec.ThinPoolConfig != nil && status.Capacity != nil {
+		if err := r.applyThinPoolUsage(ctx, vg, status); err != nil {
+			return false, fmt.Errorf("failed to collect thin pool usage: %w", err)
+		}
+	}
 
 	if vg.Spec.RAIDConfig != nil {
 		if err := r.applyRAIDStatus(ctx, vg, vgs, status); err != nil {
@@ -68,21 +106,98 @@ func (r *Reconciler) setVolumeGroupReadyStatus(ctx context.Context, vg *lvmv1alp
 		}
 	}
 
-	return r.setVolumeGroupStatus(ctx, vg, status)
+	conditions := []metav1.Condition{
+		{
+			Type:    lvmv1alpha1.VGConditionDevicesReady,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonDevicesAttached,
+			Message: "all the available devices are attached to the volume group",
+		},
+		{
+			Type:    lvmv1alpha1.VGConditionLVMDConfigured,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonLVMDConfigured,
+			Message: "the volume group is configured in the lvmd config",
+		},
+	}
+	if vg.Spec.ThinPoolConfig != nil {
+		conditions = append(conditions, metav1.Condition{
+			Type:    lvmv1alpha1.VGConditionThinPoolHealthy,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonThinPoolHealthy,
+			Message: fmt.Sprintf("the thin pool %s is active and healthy", vg.Spec.ThinPoolConfig.Name),
+		})
+	}
+
+	return r.setVolumeGroupStatus(ctx, vg, status, conditions...)
 }
 
-func (r *Reconciler) setVolumeGroupFailedStatus(ctx context.Context, vg *lvmv1alpha1.LVMVolumeGroup, vgs []lvm.VolumeGroup, devices FilteredBlockDevices, err error) (bool, error) {
-	status := &lvmv1alpha1.VGStatus{
+func (r *Reconciler) setVolumeGroupPausedStatus(ctx context.Context, vg *lvmv1alpha1.LVMVolumeGroup, vgs []lvm.VolumeGroup, reason string) (bool, error) {
+	status := &lvmv1alpha1.VolumeGroupStatus{VGStatus: lvmv1alpha1.VGStatus{
 		Name:   vg.GetName(),
-		Status: lvmv1alpha1.VGStatusFailed,
-		Reason: err.Error(),
+		Status: lvmv1alpha1.VGStatusPaused,
+		Reason: reason,
+	}}
+
+	// devices are not discovered during maintenance, so only the devices already in the volume group are reported.
+	if _, err := r.setDevices(status, vgs, FilteredBlockDevices{}); err != nil {
+		return false, err
+	}
+	setCapacity(status, vgs)
+
+	if vg.Spec.RAIDConfig != nil {
+		if err := r.applyRAIDStatus(ctx, vg, vgs, status); err != nil {
+			return false, fmt.Errorf("failed to collect RAID status: %w", err)
+		}
+	}
+
+	return r.setVolumeGroupStatus(ctx, vg, status)
+}
+
+// setVolumeGroupLeavingStatus reports that the volume group is torn down on a node that is no longer selected for it.
+func (r *Reconciler) setVolumeGroupLeavingStatus(ctx context.Context, vg *lvmv1alpha1.LVMVolumeGroup, vgs []lvm.VolumeGroup, reason string) (bool, error) {
+	status := &lvmv1alpha1.VolumeGroupStatus{
+		VGStatus: lvmv1alpha1.VGStatus{
+			Name:   vg.GetName(),
+			Status: lvmv1alpha1.VGStatusProgressing,
+			Reason: reason,
+		},
+		Transition: lvmv1alpha1.VGTransitionLeaving,
+	}
+
+	// devices are not discovered on a node that is no longer selected, so only the devices in the volume group are reported.
+	if _, err := r.setDevices(status, vgs, FilteredBlockDevices{}); err != nil {
+		return false, err
+	}
+	setCapacity(status, vgs)
+
+	return r.setVolumeGroupStatus(ctx, vg, status, metav1.Condition{
+		Type:    lvmv1alpha1.VGConditionDevicesReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonLeaving,
+		Message: reason,
+	})
+}
+
+// setVolumeGroupFailedStatus reports the volume group as failed, or degraded if it already has devices.
+// The condition of the given type is set to False with the error as message.
+func (r *Reconciler) setVolumeGroupFailedStatus(ctx context.Context, vg *lvmv1alpha1.LVMVolumeGroup, vgs []lvm.VolumeGroup, devices FilteredBlockDevices, reason EventReasonError, err error) (bool, error) {
+	conditionType, reasonCode := failureReason(reason, err)
+	status := &lvmv1alpha1.VolumeGroupStatus{
+		VGStatus: lvmv1alpha1.VGStatus{
+			Name:   vg.GetName(),
+			Status: lvmv1alpha1.VGStatusFailed,
+			Reason: err.Error(),
+		},
+		ReasonCode: reasonCode,
 	}
 
 	if devicesExist, err := r.setDevices(status, vgs, devices); err != nil {
-		return false, fmt.Errorf("could not set devices in VGStatus: %w", err)
+		return false, fmt.Errorf("could not set devices in volume group status: %w", err)
 	} else if devicesExist {
 		status.Status = lvmv1alpha1.VGStatusDegraded
 	}
+	setCapacity(status, vgs)
 
 	if vg.Spec.RAIDConfig != nil {
 		if err := r.applyRAIDStatus(ctx, vg, vgs, status); err != nil {
@@ -90,10 +205,48 @@ func (r *Reconciler) setVolumeGroupFailedStatus(ctx context.Context, vg *lvmv1al
 		}
 	}
 
-	return r.setVolumeGroupStatus(ctx, vg, status)
+	return r.setVolumeGroupStatus(ctx, vg, status, metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  string(reasonCode),
+		Message: err.Error(),
+	})
 }
 
-func (r *Reconciler) setVolumeGroupStatus(ctx context.Context, vg *lvmv1alpha1.LVMVolumeGroup, status *lvmv1alpha1.VGStatus) (bool, error) {
+// failureReasons maps the event reason of a failure to the condition it fails and its reason code.
+var failureReasons = map[EventReasonError]struct {
+	conditionType string
+	reasonCode    lvmv1alpha1.VGReasonCode
+}{
+	EventReasonErrorNoAvailableDevicesForVG:      {lvmv1alpha1.VGConditionDevicesReady, lvmv1alpha1.VGReasonNoAvailableDevices},
+	EventReasonErrorDevicePathCheckFailed:        {lvmv1alpha1.VGConditionDevicesReady, lvmv1alpha1.VGReasonMandatoryPathMissing},
+	EventReasonErrorDeviceRemovalFailed:          {lvmv1alpha1.VGConditionDevicesReady, lvmv1alpha1.VGReasonDeviceRemovalFailed},
+	EventReasonErrorVGCreateOrExtendFailed:       {lvmv1alpha1.VGConditionDevicesReady, lvmv1alpha1.VGReasonVGCreateOrExtendFailed},
+	EventReasonErrorVGDeletionFailed:             {lvmv1alpha1.VGConditionDevicesReady, lvmv1alpha1.VGReasonVGDeletionFailed},
+	EventReasonErrorThinPoolCreateOrExtendFailed: {lvmv1alpha1.VGConditionThinPoolHealthy, lvmv1alpha1.VGReasonThinPoolCreateOrExtendFailed},
+	EventReasonErrorThinPoolDeletionFailed:       {lvmv1alpha1.VGConditionThinPoolHealthy, lvmv1alpha1.VGReasonThinPoolDeletionFailed},
+	EventReasonErrorInconsistentLVs:              {lvmv1alpha1.VGConditionThinPoolHealthy, lvmv1alpha1.VGReasonInconsistentLVs},
+	EventReasonErrorRAIDHealthCheckFailed:        {lvmv1alpha1.VGConditionRAIDHealthy, lvmv1alpha1.VGReasonRAIDMissingPV},
+	EventReasonErrorLVMDConfigReadFailed:         {lvmv1alpha1.VGConditionLVMDConfigured, lvmv1alpha1.VGReasonLVMDConfigReadFailed},
+	EventReasonErrorLVMDConfigWriteFailed:        {lvmv1alpha1.VGConditionLVMDConfigured, lvmv1alpha1.VGReasonLVMDConfigWriteFailed},
+}
+
+// failureReason returns the condition that is failed and the reason code for a failure with the given event reason.
+// Unknown event reasons fail the Ready condition with the reason code Unknown.
+func failureReason(reason EventReasonError, err error) (string, lvmv1alpha1.VGReasonCode) {
+	failure, ok := failureReasons[reason]
+	if !ok {
+		return lvmv1alpha1.VGConditionReady, lvmv1alpha1.VGReasonUnknown
+	}
+	if reason == EventReasonErrorInconsistentLVs && errors.Is(err, ErrThinPoolMetadataFull) {
+		return failure.conditionType, lvmv1alpha1.VGReasonThinPoolMetadataFull
+	}
+	return failure.conditionType, failure.reasonCode
+}
+
+// setVolumeGroupStatus sets the status of the volume group in the status of the LVMVolumeGroupNodeStatus of the node,
+// together with the given conditions, and mirrors it to the deprecated VGStatus in its spec.
+func (r *Reconciler) setVolumeGroupStatus(ctx context.Context, vg *lvmv1alpha1.LVMVolumeGroup, status *lvmv1alpha1.VolumeGroupStatus, conditions ...metav1.Condition) (bool, error) {
 	logger := log.FromContext(ctx).WithValues("VolumeGroup", client.ObjectKeyFromObject(vg))
 
 	if hasExplicitDevicePaths(vg) {
@@ -118,24 +271,171 @@ func (r *Reconciler) setVolumeGroupStatus(ctx context.Context, vg *lvmv1alpha1.L
 		for i, existingVGStatus := range nodeStatus.Spec.LVMVGStatus {
 			if existingVGStatus.Name == status.Name {
 				exists = true
-				nodeStatus.Spec.LVMVGStatus[i] = *status
+				nodeStatus.Spec.LVMVGStatus[i] = status.VGStatus
 			}
 		}
 		if !exists {
-			nodeStatus.Spec.LVMVGStatus = append(nodeStatus.Spec.LVMVGStatus, *status)
+			nodeStatus.Spec.LVMVGStatus = append(nodeStatus.Spec.LVMVGStatus, status.VGStatus)
 		}
 
 		return nil
 	})
-
-	updated := result != controllerutil.OperationResultNone
 	if err != nil {
-		return updated, fmt.Errorf("LVMVolumeGroupNodeStatus could not be updated: %w", err)
+		return false, fmt.Errorf("LVMVolumeGroupNodeStatus could not be updated: %w", err)
 	}
-	if updated {
+
+	changed := result != controllerutil.OperationResultNone
+	err = r.updateVolumeGroupStatus(ctx, nodeStatus, status.Name, func(vgStatus *lvmv1alpha1.VolumeGroupStatus) {
+		previous := vgStatus.DeepCopy()
+		// the logical volume consistency is reported by a separate controller and has to be kept
+		status.LogicalVolumeConsistency = previous.LogicalVolumeConsistency
+		// the applied generation is only advanced by the ready status and kept by all other statuses
+		if status.AppliedGeneration == 0 {
+			status.AppliedGeneration = previous.AppliedGeneration
+		}
+		status.Conditions = previous.DeepCopy().Conditions
+		setVolumeGroupConditions(&status.Conditions, vg, status, conditions)
+
+		// the capacity changes with every provisioned volume, so a change of it alone is not reported as an update
+		previous.Capacity = status.Capacity
+		previous.Conditions = status.Conditions
+		changed = changed || !equality.Semantic.DeepEqual(previous, status)
+		*vgStatus = *status
+	})
+	if changed {
 		logger.Info("LVMVolumeGroupNodeStatus modified", "operation", result, "name", nodeStatus.Name)
 	}
-	return updated, nil
+	updateVolumeGroupMetrics(r.NodeName, status.Name, status)
+	return changed, err
+}
+
+// setVolumeGroupConditions applies the conditions of a volume group derived from its status and the given conditions.
+func setVolumeGroupConditions(vgConditions *[]metav1.Condition, vg *lvmv1alpha1.LVMVolumeGroup, status *lvmv1alpha1.VolumeGroupStatus, conditions []metav1.Condition) {
+	if vg.Spec.ThinPoolConfig == nil {
+		meta.RemoveStatusCondition(vgConditions, lvmv1alpha1.VGConditionThinPoolHealthy)
+	}
+
+	if vg.Spec.RAIDConfig == nil {
+		meta.RemoveStatusCondition(vgConditions, lvmv1alpha1.VGConditionRAIDHealthy)
+	} else if status.RAIDStatus != nil {
+		raidHealthy := metav1.Condition{
+			Type:    lvmv1alpha1.VGConditionRAIDHealthy,
+			Status:  metav1.ConditionTrue,
+			Reason:  string(status.RAIDStatus.Status),
+			Message: "all the RAID logical volumes are healthy",
+		}
+		if status.RAIDStatus.Status != lvmv1alpha1.RAIDHealthStatusHealthy {
+			raidHealthy.Status = metav1.ConditionFalse
+			raidHealthy.Message = fmt.Sprintf("the RAID logical volumes are %s", strings.ToLower(string(status.RAIDStatus.Status)))
+		}
+		conditions = append([]metav1.Condition{raidHealthy}, conditions...)
+	}
+
+	ready := metav1.Condition{
+		Type:    lvmv1alpha1.VGConditionReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  string(status.Status),
+		Message: status.Reason,
+	}
+	if status.Status == lvmv1alpha1.VGStatusReady {
+		ready.Status = metav1.ConditionTrue
+		ready.Message = "the volume group is ready"
+	} else if ready.Message == "" {
+		ready.Message = fmt.Sprintf("the volume group is %s", strings.ToLower(string(status.Status)))
+	}
+	conditions = append(conditions, ready)
+
+	for _, condition := range conditions {
+		condition.ObservedGeneration = vg.GetGeneration()
+		meta.SetStatusCondition(vgConditions, condition)
+	}
+}
+
+// updateVolumeGroupStatus applies the mutation to the named volume group in the status of the
+// LVMVolumeGroupNodeStatus, or removes the volume group from the status if the mutation is nil.
+// The conditions of the node are recomputed from all volume groups and the status is only updated on a change.
+func (r *Reconciler) updateVolumeGroupStatus(ctx context.Context, nodeStatus *lvmv1alpha1.LVMVolumeGroupNodeStatus, vgName string, mutate func(*lvmv1alpha1.VolumeGroupStatus)) error {
+	original := nodeStatus.Status.DeepCopy()
+
+	index := slices.IndexFunc(nodeStatus.Status.VolumeGroups, func(vg lvmv1alpha1.VolumeGroupStatus) bool {
+		return vg.Name == vgName
+	})
+	if mutate == nil {
+		if index >= 0 {
+			nodeStatus.Status.VolumeGroups = slices.Delete(nodeStatus.Status.VolumeGroups, index, index+1)
+		}
+	} else {
+		if index < 0 {
+			nodeStatus.Status.VolumeGroups = append(nodeStatus.Status.VolumeGroups, lvmv1alpha1.VolumeGroupStatus{VGStatus: lvmv1alpha1.VGStatus{Name: vgName}})
+			index = len(nodeStatus.Status.VolumeGroups) - 1
+		}
+		mutate(&nodeStatus.Status.VolumeGroups[index])
+	}
+	setNodeConditions(nodeStatus)
+
+	if equality.Semantic.DeepEqual(original, &nodeStatus.Status) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, nodeStatus); err != nil {
+		return fmt.Errorf("failed to update status of LVMVolumeGroupNodeStatus: %w", err)
+	}
+	return nil
+}
+
+// setNodeConditions summarizes the state of all volume groups on the node in the Ready, Reconciling
+// and Stalled conditions of the LVMVolumeGroupNodeStatus.
+func setNodeConditions(nodeStatus *lvmv1alpha1.LVMVolumeGroupNodeStatus) {
+	var notReady, progressing, failed []string
+	for _, vgStatus := range nodeStatus.Status.VolumeGroups {
+		switch vgStatus.Status {
+		case lvmv1alpha1.VGStatusReady:
+			continue
+		case lvmv1alpha1.VGStatusProgressing:
+			progressing = append(progressing, vgStatus.Name)
+		case lvmv1alpha1.VGStatusFailed:
+			failed = append(failed, vgStatus.Name)
+		}
+		notReady = append(notReady, fmt.Sprintf("%s (%s)", vgStatus.Name, vgStatus.Status))
+	}
+
+	ready := metav1.Condition{
+		Type:    lvmv1alpha1.VGConditionReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonVolumeGroupsReady,
+		Message: "all the volume groups on the node are ready",
+	}
+	if len(notReady) > 0 {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = ReasonVolumeGroupsNotReady
+		ready.Message = fmt.Sprintf("volume groups are not ready: %s", strings.Join(notReady, ", "))
+	}
+	meta.SetStatusCondition(&nodeStatus.Status.Conditions, ready)
+
+	reconciling := metav1.Condition{
+		Type:    lvmv1alpha1.VGConditionReconciling,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonReconciled,
+		Message: "no volume group on the node is being set up or torn down",
+	}
+	if len(progressing) > 0 {
+		reconciling.Status = metav1.ConditionTrue
+		reconciling.Reason = string(lvmv1alpha1.VGStatusProgressing)
+		reconciling.Message = fmt.Sprintf("volume groups are being set up or torn down: %s", strings.Join(progressing, ", "))
+	}
+	meta.SetStatusCondition(&nodeStatus.Status.Conditions, reconciling)
+
+	stalled := metav1.Condition{
+		Type:    lvmv1alpha1.VGConditionStalled,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonNotStalled,
+		Message: "no volume group on the node failed",
+	}
+	if len(failed) > 0 {
+		stalled.Status = metav1.ConditionTrue
+		stalled.Reason = ReasonFailed
+		stalled.Message = fmt.Sprintf("volume groups failed: %s", strings.Join(failed, ", "))
+	}
+	meta.SetStatusCondition(&nodeStatus.Status.Conditions, stalled)
 }
 
 func (r *Reconciler) removeVolumeGroupStatus(ctx context.Context, vg *lvmv1alpha1.LVMVolumeGroup) error {
@@ -144,6 +444,7 @@ func (r *Reconciler) removeVolumeGroupStatus(ctx context.Context, vg *lvmv1alpha
 	if vg.Spec.RAIDConfig != nil {
 		deleteRAIDMetrics(r.NodeName, vg.GetName())
 	}
+	deleteVolumeGroupMetrics(r.NodeName, vg.GetName())
 
 	// Get LVMVolumeGroupNodeStatus and remove the relevant VGStatus
 	nodeStatus := &lvmv1alpha1.LVMVolumeGroupNodeStatus{
@@ -189,10 +490,61 @@ func (r *Reconciler) removeVolumeGroupStatus(ctx context.Context, vg *lvmv1alpha
 		logger.Info("LVMVolumeGroupNodeStatus unchanged")
 	}
 
+	return r.updateVolumeGroupStatus(ctx, nodeStatus, vg.GetName(), nil)
+}
+
+// setCapacity sets the size and free space of the volume group as reported by lvm.
+// The capacity is not set if the volume group does not exist on the node.
+func setCapacity(status *lvmv1alpha1.VolumeGroupStatus, vgs []lvm.VolumeGroup) {
+	for _, vg := range vgs {
+		if vg.Name != status.Name {
+			continue
+		}
+		size, err := strconv.ParseFloat(vg.VgSize, 64)
+		if err != nil {
+			return
+		}
+		free, err := strconv.ParseFloat(vg.VgFree, 64)
+		if err != nil {
+			return
+		}
+		status.Capacity = &lvmv1alpha1.VGCapacity{
+			Size: *resource.NewQuantity(int64(size), resource.BinarySI),
+			Free: *resource.NewQuantity(int64(free), resource.BinarySI),
+		}
+	}
+}
+
+// applyThinPoolUsage sets the size and the used space of the thin pool of the volume group in the capacity.
+func (r *Reconciler) applyThinPoolUsage(ctx context.Context, vg *lvmv1alpha1.LVMVolumeGroup, status *lvmv1alpha1.VolumeGroupStatus) error {
+	lvReport, err := r.ListLVs(ctx, vg.GetName())
+	if err != nil {
+		return fmt.Errorf("failed to list logical volumes: %w", err)
+	}
+
+	for _, report := range lvReport.Report {
+		for _, lv := range report.Lv {
+			if lv.Name != vg.Spec.ThinPoolConfig.Name {
+				continue
+			}
+			// the usage is not reported by lvm for inactive thin pools
+			size, err := strconv.ParseFloat(lv.LvSize, 64)
+			if err != nil {
+				return nil
+			}
+			dataPercent, err := strconv.ParseFloat(lv.DataPercent, 64)
+			if err != nil {
+				return nil
+			}
+			status.Capacity.ThinPoolSize = resource.NewQuantity(int64(size), resource.BinarySI)
+			status.Capacity.ThinPoolUsed = resource.NewQuantity(int64(size*dataPercent/100), resource.BinarySI)
+			return nil
+		}
+	}
 	return nil
 }
 
-func (r *Reconciler) setDevices(status *lvmv1alpha1.VGStatus, vgs []lvm.VolumeGroup, devices FilteredBlockDevices) (bool, error) {
+func (r *Reconciler) setDevices(status *lvmv1alpha1.VolumeGroupStatus, vgs []lvm.VolumeGroup, devices FilteredBlockDevices) (bool, error) {
 	devicesExist := false
 	for _, vg := range vgs {
 		if status.Name == vg.Name {
@@ -246,7 +598,7 @@ func (r *Reconciler) getLVMVolumeGroupNodeStatus() *lvmv1alpha1.LVMVolumeGroupNo
 }
 
 // applyRAIDStatus queries logical volumes and physical volumes to populate RAID health status and metrics.
-func (r *Reconciler) applyRAIDStatus(ctx context.Context, vg *lvmv1alpha1.LVMVolumeGroup, vgs []lvm.VolumeGroup, status *lvmv1alpha1.VGStatus) error {
+func (r *Reconciler) applyRAIDStatus(ctx context.Context, vg *lvmv1alpha1.LVMVolumeGroup, vgs []lvm.VolumeGroup, status *lvmv1alpha1.VolumeGroupStatus) error {
 	lvReport, err := r.ListLVs(ctx, vg.GetName())
 	if err != nil {
 		return fmt.Errorf("failed to list logical volumes for RAID status: %w", err)
@@ -0,0 +1,107 @@
+package vgmanager
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestSetCapacity(t *testing.T) {
+	tests := []struct {
+		name     string
+		vgs      []lvm.VolumeGroup
+		expected *lvmv1alpha1.VGCapacity
+	}{
+		{
+			name: "volume group exists",
+			vgs: []lvm.VolumeGroup{
+				{Name: "other", VgSize: "1073741824", VgFree: "1073741824"},
+				{Name: "vg1", VgSize: "10737418240", VgFree: "2147483648"},
+			},
+			expected: &lvmv1alpha1.VGCapacity{
+				Size: resource.MustParse("10Gi"),
+				Free: resource.MustParse("2Gi"),
+			},
+		},
+		{
+			name: "volume group does not exist yet",
+			vgs:  []lvm.VolumeGroup{{Name: "other", VgSize: "1073741824", VgFree: "1073741824"}},
+		},
+		{
+			name: "size cannot be parsed",
+			vgs:  []lvm.VolumeGroup{{Name: "vg1", VgSize: "1123xxg", VgFree: "0"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &lvmv1alpha1.VolumeGroupStatus{VGStatus: lvmv1alpha1.VGStatus{Name: "vg1"}}
+			setCapacity(status, tt.vgs)
+			if tt.expected == nil {
+				assert.Nil(t, status.Capacity)
+				return
+			}
+			assert.NotNil(t, status.Capacity)
+			assert.Zero(t, tt.expected.Size.Cmp(status.Capacity.Size))
+			assert.Zero(t, tt.expected.Free.Cmp(status.Capacity.Free))
+		})
+	}
+}
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		name              string
+		reason            EventReasonError
+		err               error
+		expectedCondition string
+		expectedCode      lvmv1alpha1.VGReasonCode
+	}{
+		{
+			name:              "no available devices",
+			reason:            EventReasonErrorNoAvailableDevicesForVG,
+			err:               errors.New("no devices"),
+			expectedCondition: lvmv1alpha1.VGConditionDevicesReady,
+			expectedCode:      lvmv1alpha1.VGReasonNoAvailableDevices,
+		},
+		{
+			name:              "inconsistent logical volumes",
+			reason:            EventReasonErrorInconsistentLVs,
+			err:               errors.New("thin pool is not active"),
+			expectedCondition: lvmv1alpha1.VGConditionThinPoolHealthy,
+			expectedCode:      lvmv1alpha1.VGReasonInconsistentLVs,
+		},
+		{
+			name:              "full thin pool metadata",
+			reason:            EventReasonErrorInconsistentLVs,
+			err:               fmt.Errorf("error while validating logical volumes: %w", ErrThinPoolMetadataFull),
+			expectedCondition: lvmv1alpha1.VGConditionThinPoolHealthy,
+			expectedCode:      lvmv1alpha1.VGReasonThinPoolMetadataFull,
+		},
+		{
+			name:              "lvmd config write failure",
+			reason:            EventReasonErrorLVMDConfigWriteFailed,
+			err:               errors.New("read-only file system"),
+			expectedCondition: lvmv1alpha1.VGConditionLVMDConfigured,
+			expectedCode:      lvmv1alpha1.VGReasonLVMDConfigWriteFailed,
+		},
+		{
+			name:              "unknown event reason",
+			reason:            EventReasonErrorManualCleanupRequired,
+			err:               errors.New("manual cleanup required"),
+			expectedCondition: lvmv1alpha1.VGConditionReady,
+			expectedCode:      lvmv1alpha1.VGReasonUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditionType, code := failureReason(tt.reason, tt.err)
+			assert.Equal(t, tt.expectedCondition, conditionType)
+			assert.Equal(t, tt.expectedCode, code)
+		})
+	}
+}
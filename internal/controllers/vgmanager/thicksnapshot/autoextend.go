@@ -0,0 +1,163 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package thicksnapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// DefaultAutoExtendInterval is the interval in which the usage of thick snapshots is checked.
+// It is short as a snapshot becomes invalid as soon as its copy-on-write space is full.
+const DefaultAutoExtendInterval = 30 * time.Second
+
+// AutoExtender periodically extends the copy-on-write space of the thick snapshots on a node
+// once their usage reaches the AutoExtendThreshold of the ThickSnapshotConfig of their volume group.
+type AutoExtender struct {
+	client.Client
+	lvm.LVM
+
+	NodeName  string
+	Namespace string
+	Interval  time.Duration
+}
+
+var _ manager.Runnable = &AutoExtender{}
+var _ manager.LeaderElectionRunnable = &AutoExtender{}
+
+// NewAutoExtender returns an AutoExtender.
+func NewAutoExtender(client client.Client, lvm lvm.LVM, nodeName, namespace string) *AutoExtender {
+	return &AutoExtender{
+		Client:    client,
+		LVM:       lvm,
+		NodeName:  nodeName,
+		Namespace: namespace,
+		Interval:  DefaultAutoExtendInterval,
+	}
+}
+
+func (e *AutoExtender) NeedLeaderElection() bool {
+	return false
+}
+
+func (e *AutoExtender) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("thick-snapshot-autoextend")
+	ctx = log.IntoContext(ctx, logger)
+
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.extend(ctx); err != nil {
+			logger.Error(err, "failed to auto extend thick snapshots")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// extend extends all thick snapshots on the node whose usage reached the AutoExtendThreshold.
+// Nothing is extended while the node is in maintenance or the LVMCluster is paused.
+func (e *AutoExtender) extend(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	node := &corev1.Node{}
+	if err := e.Get(ctx, types.NamespacedName{Name: e.NodeName}, node); err != nil {
+		return fmt.Errorf("failed to get node %s: %w", e.NodeName, err)
+	}
+	if node.GetAnnotations()[constants.MaintenanceAnnotation] == "true" {
+		return nil
+	}
+
+	volumeGroups := &lvmv1alpha1.LVMVolumeGroupList{}
+	if err := e.List(ctx, volumeGroups, client.InNamespace(e.Namespace)); err != nil {
+		return fmt.Errorf("failed to list LVMVolumeGroups: %w", err)
+	}
+
+	var errs []error
+	for _, volumeGroup := range volumeGroups.Items {
+		config := volumeGroup.Spec.ThickSnapshotConfig
+		if config == nil || config.AutoExtendThreshold >= 100 || config.AutoExtendPercent <= 0 {
+			continue
+		}
+		if paused, err := e.isLVMClusterPaused(ctx, &volumeGroup); err != nil || paused {
+			if err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		report, err := e.ListLVs(ctx, volumeGroup.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list logical volumes in volume group %s: %w", volumeGroup.Name, err))
+			continue
+		}
+
+		for _, item := range report.Report {
+			for _, lv := range item.Lv {
+				lvAttr, err := vgmanager.ParsedLvAttr(lv.LvAttr)
+				if err != nil || lvAttr.VolumeType != vgmanager.VolumeTypeSnapshot {
+					continue
+				}
+				dataPercent, err := strconv.ParseFloat(lv.DataPercent, 64)
+				if err != nil || dataPercent < float64(config.AutoExtendThreshold) {
+					continue
+				}
+
+				logger.Info("extending thick snapshot", "LV", lv.Name, "VG", volumeGroup.Name,
+					"usage", dataPercent, "threshold", config.AutoExtendThreshold)
+				if err := e.ExtendSnapshotLV(ctx, lv.Name, volumeGroup.Name, config.AutoExtendPercent); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// isLVMClusterPaused checks if the LVMCluster controlling the volume group has set spec.paused.
+func (e *AutoExtender) isLVMClusterPaused(ctx context.Context, volumeGroup *lvmv1alpha1.LVMVolumeGroup) (bool, error) {
+	owner := metav1.GetControllerOf(volumeGroup)
+	if owner == nil || owner.Kind != "LVMCluster" {
+		return false, nil
+	}
+	lvmCluster := &lvmv1alpha1.LVMCluster{}
+	if err := e.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: volumeGroup.GetNamespace()}, lvmCluster); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return lvmCluster.Spec.Paused, nil
+}
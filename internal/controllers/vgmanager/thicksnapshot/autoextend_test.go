@@ -0,0 +1,54 @@
+package thicksnapshot
+
+import (
+	"context"
+	"testing"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	lvmmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAutoExtender(t *testing.T) {
+	const nodeName = "test-node"
+
+	tests := []struct {
+		name        string
+		config      *lvmv1alpha1.ThickSnapshotConfig
+		maintenance bool
+		extend      bool
+	}{
+		{"no thick snapshot config", nil, false, false},
+		{"auto extension disabled", &lvmv1alpha1.ThickSnapshotConfig{AutoExtendThreshold: 100, AutoExtendPercent: 20}, false, false},
+		{"node in maintenance", &lvmv1alpha1.ThickSnapshotConfig{AutoExtendThreshold: 70, AutoExtendPercent: 20}, true, false},
+		{"snapshot above threshold", &lvmv1alpha1.ThickSnapshotConfig{AutoExtendThreshold: 70, AutoExtendPercent: 20}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+			if tt.maintenance {
+				node.Annotations = map[string]string{constants.MaintenanceAnnotation: "true"}
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(node, thickVolumeGroup(tt.config)).Build()
+
+			mockLVM := lvmmocks.NewMockLVM(t)
+			if tt.extend {
+				mockLVM.EXPECT().ListLVs(mock.Anything, "thick").Return(lvReport(
+					lvm.LogicalVolume{Name: volumeID, VgName: "thick", LvAttr: "owi-aor---", LvSize: "10737418240"},
+					lvm.LogicalVolume{Name: targetID, VgName: "thick", Origin: volumeID, LvAttr: "swi-a-s---", DataPercent: "75.00"},
+					lvm.LogicalVolume{Name: "other", VgName: "thick", Origin: volumeID, LvAttr: "swi-a-s---", DataPercent: "10.00"},
+				), nil)
+				mockLVM.EXPECT().ExtendSnapshotLV(mock.Anything, targetID, "thick", 20).Return(nil)
+			}
+
+			e := NewAutoExtender(fakeClient, mockLVM, nodeName, namespace)
+			require.NoError(t, e.extend(context.Background()))
+		})
+	}
+}
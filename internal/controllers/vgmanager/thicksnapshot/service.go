@@ -0,0 +1,283 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package thicksnapshot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvmd"
+	"github.com/topolvm/topolvm/pkg/lvmd/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// accessTypeReadOnly is requested by TopoLVM for snapshots backing a VolumeSnapshot.
+	accessTypeReadOnly = "ro"
+	// accessTypeReadWrite is requested by TopoLVM for volumes restored from a VolumeSnapshot or cloned from a volume.
+	accessTypeReadWrite = "rw"
+
+	// copySuffix is appended to the name of a restored volume while its data is copied,
+	// so that an interrupted copy is never mistaken for a complete volume.
+	copySuffix = "-copy"
+	// sourceSnapshotSuffix is appended to the name of a restored volume for the temporary snapshot
+	// that is taken of the source volume of a clone.
+	sourceSnapshotSuffix = "-src"
+
+	// defaultReservePercent is the ReservePercent used if it is not set, matching the API default.
+	defaultReservePercent = 20
+)
+
+// LVService adds copy-on-write snapshots of thick logical volumes to the TopoLVM LVService.
+// Requests for thin device classes and for thick device classes without a ThickSnapshotConfig
+// are passed to the embedded LVServiceClient.
+type LVService struct {
+	proto.LVServiceClient
+	client.Client
+
+	LVM           lvm.LVM
+	VGService     proto.VGServiceClient
+	Namespace     string
+	DeviceClasses []*lvmd.DeviceClass
+}
+
+var _ proto.LVServiceClient = &LVService{}
+
+// NewLVService wraps the TopoLVM LVService to support snapshots of thick device classes.
+func NewLVService(lvService proto.LVServiceClient, vgService proto.VGServiceClient, client client.Client, lvm lvm.LVM,
+	namespace string, deviceClasses []*lvmd.DeviceClass) *LVService {
+	return &LVService{
+		LVServiceClient: lvService,
+		Client:          client,
+		LVM:             lvm,
+		VGService:       vgService,
+		Namespace:       namespace,
+		DeviceClasses:   deviceClasses,
+	}
+}
+
+// CreateLVSnapshot creates a copy-on-write snapshot for VolumeSnapshots of thick device classes and
+// a full copy for volumes restored from such a snapshot or cloned from a thick volume.
+func (s *LVService) CreateLVSnapshot(ctx context.Context, req *proto.CreateLVSnapshotRequest, opts ...grpc.CallOption) (*proto.CreateLVSnapshotResponse, error) {
+	deviceClass := s.deviceClass(req.GetDeviceClass())
+	if deviceClass == nil || deviceClass.Type == lvmd.TypeThin {
+		return s.LVServiceClient.CreateLVSnapshot(ctx, req, opts...)
+	}
+
+	config, err := s.thickSnapshotConfig(ctx, deviceClass.Name)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if config == nil {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"device class %s is thick provisioned and has no thickSnapshotConfig, snapshots are not supported", deviceClass.Name)
+	}
+
+	lvs, err := s.listLVs(ctx, deviceClass.VolumeGroup)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if _, ok := lvs[req.GetName()]; ok {
+		return s.snapshotResponse(ctx, deviceClass, req.GetName())
+	}
+	source, ok := lvs[req.GetSourceVolume()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "source logical volume %s not found in volume group %s",
+			req.GetSourceVolume(), deviceClass.VolumeGroup)
+	}
+	sourceSize, err := strconv.ParseInt(source.LvSize, 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not parse lv_size of logical volume %s: %v", source.Name, err)
+	}
+
+	switch req.GetAccessType() {
+	case accessTypeReadOnly:
+		if source.Origin != "" {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"logical volume %s is a snapshot, snapshots of thick snapshots are not supported", source.Name)
+		}
+		if err := s.LVM.CreateSnapshotLV(ctx, req.GetName(), deviceClass.VolumeGroup, source.Name,
+			reserveBytes(sourceSize, config.ReservePercent), req.GetTags(), true); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	case accessTypeReadWrite:
+		if err := s.copyLV(ctx, deviceClass, config, req, source, sourceSize, lvs); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported access type %q", req.GetAccessType())
+	}
+
+	return s.snapshotResponse(ctx, deviceClass, req.GetName())
+}
+
+// RemoveLV refuses to remove thick logical volumes that are the origin of copy-on-write snapshots,
+// as removing them would silently remove the snapshots as well.
+func (s *LVService) RemoveLV(ctx context.Context, req *proto.RemoveLVRequest, opts ...grpc.CallOption) (*proto.Empty, error) {
+	deviceClass := s.deviceClass(req.GetDeviceClass())
+	if deviceClass == nil || deviceClass.Type == lvmd.TypeThin {
+		return s.LVServiceClient.RemoveLV(ctx, req, opts...)
+	}
+
+	lvs, err := s.listLVs(ctx, deviceClass.VolumeGroup)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	for _, lv := range lvs {
+		if lv.Origin == req.GetName() {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"logical volume %s is the origin of snapshot %s, delete its VolumeSnapshots first", req.GetName(), lv.Name)
+		}
+	}
+
+	return s.LVServiceClient.RemoveLV(ctx, req, opts...)
+}
+
+// copyLV creates the logical volume requested for a restore or clone and copies the data of the source into it.
+// The volume is created under a temporary name and only renamed once the copy succeeded.
+func (s *LVService) copyLV(
+	ctx context.Context,
+	deviceClass *lvmd.DeviceClass,
+	config *lvmv1alpha1.ThickSnapshotConfig,
+	req *proto.CreateLVSnapshotRequest,
+	source lvm.LogicalVolume,
+	sourceSize int64,
+	lvs map[string]lvm.LogicalVolume,
+) error {
+	logger := log.FromContext(ctx).WithValues("LV", req.GetName(), "source", source.Name)
+	vgName := deviceClass.VolumeGroup
+	copyName := req.GetName() + copySuffix
+	sourceSnapshotName := req.GetName() + sourceSnapshotSuffix
+
+	// remove the leftovers of an interrupted attempt
+	for _, name := range []string{copyName, sourceSnapshotName} {
+		if _, ok := lvs[name]; !ok {
+			continue
+		}
+		if err := s.LVM.DeleteLV(ctx, name, vgName); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	// a clone is copied from a temporary snapshot so that the copy is consistent,
+	// a restore is copied from the snapshot directly.
+	copySource := source.Name
+	if source.Origin == "" {
+		if err := s.LVM.CreateSnapshotLV(ctx, sourceSnapshotName, vgName, source.Name,
+			reserveBytes(sourceSize, config.ReservePercent), nil, true); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		defer func() {
+			if err := s.LVM.DeleteLV(ctx, sourceSnapshotName, vgName); err != nil {
+				logger.Error(err, "failed to remove temporary snapshot", "snapshot", sourceSnapshotName)
+			}
+		}()
+		copySource = sourceSnapshotName
+	}
+
+	if _, err := s.LVServiceClient.CreateLV(ctx, &proto.CreateLVRequest{
+		Name:        copyName,
+		DeviceClass: deviceClass.Name,
+		Tags:        req.GetTags(),
+		SizeBytes:   max(req.GetSizeBytes(), sourceSize),
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("copying logical volume", "size", sourceSize)
+	if err := s.LVM.CopyLV(ctx, vgName, copySource, copyName); err != nil {
+		if err := s.LVM.DeleteLV(ctx, copyName, vgName); err != nil {
+			logger.Error(err, "failed to remove incomplete copy", "copy", copyName)
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	if err := s.LVM.RenameLV(ctx, copyName, vgName, req.GetName()); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// snapshotResponse looks up the created logical volume through the VGService so that it is reported
+// in the same way as the logical volumes created by TopoLVM.
+func (s *LVService) snapshotResponse(ctx context.Context, deviceClass *lvmd.DeviceClass, name string) (*proto.CreateLVSnapshotResponse, error) {
+	res, err := s.VGService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: deviceClass.Name})
+	if err != nil {
+		return nil, err
+	}
+	for _, lv := range res.GetVolumes() {
+		if lv.Name == name {
+			return &proto.CreateLVSnapshotResponse{Snapshot: lv}, nil
+		}
+	}
+	return nil, status.Errorf(codes.Internal, "created logical volume %s not found in device class %s", name, deviceClass.Name)
+}
+
+// deviceClass returns the lvmd device class with the given name, or the default device class if the name is empty.
+func (s *LVService) deviceClass(name string) *lvmd.DeviceClass {
+	for _, deviceClass := range s.DeviceClasses {
+		if (name == "" && deviceClass.Default) || deviceClass.Name == name {
+			return deviceClass
+		}
+	}
+	return nil
+}
+
+// thickSnapshotConfig returns the ThickSnapshotConfig of the LVMVolumeGroup of the device class.
+func (s *LVService) thickSnapshotConfig(ctx context.Context, deviceClass string) (*lvmv1alpha1.ThickSnapshotConfig, error) {
+	volumeGroup := &lvmv1alpha1.LVMVolumeGroup{}
+	if err := s.Get(ctx, types.NamespacedName{Name: deviceClass, Namespace: s.Namespace}, volumeGroup); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get LVMVolumeGroup %s: %w", deviceClass, err)
+	}
+	return volumeGroup.Spec.ThickSnapshotConfig, nil
+}
+
+func (s *LVService) listLVs(ctx context.Context, vgName string) (map[string]lvm.LogicalVolume, error) {
+	report, err := s.LVM.ListLVs(ctx, vgName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list logical volumes in volume group %s: %w", vgName, err)
+	}
+	lvs := make(map[string]lvm.LogicalVolume)
+	for _, item := range report.Report {
+		for _, lv := range item.Lv {
+			lvs[lv.Name] = lv
+		}
+	}
+	return lvs, nil
+}
+
+// reserveBytes returns the copy-on-write space reserved for a snapshot of a volume of the given size.
+func reserveBytes(sizeBytes int64, reservePercent int) int64 {
+	if reservePercent <= 0 {
+		reservePercent = defaultReservePercent
+	}
+	return max(sizeBytes*int64(reservePercent)/100, 1)
+}
@@ -0,0 +1,189 @@
+package thicksnapshot
+
+import (
+	"context"
+	"testing"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	lvmmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm/mocks"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/topolvm/topolvm/pkg/lvmd/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	namespace = "openshift-lvm-storage"
+	volumeID  = "0d2b1c3e-6a1f-4e4c-8d43-2b9a7a3c1f10"
+	targetID  = "7e6f4f7c-2f5c-4a0d-9a0e-5f3c2b1d0e9a"
+)
+
+// fakeLVService records the requests passed to TopoLVM.
+type fakeLVService struct {
+	proto.LVServiceClient
+	createLV         []*proto.CreateLVRequest
+	removeLV         []*proto.RemoveLVRequest
+	createLVSnapshot []*proto.CreateLVSnapshotRequest
+}
+
+func (f *fakeLVService) CreateLV(_ context.Context, req *proto.CreateLVRequest, _ ...grpc.CallOption) (*proto.CreateLVResponse, error) {
+	f.createLV = append(f.createLV, req)
+	return &proto.CreateLVResponse{Volume: &proto.LogicalVolume{Name: req.Name, SizeBytes: req.SizeBytes}}, nil
+}
+
+func (f *fakeLVService) RemoveLV(_ context.Context, req *proto.RemoveLVRequest, _ ...grpc.CallOption) (*proto.Empty, error) {
+	f.removeLV = append(f.removeLV, req)
+	return &proto.Empty{}, nil
+}
+
+func (f *fakeLVService) CreateLVSnapshot(_ context.Context, req *proto.CreateLVSnapshotRequest, _ ...grpc.CallOption) (*proto.CreateLVSnapshotResponse, error) {
+	f.createLVSnapshot = append(f.createLVSnapshot, req)
+	return &proto.CreateLVSnapshotResponse{Snapshot: &proto.LogicalVolume{Name: req.Name}}, nil
+}
+
+// fakeVGService reports the given logical volumes for every device class.
+type fakeVGService struct {
+	proto.VGServiceClient
+	volumes []*proto.LogicalVolume
+}
+
+func (f *fakeVGService) GetLVList(_ context.Context, _ *proto.GetLVListRequest, _ ...grpc.CallOption) (*proto.GetLVListResponse, error) {
+	return &proto.GetLVListResponse{Volumes: f.volumes}, nil
+}
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, lvmv1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+func newLVService(t *testing.T, mockLVM lvm.LVM, objs ...client.Object) (*LVService, *fakeLVService) {
+	t.Helper()
+	fakeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+	lvService := &fakeLVService{}
+	vgService := &fakeVGService{volumes: []*proto.LogicalVolume{{Name: volumeID}, {Name: targetID}}}
+	deviceClasses := []*lvmd.DeviceClass{
+		{Name: "thin", VolumeGroup: "thin", Type: lvmd.TypeThin},
+		{Name: "thick", VolumeGroup: "thick", Type: lvmd.TypeThick, Default: true},
+	}
+	return NewLVService(lvService, vgService, fakeClient, mockLVM, namespace, deviceClasses), lvService
+}
+
+func thickVolumeGroup(config *lvmv1alpha1.ThickSnapshotConfig) *lvmv1alpha1.LVMVolumeGroup {
+	return &lvmv1alpha1.LVMVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "thick", Namespace: namespace},
+		Spec:       lvmv1alpha1.LVMVolumeGroupSpec{ThickSnapshotConfig: config},
+	}
+}
+
+func lvReport(lvs ...lvm.LogicalVolume) *lvm.LVReport {
+	return &lvm.LVReport{Report: []lvm.LVReportItem{{Lv: lvs}}}
+}
+
+func TestLVService_CreateLVSnapshot_Thin(t *testing.T) {
+	s, lvService := newLVService(t, lvmmocks.NewMockLVM(t))
+
+	req := &proto.CreateLVSnapshotRequest{Name: targetID, DeviceClass: "thin", SourceVolume: volumeID, AccessType: "ro"}
+	_, err := s.CreateLVSnapshot(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []*proto.CreateLVSnapshotRequest{req}, lvService.createLVSnapshot)
+}
+
+func TestLVService_CreateLVSnapshot_NotEnabled(t *testing.T) {
+	s, lvService := newLVService(t, lvmmocks.NewMockLVM(t), thickVolumeGroup(nil))
+
+	_, err := s.CreateLVSnapshot(context.Background(), &proto.CreateLVSnapshotRequest{
+		Name: targetID, DeviceClass: "thick", SourceVolume: volumeID, AccessType: "ro",
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Empty(t, lvService.createLVSnapshot)
+}
+
+func TestLVService_CreateLVSnapshot_ReadOnly(t *testing.T) {
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListLVs(mock.Anything, "thick").Return(lvReport(
+		lvm.LogicalVolume{Name: volumeID, VgName: "thick", LvAttr: "owi-aor---", LvSize: "10737418240"},
+	), nil)
+	mockLVM.EXPECT().CreateSnapshotLV(mock.Anything, targetID, "thick", volumeID, int64(3221225472), []string{"tag"}, true).Return(nil)
+
+	s, _ := newLVService(t, mockLVM, thickVolumeGroup(&lvmv1alpha1.ThickSnapshotConfig{ReservePercent: 30}))
+
+	// an empty device class refers to the default device class
+	res, err := s.CreateLVSnapshot(context.Background(), &proto.CreateLVSnapshotRequest{
+		Name: targetID, SourceVolume: volumeID, AccessType: "ro", Tags: []string{"tag"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, targetID, res.Snapshot.Name)
+}
+
+func TestLVService_CreateLVSnapshot_Restore(t *testing.T) {
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListLVs(mock.Anything, "thick").Return(lvReport(
+		lvm.LogicalVolume{Name: volumeID, VgName: "thick", Origin: "origin", LvAttr: "swi-a-s---", LvSize: "10737418240"},
+		// leftover of an interrupted copy
+		lvm.LogicalVolume{Name: targetID + copySuffix, VgName: "thick", LvAttr: "-wi-a-----", LvSize: "10737418240"},
+	), nil)
+	mockLVM.EXPECT().DeleteLV(mock.Anything, targetID+copySuffix, "thick").Return(nil)
+	mockLVM.EXPECT().CopyLV(mock.Anything, "thick", volumeID, targetID+copySuffix).Return(nil)
+	mockLVM.EXPECT().RenameLV(mock.Anything, targetID+copySuffix, "thick", targetID).Return(nil)
+
+	s, lvService := newLVService(t, mockLVM, thickVolumeGroup(&lvmv1alpha1.ThickSnapshotConfig{ReservePercent: 20}))
+
+	_, err := s.CreateLVSnapshot(context.Background(), &proto.CreateLVSnapshotRequest{
+		Name: targetID, DeviceClass: "thick", SourceVolume: volumeID, AccessType: "rw", SizeBytes: 21474836480,
+	})
+	require.NoError(t, err)
+	require.Len(t, lvService.createLV, 1)
+	assert.Equal(t, targetID+copySuffix, lvService.createLV[0].Name)
+	assert.Equal(t, "thick", lvService.createLV[0].DeviceClass)
+	assert.Equal(t, int64(21474836480), lvService.createLV[0].SizeBytes)
+}
+
+func TestLVService_CreateLVSnapshot_Clone(t *testing.T) {
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListLVs(mock.Anything, "thick").Return(lvReport(
+		lvm.LogicalVolume{Name: volumeID, VgName: "thick", LvAttr: "-wi-ao----", LvSize: "10737418240"},
+	), nil)
+	mockLVM.EXPECT().CreateSnapshotLV(mock.Anything, targetID+sourceSnapshotSuffix, "thick", volumeID, int64(2147483648), []string(nil), true).Return(nil)
+	mockLVM.EXPECT().CopyLV(mock.Anything, "thick", targetID+sourceSnapshotSuffix, targetID+copySuffix).Return(nil)
+	mockLVM.EXPECT().RenameLV(mock.Anything, targetID+copySuffix, "thick", targetID).Return(nil)
+	mockLVM.EXPECT().DeleteLV(mock.Anything, targetID+sourceSnapshotSuffix, "thick").Return(nil)
+
+	s, lvService := newLVService(t, mockLVM, thickVolumeGroup(&lvmv1alpha1.ThickSnapshotConfig{}))
+
+	_, err := s.CreateLVSnapshot(context.Background(), &proto.CreateLVSnapshotRequest{
+		Name: targetID, DeviceClass: "thick", SourceVolume: volumeID, AccessType: "rw", SizeBytes: 1073741824,
+	})
+	require.NoError(t, err)
+	require.Len(t, lvService.createLV, 1)
+	assert.Equal(t, int64(10737418240), lvService.createLV[0].SizeBytes, "copy should not be smaller than its source")
+}
+
+func TestLVService_RemoveLV(t *testing.T) {
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListLVs(mock.Anything, "thick").Return(lvReport(
+		lvm.LogicalVolume{Name: volumeID, VgName: "thick", LvAttr: "owi-aor---"},
+		lvm.LogicalVolume{Name: targetID, VgName: "thick", Origin: volumeID, LvAttr: "swi-a-s---"},
+	), nil)
+
+	s, lvService := newLVService(t, mockLVM)
+
+	_, err := s.RemoveLV(context.Background(), &proto.RemoveLVRequest{Name: volumeID, DeviceClass: "thick"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "origin of a snapshot should not be removed")
+
+	_, err = s.RemoveLV(context.Background(), &proto.RemoveLVRequest{Name: targetID, DeviceClass: "thick"})
+	require.NoError(t, err)
+	assert.Len(t, lvService.removeLV, 1)
+}
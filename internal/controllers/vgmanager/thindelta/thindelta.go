@@ -0,0 +1,225 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package thindelta
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	vgmanagerexec "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/exec"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var (
+	DefaultThinDelta = "/usr/sbin/thin_delta"
+	DefaultThinLS    = "/usr/sbin/thin_ls"
+	DefaultDMSetup   = "/usr/sbin/dmsetup"
+)
+
+// sectorSize is the unit of the data block size reported by thin_delta.
+const sectorSize = 512
+
+// Range is a region of a thin volume in bytes.
+type Range struct {
+	Offset int64
+	Length int64
+}
+
+type ThinDelta interface {
+	// Delta returns the regions in which the thin volumes with the thin ids differ.
+	// Both volumes have to belong to the same thin pool.
+	Delta(ctx context.Context, vgName, poolName string, thinID1, thinID2 int) ([]Range, error)
+	// ExclusiveBytes returns the bytes of every thin volume of the thin pool by thin id
+	// that are not shared with any other thin volume of the pool.
+	ExclusiveBytes(ctx context.Context, vgName, poolName string) (map[int]int64, error)
+}
+
+type HostThinDelta struct {
+	vgmanagerexec.Executor
+	thinDelta string
+	thinLS    string
+	dmsetup   string
+
+	// mu serializes the use of the single metadata snapshot a thin pool can hold
+	mu sync.Mutex
+}
+
+func NewDefaultHostThinDelta() *HostThinDelta {
+	return NewHostThinDelta(&vgmanagerexec.CommandExecutor{}, DefaultThinDelta, DefaultDMSetup)
+}
+
+func NewHostThinDelta(executor vgmanagerexec.Executor, thinDelta, dmsetup string) *HostThinDelta {
+	return &HostThinDelta{
+		Executor:  executor,
+		thinDelta: thinDelta,
+		thinLS:    DefaultThinLS,
+		dmsetup:   dmsetup,
+	}
+}
+
+// deltaReport represents the output of thin_delta
+type deltaReport struct {
+	DataBlockSize int64 `xml:"data_block_size,attr"`
+	Diff          struct {
+		Ranges []deltaRange `xml:",any"`
+	} `xml:"diff"`
+}
+
+type deltaRange struct {
+	XMLName xml.Name
+	Begin   int64 `xml:"begin,attr"`
+	Length  int64 `xml:"length,attr"`
+}
+
+// Delta runs thin_delta on a metadata snapshot of the thin pool, as the metadata of an active pool
+// can not be read consistently otherwise. The metadata snapshot is released afterwards.
+func (t *HostThinDelta) Delta(ctx context.Context, vgName, poolName string, thinID1, thinID2 int) ([]Range, error) {
+	if vgName == "" || poolName == "" {
+		return nil, errors.New("failed to compare thin volumes: volume group or thin pool name is empty")
+	}
+	pool := strings.TrimPrefix(lvm.DeviceMapperPath(vgName, poolName), "/dev/mapper/")
+
+	release, err := t.reserveMetadataSnapshot(ctx, vgName, poolName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	output, err := t.StartCommandWithOutputAsHost(ctx, t.thinDelta,
+		"--metadata-snap",
+		"--snap1", strconv.Itoa(thinID1),
+		"--snap2", strconv.Itoa(thinID2),
+		fmt.Sprintf("/dev/mapper/%s_tmeta", pool),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute thin_delta: %w", err)
+	}
+	report := &deltaReport{}
+	err = xml.NewDecoder(output).Decode(report)
+	if err := errors.Join(output.Close(), err); err != nil {
+		return nil, fmt.Errorf("failed to compare thin volumes %d and %d of thin pool %s/%s: %w", thinID1, thinID2, vgName, poolName, err)
+	}
+
+	return report.ranges()
+}
+
+// ExclusiveBytes runs thin_ls on a metadata snapshot of the thin pool in the same way as Delta.
+// Blocks shared with a snapshot or the origin of a thin volume are not exclusive to it.
+func (t *HostThinDelta) ExclusiveBytes(ctx context.Context, vgName, poolName string) (map[int]int64, error) {
+	if vgName == "" || poolName == "" {
+		return nil, errors.New("failed to list thin volumes: volume group or thin pool name is empty")
+	}
+	pool := strings.TrimPrefix(lvm.DeviceMapperPath(vgName, poolName), "/dev/mapper/")
+
+	release, err := t.reserveMetadataSnapshot(ctx, vgName, poolName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	output, err := t.StartCommandWithOutputAsHost(ctx, t.thinLS,
+		"--metadata-snap",
+		"--no-headers",
+		"--format", "DEV,EXCLUSIVE_BYTES",
+		fmt.Sprintf("/dev/mapper/%s_tmeta", pool),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute thin_ls: %w", err)
+	}
+	exclusive, err := parseExclusiveBytes(output)
+	if err := errors.Join(output.Close(), err); err != nil {
+		return nil, fmt.Errorf("failed to list thin volumes of thin pool %s/%s: %w", vgName, poolName, err)
+	}
+	return exclusive, nil
+}
+
+// parseExclusiveBytes parses the DEV and EXCLUSIVE_BYTES columns of the thin_ls output.
+func parseExclusiveBytes(output io.Reader) (map[int]int64, error) {
+	exclusive := make(map[int]int64)
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("unexpected line %q in thin_ls output", scanner.Text())
+		}
+		thinID, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid thin id in thin_ls output: %w", err)
+		}
+		bytes, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclusive bytes of thin id %d in thin_ls output: %w", thinID, err)
+		}
+		exclusive[thinID] = bytes
+	}
+	return exclusive, scanner.Err()
+}
+
+// reserveMetadataSnapshot reserves the single metadata snapshot of the thin pool and returns a function releasing it.
+func (t *HostThinDelta) reserveMetadataSnapshot(ctx context.Context, vgName, poolName string) (func(), error) {
+	pool := strings.TrimPrefix(lvm.DeviceMapperPath(vgName, poolName), "/dev/mapper/")
+
+	t.mu.Lock()
+	if output, err := t.CombinedOutputCommandAsHost(ctx, t.dmsetup, "message", pool+"-tpool", "0", "reserve_metadata_snap"); err != nil {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("failed to reserve metadata snapshot of thin pool %s/%s: %s: %w", vgName, poolName, strings.TrimSpace(string(output)), err)
+	}
+	return func() {
+		defer t.mu.Unlock()
+		if output, err := t.CombinedOutputCommandAsHost(ctx, t.dmsetup, "message", pool+"-tpool", "0", "release_metadata_snap"); err != nil {
+			log.FromContext(ctx).Error(err, "failed to release metadata snapshot of thin pool", "VGName", vgName, "pool", poolName, "output", string(output))
+		}
+	}, nil
+}
+
+// ranges returns the regions of the report that are not the same in both volumes in bytes,
+// merging adjacent regions.
+func (r *deltaReport) ranges() ([]Range, error) {
+	if r.DataBlockSize <= 0 {
+		return nil, fmt.Errorf("invalid data block size %d in thin_delta output", r.DataBlockSize)
+	}
+	blockSize := r.DataBlockSize * sectorSize
+
+	var ranges []Range
+	for _, rng := range r.Diff.Ranges {
+		switch rng.XMLName.Local {
+		case "same":
+			continue
+		case "different", "left_only", "right_only":
+		default:
+			return nil, fmt.Errorf("unknown region %q in thin_delta output", rng.XMLName.Local)
+		}
+		offset, length := rng.Begin*blockSize, rng.Length*blockSize
+		if n := len(ranges); n > 0 && ranges[n-1].Offset+ranges[n-1].Length == offset {
+			ranges[n-1].Length += length
+			continue
+		}
+		ranges = append(ranges, Range{Offset: offset, Length: length})
+	}
+	return ranges, nil
+}
@@ -0,0 +1,105 @@
+package thindelta
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	mockExec "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const deltaOutput = `<superblock uuid="" time="2" transaction="4" flags="0" version="2" data_block_size="128" nr_data_blocks="0">
+  <diff left="1" right="3">
+    <same begin="0" length="4"/>
+    <different begin="4" length="2"/>
+    <right_only begin="6" length="1"/>
+    <same begin="7" length="10"/>
+    <left_only begin="17" length="1"/>
+  </diff>
+</superblock>
+`
+
+func TestDelta(t *testing.T) {
+	ctx := log.IntoContext(context.Background(), testr.New(t))
+
+	var messages []string
+	executor := &mockExec.MockExecutor{
+		MockCombinedOutputCommandAsHost: func(ctx context.Context, command string, args ...string) ([]byte, error) {
+			assert.Equal(t, DefaultDMSetup, command)
+			assert.Equal(t, []string{"message", "vg--1-thin--pool-tpool", "0"}, args[:3])
+			messages = append(messages, args[3])
+			return nil, nil
+		},
+		MockExecuteCommandWithOutputAsHost: func(ctx context.Context, command string, args ...string) (io.ReadCloser, error) {
+			assert.Equal(t, DefaultThinDelta, command)
+			assert.Equal(t, []string{"--metadata-snap", "--snap1", "1", "--snap2", "3", "/dev/mapper/vg--1-thin--pool_tmeta"}, args)
+			return io.NopCloser(strings.NewReader(deltaOutput)), nil
+		},
+	}
+
+	ranges, err := NewHostThinDelta(executor, DefaultThinDelta, DefaultDMSetup).Delta(ctx, "vg-1", "thin-pool", 1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []Range{
+		{Offset: 4 * 65536, Length: 3 * 65536},
+		{Offset: 17 * 65536, Length: 65536},
+	}, ranges)
+	assert.Equal(t, []string{"reserve_metadata_snap", "release_metadata_snap"}, messages)
+}
+
+func TestDelta_ReserveFailed(t *testing.T) {
+	ctx := log.IntoContext(context.Background(), testr.New(t))
+
+	executor := &mockExec.MockExecutor{
+		MockCombinedOutputCommandAsHost: func(ctx context.Context, command string, args ...string) ([]byte, error) {
+			return []byte("device-mapper: message ioctl failed: File exists"), errors.New("exit status 1")
+		},
+	}
+
+	_, err := NewHostThinDelta(executor, DefaultThinDelta, DefaultDMSetup).Delta(ctx, "vg1", "thin-pool", 1, 3)
+	assert.ErrorContains(t, err, "File exists")
+}
+
+func TestExclusiveBytes(t *testing.T) {
+	ctx := log.IntoContext(context.Background(), testr.New(t))
+
+	var messages []string
+	executor := &mockExec.MockExecutor{
+		MockCombinedOutputCommandAsHost: func(ctx context.Context, command string, args ...string) ([]byte, error) {
+			assert.Equal(t, DefaultDMSetup, command)
+			messages = append(messages, args[3])
+			return nil, nil
+		},
+		MockExecuteCommandWithOutputAsHost: func(ctx context.Context, command string, args ...string) (io.ReadCloser, error) {
+			assert.Equal(t, DefaultThinLS, command)
+			assert.Equal(t, []string{"--metadata-snap", "--no-headers", "--format", "DEV,EXCLUSIVE_BYTES", "/dev/mapper/vg--1-thin--pool_tmeta"}, args)
+			return io.NopCloser(strings.NewReader("1  134217728\n2   67108864\n\n")), nil
+		},
+	}
+
+	exclusive, err := NewHostThinDelta(executor, DefaultThinDelta, DefaultDMSetup).ExclusiveBytes(ctx, "vg-1", "thin-pool")
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{1: 134217728, 2: 67108864}, exclusive)
+	assert.Equal(t, []string{"reserve_metadata_snap", "release_metadata_snap"}, messages)
+}
+
+func TestExclusiveBytes_InvalidOutput(t *testing.T) {
+	ctx := log.IntoContext(context.Background(), testr.New(t))
+
+	executor := &mockExec.MockExecutor{
+		MockCombinedOutputCommandAsHost: func(ctx context.Context, command string, args ...string) ([]byte, error) {
+			return nil, nil
+		},
+		MockExecuteCommandWithOutputAsHost: func(ctx context.Context, command string, args ...string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("1 134217728 2\n")), nil
+		},
+	}
+
+	_, err := NewHostThinDelta(executor, DefaultThinDelta, DefaultDMSetup).ExclusiveBytes(ctx, "vg1", "thin-pool")
+	assert.ErrorContains(t, err, "unexpected line")
+}
@@ -0,0 +1,37 @@
+package vgmanager
+
+import (
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var volumeGroupFailed = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "lvms_volume_group_failed",
+		Help: "Whether the volume group of a device class is failed or degraded on a node. " +
+			"1=failed or degraded, the reason_code label holds the machine-readable reason.",
+	},
+	[]string{"node", "device_class", "status", "reason_code"},
+)
+
+// VolumeGroupMetrics returns the Prometheus collectors for the failures of volume groups.
+func VolumeGroupMetrics() []prometheus.Collector {
+	return []prometheus.Collector{
+		volumeGroupFailed,
+	}
+}
+
+// updateVolumeGroupMetrics sets the failure gauge of a device class on a node from its status.
+// The series of a previous failure are removed, so only the current reason code is reported.
+func updateVolumeGroupMetrics(nodeName, deviceClassName string, status *lvmv1alpha1.VolumeGroupStatus) {
+	deleteVolumeGroupMetrics(nodeName, deviceClassName)
+	if status.ReasonCode == "" {
+		return
+	}
+	volumeGroupFailed.WithLabelValues(nodeName, deviceClassName, string(status.Status), string(status.ReasonCode)).Set(1)
+}
+
+// deleteVolumeGroupMetrics removes all volume group metric series for a device class on a node.
+func deleteVolumeGroupMetrics(nodeName, deviceClassName string) {
+	volumeGroupFailed.DeletePartialMatch(prometheus.Labels{"node": nodeName, "device_class": deviceClassName})
+}
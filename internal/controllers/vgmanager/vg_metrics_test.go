@@ -0,0 +1,52 @@
+package vgmanager
+
+import (
+	"testing"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+// countNodeSeries counts the series of the gauge for the given node, ignoring series of other tests.
+func countNodeSeries(g *prometheus.GaugeVec, nodeName string) int {
+	ch := make(chan prometheus.Metric, 100)
+	g.Collect(ch)
+	close(ch)
+	count := 0
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			continue
+		}
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "node" && label.GetValue() == nodeName {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestUpdateVolumeGroupMetrics(t *testing.T) {
+	const nodeName = "metrics-node"
+	t.Cleanup(func() { deleteVolumeGroupMetrics(nodeName, "vg1") })
+
+	updateVolumeGroupMetrics(nodeName, "vg1", &lvmv1alpha1.VolumeGroupStatus{
+		VGStatus:   lvmv1alpha1.VGStatus{Status: lvmv1alpha1.VGStatusFailed},
+		ReasonCode: lvmv1alpha1.VGReasonNoAvailableDevices,
+	})
+	assert.Equal(t, 1, countNodeSeries(volumeGroupFailed, nodeName))
+	assert.Equal(t, float64(1), getGaugeValue(volumeGroupFailed, nodeName, "vg1", "Failed", "NoAvailableDevices"))
+
+	updateVolumeGroupMetrics(nodeName, "vg1", &lvmv1alpha1.VolumeGroupStatus{
+		VGStatus:   lvmv1alpha1.VGStatus{Status: lvmv1alpha1.VGStatusDegraded},
+		ReasonCode: lvmv1alpha1.VGReasonRAIDMissingPV,
+	})
+	assert.Equal(t, 1, countNodeSeries(volumeGroupFailed, nodeName), "the previous reason code should be removed")
+	assert.Equal(t, float64(1), getGaugeValue(volumeGroupFailed, nodeName, "vg1", "Degraded", "RAIDMissingPV"))
+
+	updateVolumeGroupMetrics(nodeName, "vg1", &lvmv1alpha1.VolumeGroupStatus{VGStatus: lvmv1alpha1.VGStatus{Status: lvmv1alpha1.VGStatusReady}})
+	assert.Equal(t, 0, countNodeSeries(volumeGroupFailed, nodeName))
+}
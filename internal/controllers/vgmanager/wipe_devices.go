@@ -2,8 +2,10 @@ package vgmanager
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	symlinkResolver "github.com/openshift/lvm-operator/v4/internal/controllers/symlink-resolver"
@@ -11,6 +13,7 @@ import (
 	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/dmsetup"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/filter"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lsblk"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -28,6 +31,27 @@ func (r *Reconciler) wipeDevices(
 		return false, nil
 	}
 
+	wipedPaths, err := r.getWipedPaths(volumeGroup)
+	if err != nil {
+		return false, err
+	}
+	_, wipedBefore := volumeGroup.Annotations[constants.DevicesWipedAnnotationPrefix+r.NodeName]
+	flattened := lsblk.FlattenedBlockDevices(blockDevices)
+
+	// skipWipe returns true if the device was wiped before and should not be wiped again.
+	// Volume groups that were wiped before the individual paths were recorded only carry the wiped annotation,
+	// in which case all devices that already are lvm physical volumes are considered to have been wiped.
+	skipWipe := func(path lvmv1alpha1.DevicePath, pathResolved string) bool {
+		if slices.Contains(wipedPaths, path.Unresolved()) {
+			return true
+		}
+		if !wipedBefore {
+			return false
+		}
+		device, found := flattened[pathResolved]
+		return !found || device.FSType == filter.FSTypeLVM2Member
+	}
+
 	updated := false
 
 	for _, path := range volumeGroup.Spec.DeviceSelector.Paths {
@@ -35,10 +59,15 @@ func (r *Reconciler) wipeDevices(
 		if err != nil {
 			return false, fmt.Errorf("failed to wipe device %s: %w", path, err)
 		}
+		if skipWipe(path, pathResolved) {
+			logger.V(1).Info("skipping wiping device as it was wiped before", "path", path)
+			continue
+		}
 
 		if deviceWiped, err := r.wipeDevice(ctx, pathResolved, blockDevices); err != nil {
 			return false, fmt.Errorf("failed to wipe device %s: %w", path, err)
 		} else if deviceWiped {
+			wipedPaths = append(wipedPaths, path.Unresolved())
 			updated = true
 		}
 	}
@@ -46,10 +75,16 @@ func (r *Reconciler) wipeDevices(
 		pathResolved, err := resolver.Resolve(path.Unresolved())
 		if err != nil {
 			logger.Info(fmt.Sprintf("skipping wiping optional device %s: %v", path, err))
+			continue
+		}
+		if skipWipe(path, pathResolved) {
+			logger.V(1).Info("skipping wiping optional device as it was wiped before", "path", path)
+			continue
 		}
 		if deviceWiped, err := r.wipeDevice(ctx, pathResolved, blockDevices); err != nil {
 			logger.Info(fmt.Sprintf("skipping wiping optional device %s: %v", path, err))
 		} else if deviceWiped {
+			wipedPaths = append(wipedPaths, path.Unresolved())
 			updated = true
 		}
 	}
@@ -63,16 +98,34 @@ func (r *Reconciler) wipeDevices(
 				"serves as indicator that the devices have been wiped before and should not be wiped again."+
 				"removal of this annotation is unsupported and may lead to data loss due to additional wiping.",
 			time.Now().Format(time.RFC3339))
+		paths, err := json.Marshal(wipedPaths)
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal wiped device paths: %w", err)
+		}
+		volumeGroup.Annotations[constants.WipedDevicePathsAnnotationPrefix+r.NodeName] = string(paths)
 	}
 
 	return updated, nil
 }
 
+// getWipedPaths returns the device paths that have been wiped on this node before.
+func (r *Reconciler) getWipedPaths(vg *lvmv1alpha1.LVMVolumeGroup) ([]string, error) {
+	value, ok := vg.Annotations[constants.WipedDevicePathsAnnotationPrefix+r.NodeName]
+	if !ok {
+		return nil, nil
+	}
+	var paths []string
+	if err := json.Unmarshal([]byte(value), &paths); err != nil {
+		return nil, fmt.Errorf("failed to parse wiped device paths annotation: %w", err)
+	}
+	return paths, nil
+}
+
 // shouldWipeDevicesOnVolumeGroup checks if the volume group should have its devices wiped
 // based on the ForceWipeDevicesAndDestroyAllData field in the DeviceSelector.
 // If the field is not set, it returns false.
 // If the field is set to false, it returns false.
-// If the field is set to true, it returns true if the volume group has not been wiped before.
+// If the field is set to true, it returns true. Devices that have been wiped before are skipped in wipeDevices.
 func (r *Reconciler) shouldWipeDevicesOnVolumeGroup(vg *lvmv1alpha1.LVMVolumeGroup) bool {
 	// If the volume group does not have the DeviceSelector field, it should not be wiped because it is unsafe.
 	// If devices are detected at runtime, wiping can lead to data loss.
@@ -84,18 +137,7 @@ func (r *Reconciler) shouldWipeDevicesOnVolumeGroup(vg *lvmv1alpha1.LVMVolumeGro
 	if vg.Spec.DeviceSelector.ForceWipeDevicesAndDestroyAllData == nil {
 		return false
 	}
-	if !*vg.Spec.DeviceSelector.ForceWipeDevicesAndDestroyAllData {
-		return false
-	}
-
-	// If the wiped annotation is present, the devices have been wiped before.
-	// If the devices have not been wiped before, they should be wiped.
-	var wipedBefore bool
-	if vg.Annotations != nil {
-		_, wipedBefore = vg.Annotations[constants.DevicesWipedAnnotationPrefix+r.NodeName]
-	}
-
-	return !wipedBefore
+	return *vg.Spec.DeviceSelector.ForceWipeDevicesAndDestroyAllData
 }
 
 func (r *Reconciler) wipeDevice(ctx context.Context, deviceName string, blockDevices []lsblk.BlockDevice) (bool, error) {
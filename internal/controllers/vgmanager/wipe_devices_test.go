@@ -11,6 +11,7 @@ import (
 	"github.com/openshift/lvm-operator/v4/api/v1alpha1"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
 	dmsetupmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/dmsetup/mocks"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/filter"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lsblk"
 	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
 	wipefsmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/wipefs/mocks"
@@ -33,6 +34,7 @@ func TestWipeDevices(t *testing.T) {
 		wipeCount            int
 		removeReferenceCount int
 		wipedBefore          bool
+		wipedPaths           string
 	}{
 		{
 			name:                 "Force wipe feature is not enabled",
@@ -95,12 +97,41 @@ func TestWipeDevices(t *testing.T) {
 		{
 			name:                 "Device exist in the device list and is already part of a vg, but was already wiped before",
 			devicePaths:          []v1alpha1.DevicePath{"/dev/loop1"},
-			blockDevices:         []lsblk.BlockDevice{{KName: "/dev/sda"}, {KName: "/dev/sdb"}, {KName: "/dev/loop1"}},
+			blockDevices:         []lsblk.BlockDevice{{KName: "/dev/sda"}, {KName: "/dev/sdb"}, {KName: "/dev/loop1", FSType: filter.FSTypeLVM2Member}},
 			vgs:                  []lvm.VolumeGroup{{Name: "vg1", PVs: []lvm.PhysicalVolume{{PvName: "/dev/loop1"}}}},
 			wipedBefore:          true,
 			wipeCount:            0,
 			removeReferenceCount: 0,
 		},
+		{
+			name:                 "Device was appended after the devices were wiped before",
+			devicePaths:          []v1alpha1.DevicePath{"/dev/loop1", "/dev/loop2"},
+			blockDevices:         []lsblk.BlockDevice{{KName: "/dev/sda"}, {KName: "/dev/loop1", FSType: filter.FSTypeLVM2Member}, {KName: "/dev/loop2"}},
+			vgs:                  []lvm.VolumeGroup{{Name: "vg1", PVs: []lvm.PhysicalVolume{{PvName: "/dev/loop1"}}}},
+			wipedBefore:          true,
+			wipeCount:            1,
+			removeReferenceCount: 0,
+		},
+		{
+			name:                 "Device was appended after the recorded devices were wiped before",
+			devicePaths:          []v1alpha1.DevicePath{"/dev/loop1", "/dev/loop2"},
+			blockDevices:         []lsblk.BlockDevice{{KName: "/dev/sda"}, {KName: "/dev/loop1", FSType: filter.FSTypeLVM2Member}, {KName: "/dev/loop2"}},
+			vgs:                  []lvm.VolumeGroup{{Name: "vg1", PVs: []lvm.PhysicalVolume{{PvName: "/dev/loop1"}}}},
+			wipedBefore:          true,
+			wipedPaths:           `["/dev/loop1"]`,
+			wipeCount:            1,
+			removeReferenceCount: 0,
+		},
+		{
+			name:                 "Recorded devices are not wiped again",
+			devicePaths:          []v1alpha1.DevicePath{"/dev/loop1"},
+			optionalDevicePaths:  []v1alpha1.DevicePath{"/dev/loop2"},
+			blockDevices:         []lsblk.BlockDevice{{KName: "/dev/sda"}, {KName: "/dev/loop1"}, {KName: "/dev/loop2"}},
+			wipedBefore:          true,
+			wipedPaths:           `["/dev/loop1","/dev/loop2"]`,
+			wipeCount:            0,
+			removeReferenceCount: 0,
+		},
 		{
 			name:                 "Only one device out of two exists in the device list",
 			devicePaths:          []v1alpha1.DevicePath{"/dev/loop1", "/dev/loop2"},
@@ -191,10 +222,14 @@ func TestWipeDevices(t *testing.T) {
 				volumeGroup.Annotations = map[string]string{
 					constants.DevicesWipedAnnotationPrefix + r.NodeName: time.Now().Format(time.RFC3339)}
 			}
+			if tt.wipedPaths != "" {
+				volumeGroup.Annotations[constants.WipedDevicePathsAnnotationPrefix+r.NodeName] = tt.wipedPaths
+			}
 
 			wiped, err := r.wipeDevices(ctx, volumeGroup, tt.blockDevices, symlinkResolver.NewWithResolver(r.SymlinkResolveFn))
 			if tt.wipeCount > 0 {
 				assert.True(t, wiped)
+				assert.Contains(t, volumeGroup.Annotations, constants.WipedDevicePathsAnnotationPrefix+r.NodeName)
 			} else {
 				assert.False(t, wiped)
 			}
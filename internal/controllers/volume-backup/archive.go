@@ -0,0 +1,340 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume_backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/thindelta"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ChunkSize is the size of the blocks a volume is uploaded in.
+	ChunkSize = 4 * 1024 * 1024
+
+	// ManifestVersion is the version of the manifest format written by Upload.
+	ManifestVersion = 1
+
+	compressionGzip = "gzip"
+)
+
+var ErrInvalidManifest = errors.New("invalid backup manifest")
+
+// Manifest describes the objects of a backup. Every object, including the manifest, is compressed
+// and optionally encrypted.
+type Manifest struct {
+	Version     int                         `json:"version"`
+	Size        int64                       `json:"size"`
+	ChunkSize   int64                       `json:"chunkSize"`
+	Compression string                      `json:"compression"`
+	Encrypted   bool                        `json:"encrypted"`
+	VolumeMode  corev1.PersistentVolumeMode `json:"volumeMode,omitempty"`
+	FSType      string                      `json:"fsType,omitempty"`
+	// Chunks are the keys of the objects of all chunks of the volume in order.
+	// Chunks that only contain zeros are not uploaded and have an empty key.
+	// An incremental backup references the objects of its base backup for unchanged chunks.
+	Chunks []string `json:"chunks"`
+}
+
+// Progress is updated while a backup is uploaded or downloaded.
+type Progress struct {
+	// Processed is the amount of data of the volume that was read or written.
+	Processed atomic.Int64
+	// Transferred is the amount of compressed data that was uploaded or downloaded.
+	Transferred atomic.Int64
+}
+
+// UploadFunc uploads the device of a volume to the store and returns the manifest of the backup.
+type UploadFunc func(ctx context.Context, store ObjectStore, codec *Codec, devicePath, keyPrefix string, manifest Manifest, base *Manifest, changed []thindelta.Range, progress *Progress) (*Manifest, error)
+
+// DownloadFunc writes the chunks of a backup to the device of a volume.
+type DownloadFunc func(ctx context.Context, store ObjectStore, codec *Codec, manifest *Manifest, devicePath string, sparse bool, progress *Progress) error
+
+// Codec compresses and optionally encrypts the objects of a backup.
+type Codec struct {
+	aead cipher.AEAD
+}
+
+// NewCodec returns a Codec that encrypts with AES-256-GCM using the SHA-256 hash of the key.
+// Without a key, objects are only compressed.
+func NewCodec(key []byte) (*Codec, error) {
+	if len(key) == 0 {
+		return &Codec{}, nil
+	}
+	hash := sha256.Sum256(key)
+	block, err := aes.NewCipher(hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return &Codec{aead: aead}, nil
+}
+
+// Encrypted returns whether the codec encrypts objects.
+func (c *Codec) Encrypted() bool {
+	return c.aead != nil
+}
+
+// Encode compresses data and encrypts it bound to the key of the object, so objects can not be swapped.
+func (c *Codec) Encode(key string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress object %s: %w", key, err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress object %s: %w", key, err)
+	}
+	if c.aead == nil {
+		return buf.Bytes(), nil
+	}
+
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+buf.Len()+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce for object %s: %w", key, err)
+	}
+	return c.aead.Seal(nonce, nonce, buf.Bytes(), []byte(key)), nil
+}
+
+// Decode reverses Encode.
+func (c *Codec) Decode(key string, data []byte) ([]byte, error) {
+	if c.aead != nil {
+		if len(data) < c.aead.NonceSize() {
+			return nil, fmt.Errorf("failed to decrypt object %s: object is too short", key)
+		}
+		nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+		plaintext, err := c.aead.Open(nil, nonce, ciphertext, []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt object %s: %w", key, err)
+		}
+		data = plaintext
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress object %s: %w", key, err)
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress object %s: %w", key, err)
+	}
+	return out, nil
+}
+
+// KeyPrefix returns the prefix of the keys of the objects of a backup.
+func KeyPrefix(prefix, backupUID string) string {
+	return path.Join(strings.Trim(prefix, "/"), backupUID)
+}
+
+// ManifestKey returns the key of the manifest of a backup.
+func ManifestKey(keyPrefix string) string {
+	return keyPrefix + "/manifest"
+}
+
+func chunkKey(keyPrefix string, index int) string {
+	return fmt.Sprintf("%s/chunks/%016x", keyPrefix, index)
+}
+
+// GetManifest downloads and validates the manifest of a backup.
+func GetManifest(ctx context.Context, store ObjectStore, codec *Codec, keyPrefix string) (*Manifest, error) {
+	key := ManifestKey(keyPrefix)
+	data, err := store.GetObject(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = codec.Decode(key, data); err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+	switch {
+	case manifest.Version != ManifestVersion:
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidManifest, manifest.Version)
+	case manifest.Compression != compressionGzip:
+		return nil, fmt.Errorf("%w: unsupported compression %q", ErrInvalidManifest, manifest.Compression)
+	case manifest.Encrypted != codec.Encrypted():
+		return nil, fmt.Errorf("%w: encryption of the backup does not match the encryption key", ErrInvalidManifest)
+	case manifest.ChunkSize <= 0 || int64(len(manifest.Chunks)) != (manifest.Size+manifest.ChunkSize-1)/manifest.ChunkSize:
+		return nil, fmt.Errorf("%w: %d chunks do not match size %d", ErrInvalidManifest, len(manifest.Chunks), manifest.Size)
+	}
+	return manifest, nil
+}
+
+// Upload reads the device in chunks and uploads every chunk that does not only contain zeros.
+// With a base manifest, only the chunks that overlap the changed ranges are read and the other
+// chunks reference the objects of the base backup. The manifest is uploaded last, so a backup
+// without manifest is incomplete.
+func Upload(
+	ctx context.Context,
+	store ObjectStore,
+	codec *Codec,
+	devicePath, keyPrefix string,
+	manifest Manifest,
+	base *Manifest,
+	changed []thindelta.Range,
+	progress *Progress,
+) (*Manifest, error) {
+	device, err := os.Open(devicePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open device %s: %w", devicePath, err)
+	}
+	defer func() {
+		_ = device.Close()
+	}()
+
+	manifest.Version = ManifestVersion
+	manifest.ChunkSize = ChunkSize
+	manifest.Compression = compressionGzip
+	manifest.Encrypted = codec.Encrypted()
+	manifest.Chunks = make([]string, (manifest.Size+ChunkSize-1)/ChunkSize)
+
+	buf := make([]byte, ChunkSize)
+	for i := range manifest.Chunks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		offset := int64(i) * ChunkSize
+		length := min(ChunkSize, manifest.Size-offset)
+		if base != nil && !overlaps(changed, offset, length) {
+			manifest.Chunks[i] = base.Chunks[i]
+			progress.Processed.Add(length)
+			continue
+		}
+
+		chunk := buf[:length]
+		n, err := device.ReadAt(chunk, offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read device %s at offset %d: %w", devicePath, offset, err)
+		}
+		clear(chunk[n:])
+		progress.Processed.Add(length)
+		if isZero(chunk) {
+			continue
+		}
+		key := chunkKey(keyPrefix, i)
+		data, err := codec.Encode(key, chunk)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.PutObject(ctx, key, data); err != nil {
+			return nil, err
+		}
+		manifest.Chunks[i] = key
+		progress.Transferred.Add(int64(len(data)))
+	}
+
+	data, err := json.Marshal(&manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	key := ManifestKey(keyPrefix)
+	if data, err = codec.Encode(key, data); err != nil {
+		return nil, err
+	}
+	if err := store.PutObject(ctx, key, data); err != nil {
+		return nil, err
+	}
+	progress.Transferred.Add(int64(len(data)))
+	return &manifest, nil
+}
+
+// Download writes all chunks of the manifest to the device. Chunks that only contain zeros are
+// skipped if the device is sparse and already reads as zeros, like a new thin volume.
+func Download(ctx context.Context, store ObjectStore, codec *Codec, manifest *Manifest, devicePath string, sparse bool, progress *Progress) error {
+	device, err := os.OpenFile(devicePath, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open device %s: %w", devicePath, err)
+	}
+	defer func() {
+		_ = device.Close()
+	}()
+
+	zero := make([]byte, manifest.ChunkSize)
+	for i, key := range manifest.Chunks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		offset := int64(i) * manifest.ChunkSize
+		length := min(manifest.ChunkSize, manifest.Size-offset)
+
+		chunk := zero[:length]
+		if key != "" {
+			data, err := store.GetObject(ctx, key)
+			if err != nil {
+				return err
+			}
+			progress.Transferred.Add(int64(len(data)))
+			if chunk, err = codec.Decode(key, data); err != nil {
+				return err
+			}
+			if int64(len(chunk)) != length {
+				return fmt.Errorf("%w: chunk %s has %d bytes, expected %d", ErrInvalidManifest, key, len(chunk), length)
+			}
+		} else if sparse {
+			progress.Processed.Add(length)
+			continue
+		}
+		if _, err := device.WriteAt(chunk, offset); err != nil {
+			return fmt.Errorf("failed to write device %s at offset %d: %w", devicePath, offset, err)
+		}
+		progress.Processed.Add(length)
+	}
+
+	if err := device.Sync(); err != nil {
+		return fmt.Errorf("failed to sync device %s: %w", devicePath, err)
+	}
+	return nil
+}
+
+// overlaps returns whether any of the ranges overlaps the region at offset with length.
+func overlaps(ranges []thindelta.Range, offset, length int64) bool {
+	for _, r := range ranges {
+		if r.Offset < offset+length && offset < r.Offset+r.Length {
+			return true
+		}
+	}
+	return false
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+package volume_backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/thindelta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// memoryStore is an ObjectStore that keeps the objects in memory.
+type memoryStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{objects: make(map[string][]byte)}
+}
+
+func (s *memoryStore) PutObject(_ context.Context, key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = bytes.Clone(data)
+	return nil
+}
+
+func (s *memoryStore) GetObject(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("failed to get object %s: %w", key, ErrObjectNotFound)
+	}
+	return data, nil
+}
+
+func newDevice(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func randomData(t *testing.T, size int) []byte {
+	t.Helper()
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	return data
+}
+
+func TestUploadDownload(t *testing.T) {
+	ctx := context.Background()
+	size := int64(3*ChunkSize + 1024)
+
+	// the second chunk only contains zeros and is not uploaded
+	data := randomData(t, int(size))
+	clear(data[ChunkSize : 2*ChunkSize])
+	source := newDevice(t, "source", data)
+
+	for _, key := range [][]byte{nil, []byte("secret")} {
+		t.Run(fmt.Sprintf("encrypted=%t", key != nil), func(t *testing.T) {
+			store := newMemoryStore()
+			codec, err := NewCodec(key)
+			require.NoError(t, err)
+
+			uploaded := &Progress{}
+			manifest, err := Upload(ctx, store, codec, source, "backups/uid", Manifest{
+				Size: size, VolumeMode: corev1.PersistentVolumeFilesystem, FSType: "xfs",
+			}, nil, nil, uploaded)
+			require.NoError(t, err)
+			assert.Equal(t, size, uploaded.Processed.Load())
+			assert.Equal(t, []string{"backups/uid/chunks/0000000000000000", "", "backups/uid/chunks/0000000000000002",
+				"backups/uid/chunks/0000000000000003"}, manifest.Chunks)
+			assert.Len(t, store.objects, 4)
+
+			stored, err := GetManifest(ctx, store, codec, "backups/uid")
+			require.NoError(t, err)
+			assert.Equal(t, manifest, stored)
+			assert.Equal(t, key != nil, stored.Encrypted)
+			assert.Equal(t, "xfs", stored.FSType)
+
+			for _, sparse := range []bool{false, true} {
+				target := randomData(t, int(size))
+				if sparse {
+					target = make([]byte, size)
+				}
+				targetPath := newDevice(t, "target", target)
+				downloaded := &Progress{}
+				require.NoError(t, Download(ctx, store, codec, stored, targetPath, sparse, downloaded))
+				assert.Equal(t, size, downloaded.Processed.Load())
+
+				restored, err := os.ReadFile(targetPath)
+				require.NoError(t, err)
+				assert.True(t, bytes.Equal(data, restored), "target should contain the source after the download")
+			}
+		})
+	}
+}
+
+func TestUpload_Incremental(t *testing.T) {
+	ctx := context.Background()
+	size := int64(4 * ChunkSize)
+	store := newMemoryStore()
+	codec, err := NewCodec(nil)
+	require.NoError(t, err)
+
+	data := randomData(t, int(size))
+	base, err := Upload(ctx, store, codec, newDevice(t, "base", data), "base", Manifest{Size: size}, nil, nil, &Progress{})
+	require.NoError(t, err)
+
+	copy(data[ChunkSize+100:], randomData(t, 10))
+	clear(data[3*ChunkSize:])
+	changed := []thindelta.Range{
+		{Offset: ChunkSize, Length: 65536},
+		{Offset: 3 * ChunkSize, Length: ChunkSize},
+	}
+	progress := &Progress{}
+	manifest, err := Upload(ctx, store, codec, newDevice(t, "current", data), "current", Manifest{Size: size}, base, changed, progress)
+	require.NoError(t, err)
+	assert.Equal(t, []string{base.Chunks[0], "current/chunks/0000000000000001", base.Chunks[2], ""}, manifest.Chunks)
+	assert.Equal(t, size, progress.Processed.Load())
+
+	target := newDevice(t, "target", make([]byte, size))
+	require.NoError(t, Download(ctx, store, codec, manifest, target, true, &Progress{}))
+	restored, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, restored), "target should contain the changed source after the download")
+}
+
+func TestGetManifest_WrongKey(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	codec, err := NewCodec([]byte("secret"))
+	require.NoError(t, err)
+	_, err = Upload(ctx, store, codec, newDevice(t, "source", randomData(t, 1024)), "uid", Manifest{Size: 1024}, nil, nil, &Progress{})
+	require.NoError(t, err)
+
+	other, err := NewCodec([]byte("other"))
+	require.NoError(t, err)
+	_, err = GetManifest(ctx, store, other, "uid")
+	assert.ErrorContains(t, err, "failed to decrypt")
+
+	unencrypted, err := NewCodec(nil)
+	require.NoError(t, err)
+	_, err = GetManifest(ctx, store, unencrypted, "uid")
+	assert.Error(t, err)
+}
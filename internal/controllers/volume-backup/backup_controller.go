@@ -0,0 +1,705 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume_backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	snapapi "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/thindelta"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	BackupControllerName = "lvms-volume-backup"
+
+	// pollInterval is the interval in which a backup or restore is checked while data is transferred
+	// or while it waits for its source.
+	pollInterval = 10 * time.Second
+
+	// snapshotSuffix is appended to the name of a thin snapshot for the temporary snapshot the backup is read from,
+	// as the logical volumes of VolumeSnapshots are not activated.
+	snapshotSuffix = "-backup"
+
+	CredentialsAccessKeyIDKey     = "accessKeyID"
+	CredentialsSecretAccessKeyKey = "secretAccessKey"
+	EncryptionKeyKey              = "key"
+
+	fsTypeParameter = "csi.storage.k8s.io/fstype"
+)
+
+type (
+	EventReasonInfo  string
+	EventReasonError string
+)
+
+const (
+	EventReasonErrorBackupFailed   EventReasonError = "BackupFailed"
+	EventReasonErrorUploadFailed   EventReasonError = "UploadFailed"
+	EventReasonErrorRestoreFailed  EventReasonError = "RestoreFailed"
+	EventReasonErrorDownloadFailed EventReasonError = "DownloadFailed"
+	EventReasonVolumeBackedUp      EventReasonInfo  = "VolumeBackedUp"
+	EventReasonVolumeRestored      EventReasonInfo  = "VolumeRestored"
+)
+
+const (
+	ReasonResolving      = "Resolving"
+	ReasonPaused         = "Paused"
+	ReasonUploading      = "Uploading"
+	ReasonUploadFailed   = "UploadFailed"
+	ReasonBackupFailed   = "BackupFailed"
+	ReasonVolumeBackedUp = "VolumeBackedUp"
+)
+
+// ErrBackupFailed is returned for backups that can not succeed without a change to the LVMVolumeBackup
+// or the VolumeSnapshot, so they are reported in the status instead of being retried.
+var ErrBackupFailed = errors.New("volume backup failed")
+
+// job is an upload or download of a volume running in the background.
+type job struct {
+	cancel   context.CancelFunc
+	done     chan struct{}
+	progress Progress
+
+	manifest *Manifest
+	err      error
+}
+
+// jobs tracks the running jobs of a reconciler.
+type jobs struct {
+	mu    sync.Mutex
+	items map[types.NamespacedName]*job
+}
+
+func (j *jobs) get(key types.NamespacedName) *job {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.items[key]
+}
+
+func (j *jobs) set(key types.NamespacedName, running *job) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.items == nil {
+		j.items = make(map[types.NamespacedName]*job)
+	}
+	j.items[key] = running
+}
+
+func (j *jobs) remove(key types.NamespacedName) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	delete(j.items, key)
+}
+
+// stop cancels a running job.
+func (j *jobs) stop(key types.NamespacedName) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if running, ok := j.items[key]; ok {
+		running.cancel()
+		delete(j.items, key)
+	}
+}
+
+// BackupReconciler reconciles LVMVolumeBackup objects for the node it runs on.
+// The vg-manager of the node holding the snapshot of the VolumeSnapshot uploads it to the object storage.
+// Thin snapshots are uploaded incrementally to a base backup by only reading the blocks that thin_delta
+// reports as changed between both snapshots.
+type BackupReconciler struct {
+	client.Client
+	events.EventRecorder
+	lvm.LVM
+	thindelta.ThinDelta
+
+	// APIReader reads the VolumeSnapshots outside the namespace of the operator, which are not part of
+	// the cache of vg-manager, and the Secrets of the backups, which are not cached.
+	APIReader      client.Reader
+	NewObjectStore ObjectStoreFunc
+	Upload         UploadFunc
+	NodeName       string
+	Namespace      string
+
+	jobs jobs
+}
+
+// NewBackupReconciler returns BackupReconciler.
+func NewBackupReconciler(
+	client client.Client,
+	apiReader client.Reader,
+	eventRecorder events.EventRecorder,
+	lvm lvm.LVM,
+	thinDelta thindelta.ThinDelta,
+	nodeName, namespace string,
+) *BackupReconciler {
+	return &BackupReconciler{
+		Client:         client,
+		EventRecorder:  eventRecorder,
+		LVM:            lvm,
+		ThinDelta:      thinDelta,
+		APIReader:      apiReader,
+		NewObjectStore: NewS3Client,
+		Upload:         Upload,
+		NodeName:       nodeName,
+		Namespace:      namespace,
+	}
+}
+
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumebackups,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumebackups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshotcontents,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;update;patch
+
+// Reconcile uploads the snapshot requested by the LVMVolumeBackup if it is located on this node.
+func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	backup := &lvmv1alpha1.LVMVolumeBackup{}
+	if err := r.Get(ctx, req.NamespacedName, backup); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.jobs.stop(req.NamespacedName)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !backup.DeletionTimestamp.IsZero() ||
+		backup.Status.Phase == lvmv1alpha1.LVMVolumeBackupCompleted ||
+		backup.Status.Phase == lvmv1alpha1.LVMVolumeBackupFailed {
+		r.jobs.stop(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+	if backup.Status.NodeName != "" && backup.Status.NodeName != r.NodeName {
+		return ctrl.Result{}, nil
+	}
+
+	status := backup.Status.DeepCopy()
+	var requeue time.Duration
+	var err error
+	if backup.Status.NodeName == "" {
+		requeue, err = r.resolve(ctx, backup)
+	} else {
+		requeue, err = r.reconcile(ctx, backup)
+	}
+	if errors.Is(err, ErrBackupFailed) {
+		r.jobs.stop(req.NamespacedName)
+		return ctrl.Result{}, r.fail(ctx, backup, err)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !equality.Semantic.DeepEqual(status, &backup.Status) {
+		if err := r.updateStatus(ctx, backup); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+// resolve determines the snapshot logical volume of the VolumeSnapshot. It is handled by the vg-manager
+// of the node that holds the snapshot.
+func (r *BackupReconciler) resolve(ctx context.Context, backup *lvmv1alpha1.LVMVolumeBackup) (time.Duration, error) {
+	snapshotRef := backup.Spec.VolumeSnapshot
+
+	snapshot := &snapapi.VolumeSnapshot{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Name: snapshotRef.Name, Namespace: snapshotRef.Namespace}, snapshot); err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, fmt.Errorf("%w: VolumeSnapshot %s/%s not found", ErrBackupFailed, snapshotRef.Namespace, snapshotRef.Name)
+		}
+		return 0, fmt.Errorf("failed to get VolumeSnapshot %s/%s: %w", snapshotRef.Namespace, snapshotRef.Name, err)
+	}
+	if snapshot.Status == nil || !ptr.Deref(snapshot.Status.ReadyToUse, false) || snapshot.Status.BoundVolumeSnapshotContentName == nil {
+		setBackupInProgress(backup, lvmv1alpha1.LVMVolumeBackupPending, ReasonResolving,
+			fmt.Sprintf("waiting for VolumeSnapshot %s/%s to be ready to use", snapshotRef.Namespace, snapshotRef.Name))
+		return pollInterval, nil
+	}
+
+	content := &snapapi.VolumeSnapshotContent{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Name: *snapshot.Status.BoundVolumeSnapshotContentName}, content); err != nil {
+		return 0, fmt.Errorf("failed to get VolumeSnapshotContent %s: %w", *snapshot.Status.BoundVolumeSnapshotContentName, err)
+	}
+	if content.Spec.Driver != constants.TopolvmCSIDriverName || content.Status == nil || content.Status.SnapshotHandle == nil {
+		return 0, fmt.Errorf("%w: VolumeSnapshotContent %s was not created by LVMS", ErrBackupFailed, content.GetName())
+	}
+
+	logicalVolumes := &topolvmv1.LogicalVolumeList{}
+	if err := r.List(ctx, logicalVolumes); err != nil {
+		return 0, fmt.Errorf("failed to list TopoLVM LogicalVolumes: %w", err)
+	}
+	var snapshotLV, sourceLV *topolvmv1.LogicalVolume
+	for i := range logicalVolumes.Items {
+		if logicalVolumes.Items[i].Status.VolumeID == *content.Status.SnapshotHandle {
+			snapshotLV = &logicalVolumes.Items[i]
+		}
+	}
+	if snapshotLV == nil {
+		return 0, fmt.Errorf("%w: LogicalVolume of VolumeSnapshotContent %s not found", ErrBackupFailed, content.GetName())
+	}
+	// the backup is handled by the vg-manager on the node of the snapshot
+	if snapshotLV.Spec.NodeName != r.NodeName {
+		return 0, nil
+	}
+	for i := range logicalVolumes.Items {
+		if logicalVolumes.Items[i].GetName() == snapshotLV.Spec.Source {
+			sourceLV = &logicalVolumes.Items[i]
+		}
+	}
+	if sourceLV == nil {
+		return 0, fmt.Errorf("%w: source LogicalVolume %s of VolumeSnapshot %s/%s not found",
+			ErrBackupFailed, snapshotLV.Spec.Source, snapshotRef.Namespace, snapshotRef.Name)
+	}
+
+	vgName := snapshotLV.Spec.DeviceClass
+	lvReport, err := r.ListLVs(ctx, vgName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list logical volumes in volume group %s: %w", vgName, err)
+	}
+	volume := findLV(lvReport, snapshotLV.Status.VolumeID)
+	if volume == nil {
+		return 0, fmt.Errorf("%w: snapshot logical volume %s not found in volume group %s", ErrBackupFailed, snapshotLV.Status.VolumeID, vgName)
+	}
+	size, err := strconv.ParseInt(volume.LvSize, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse lv_size from logical volume %s: %w", volume.Name, err)
+	}
+
+	fsType, err := r.filesystemType(ctx, sourceLV)
+	if err != nil {
+		return 0, err
+	}
+
+	backup.Status.NodeName = r.NodeName
+	backup.Status.DeviceClass = vgName
+	backup.Status.SourceLogicalVolume = sourceLV.Status.VolumeID
+	backup.Status.SnapshotLogicalVolume = volume.Name
+	backup.Status.SizeBytes = size
+	backup.Status.VolumeMode = ptr.Deref(content.Spec.SourceVolumeMode, corev1.PersistentVolumeFilesystem)
+	if backup.Status.VolumeMode == corev1.PersistentVolumeFilesystem {
+		backup.Status.FSType = fsType
+	}
+	backup.Status.ManifestKey = ManifestKey(KeyPrefix(backup.Spec.Location.Prefix, string(backup.GetUID())))
+	setBackupInProgress(backup, lvmv1alpha1.LVMVolumeBackupUploading, ReasonUploading,
+		fmt.Sprintf("waiting to upload snapshot logical volume %s", volume.Name))
+	log.FromContext(ctx).Info("resolved snapshot for backup", "LV", volume.Name, "VGName", vgName)
+
+	return pollInterval, nil
+}
+
+// filesystemType returns the filesystem of the source volume from its PersistentVolume, or from the
+// StorageClass of the device class if the PersistentVolume no longer exists.
+func (r *BackupReconciler) filesystemType(ctx context.Context, sourceLV *topolvmv1.LogicalVolume) (string, error) {
+	pv := &corev1.PersistentVolume{}
+	err := r.Get(ctx, client.ObjectKey{Name: sourceLV.Spec.Name}, pv)
+	if err == nil && pv.Spec.CSI != nil && pv.Spec.CSI.FSType != "" {
+		return pv.Spec.CSI.FSType, nil
+	}
+	if client.IgnoreNotFound(err) != nil {
+		return "", fmt.Errorf("failed to get PersistentVolume %s: %w", sourceLV.Spec.Name, err)
+	}
+
+	storageClass := &storagev1.StorageClass{}
+	scName := constants.StorageClassPrefix + sourceLV.Spec.DeviceClass
+	if err := r.Get(ctx, client.ObjectKey{Name: scName}, storageClass); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	return storageClass.Parameters[fsTypeParameter], nil
+}
+
+// reconcile uploads the snapshot in the background and reports the progress of the upload.
+func (r *BackupReconciler) reconcile(ctx context.Context, backup *lvmv1alpha1.LVMVolumeBackup) (time.Duration, error) {
+	key := client.ObjectKeyFromObject(backup)
+	if running := r.jobs.get(key); running != nil {
+		select {
+		case <-running.done:
+			r.jobs.remove(key)
+			return r.uploadCompleted(ctx, backup, running)
+		default:
+			backup.Status.Progress = progress(running.progress.Processed.Load(), backup.Status.SizeBytes)
+			backup.Status.BytesUploaded = running.progress.Transferred.Load()
+			return pollInterval, nil
+		}
+	}
+
+	paused, err := isPaused(ctx, r.Client, r.NodeName, r.Namespace, backup.Status.DeviceClass)
+	if err != nil {
+		return 0, err
+	}
+	if paused {
+		setBackupInProgress(backup, backup.Status.Phase, ReasonPaused,
+			"waiting for the node to leave maintenance and the LVMCluster to be unpaused")
+		return pollInterval, nil
+	}
+
+	return r.startUpload(ctx, backup)
+}
+
+// startUpload starts the upload of the snapshot in the background. Thin snapshots are read from a temporary
+// snapshot that is removed once the upload completed.
+func (r *BackupReconciler) startUpload(ctx context.Context, backup *lvmv1alpha1.LVMVolumeBackup) (time.Duration, error) {
+	vgName := backup.Status.DeviceClass
+	logger := log.FromContext(ctx).WithValues("LV", backup.Status.SnapshotLogicalVolume, "VGName", vgName)
+
+	store, codec, err := r.objectStore(ctx, backup.GetNamespace(), backup.Spec.Location, backup.Spec.EncryptionKeySecretName)
+	if err != nil {
+		return 0, err
+	}
+
+	lvReport, err := r.ListLVs(ctx, vgName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list logical volumes in volume group %s: %w", vgName, err)
+	}
+	volume := findLV(lvReport, backup.Status.SnapshotLogicalVolume)
+	if volume == nil {
+		return 0, fmt.Errorf("%w: snapshot logical volume %s not found in volume group %s",
+			ErrBackupFailed, backup.Status.SnapshotLogicalVolume, vgName)
+	}
+	lvAttr, err := vgmanager.ParsedLvAttr(volume.LvAttr)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse lv_attr from logical volume %s: %w", volume.Name, err)
+	}
+	thin := lvAttr.VolumeType == vgmanager.VolumeTypeThinVolume
+
+	base, changed, err := r.incrementalBase(ctx, backup, store, codec, lvReport, volume, thin)
+	if err != nil {
+		return 0, err
+	}
+
+	devicePath := lvm.DeviceMapperPath(vgName, volume.Name)
+	var readName string
+	if thin {
+		readName = volume.Name + snapshotSuffix
+		// leftover of an interrupted upload
+		if findLV(lvReport, readName) != nil {
+			if err := r.DeleteLV(ctx, readName, vgName); err != nil {
+				return 0, fmt.Errorf("failed to delete snapshot %s of previous upload: %w", readName, err)
+			}
+		}
+		if err := r.CreateThinSnapshotLV(ctx, readName, vgName, volume.Name); err != nil {
+			return 0, fmt.Errorf("failed to create snapshot of logical volume %s for upload: %w", volume.Name, err)
+		}
+		devicePath = lvm.DeviceMapperPath(vgName, readName)
+	}
+
+	manifest := Manifest{
+		Size:       backup.Status.SizeBytes,
+		VolumeMode: backup.Status.VolumeMode,
+		FSType:     backup.Status.FSType,
+	}
+	keyPrefix := KeyPrefix(backup.Spec.Location.Prefix, string(backup.GetUID()))
+
+	key := client.ObjectKeyFromObject(backup)
+	uploadCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	running := &job{cancel: cancel, done: make(chan struct{})}
+	r.jobs.set(key, running)
+
+	go func() {
+		defer close(running.done)
+		defer cancel()
+		running.manifest, running.err = r.Upload(uploadCtx, store, codec, devicePath, keyPrefix, manifest, base, changed, &running.progress)
+		if readName != "" {
+			if err := r.DeleteLV(context.WithoutCancel(uploadCtx), readName, vgName); err != nil {
+				running.err = errors.Join(running.err, fmt.Errorf("failed to delete snapshot %s after upload: %w", readName, err))
+			}
+		}
+	}()
+
+	if backup.Status.StartTime == nil {
+		backup.Status.StartTime = ptr.To(metav1.Now())
+	}
+	backup.Status.Progress = "0%"
+	backup.Status.Incremental = base != nil
+	backup.Status.BaseBackupName = ""
+	msg := fmt.Sprintf("uploading snapshot logical volume %s to bucket %s", volume.Name, backup.Spec.Location.Bucket)
+	if base != nil {
+		backup.Status.BaseBackupName = backup.Spec.BaseBackupName
+		msg = fmt.Sprintf("uploading changes of snapshot logical volume %s since backup %s to bucket %s",
+			volume.Name, backup.Spec.BaseBackupName, backup.Spec.Location.Bucket)
+	}
+	setBackupInProgress(backup, lvmv1alpha1.LVMVolumeBackupUploading, ReasonUploading, msg)
+	logger.Info(msg, "incremental", base != nil)
+
+	return pollInterval, nil
+}
+
+// incrementalBase returns the manifest of the base backup and the ranges that changed since its snapshot.
+// It returns no manifest if the backup has to be a full backup.
+func (r *BackupReconciler) incrementalBase(
+	ctx context.Context,
+	backup *lvmv1alpha1.LVMVolumeBackup,
+	store ObjectStore,
+	codec *Codec,
+	lvReport *lvm.LVReport,
+	volume *lvm.LogicalVolume,
+	thin bool,
+) (*Manifest, []thindelta.Range, error) {
+	if backup.Spec.BaseBackupName == "" {
+		return nil, nil, nil
+	}
+	logger := log.FromContext(ctx).WithValues("baseBackup", backup.Spec.BaseBackupName)
+
+	base := &lvmv1alpha1.LVMVolumeBackup{}
+	if err := r.Get(ctx, types.NamespacedName{Name: backup.Spec.BaseBackupName, Namespace: backup.GetNamespace()}, base); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("%w: base LVMVolumeBackup %s not found", ErrBackupFailed, backup.Spec.BaseBackupName)
+		}
+		return nil, nil, fmt.Errorf("failed to get LVMVolumeBackup %s: %w", backup.Spec.BaseBackupName, err)
+	}
+	if base.Status.Phase != lvmv1alpha1.LVMVolumeBackupCompleted {
+		return nil, nil, fmt.Errorf("%w: base LVMVolumeBackup %s is not completed", ErrBackupFailed, base.GetName())
+	}
+
+	var reason string
+	baseVolume := findLV(lvReport, base.Status.SnapshotLogicalVolume)
+	switch {
+	case !thin:
+		reason = "the snapshot is not a thin snapshot"
+	case base.Status.NodeName != backup.Status.NodeName || base.Status.DeviceClass != backup.Status.DeviceClass ||
+		base.Status.SourceLogicalVolume != backup.Status.SourceLogicalVolume:
+		reason = "the base backup was taken of another volume"
+	case base.Status.SizeBytes != backup.Status.SizeBytes:
+		reason = "the volume was resized since the base backup"
+	case !equality.Semantic.DeepEqual(base.Spec.Location, backup.Spec.Location) ||
+		base.Spec.EncryptionKeySecretName != backup.Spec.EncryptionKeySecretName:
+		reason = "the base backup was uploaded to another location or with another encryption key"
+	case baseVolume == nil:
+		reason = "the snapshot of the base backup no longer exists"
+	case baseVolume.PoolName != volume.PoolName || baseVolume.ThinID == "" || volume.ThinID == "":
+		reason = "the snapshot of the base backup is not in the same thin pool"
+	}
+	if reason != "" {
+		logger.Info("taking a full backup instead of an incremental backup", "reason", reason)
+		return nil, nil, nil
+	}
+
+	baseID, err := strconv.Atoi(baseVolume.ThinID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse thin_id from logical volume %s: %w", baseVolume.Name, err)
+	}
+	id, err := strconv.Atoi(volume.ThinID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse thin_id from logical volume %s: %w", volume.Name, err)
+	}
+	changed, err := r.Delta(ctx, backup.Status.DeviceClass, volume.PoolName, baseID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manifest, err := GetManifest(ctx, store, codec, KeyPrefix(base.Spec.Location.Prefix, string(base.GetUID())))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get manifest of base LVMVolumeBackup %s: %w", base.GetName(), err)
+	}
+	if manifest.Size != backup.Status.SizeBytes || manifest.ChunkSize != ChunkSize {
+		logger.Info("taking a full backup instead of an incremental backup", "reason", "the base backup has a different layout")
+		return nil, nil, nil
+	}
+	return manifest, changed, nil
+}
+
+// uploadCompleted records the result of an upload. Failed uploads are retried.
+func (r *BackupReconciler) uploadCompleted(ctx context.Context, backup *lvmv1alpha1.LVMVolumeBackup, running *job) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+	if running.err != nil {
+		logger.Error(running.err, "upload of backup failed, retrying")
+		msg := fmt.Sprintf("upload to bucket %s failed and is retried: %v", backup.Spec.Location.Bucket, running.err)
+		r.Eventf(backup, nil, corev1.EventTypeWarning, string(EventReasonErrorUploadFailed), "BackupVolume", msg)
+		setBackupInProgress(backup, lvmv1alpha1.LVMVolumeBackupUploading, ReasonUploadFailed, msg)
+		return pollInterval, nil
+	}
+
+	backup.Status.Phase = lvmv1alpha1.LVMVolumeBackupCompleted
+	backup.Status.Progress = "100%"
+	backup.Status.BytesUploaded = running.progress.Transferred.Load()
+	backup.Status.CompletionTime = ptr.To(metav1.Now())
+	snapshotRef := backup.Spec.VolumeSnapshot
+	msg := fmt.Sprintf("VolumeSnapshot %s/%s was uploaded to bucket %s", snapshotRef.Namespace, snapshotRef.Name, backup.Spec.Location.Bucket)
+	meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeBackedUp,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonVolumeBackedUp,
+		Message: msg,
+	})
+	logger.Info(msg, "bytesUploaded", backup.Status.BytesUploaded)
+	r.Eventf(backup, nil, corev1.EventTypeNormal, string(EventReasonVolumeBackedUp), "BackupVolume", msg)
+	return 0, nil
+}
+
+// objectStore returns the ObjectStore of the location and the Codec of the encryption key.
+func (r *BackupReconciler) objectStore(ctx context.Context, namespace string, location lvmv1alpha1.BackupLocation, encryptionKeySecretName string) (ObjectStore, *Codec, error) {
+	return objectStore(ctx, r.APIReader, r.NewObjectStore, namespace, location, encryptionKeySecretName, ErrBackupFailed)
+}
+
+func (r *BackupReconciler) fail(ctx context.Context, backup *lvmv1alpha1.LVMVolumeBackup, err error) error {
+	r.Eventf(backup, nil, corev1.EventTypeWarning, string(EventReasonErrorBackupFailed), "BackupVolume", err.Error())
+	backup.Status.Phase = lvmv1alpha1.LVMVolumeBackupFailed
+	meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeBackedUp,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonBackupFailed,
+		Message: err.Error(),
+	})
+	return r.updateStatus(ctx, backup)
+}
+
+func (r *BackupReconciler) updateStatus(ctx context.Context, backup *lvmv1alpha1.LVMVolumeBackup) error {
+	if err := r.Status().Update(ctx, backup); err != nil {
+		return fmt.Errorf("failed to update status of LVMVolumeBackup %s: %w", backup.GetName(), err)
+	}
+	return nil
+}
+
+func setBackupInProgress(backup *lvmv1alpha1.LVMVolumeBackup, phase lvmv1alpha1.LVMVolumeBackupPhase, reason, msg string) {
+	if phase == "" {
+		phase = lvmv1alpha1.LVMVolumeBackupPending
+	}
+	backup.Status.Phase = phase
+	meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeBackedUp,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: msg,
+	})
+}
+
+// objectStore reads the credentials and the encryption key of a backup from their Secrets.
+// Missing Secrets are reported with failed as they require a change by the user.
+func objectStore(
+	ctx context.Context,
+	reader client.Reader,
+	newObjectStore ObjectStoreFunc,
+	namespace string,
+	location lvmv1alpha1.BackupLocation,
+	encryptionKeySecretName string,
+	failed error,
+) (ObjectStore, *Codec, error) {
+	credentials, err := secretData(ctx, reader, namespace, location.CredentialsSecretName, failed,
+		CredentialsAccessKeyIDKey, CredentialsSecretAccessKeyKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := newObjectStore(location, string(credentials[CredentialsAccessKeyIDKey]), string(credentials[CredentialsSecretAccessKeyKey]))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", failed, err)
+	}
+
+	var key []byte
+	if encryptionKeySecretName != "" {
+		data, err := secretData(ctx, reader, namespace, encryptionKeySecretName, failed, EncryptionKeyKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		key = data[EncryptionKeyKey]
+	}
+	codec, err := NewCodec(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return store, codec, nil
+}
+
+func secretData(ctx context.Context, reader client.Reader, namespace, name string, failed error, keys ...string) (map[string][]byte, error) {
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: Secret %s not found", failed, name)
+		}
+		return nil, fmt.Errorf("failed to get Secret %s: %w", name, err)
+	}
+	for _, key := range keys {
+		if len(secret.Data[key]) == 0 {
+			return nil, fmt.Errorf("%w: Secret %s has no %q", failed, name, key)
+		}
+	}
+	return secret.Data, nil
+}
+
+// isPaused checks if the node is in maintenance or the LVMCluster controlling the volume group is paused.
+func isPaused(ctx context.Context, c client.Client, nodeName, namespace, vgName string) (bool, error) {
+	node := &corev1.Node{}
+	if err := c.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		return false, fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	if node.GetAnnotations()[constants.MaintenanceAnnotation] == "true" {
+		return true, nil
+	}
+
+	volumeGroup := &lvmv1alpha1.LVMVolumeGroup{}
+	if err := c.Get(ctx, types.NamespacedName{Name: vgName, Namespace: namespace}, volumeGroup); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	owner := metav1.GetControllerOf(volumeGroup)
+	if owner == nil || owner.Kind != "LVMCluster" {
+		return false, nil
+	}
+	lvmCluster := &lvmv1alpha1.LVMCluster{}
+	if err := c.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: volumeGroup.GetNamespace()}, lvmCluster); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return lvmCluster.Spec.Paused, nil
+}
+
+func findLV(report *lvm.LVReport, name string) *lvm.LogicalVolume {
+	for _, item := range report.Report {
+		for i := range item.Lv {
+			if item.Lv[i].Name == name {
+				return &item.Lv[i]
+			}
+		}
+	}
+	return nil
+}
+
+func progress(processed, size int64) string {
+	if size <= 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%d%%", min(processed*100/size, 100))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&lvmv1alpha1.LVMVolumeBackup{}).
+		WithOptions(controller.Options{SkipNameValidation: ptr.To(true)}).
+		Named("lvms_volumebackup").
+		Complete(r)
+}
@@ -0,0 +1,270 @@
+package volume_backup
+
+import (
+	"context"
+	"testing"
+
+	snapapi "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	lvmmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm/mocks"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/thindelta"
+	thindeltamocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/thindelta/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNode        = "test-node"
+	testNamespace   = "openshift-lvm-storage"
+	testDeviceClass = "vg1"
+	testBackup      = "test-backup"
+	testVolume      = "0d2b1c3e-6a1f-4e4c-8d43-2b9a7a3c1f10"
+	testSnapshot    = "7e6f4f7c-2f5c-4a0d-9a0e-5f3c2b1d0e9a"
+	baseSnapshot    = "3c9d8e7f-1a2b-4c5d-8e9f-0a1b2c3d4e5f"
+	testSize        = int64(1073741824)
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, lvmv1alpha1.AddToScheme(scheme))
+	require.NoError(t, topolvmv1.AddToScheme(scheme))
+	require.NoError(t, snapapi.AddToScheme(scheme))
+	return scheme
+}
+
+func testLocation() lvmv1alpha1.BackupLocation {
+	return lvmv1alpha1.BackupLocation{
+		Endpoint:              "http://minio.minio.svc:9000",
+		Bucket:                "backups",
+		Prefix:                "volumes",
+		CredentialsSecretName: "s3-credentials",
+	}
+}
+
+func backupObjects() []client.Object {
+	return []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "s3-credentials", Namespace: testNamespace},
+			Data: map[string][]byte{
+				CredentialsAccessKeyIDKey:     []byte("access"),
+				CredentialsSecretAccessKeyKey: []byte("secret"),
+			},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{
+					Driver: constants.TopolvmCSIDriverName, VolumeHandle: testVolume, FSType: "xfs",
+				}},
+			},
+		},
+		&topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+			Spec:       topolvmv1.LogicalVolumeSpec{Name: "pvc-1", NodeName: testNode, DeviceClass: testDeviceClass},
+			Status:     topolvmv1.LogicalVolumeStatus{VolumeID: testVolume},
+		},
+		&topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "snapshot-1"},
+			Spec:       topolvmv1.LogicalVolumeSpec{Name: "snapshot-1", NodeName: testNode, DeviceClass: testDeviceClass, Source: "pvc-1"},
+			Status:     topolvmv1.LogicalVolumeStatus{VolumeID: testSnapshot},
+		},
+		&snapapi.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-snap", Namespace: "app"},
+			Spec:       snapapi.VolumeSnapshotSpec{Source: snapapi.VolumeSnapshotSource{PersistentVolumeClaimName: ptr.To("data")}},
+			Status:     &snapapi.VolumeSnapshotStatus{ReadyToUse: ptr.To(true), BoundVolumeSnapshotContentName: ptr.To("content-1")},
+		},
+		&snapapi.VolumeSnapshotContent{
+			ObjectMeta: metav1.ObjectMeta{Name: "content-1"},
+			Spec:       snapapi.VolumeSnapshotContentSpec{Driver: constants.TopolvmCSIDriverName},
+			Status:     &snapapi.VolumeSnapshotContentStatus{SnapshotHandle: ptr.To(testSnapshot)},
+		},
+	}
+}
+
+func testLVMVolumeBackup(name, uid string, status lvmv1alpha1.LVMVolumeBackupStatus) *lvmv1alpha1.LVMVolumeBackup {
+	return &lvmv1alpha1.LVMVolumeBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, UID: types.UID(uid)},
+		Spec: lvmv1alpha1.LVMVolumeBackupSpec{
+			VolumeSnapshot: lvmv1alpha1.VolumeBackupSnapshotReference{Name: "data-snap", Namespace: "app"},
+			Location:       testLocation(),
+		},
+		Status: status,
+	}
+}
+
+// resolvedBackupStatus is the status of a backup after the node of the snapshot resolved it.
+func resolvedBackupStatus(snapshot string) lvmv1alpha1.LVMVolumeBackupStatus {
+	return lvmv1alpha1.LVMVolumeBackupStatus{
+		Phase:                 lvmv1alpha1.LVMVolumeBackupUploading,
+		NodeName:              testNode,
+		DeviceClass:           testDeviceClass,
+		SourceLogicalVolume:   testVolume,
+		SnapshotLogicalVolume: snapshot,
+		SizeBytes:             testSize,
+		VolumeMode:            corev1.PersistentVolumeFilesystem,
+		FSType:                "xfs",
+	}
+}
+
+func snapshotLV(name, thinID string) lvm.LogicalVolume {
+	return lvm.LogicalVolume{
+		Name: name, VgName: testDeviceClass, LvAttr: "Vri---tz-k", LvSize: "1073741824",
+		PoolName: "thin-pool-1", Origin: testVolume, ThinID: thinID,
+	}
+}
+
+func snapshotReport(lvs ...lvm.LogicalVolume) *lvm.LVReport {
+	return &lvm.LVReport{Report: []lvm.LVReportItem{{Lv: lvs}}}
+}
+
+func newTestBackupReconciler(t *testing.T, mockLVM lvm.LVM, thinDelta thindelta.ThinDelta, store ObjectStore, objs ...client.Object) (*BackupReconciler, client.Client) {
+	t.Helper()
+	clnt := fake.NewClientBuilder().
+		WithScheme(newScheme(t)).
+		WithObjects(append(backupObjects(), objs...)...).
+		WithStatusSubresource(&lvmv1alpha1.LVMVolumeBackup{}).
+		Build()
+	r := NewBackupReconciler(clnt, clnt, events.NewFakeRecorder(10), mockLVM, thinDelta, testNode, testNamespace)
+	r.NewObjectStore = func(lvmv1alpha1.BackupLocation, string, string) (ObjectStore, error) {
+		return store, nil
+	}
+	return r, clnt
+}
+
+func reconcileBackup(t *testing.T, r *BackupReconciler, clnt client.Client) *lvmv1alpha1.LVMVolumeBackup {
+	t.Helper()
+	ctx := context.Background()
+	key := types.NamespacedName{Name: testBackup, Namespace: testNamespace}
+	_, err := r.Reconcile(ctx, controllerruntime.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	backup := &lvmv1alpha1.LVMVolumeBackup{}
+	require.NoError(t, clnt.Get(ctx, key, backup))
+	return backup
+}
+
+func waitForJob(t *testing.T, running *jobs, name string) {
+	t.Helper()
+	j := running.get(types.NamespacedName{Name: name, Namespace: testNamespace})
+	require.NotNil(t, j, "job should be running")
+	<-j.done
+}
+
+func TestBackupReconciler_Resolve(t *testing.T) {
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(snapshotReport(snapshotLV(testSnapshot, "3")), nil)
+
+	r, clnt := newTestBackupReconciler(t, mockLVM, nil, newMemoryStore(), testLVMVolumeBackup(testBackup, "backup-uid", lvmv1alpha1.LVMVolumeBackupStatus{}))
+	backup := reconcileBackup(t, r, clnt)
+
+	expected := resolvedBackupStatus(testSnapshot)
+	assert.Equal(t, expected.Phase, backup.Status.Phase)
+	assert.Equal(t, expected.NodeName, backup.Status.NodeName)
+	assert.Equal(t, expected.SourceLogicalVolume, backup.Status.SourceLogicalVolume)
+	assert.Equal(t, expected.SnapshotLogicalVolume, backup.Status.SnapshotLogicalVolume)
+	assert.Equal(t, expected.SizeBytes, backup.Status.SizeBytes)
+	assert.Equal(t, expected.FSType, backup.Status.FSType)
+	assert.Equal(t, "volumes/backup-uid/manifest", backup.Status.ManifestKey)
+
+	// the snapshot is not on this node
+	r, clnt = newTestBackupReconciler(t, lvmmocks.NewMockLVM(t), nil, newMemoryStore(), testLVMVolumeBackup(testBackup, "backup-uid", lvmv1alpha1.LVMVolumeBackupStatus{}))
+	r.NodeName = "other-node"
+	backup = reconcileBackup(t, r, clnt)
+	assert.Empty(t, backup.Status.Phase)
+}
+
+func TestBackupReconciler_Upload(t *testing.T) {
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(snapshotReport(snapshotLV(testSnapshot, "3")), nil)
+	mockLVM.EXPECT().CreateThinSnapshotLV(mock.Anything, testSnapshot+snapshotSuffix, testDeviceClass, testSnapshot).Return(nil)
+	mockLVM.EXPECT().DeleteLV(mock.Anything, testSnapshot+snapshotSuffix, testDeviceClass).Return(nil)
+
+	r, clnt := newTestBackupReconciler(t, mockLVM, nil, newMemoryStore(), testLVMVolumeBackup(testBackup, "backup-uid", resolvedBackupStatus(testSnapshot)))
+	r.Upload = func(_ context.Context, _ ObjectStore, codec *Codec, devicePath, keyPrefix string, manifest Manifest, base *Manifest, _ []thindelta.Range, progress *Progress) (*Manifest, error) {
+		assert.Equal(t, lvm.DeviceMapperPath(testDeviceClass, testSnapshot+snapshotSuffix), devicePath)
+		assert.Equal(t, "volumes/backup-uid", keyPrefix)
+		assert.Equal(t, testSize, manifest.Size)
+		assert.Nil(t, base)
+		assert.False(t, codec.Encrypted())
+		progress.Transferred.Store(1024)
+		return &manifest, nil
+	}
+
+	backup := reconcileBackup(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeBackupUploading, backup.Status.Phase)
+	assert.NotNil(t, backup.Status.StartTime)
+	waitForJob(t, &r.jobs, testBackup)
+
+	backup = reconcileBackup(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeBackupCompleted, backup.Status.Phase)
+	assert.Equal(t, "100%", backup.Status.Progress)
+	assert.Equal(t, int64(1024), backup.Status.BytesUploaded)
+	assert.False(t, backup.Status.Incremental)
+	assert.NotNil(t, backup.Status.CompletionTime)
+}
+
+func TestBackupReconciler_Incremental(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	codec, err := NewCodec(nil)
+	require.NoError(t, err)
+	data := make([]byte, testSize)
+	_, err = Upload(ctx, store, codec, newDevice(t, "base", data[:1024]), "volumes/base-uid", Manifest{Size: testSize}, nil, nil, &Progress{})
+	require.NoError(t, err)
+
+	baseStatus := resolvedBackupStatus(baseSnapshot)
+	baseStatus.Phase = lvmv1alpha1.LVMVolumeBackupCompleted
+	base := testLVMVolumeBackup("base", "base-uid", baseStatus)
+	backup := testLVMVolumeBackup(testBackup, "backup-uid", resolvedBackupStatus(testSnapshot))
+	backup.Spec.BaseBackupName = "base"
+
+	changed := []thindelta.Range{{Offset: 0, Length: 65536}}
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(snapshotReport(snapshotLV(baseSnapshot, "1"), snapshotLV(testSnapshot, "3")), nil)
+	mockLVM.EXPECT().CreateThinSnapshotLV(mock.Anything, testSnapshot+snapshotSuffix, testDeviceClass, testSnapshot).Return(nil)
+	mockLVM.EXPECT().DeleteLV(mock.Anything, testSnapshot+snapshotSuffix, testDeviceClass).Return(nil)
+	mockThinDelta := thindeltamocks.NewMockThinDelta(t)
+	mockThinDelta.EXPECT().Delta(mock.Anything, testDeviceClass, "thin-pool-1", 1, 3).Return(changed, nil)
+
+	r, clnt := newTestBackupReconciler(t, mockLVM, mockThinDelta, store, base, backup)
+	r.Upload = func(_ context.Context, _ ObjectStore, _ *Codec, _, _ string, manifest Manifest, base *Manifest, ranges []thindelta.Range, _ *Progress) (*Manifest, error) {
+		require.NotNil(t, base)
+		assert.Equal(t, testSize, base.Size)
+		assert.Equal(t, changed, ranges)
+		return &manifest, nil
+	}
+
+	backup = reconcileBackup(t, r, clnt)
+	assert.True(t, backup.Status.Incremental)
+	assert.Equal(t, "base", backup.Status.BaseBackupName)
+	waitForJob(t, &r.jobs, testBackup)
+
+	backup = reconcileBackup(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeBackupCompleted, backup.Status.Phase)
+}
+
+func TestBackupReconciler_MissingCredentials(t *testing.T) {
+	backup := testLVMVolumeBackup(testBackup, "backup-uid", resolvedBackupStatus(testSnapshot))
+	backup.Spec.Location.CredentialsSecretName = "missing"
+
+	r, clnt := newTestBackupReconciler(t, lvmmocks.NewMockLVM(t), nil, newMemoryStore(), backup)
+	backup = reconcileBackup(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeBackupFailed, backup.Status.Phase)
+	assert.Contains(t, backup.Status.Conditions[0].Message, "Secret missing not found")
+}
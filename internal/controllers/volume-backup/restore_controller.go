@@ -0,0 +1,635 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume_backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	RestoreControllerName = "lvms-volume-restore"
+
+	// RestoreLabel is set to the name of the restore on the LogicalVolume, the PersistentVolume and the
+	// PersistentVolumeClaim created for the restored volume.
+	RestoreLabel = "lvm.topolvm.io/volume-restore"
+
+	// RestoreFinalizer removes the restored volume if the restore fails or is deleted before it completed.
+	RestoreFinalizer = "lvm.topolvm.io/volume-restore"
+
+	// provisionedByAnnotation marks the PersistentVolume as provisioned by TopoLVM so that the
+	// external-provisioner handles it on deletion.
+	provisionedByAnnotation = "pv.kubernetes.io/provisioned-by"
+)
+
+const (
+	ReasonPreparing       = "Preparing"
+	ReasonRestoring       = "Restoring"
+	ReasonDownloadFailed  = "DownloadFailed"
+	ReasonBinding         = "Binding"
+	ReasonRestoreFailed   = "RestoreFailed"
+	ReasonVolumeRestored  = "VolumeRestored"
+	ReasonWaitingOnBackup = "WaitingOnBackup"
+)
+
+// ErrRestoreFailed is returned for restores that can not succeed without a change to the LVMVolumeRestore
+// or the LVMVolumeBackup, so they are reported in the status instead of being retried.
+var ErrRestoreFailed = errors.New("volume restore failed")
+
+// RestoreReconciler reconciles LVMVolumeRestore objects for the node it runs on.
+// The vg-manager of the node of the restore creates a new volume, writes the chunks of the backup
+// to it and binds it to a new PersistentVolumeClaim.
+type RestoreReconciler struct {
+	client.Client
+	events.EventRecorder
+	lvm.LVM
+
+	// APIReader reads the PersistentVolumeClaims outside the namespace of the operator, which are not part of
+	// the cache of vg-manager, and the Secrets of the backups, which are not cached.
+	APIReader      client.Reader
+	NewObjectStore ObjectStoreFunc
+	Download       DownloadFunc
+	NodeName       string
+	Namespace      string
+
+	jobs jobs
+}
+
+// NewRestoreReconciler returns RestoreReconciler.
+func NewRestoreReconciler(
+	client client.Client,
+	apiReader client.Reader,
+	eventRecorder events.EventRecorder,
+	lvm lvm.LVM,
+	nodeName, namespace string,
+) *RestoreReconciler {
+	return &RestoreReconciler{
+		Client:         client,
+		EventRecorder:  eventRecorder,
+		LVM:            lvm,
+		APIReader:      apiReader,
+		NewObjectStore: NewS3Client,
+		Download:       Download,
+		NodeName:       nodeName,
+		Namespace:      namespace,
+	}
+}
+
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumerestores,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumerestores/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumerestores/finalizers,verbs=update
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumebackups,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;update;patch
+
+// Reconcile restores the backup requested by the LVMVolumeRestore if the volume is restored on this node.
+func (r *RestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	restore := &lvmv1alpha1.LVMVolumeRestore{}
+	if err := r.Get(ctx, req.NamespacedName, restore); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.jobs.stop(req.NamespacedName)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if restore.Spec.NodeName != r.NodeName {
+		return ctrl.Result{}, nil
+	}
+
+	if !restore.DeletionTimestamp.IsZero() {
+		r.jobs.stop(req.NamespacedName)
+		return ctrl.Result{}, r.finalize(ctx, restore)
+	}
+	switch restore.Status.Phase {
+	case lvmv1alpha1.LVMVolumeRestoreCompleted:
+		return ctrl.Result{}, nil
+	case lvmv1alpha1.LVMVolumeRestoreFailed:
+		r.jobs.stop(req.NamespacedName)
+		return ctrl.Result{}, r.finalize(ctx, restore)
+	}
+
+	status := restore.Status.DeepCopy()
+	var requeue time.Duration
+	var err error
+	switch restore.Status.Phase {
+	case lvmv1alpha1.LVMVolumeRestoreRestoring:
+		requeue, err = r.restoreVolume(ctx, restore)
+	case lvmv1alpha1.LVMVolumeRestoreBinding:
+		requeue, err = r.bind(ctx, restore)
+	default:
+		requeue, err = r.resolve(ctx, restore)
+	}
+	if errors.Is(err, ErrRestoreFailed) {
+		r.jobs.stop(req.NamespacedName)
+		return ctrl.Result{}, r.fail(ctx, restore, err)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !equality.Semantic.DeepEqual(status, &restore.Status) {
+		if err := r.updateStatus(ctx, restore); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+// resolve waits for the backup to complete and checks that the volume can be restored on this node.
+func (r *RestoreReconciler) resolve(ctx context.Context, restore *lvmv1alpha1.LVMVolumeRestore) (time.Duration, error) {
+	backup, err := r.backup(ctx, restore)
+	if err != nil {
+		return 0, err
+	}
+	switch backup.Status.Phase {
+	case lvmv1alpha1.LVMVolumeBackupCompleted:
+	case lvmv1alpha1.LVMVolumeBackupFailed:
+		return 0, fmt.Errorf("%w: LVMVolumeBackup %s failed", ErrRestoreFailed, backup.GetName())
+	default:
+		setRestoreInProgress(restore, lvmv1alpha1.LVMVolumeRestorePending, ReasonWaitingOnBackup,
+			fmt.Sprintf("waiting for LVMVolumeBackup %s to complete", backup.GetName()))
+		return pollInterval, nil
+	}
+
+	claimRef := restore.Spec.PersistentVolumeClaim
+	err = r.APIReader.Get(ctx, types.NamespacedName{Name: claimRef.Name, Namespace: claimRef.Namespace}, &corev1.PersistentVolumeClaim{})
+	if err == nil {
+		return 0, fmt.Errorf("%w: PersistentVolumeClaim %s/%s already exists", ErrRestoreFailed, claimRef.Namespace, claimRef.Name)
+	}
+	if !apierrors.IsNotFound(err) {
+		return 0, fmt.Errorf("failed to get PersistentVolumeClaim %s/%s: %w", claimRef.Namespace, claimRef.Name, err)
+	}
+
+	deviceClass := restore.Spec.DeviceClass
+	if deviceClass == "" {
+		deviceClass = backup.Status.DeviceClass
+	}
+	if err := r.verifyDeviceClass(ctx, deviceClass); err != nil {
+		return 0, err
+	}
+
+	restore.Status.DeviceClass = deviceClass
+	restore.Status.SizeBytes = backup.Status.SizeBytes
+	setRestoreInProgress(restore, lvmv1alpha1.LVMVolumeRestoreRestoring, ReasonPreparing,
+		fmt.Sprintf("waiting for the volume to be created in device class %s", deviceClass))
+	log.FromContext(ctx).Info("resolved backup for restore", "backup", backup.GetName(), "deviceClass", deviceClass)
+	return pollInterval, nil
+}
+
+// verifyDeviceClass checks that this node has a volume group for the device class and that
+// the PersistentVolumeClaim can use its StorageClass.
+func (r *RestoreReconciler) verifyDeviceClass(ctx context.Context, deviceClass string) error {
+	nodeStatus := &lvmv1alpha1.LVMVolumeGroupNodeStatus{}
+	if err := r.Get(ctx, types.NamespacedName{Name: r.NodeName, Namespace: r.Namespace}, nodeStatus); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to get LVMVolumeGroupNodeStatus %s: %w", r.NodeName, err)
+	}
+	if vgStatus := nodeStatus.VolumeGroupStatus(deviceClass); vgStatus == nil || vgStatus.Status != lvmv1alpha1.VGStatusReady {
+		return fmt.Errorf("%w: node %s has no ready volume group for device class %s", ErrRestoreFailed, r.NodeName, deviceClass)
+	}
+
+	scName := constants.StorageClassPrefix + deviceClass
+	if err := r.Get(ctx, client.ObjectKey{Name: scName}, &storagev1.StorageClass{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: StorageClass %s of device class %s not found", ErrRestoreFailed, scName, deviceClass)
+		}
+		return fmt.Errorf("failed to get StorageClass %s: %w", scName, err)
+	}
+	return nil
+}
+
+// restoreVolume creates the volume and downloads the backup to it in the background.
+func (r *RestoreReconciler) restoreVolume(ctx context.Context, restore *lvmv1alpha1.LVMVolumeRestore) (time.Duration, error) {
+	key := client.ObjectKeyFromObject(restore)
+	if running := r.jobs.get(key); running != nil {
+		select {
+		case <-running.done:
+			r.jobs.remove(key)
+			return r.downloadCompleted(ctx, restore, running)
+		default:
+			restore.Status.Progress = progress(running.progress.Processed.Load(), restore.Status.SizeBytes)
+			restore.Status.BytesDownloaded = running.progress.Transferred.Load()
+			return pollInterval, nil
+		}
+	}
+
+	if controllerutil.AddFinalizer(restore, RestoreFinalizer) {
+		if err := r.Update(ctx, restore); err != nil {
+			return 0, fmt.Errorf("failed to add finalizer to LVMVolumeRestore %s: %w", restore.GetName(), err)
+		}
+	}
+
+	logicalVolume, err := r.getOrCreateLogicalVolume(ctx, restore)
+	if err != nil {
+		return 0, err
+	}
+	if logicalVolume.Status.Code != codes.OK {
+		return 0, fmt.Errorf("%w: logical volume could not be created on node %s: %s",
+			ErrRestoreFailed, r.NodeName, logicalVolume.Status.Message)
+	}
+	if logicalVolume.Status.VolumeID == "" {
+		setRestoreInProgress(restore, lvmv1alpha1.LVMVolumeRestoreRestoring, ReasonPreparing,
+			fmt.Sprintf("waiting for LogicalVolume %s to be created on node %s", logicalVolume.GetName(), r.NodeName))
+		return pollInterval, nil
+	}
+	restore.Status.LogicalVolumeName = logicalVolume.GetName()
+	restore.Status.LogicalVolume = logicalVolume.Status.VolumeID
+
+	vgName := restore.Status.DeviceClass
+	paused, err := isPaused(ctx, r.Client, r.NodeName, r.Namespace, vgName)
+	if err != nil {
+		return 0, err
+	}
+	if paused {
+		setRestoreInProgress(restore, restore.Status.Phase, ReasonPaused,
+			"waiting for the node to leave maintenance and the LVMCluster to be unpaused")
+		return pollInterval, nil
+	}
+
+	backup, err := r.backup(ctx, restore)
+	if err != nil {
+		return 0, err
+	}
+	store, codec, err := objectStore(ctx, r.APIReader, r.NewObjectStore, backup.GetNamespace(),
+		backup.Spec.Location, backup.Spec.EncryptionKeySecretName, ErrRestoreFailed)
+	if err != nil {
+		return 0, err
+	}
+	manifest, err := GetManifest(ctx, store, codec, KeyPrefix(backup.Spec.Location.Prefix, string(backup.GetUID())))
+	if errors.Is(err, ErrObjectNotFound) || errors.Is(err, ErrInvalidManifest) {
+		return 0, fmt.Errorf("%w: manifest of LVMVolumeBackup %s: %w", ErrRestoreFailed, backup.GetName(), err)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get manifest of LVMVolumeBackup %s: %w", backup.GetName(), err)
+	}
+
+	// a new thin volume already reads as zeros, so empty chunks do not have to be written
+	lvReport, err := r.ListLVs(ctx, vgName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list logical volumes in volume group %s: %w", vgName, err)
+	}
+	volume := findLV(lvReport, logicalVolume.Status.VolumeID)
+	if volume == nil {
+		return 0, fmt.Errorf("logical volume %s not found in volume group %s", logicalVolume.Status.VolumeID, vgName)
+	}
+	lvAttr, err := vgmanager.ParsedLvAttr(volume.LvAttr)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse lv_attr from logical volume %s: %w", volume.Name, err)
+	}
+	sparse := lvAttr.VolumeType == vgmanager.VolumeTypeThinVolume
+	devicePath := lvm.DeviceMapperPath(vgName, volume.Name)
+
+	downloadCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	running := &job{cancel: cancel, done: make(chan struct{}), manifest: manifest}
+	r.jobs.set(key, running)
+
+	go func() {
+		defer close(running.done)
+		defer cancel()
+		running.err = r.Download(downloadCtx, store, codec, manifest, devicePath, sparse, &running.progress)
+	}()
+
+	if restore.Status.StartTime == nil {
+		restore.Status.StartTime = ptr.To(metav1.Now())
+	}
+	restore.Status.Progress = "0%"
+	msg := fmt.Sprintf("downloading LVMVolumeBackup %s to logical volume %s", backup.GetName(), volume.Name)
+	setRestoreInProgress(restore, lvmv1alpha1.LVMVolumeRestoreRestoring, ReasonRestoring, msg)
+	log.FromContext(ctx).Info(msg, "VGName", vgName, "sparse", sparse)
+
+	return pollInterval, nil
+}
+
+func (r *RestoreReconciler) getOrCreateLogicalVolume(ctx context.Context, restore *lvmv1alpha1.LVMVolumeRestore) (*topolvmv1.LogicalVolume, error) {
+	name := restoreLogicalVolumeName(restore)
+	logicalVolume := &topolvmv1.LogicalVolume{}
+	err := r.Get(ctx, client.ObjectKey{Name: name}, logicalVolume)
+	if err == nil {
+		if logicalVolume.GetLabels()[RestoreLabel] != restore.GetName() {
+			return nil, fmt.Errorf("%w: LogicalVolume %s already exists and was not created by this restore", ErrRestoreFailed, name)
+		}
+		return logicalVolume, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get LogicalVolume %s: %w", name, err)
+	}
+
+	logicalVolume = &topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{RestoreLabel: restore.GetName()},
+		},
+		Spec: topolvmv1.LogicalVolumeSpec{
+			Name:        name,
+			NodeName:    r.NodeName,
+			Size:        *resource.NewQuantity(restore.Status.SizeBytes, resource.BinarySI),
+			DeviceClass: restore.Status.DeviceClass,
+		},
+	}
+	if err := r.Create(ctx, logicalVolume); err != nil {
+		return nil, fmt.Errorf("failed to create LogicalVolume %s: %w", name, err)
+	}
+	log.FromContext(ctx).Info("created LogicalVolume for restore", "LogicalVolume", name)
+	return logicalVolume, nil
+}
+
+// downloadCompleted records the result of a download. Failed downloads are retried.
+func (r *RestoreReconciler) downloadCompleted(ctx context.Context, restore *lvmv1alpha1.LVMVolumeRestore, running *job) (time.Duration, error) {
+	if running.err != nil {
+		log.FromContext(ctx).Error(running.err, "download of backup failed, retrying")
+		msg := fmt.Sprintf("download of LVMVolumeBackup %s failed and is retried: %v", restore.Spec.BackupName, running.err)
+		r.Eventf(restore, nil, corev1.EventTypeWarning, string(EventReasonErrorDownloadFailed), "RestoreVolume", msg)
+		setRestoreInProgress(restore, lvmv1alpha1.LVMVolumeRestoreRestoring, ReasonDownloadFailed, msg)
+		return pollInterval, nil
+	}
+
+	restore.Status.Progress = "100%"
+	restore.Status.BytesDownloaded = running.progress.Transferred.Load()
+	claimRef := restore.Spec.PersistentVolumeClaim
+	setRestoreInProgress(restore, lvmv1alpha1.LVMVolumeRestoreBinding, ReasonBinding,
+		fmt.Sprintf("binding PersistentVolumeClaim %s/%s to the restored volume", claimRef.Namespace, claimRef.Name))
+	log.FromContext(ctx).Info("download of backup completed", "bytesDownloaded", restore.Status.BytesDownloaded)
+	return pollInterval, nil
+}
+
+// bind creates the PersistentVolume of the restored volume and the PersistentVolumeClaim bound to it.
+func (r *RestoreReconciler) bind(ctx context.Context, restore *lvmv1alpha1.LVMVolumeRestore) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+	claimRef := restore.Spec.PersistentVolumeClaim
+	claimKey := types.NamespacedName{Name: claimRef.Name, Namespace: claimRef.Namespace}
+
+	backup, err := r.backup(ctx, restore)
+	if err != nil {
+		return 0, err
+	}
+	logicalVolume := &topolvmv1.LogicalVolume{}
+	if err := r.Get(ctx, client.ObjectKey{Name: restore.Status.LogicalVolumeName}, logicalVolume); err != nil {
+		return 0, fmt.Errorf("failed to get LogicalVolume %s: %w", restore.Status.LogicalVolumeName, err)
+	}
+
+	pv := &corev1.PersistentVolume{}
+	err = r.Get(ctx, client.ObjectKey{Name: logicalVolume.GetName()}, pv)
+	if apierrors.IsNotFound(err) {
+		pv = persistentVolumeForRestore(restore, backup, logicalVolume)
+		if err := r.Create(ctx, pv); err != nil {
+			return 0, fmt.Errorf("failed to create PersistentVolume %s: %w", pv.GetName(), err)
+		}
+		logger.Info("created PersistentVolume for restored volume", "PersistentVolume", pv.GetName())
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to get PersistentVolume %s: %w", logicalVolume.GetName(), err)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	err = r.APIReader.Get(ctx, claimKey, pvc)
+	switch {
+	case apierrors.IsNotFound(err):
+		pvc = persistentVolumeClaimForRestore(restore, pv)
+		if err := r.Create(ctx, pvc); err != nil {
+			return 0, fmt.Errorf("failed to create PersistentVolumeClaim %s: %w", claimKey, err)
+		}
+		logger.Info("created PersistentVolumeClaim for restored volume", "PersistentVolumeClaim", claimKey)
+		setRestoreInProgress(restore, lvmv1alpha1.LVMVolumeRestoreBinding, ReasonBinding,
+			fmt.Sprintf("waiting for PersistentVolumeClaim %s to be bound to PersistentVolume %s", claimKey, pv.GetName()))
+		return pollInterval, nil
+	case err != nil:
+		return 0, fmt.Errorf("failed to get PersistentVolumeClaim %s: %w", claimKey, err)
+	case pvc.GetLabels()[RestoreLabel] != restore.GetName():
+		return 0, fmt.Errorf("%w: PersistentVolumeClaim %s was created by someone else", ErrRestoreFailed, claimKey)
+	case pvc.Status.Phase != corev1.ClaimBound:
+		setRestoreInProgress(restore, lvmv1alpha1.LVMVolumeRestoreBinding, ReasonBinding,
+			fmt.Sprintf("waiting for PersistentVolumeClaim %s to be bound to PersistentVolume %s", claimKey, pv.GetName()))
+		return pollInterval, nil
+	}
+
+	if controllerutil.RemoveFinalizer(restore, RestoreFinalizer) {
+		if err := r.Update(ctx, restore); err != nil {
+			return 0, fmt.Errorf("failed to remove finalizer from LVMVolumeRestore %s: %w", restore.GetName(), err)
+		}
+	}
+
+	restore.Status.Phase = lvmv1alpha1.LVMVolumeRestoreCompleted
+	restore.Status.CompletionTime = ptr.To(metav1.Now())
+	msg := fmt.Sprintf("LVMVolumeBackup %s was restored to PersistentVolumeClaim %s on node %s", backup.GetName(), claimKey, r.NodeName)
+	meta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeRestored,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonVolumeRestored,
+		Message: msg,
+	})
+	logger.Info(msg)
+	r.Eventf(restore, nil, corev1.EventTypeNormal, string(EventReasonVolumeRestored), "RestoreVolume", msg)
+	return 0, nil
+}
+
+// finalize removes the restored volume of a restore that did not complete.
+func (r *RestoreReconciler) finalize(ctx context.Context, restore *lvmv1alpha1.LVMVolumeRestore) error {
+	if !controllerutil.ContainsFinalizer(restore, RestoreFinalizer) {
+		return nil
+	}
+	name := restoreLogicalVolumeName(restore)
+
+	pv := &corev1.PersistentVolume{}
+	err := r.Get(ctx, client.ObjectKey{Name: name}, pv)
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to get PersistentVolume %s: %w", name, err)
+	}
+	if err == nil && pv.GetLabels()[RestoreLabel] == restore.GetName() {
+		if err := r.Delete(ctx, pv); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete PersistentVolume %s: %w", name, err)
+		}
+	}
+
+	logicalVolume := &topolvmv1.LogicalVolume{}
+	err = r.Get(ctx, client.ObjectKey{Name: name}, logicalVolume)
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to get LogicalVolume %s: %w", name, err)
+	}
+	if err == nil && logicalVolume.GetLabels()[RestoreLabel] == restore.GetName() {
+		if err := r.Delete(ctx, logicalVolume); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete LogicalVolume %s: %w", name, err)
+		}
+		log.FromContext(ctx).Info("deleted LogicalVolume of unfinished restore", "LogicalVolume", name)
+	}
+
+	controllerutil.RemoveFinalizer(restore, RestoreFinalizer)
+	if err := r.Update(ctx, restore); err != nil {
+		return fmt.Errorf("failed to remove finalizer from LVMVolumeRestore %s: %w", restore.GetName(), err)
+	}
+	return nil
+}
+
+func (r *RestoreReconciler) backup(ctx context.Context, restore *lvmv1alpha1.LVMVolumeRestore) (*lvmv1alpha1.LVMVolumeBackup, error) {
+	backup := &lvmv1alpha1.LVMVolumeBackup{}
+	if err := r.Get(ctx, types.NamespacedName{Name: restore.Spec.BackupName, Namespace: restore.GetNamespace()}, backup); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: LVMVolumeBackup %s not found", ErrRestoreFailed, restore.Spec.BackupName)
+		}
+		return nil, fmt.Errorf("failed to get LVMVolumeBackup %s: %w", restore.Spec.BackupName, err)
+	}
+	return backup, nil
+}
+
+func (r *RestoreReconciler) fail(ctx context.Context, restore *lvmv1alpha1.LVMVolumeRestore, err error) error {
+	r.Eventf(restore, nil, corev1.EventTypeWarning, string(EventReasonErrorRestoreFailed), "RestoreVolume", err.Error())
+	restore.Status.Phase = lvmv1alpha1.LVMVolumeRestoreFailed
+	meta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeRestored,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonRestoreFailed,
+		Message: err.Error(),
+	})
+	return r.updateStatus(ctx, restore)
+}
+
+func (r *RestoreReconciler) updateStatus(ctx context.Context, restore *lvmv1alpha1.LVMVolumeRestore) error {
+	if err := r.Status().Update(ctx, restore); err != nil {
+		return fmt.Errorf("failed to update status of LVMVolumeRestore %s: %w", restore.GetName(), err)
+	}
+	return nil
+}
+
+func setRestoreInProgress(restore *lvmv1alpha1.LVMVolumeRestore, phase lvmv1alpha1.LVMVolumeRestorePhase, reason, msg string) {
+	if phase == "" {
+		phase = lvmv1alpha1.LVMVolumeRestorePending
+	}
+	restore.Status.Phase = phase
+	meta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeRestored,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: msg,
+	})
+}
+
+func persistentVolumeForRestore(
+	restore *lvmv1alpha1.LVMVolumeRestore,
+	backup *lvmv1alpha1.LVMVolumeBackup,
+	logicalVolume *topolvmv1.LogicalVolume,
+) *corev1.PersistentVolume {
+	volumeMode := backup.Status.VolumeMode
+	if volumeMode == "" {
+		volumeMode = corev1.PersistentVolumeFilesystem
+	}
+	claimRef := restore.Spec.PersistentVolumeClaim
+
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        logicalVolume.GetName(),
+			Labels:      map[string]string{RestoreLabel: restore.GetName()},
+			Annotations: map[string]string{provisionedByAnnotation: constants.TopolvmCSIDriverName},
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: logicalVolume.Spec.Size,
+			},
+			AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+			StorageClassName:              constants.StorageClassPrefix + restore.Status.DeviceClass,
+			VolumeMode:                    &volumeMode,
+			ClaimRef: &corev1.ObjectReference{
+				Kind:       "PersistentVolumeClaim",
+				APIVersion: "v1",
+				Name:       claimRef.Name,
+				Namespace:  claimRef.Namespace,
+			},
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:       constants.TopolvmCSIDriverName,
+					VolumeHandle: logicalVolume.Status.VolumeID,
+					FSType:       backup.Status.FSType,
+					VolumeAttributes: map[string]string{
+						constants.DeviceClassKey: restore.Status.DeviceClass,
+					},
+				},
+			},
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      topolvm.GetTopologyNodeKey(),
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{logicalVolume.Spec.NodeName},
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func persistentVolumeClaimForRestore(restore *lvmv1alpha1.LVMVolumeRestore, pv *corev1.PersistentVolume) *corev1.PersistentVolumeClaim {
+	claimRef := restore.Spec.PersistentVolumeClaim
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claimRef.Name,
+			Namespace: claimRef.Namespace,
+			Labels:    map[string]string{RestoreLabel: restore.GetName()},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: pv.Spec.AccessModes,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: pv.Spec.Capacity[corev1.ResourceStorage]},
+			},
+			StorageClassName: ptr.To(pv.Spec.StorageClassName),
+			VolumeMode:       pv.Spec.VolumeMode,
+			VolumeName:       pv.GetName(),
+		},
+	}
+}
+
+// restoreLogicalVolumeName is the name of the LogicalVolume and the PersistentVolume of the restored volume,
+// which follows the naming of PersistentVolumes provisioned by TopoLVM.
+func restoreLogicalVolumeName(restore *lvmv1alpha1.LVMVolumeRestore) string {
+	return "pvc-" + string(restore.GetUID())
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&lvmv1alpha1.LVMVolumeRestore{}).
+		WithOptions(controller.Options{SkipNameValidation: ptr.To(true)}).
+		Named("lvms_volumerestore").
+		Complete(r)
+}
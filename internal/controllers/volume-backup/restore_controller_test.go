@@ -0,0 +1,220 @@
+package volume_backup
+
+import (
+	"context"
+	"testing"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	lvmmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	testRestore     = "test-restore"
+	restoredVolume  = "5b4a3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d"
+	restoredLVName  = "pvc-restore-uid"
+	completedBackup = "completed-backup"
+)
+
+func restoreObjects() []client.Object {
+	status := resolvedBackupStatus(testSnapshot)
+	status.Phase = lvmv1alpha1.LVMVolumeBackupCompleted
+	return []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "s3-credentials", Namespace: testNamespace},
+			Data: map[string][]byte{
+				CredentialsAccessKeyIDKey:     []byte("access"),
+				CredentialsSecretAccessKeyKey: []byte("secret"),
+			},
+		},
+		&lvmv1alpha1.LVMVolumeGroupNodeStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: testNode, Namespace: testNamespace},
+			Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+				VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
+					{VGStatus: lvmv1alpha1.VGStatus{Name: testDeviceClass, Status: lvmv1alpha1.VGStatusReady}},
+				},
+			},
+		},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "lvms-vg1"}},
+		testLVMVolumeBackup(completedBackup, "backup-uid", status),
+	}
+}
+
+func testLVMVolumeRestore(status lvmv1alpha1.LVMVolumeRestoreStatus) *lvmv1alpha1.LVMVolumeRestore {
+	return &lvmv1alpha1.LVMVolumeRestore{
+		ObjectMeta: metav1.ObjectMeta{Name: testRestore, Namespace: testNamespace, UID: "restore-uid"},
+		Spec: lvmv1alpha1.LVMVolumeRestoreSpec{
+			BackupName:            completedBackup,
+			PersistentVolumeClaim: lvmv1alpha1.VolumeRestoreClaimReference{Name: "restored", Namespace: "app"},
+			NodeName:              testNode,
+		},
+		Status: status,
+	}
+}
+
+func restoringStatus(phase lvmv1alpha1.LVMVolumeRestorePhase) lvmv1alpha1.LVMVolumeRestoreStatus {
+	return lvmv1alpha1.LVMVolumeRestoreStatus{
+		Phase:       phase,
+		DeviceClass: testDeviceClass,
+		SizeBytes:   testSize,
+	}
+}
+
+func newTestRestoreReconciler(t *testing.T, mockLVM lvm.LVM, store ObjectStore, objs ...client.Object) (*RestoreReconciler, client.Client) {
+	t.Helper()
+	clnt := fake.NewClientBuilder().
+		WithScheme(newScheme(t)).
+		WithObjects(append(restoreObjects(), objs...)...).
+		WithStatusSubresource(&lvmv1alpha1.LVMVolumeRestore{}, &topolvmv1.LogicalVolume{}).
+		Build()
+	r := NewRestoreReconciler(clnt, clnt, events.NewFakeRecorder(10), mockLVM, testNode, testNamespace)
+	r.NewObjectStore = func(lvmv1alpha1.BackupLocation, string, string) (ObjectStore, error) {
+		return store, nil
+	}
+	return r, clnt
+}
+
+func reconcileRestore(t *testing.T, r *RestoreReconciler, clnt client.Client) *lvmv1alpha1.LVMVolumeRestore {
+	t.Helper()
+	ctx := context.Background()
+	key := types.NamespacedName{Name: testRestore, Namespace: testNamespace}
+	_, err := r.Reconcile(ctx, controllerruntime.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	restore := &lvmv1alpha1.LVMVolumeRestore{}
+	require.NoError(t, clnt.Get(ctx, key, restore))
+	return restore
+}
+
+func TestRestoreReconciler_Resolve(t *testing.T) {
+	r, clnt := newTestRestoreReconciler(t, lvmmocks.NewMockLVM(t), newMemoryStore(), testLVMVolumeRestore(lvmv1alpha1.LVMVolumeRestoreStatus{}))
+	restore := reconcileRestore(t, r, clnt)
+
+	assert.Equal(t, restoringStatus(lvmv1alpha1.LVMVolumeRestoreRestoring).Phase, restore.Status.Phase)
+	assert.Equal(t, testDeviceClass, restore.Status.DeviceClass)
+	assert.Equal(t, testSize, restore.Status.SizeBytes)
+}
+
+func TestRestoreReconciler_ResolveFailed(t *testing.T) {
+	failedStatus := resolvedBackupStatus(testSnapshot)
+	failedStatus.Phase = lvmv1alpha1.LVMVolumeBackupFailed
+
+	tests := []struct {
+		name        string
+		deviceClass string
+		backupName  string
+		objs        []client.Object
+	}{
+		{"backup not found", "", "missing", nil},
+		{"backup failed", "", "failed-backup", []client.Object{testLVMVolumeBackup("failed-backup", "failed-uid", failedStatus)}},
+		{"claim exists", "", completedBackup, []client.Object{&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "restored", Namespace: "app"},
+		}}},
+		{"device class without volume group", "vg2", completedBackup, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore := testLVMVolumeRestore(lvmv1alpha1.LVMVolumeRestoreStatus{})
+			restore.Spec.BackupName = tt.backupName
+			restore.Spec.DeviceClass = tt.deviceClass
+			r, clnt := newTestRestoreReconciler(t, lvmmocks.NewMockLVM(t), newMemoryStore(), append(tt.objs, restore)...)
+
+			restore = reconcileRestore(t, r, clnt)
+			assert.Equal(t, lvmv1alpha1.LVMVolumeRestoreFailed, restore.Status.Phase)
+		})
+	}
+}
+
+func TestRestoreReconciler_Restore(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	codec, err := NewCodec(nil)
+	require.NoError(t, err)
+	_, err = Upload(ctx, store, codec, newDevice(t, "source", make([]byte, 1024)), "volumes/backup-uid",
+		Manifest{Size: testSize, VolumeMode: corev1.PersistentVolumeFilesystem, FSType: "xfs"}, nil, nil, &Progress{})
+	require.NoError(t, err)
+
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(snapshotReport(lvm.LogicalVolume{
+		Name: restoredVolume, VgName: testDeviceClass, LvAttr: "Vwi-a-tz--", LvSize: "1073741824", PoolName: "thin-pool-1",
+	}), nil)
+
+	r, clnt := newTestRestoreReconciler(t, mockLVM, store, testLVMVolumeRestore(restoringStatus(lvmv1alpha1.LVMVolumeRestoreRestoring)))
+	r.Download = func(_ context.Context, _ ObjectStore, _ *Codec, manifest *Manifest, devicePath string, sparse bool, progress *Progress) error {
+		assert.Equal(t, testSize, manifest.Size)
+		assert.Equal(t, lvm.DeviceMapperPath(testDeviceClass, restoredVolume), devicePath)
+		assert.True(t, sparse, "a new thin volume should not be zeroed")
+		progress.Transferred.Store(2048)
+		return nil
+	}
+
+	// the volume is created first
+	restore := reconcileRestore(t, r, clnt)
+	assert.True(t, controllerutil.ContainsFinalizer(restore, RestoreFinalizer))
+	logicalVolume := &topolvmv1.LogicalVolume{}
+	require.NoError(t, clnt.Get(ctx, client.ObjectKey{Name: restoredLVName}, logicalVolume))
+	assert.Equal(t, testNode, logicalVolume.Spec.NodeName)
+	assert.Equal(t, testSize, logicalVolume.Spec.Size.Value())
+	logicalVolume.Status.VolumeID = restoredVolume
+	require.NoError(t, clnt.Status().Update(ctx, logicalVolume))
+
+	restore = reconcileRestore(t, r, clnt)
+	assert.Equal(t, restoredVolume, restore.Status.LogicalVolume)
+	waitForJob(t, &r.jobs, testRestore)
+
+	restore = reconcileRestore(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeRestoreBinding, restore.Status.Phase)
+	assert.Equal(t, int64(2048), restore.Status.BytesDownloaded)
+
+	restore = reconcileRestore(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeRestoreBinding, restore.Status.Phase)
+	pv := &corev1.PersistentVolume{}
+	require.NoError(t, clnt.Get(ctx, client.ObjectKey{Name: restoredLVName}, pv))
+	assert.Equal(t, restoredVolume, pv.Spec.CSI.VolumeHandle)
+	assert.Equal(t, "xfs", pv.Spec.CSI.FSType)
+	assert.Equal(t, "lvms-vg1", pv.Spec.StorageClassName)
+	assert.Equal(t, "restored", pv.Spec.ClaimRef.Name)
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, clnt.Get(ctx, types.NamespacedName{Name: "restored", Namespace: "app"}, pvc))
+	assert.Equal(t, restoredLVName, pvc.Spec.VolumeName)
+
+	pvc.Status.Phase = corev1.ClaimBound
+	require.NoError(t, clnt.Status().Update(ctx, pvc))
+	restore = reconcileRestore(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeRestoreCompleted, restore.Status.Phase)
+	assert.False(t, controllerutil.ContainsFinalizer(restore, RestoreFinalizer))
+}
+
+func TestRestoreReconciler_FinalizeFailed(t *testing.T) {
+	ctx := context.Background()
+	status := restoringStatus(lvmv1alpha1.LVMVolumeRestoreFailed)
+	status.LogicalVolumeName = restoredLVName
+	restore := testLVMVolumeRestore(status)
+	restore.Finalizers = []string{RestoreFinalizer}
+	logicalVolume := &topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: restoredLVName, Labels: map[string]string{RestoreLabel: testRestore}},
+		Spec:       topolvmv1.LogicalVolumeSpec{Name: restoredLVName, NodeName: testNode, DeviceClass: testDeviceClass},
+	}
+
+	r, clnt := newTestRestoreReconciler(t, lvmmocks.NewMockLVM(t), newMemoryStore(), restore, logicalVolume)
+	restore = reconcileRestore(t, r, clnt)
+	assert.False(t, controllerutil.ContainsFinalizer(restore, RestoreFinalizer))
+
+	err := clnt.Get(ctx, client.ObjectKey{Name: restoredLVName}, &topolvmv1.LogicalVolume{})
+	assert.True(t, apierrors.IsNotFound(err), "LogicalVolume of the failed restore should be deleted")
+}
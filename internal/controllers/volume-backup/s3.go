@@ -0,0 +1,247 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume_backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+)
+
+const (
+	// DefaultRegion is the region requests are signed for if the location does not specify one.
+	DefaultRegion = "us-east-1"
+
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat    = "20060102T150405Z"
+	requestTimeout   = 5 * time.Minute
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectStore stores the objects of backups.
+type ObjectStore interface {
+	// PutObject stores data under key.
+	PutObject(ctx context.Context, key string, data []byte) error
+	// GetObject returns the data stored under key or ErrObjectNotFound.
+	GetObject(ctx context.Context, key string) ([]byte, error)
+}
+
+// ObjectStoreFunc returns the ObjectStore of a backup location.
+type ObjectStoreFunc func(location lvmv1alpha1.BackupLocation, accessKeyID, secretAccessKey string) (ObjectStore, error)
+
+// S3Client is an ObjectStore for a bucket of an S3-compatible object storage.
+// Buckets are addressed path-style and requests are signed with AWS Signature Version 4.
+type S3Client struct {
+	endpoint        *url.URL
+	region          string
+	bucket          string
+	accessKeyID     string
+	secretAccessKey string
+	httpClient      *http.Client
+	now             func() time.Time
+}
+
+// NewS3Client returns an S3Client for the bucket of the location.
+func NewS3Client(location lvmv1alpha1.BackupLocation, accessKeyID, secretAccessKey string) (ObjectStore, error) {
+	endpoint, err := url.Parse(location.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", location.Endpoint, err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid endpoint %q: scheme must be http or https", location.Endpoint)
+	}
+	region := location.Region
+	if region == "" {
+		region = DefaultRegion
+	}
+	return &S3Client{
+		endpoint:        endpoint,
+		region:          region,
+		bucket:          location.Bucket,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		httpClient:      &http.Client{Timeout: requestTimeout},
+		now:             time.Now,
+	}, nil
+}
+
+func (c *S3Client) PutObject(ctx context.Context, key string, data []byte) error {
+	resp, err := c.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to put object %s: %w", key, responseError(resp))
+	}
+	return nil
+}
+
+func (c *S3Client) GetObject(ctx context.Context, key string) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("failed to get object %s: %w", key, ErrObjectNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get object %s: %w", key, responseError(resp))
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %s: %w", key, err)
+	}
+	return data, nil
+}
+
+func (c *S3Client) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	u := *c.endpoint
+	u.RawPath = strings.TrimSuffix(c.endpoint.EscapedPath(), "/") + "/" + uriEncode(c.bucket, false) + "/" + uriEncode(key, true)
+	path, err := url.PathUnescape(u.RawPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object key %q: %w", key, err)
+	}
+	u.Path = path
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for object %s: %w", key, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	c.sign(req, c.now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request for object %s: %w", key, err)
+	}
+	return resp, nil
+}
+
+// sign adds the Authorization header for AWS Signature Version 4 to the request, signing the host
+// and all headers set on the request. The X-Amz-Content-Sha256 header has to be set already.
+func (c *S3Client) sign(req *http.Request, now time.Time) {
+	amzDate := now.UTC().Format(amzDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", amzDate[:8], c.region)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{signingAlgorithm, amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+c.secretAccessKey), amzDate[:8])
+	for _, part := range []string{c.region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		signingAlgorithm, c.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var params []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, uriEncode(key, false)+"="+uriEncode(value, false))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// uriEncode encodes all characters except the unreserved ones as required by AWS Signature Version 4.
+// Slashes are kept if keepSlash is set.
+func uriEncode(s string, keepSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func responseError(resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
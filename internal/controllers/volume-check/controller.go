@@ -0,0 +1,477 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume_check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/fsck"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	ControllerName = "lvms-volume-check"
+
+	// pollInterval is the interval in which a check is retried while it waits for the volume to be unpublished.
+	pollInterval = 10 * time.Second
+
+	// snapshotSuffix is appended to the name of the logical volume for the temporary snapshot that is checked.
+	snapshotSuffix = "-check"
+)
+
+type (
+	EventReasonInfo  string
+	EventReasonError string
+)
+
+const (
+	EventReasonErrorCheckFailed           EventReasonError = "CheckFailed"
+	EventReasonErrorFilesystemErrorsFound EventReasonError = "FilesystemErrorsFound"
+	EventReasonFilesystemClean            EventReasonInfo  = "FilesystemClean"
+	EventReasonFilesystemRepaired         EventReasonInfo  = "FilesystemRepaired"
+)
+
+const (
+	ReasonResolving           = "Resolving"
+	ReasonWaitingForUnpublish = "WaitingForUnpublish"
+	ReasonPaused              = "Paused"
+	ReasonChecking            = "Checking"
+	ReasonCheckFailed         = "CheckFailed"
+	ReasonClean               = "Clean"
+	ReasonErrorsFound         = "ErrorsFound"
+	ReasonRepaired            = "Repaired"
+)
+
+// ErrCheckFailed is returned for checks that can not succeed without a change to the LVMVolumeCheck
+// or the PersistentVolumeClaim, so they are reported in the status instead of being retried.
+var ErrCheckFailed = errors.New("volume check failed")
+
+// checkTarget is the logical volume of a check and the PersistentVolumeClaim it belongs to.
+type checkTarget struct {
+	NodeName    string
+	DeviceClass string
+	Volume      string
+	Claim       *corev1.PersistentVolumeClaim
+}
+
+// Reconciler reconciles LVMVolumeCheck objects for the node it runs on.
+// It checks the filesystem of the logical volume of a PersistentVolumeClaim once the volume is no longer in use,
+// or of a temporary thin snapshot of the volume, and reports the result in the status and as events
+// of the PersistentVolumeClaim.
+type Reconciler struct {
+	client.Client
+	events.EventRecorder
+	lvm.LVM
+	fsck.Fsck
+
+	// APIReader reads the PersistentVolumeClaims outside the namespace of the operator,
+	// which are not part of the cache of vg-manager.
+	APIReader client.Reader
+	NodeName  string
+	Namespace string
+}
+
+// NewReconciler returns Reconciler.
+func NewReconciler(client client.Client, apiReader client.Reader, eventRecorder events.EventRecorder, lvm lvm.LVM, fsck fsck.Fsck, nodeName, namespace string) *Reconciler {
+	return &Reconciler{
+		Client:        client,
+		EventRecorder: eventRecorder,
+		LVM:           lvm,
+		Fsck:          fsck,
+		APIReader:     apiReader,
+		NodeName:      nodeName,
+		Namespace:     namespace,
+	}
+}
+
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumechecks,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumechecks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;update;patch
+
+// Reconcile checks the filesystem of the volume requested by the LVMVolumeCheck if it is located on this node.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	check := &lvmv1alpha1.LVMVolumeCheck{}
+	if err := r.Get(ctx, req.NamespacedName, check); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !check.DeletionTimestamp.IsZero() ||
+		check.Status.Phase == lvmv1alpha1.LVMVolumeCheckCompleted ||
+		check.Status.Phase == lvmv1alpha1.LVMVolumeCheckFailed {
+		return ctrl.Result{}, nil
+	}
+	if check.Status.NodeName != "" && check.Status.NodeName != r.NodeName {
+		return ctrl.Result{}, nil
+	}
+
+	target, err := r.resolve(ctx, check)
+	if errors.Is(err, ErrCheckFailed) {
+		return ctrl.Result{}, r.fail(ctx, check, err)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// the check is handled by the vg-manager on the node of the volume
+	if target.NodeName != r.NodeName {
+		return ctrl.Result{}, nil
+	}
+
+	check.Status.NodeName = target.NodeName
+	check.Status.DeviceClass = target.DeviceClass
+	check.Status.LogicalVolume = target.Volume
+
+	requeue, err := r.reconcile(ctx, check, target)
+	if errors.Is(err, ErrCheckFailed) {
+		return ctrl.Result{}, r.fail(ctx, check, err)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: requeue}, r.updateStatus(ctx, check)
+}
+
+// resolve determines the logical volume of the PersistentVolumeClaim.
+func (r *Reconciler) resolve(ctx context.Context, check *lvmv1alpha1.LVMVolumeCheck) (*checkTarget, error) {
+	claimRef := check.Spec.PersistentVolumeClaim
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Name: claimRef.Name, Namespace: claimRef.Namespace}, pvc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: PersistentVolumeClaim %s/%s not found", ErrCheckFailed, claimRef.Namespace, claimRef.Name)
+		}
+		return nil, fmt.Errorf("failed to get PersistentVolumeClaim %s/%s: %w", claimRef.Namespace, claimRef.Name, err)
+	}
+	if pvc.Spec.VolumeName == "" {
+		return nil, fmt.Errorf("%w: PersistentVolumeClaim %s/%s is not bound", ErrCheckFailed, claimRef.Namespace, claimRef.Name)
+	}
+	if ptr.Deref(pvc.Spec.VolumeMode, corev1.PersistentVolumeFilesystem) == corev1.PersistentVolumeBlock {
+		return nil, fmt.Errorf("%w: PersistentVolumeClaim %s/%s is a block volume without filesystem",
+			ErrCheckFailed, claimRef.Namespace, claimRef.Name)
+	}
+
+	logicalVolumes := &topolvmv1.LogicalVolumeList{}
+	if err := r.List(ctx, logicalVolumes); err != nil {
+		return nil, fmt.Errorf("failed to list TopoLVM LogicalVolumes: %w", err)
+	}
+	var logicalVolume *topolvmv1.LogicalVolume
+	for i := range logicalVolumes.Items {
+		if logicalVolumes.Items[i].Spec.Name == pvc.Spec.VolumeName {
+			logicalVolume = &logicalVolumes.Items[i]
+			break
+		}
+	}
+	if logicalVolume == nil || logicalVolume.Status.VolumeID == "" {
+		return nil, fmt.Errorf("%w: PersistentVolume %s of PersistentVolumeClaim %s/%s is not provisioned by LVMS",
+			ErrCheckFailed, pvc.Spec.VolumeName, claimRef.Namespace, claimRef.Name)
+	}
+
+	return &checkTarget{
+		NodeName:    logicalVolume.Spec.NodeName,
+		DeviceClass: logicalVolume.Spec.DeviceClass,
+		Volume:      logicalVolume.Status.VolumeID,
+		Claim:       pvc,
+	}, nil
+}
+
+// reconcile runs the check on the node once the volume can be checked.
+// It returns the time after which the check has to be retried.
+func (r *Reconciler) reconcile(ctx context.Context, check *lvmv1alpha1.LVMVolumeCheck, target *checkTarget) (time.Duration, error) {
+	vgName := target.DeviceClass
+
+	paused, err := r.isPaused(ctx, vgName)
+	if err != nil {
+		return 0, err
+	}
+	if paused {
+		setInProgress(check, check.Status.Phase, ReasonPaused,
+			"waiting for the node to leave maintenance and the LVMCluster to be unpaused")
+		return pollInterval, nil
+	}
+
+	volume, err := r.findLV(ctx, vgName, target.Volume)
+	if err != nil {
+		return 0, err
+	}
+	lvAttr, err := vgmanager.ParsedLvAttr(volume.LvAttr)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse lv_attr from logical volume %s: %w", volume.Name, err)
+	}
+
+	if source(check) == lvmv1alpha1.LVMVolumeCheckSourceSnapshot {
+		if lvAttr.VolumeType != vgmanager.VolumeTypeThinVolume {
+			return 0, fmt.Errorf("%w: logical volume %s is not a thin volume, only thin volumes can be checked with a snapshot",
+				ErrCheckFailed, volume.Name)
+		}
+		return 0, r.checkSnapshot(ctx, check, target)
+	}
+
+	if lvAttr.Open == vgmanager.OpenTrue {
+		setWaitingForUnpublish(check, target)
+		return pollInterval, nil
+	}
+
+	// publishing the volume is refused by the node server until the check is completed or failed,
+	// the volume is only checked if it was not published before the check was stored.
+	r.startChecking(check, fmt.Sprintf("checking filesystem of logical volume %s", volume.Name))
+	if err := r.updateStatus(ctx, check); err != nil {
+		return 0, err
+	}
+	if volume, err = r.findLV(ctx, vgName, target.Volume); err != nil {
+		return 0, err
+	}
+	if lvAttr, err = vgmanager.ParsedLvAttr(volume.LvAttr); err != nil {
+		return 0, fmt.Errorf("could not parse lv_attr from logical volume %s: %w", volume.Name, err)
+	}
+	if lvAttr.Open == vgmanager.OpenTrue {
+		setWaitingForUnpublish(check, target)
+		return pollInterval, nil
+	}
+
+	return 0, r.run(ctx, check, target, lvm.DeviceMapperPath(vgName, volume.Name))
+}
+
+// checkSnapshot checks a temporary thin snapshot of the volume, which is deleted after the check.
+func (r *Reconciler) checkSnapshot(ctx context.Context, check *lvmv1alpha1.LVMVolumeCheck, target *checkTarget) (err error) {
+	vgName := target.DeviceClass
+	snapshotName := target.Volume + snapshotSuffix
+
+	// leftover of an interrupted check
+	if exists, err := r.LVExists(ctx, snapshotName, vgName); err != nil {
+		return fmt.Errorf("failed to check for snapshot %s of previous check: %w", snapshotName, err)
+	} else if exists {
+		if err := r.DeleteLV(ctx, snapshotName, vgName); err != nil {
+			return fmt.Errorf("failed to delete snapshot %s of previous check: %w", snapshotName, err)
+		}
+	}
+
+	r.startChecking(check, fmt.Sprintf("checking filesystem of snapshot %s of logical volume %s", snapshotName, target.Volume))
+	if err := r.updateStatus(ctx, check); err != nil {
+		return err
+	}
+
+	if err := r.CreateThinSnapshotLV(ctx, snapshotName, vgName, target.Volume); err != nil {
+		return fmt.Errorf("failed to create snapshot of logical volume %s for check: %w", target.Volume, err)
+	}
+	defer func() {
+		if deleteErr := r.DeleteLV(context.WithoutCancel(ctx), snapshotName, vgName); deleteErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to delete snapshot %s after check: %w", snapshotName, deleteErr))
+		}
+	}()
+
+	return r.run(ctx, check, target, lvm.DeviceMapperPath(vgName, snapshotName))
+}
+
+// run checks or repairs the filesystem on the device and completes the check with the result.
+func (r *Reconciler) run(ctx context.Context, check *lvmv1alpha1.LVMVolumeCheck, target *checkTarget, device string) error {
+	logger := log.FromContext(ctx).WithValues("VGName", target.DeviceClass, "LV", target.Volume, "device", device)
+
+	fsType, err := r.FilesystemType(ctx, device)
+	if err != nil {
+		return fmt.Errorf("failed to determine filesystem of logical volume %s: %w", target.Volume, err)
+	}
+	if fsType == "" {
+		return fmt.Errorf("%w: no filesystem found on logical volume %s", ErrCheckFailed, target.Volume)
+	}
+	check.Status.FilesystemType = fsType
+
+	logger.Info("checking filesystem", "fsType", fsType, "mode", mode(check))
+	var result fsck.Result
+	if mode(check) == lvmv1alpha1.LVMVolumeCheckModeRepair {
+		result, err = r.Repair(ctx, device, fsType)
+	} else {
+		result, err = r.Check(ctx, device, fsType)
+	}
+	check.Status.Output = result.Output
+	if errors.Is(err, fsck.ErrLogReplayRequired) {
+		return fmt.Errorf("%w: the log of the filesystem has to be replayed by mounting the volume before it can be repaired", ErrCheckFailed)
+	}
+	// a check is not retried, as it would run into the same error
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCheckFailed, err)
+	}
+
+	r.complete(ctx, check, target, result)
+	return nil
+}
+
+// complete marks the check as completed and reports the result on the LVMVolumeCheck and the PersistentVolumeClaim.
+func (r *Reconciler) complete(ctx context.Context, check *lvmv1alpha1.LVMVolumeCheck, target *checkTarget, result fsck.Result) {
+	claimRef := check.Spec.PersistentVolumeClaim
+	claim := fmt.Sprintf("%s/%s", claimRef.Namespace, claimRef.Name)
+
+	eventType, reason, eventReason := corev1.EventTypeNormal, ReasonClean, string(EventReasonFilesystemClean)
+	check.Status.Result = lvmv1alpha1.LVMVolumeCheckResultClean
+	msg := fmt.Sprintf("no errors found in %s filesystem of PersistentVolumeClaim %s", check.Status.FilesystemType, claim)
+	switch {
+	case result.Repaired:
+		reason, eventReason = ReasonRepaired, string(EventReasonFilesystemRepaired)
+		check.Status.Result = lvmv1alpha1.LVMVolumeCheckResultRepaired
+		msg = fmt.Sprintf("errors in %s filesystem of PersistentVolumeClaim %s were repaired", check.Status.FilesystemType, claim)
+	case result.ErrorsFound:
+		eventType, reason, eventReason = corev1.EventTypeWarning, ReasonErrorsFound, string(EventReasonErrorFilesystemErrorsFound)
+		check.Status.Result = lvmv1alpha1.LVMVolumeCheckResultErrorsFound
+		msg = fmt.Sprintf("errors found in %s filesystem of PersistentVolumeClaim %s", check.Status.FilesystemType, claim)
+	}
+
+	check.Status.Phase = lvmv1alpha1.LVMVolumeCheckCompleted
+	check.Status.CompletionTime = ptr.To(metav1.Now())
+	meta.SetStatusCondition(&check.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeChecked,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: msg,
+	})
+	log.FromContext(ctx).Info(msg, "result", check.Status.Result)
+	r.Eventf(check, nil, eventType, eventReason, "CheckFilesystem", msg)
+	r.Eventf(target.Claim, check, eventType, eventReason, "CheckFilesystem",
+		fmt.Sprintf("%s, see LVMVolumeCheck %s/%s", msg, check.GetNamespace(), check.GetName()))
+}
+
+func (r *Reconciler) startChecking(check *lvmv1alpha1.LVMVolumeCheck, msg string) {
+	check.Status.StartTime = ptr.To(metav1.Now())
+	setInProgress(check, lvmv1alpha1.LVMVolumeCheckChecking, ReasonChecking, msg)
+}
+
+// findLV returns the logical volume with the name in the volume group.
+func (r *Reconciler) findLV(ctx context.Context, vgName, lvName string) (*lvm.LogicalVolume, error) {
+	lvReport, err := r.ListLVs(ctx, vgName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list logical volumes in volume group %s: %w", vgName, err)
+	}
+	for _, item := range lvReport.Report {
+		for i := range item.Lv {
+			if item.Lv[i].Name == lvName {
+				return &item.Lv[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: logical volume %s not found in volume group %s", ErrCheckFailed, lvName, vgName)
+}
+
+// isPaused checks if the node is in maintenance or the LVMCluster controlling the volume group is paused.
+func (r *Reconciler) isPaused(ctx context.Context, vgName string) (bool, error) {
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: r.NodeName}, node); err != nil {
+		return false, fmt.Errorf("failed to get node %s: %w", r.NodeName, err)
+	}
+	if node.GetAnnotations()[constants.MaintenanceAnnotation] == "true" {
+		return true, nil
+	}
+
+	volumeGroup := &lvmv1alpha1.LVMVolumeGroup{}
+	if err := r.Get(ctx, types.NamespacedName{Name: vgName, Namespace: r.Namespace}, volumeGroup); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	owner := metav1.GetControllerOf(volumeGroup)
+	if owner == nil || owner.Kind != "LVMCluster" {
+		return false, nil
+	}
+	lvmCluster := &lvmv1alpha1.LVMCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: volumeGroup.GetNamespace()}, lvmCluster); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return lvmCluster.Spec.Paused, nil
+}
+
+func (r *Reconciler) fail(ctx context.Context, check *lvmv1alpha1.LVMVolumeCheck, err error) error {
+	r.Eventf(check, nil, corev1.EventTypeWarning, string(EventReasonErrorCheckFailed), "CheckFilesystem", err.Error())
+	check.Status.Phase = lvmv1alpha1.LVMVolumeCheckFailed
+	check.Status.CompletionTime = ptr.To(metav1.Now())
+	meta.SetStatusCondition(&check.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeChecked,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonCheckFailed,
+		Message: err.Error(),
+	})
+	return r.updateStatus(ctx, check)
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, check *lvmv1alpha1.LVMVolumeCheck) error {
+	if err := r.Status().Update(ctx, check); err != nil {
+		return fmt.Errorf("failed to update status of LVMVolumeCheck %s: %w", check.GetName(), err)
+	}
+	return nil
+}
+
+func setWaitingForUnpublish(check *lvmv1alpha1.LVMVolumeCheck, target *checkTarget) {
+	claimRef := check.Spec.PersistentVolumeClaim
+	setInProgress(check, lvmv1alpha1.LVMVolumeCheckWaitingForUnpublish, ReasonWaitingForUnpublish,
+		fmt.Sprintf("logical volume %s is in use, waiting for all pods using PersistentVolumeClaim %s/%s to be stopped",
+			target.Volume, claimRef.Namespace, claimRef.Name))
+}
+
+func setInProgress(check *lvmv1alpha1.LVMVolumeCheck, phase lvmv1alpha1.LVMVolumeCheckPhase, reason, msg string) {
+	if phase == "" {
+		phase = lvmv1alpha1.LVMVolumeCheckPending
+	}
+	check.Status.Phase = phase
+	meta.SetStatusCondition(&check.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeChecked,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: msg,
+	})
+}
+
+// mode returns the mode of the check, which defaults to Check.
+func mode(check *lvmv1alpha1.LVMVolumeCheck) lvmv1alpha1.LVMVolumeCheckMode {
+	if check.Spec.Mode == "" {
+		return lvmv1alpha1.LVMVolumeCheckModeCheck
+	}
+	return check.Spec.Mode
+}
+
+// source returns the source of the check, which defaults to Volume.
+func source(check *lvmv1alpha1.LVMVolumeCheck) lvmv1alpha1.LVMVolumeCheckSource {
+	if check.Spec.Source == "" {
+		return lvmv1alpha1.LVMVolumeCheckSourceVolume
+	}
+	return check.Spec.Source
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&lvmv1alpha1.LVMVolumeCheck{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controller.Options{SkipNameValidation: ptr.To(true)}).
+		Named("lvms_volumecheck").
+		Complete(r)
+}
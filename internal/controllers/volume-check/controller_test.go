@@ -0,0 +1,218 @@
+package volume_check
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/fsck"
+	fsckmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/fsck/mocks"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	lvmmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/events"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNode        = "test-node"
+	testNamespace   = "openshift-lvm-storage"
+	testDeviceClass = "vg1"
+	testCheck       = "test-check"
+	testVolume      = "0d2b1c3e-6a1f-4e4c-8d43-2b9a7a3c1f10"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, lvmv1alpha1.AddToScheme(scheme))
+	require.NoError(t, topolvmv1.AddToScheme(scheme))
+	return scheme
+}
+
+func testObjects(nodeName string) []client.Object {
+	return []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode}},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "app"},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pvc-1"},
+		},
+		&topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+			Spec:       topolvmv1.LogicalVolumeSpec{Name: "pvc-1", NodeName: nodeName, DeviceClass: testDeviceClass},
+			Status:     topolvmv1.LogicalVolumeStatus{VolumeID: testVolume},
+		},
+	}
+}
+
+func lvReport(attr string) *lvm.LVReport {
+	return &lvm.LVReport{Report: []lvm.LVReportItem{{Lv: []lvm.LogicalVolume{
+		{Name: testVolume, VgName: testDeviceClass, LvAttr: attr, LvSize: "10737418240"},
+	}}}}
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	device := lvm.DeviceMapperPath(testDeviceClass, testVolume)
+	snapshot := testVolume + snapshotSuffix
+	snapshotDevice := lvm.DeviceMapperPath(testDeviceClass, snapshot)
+
+	tests := []struct {
+		name           string
+		nodeName       string
+		spec           lvmv1alpha1.LVMVolumeCheckSpec
+		setupMocks     func(*lvmmocks.MockLVM, *fsckmocks.MockFsck)
+		expectedPhase  lvmv1alpha1.LVMVolumeCheckPhase
+		expectedResult lvmv1alpha1.LVMVolumeCheckResult
+		expectedEvents int
+	}{
+		{
+			name:     "volume on other node is ignored",
+			nodeName: "other-node",
+		},
+		{
+			name:     "waits for volume to be unpublished",
+			nodeName: testNode,
+			setupMocks: func(mockLVM *lvmmocks.MockLVM, _ *fsckmocks.MockFsck) {
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("Vwi-aotz--"), nil)
+			},
+			expectedPhase: lvmv1alpha1.LVMVolumeCheckWaitingForUnpublish,
+		},
+		{
+			name:     "checks unused volume",
+			nodeName: testNode,
+			setupMocks: func(mockLVM *lvmmocks.MockLVM, mockFsck *fsckmocks.MockFsck) {
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("-wi-a-----"), nil).Twice()
+				mockFsck.EXPECT().FilesystemType(mock.Anything, device).Return("xfs", nil)
+				mockFsck.EXPECT().Check(mock.Anything, device, "xfs").Return(fsck.Result{Output: "done"}, nil)
+			},
+			expectedPhase:  lvmv1alpha1.LVMVolumeCheckCompleted,
+			expectedResult: lvmv1alpha1.LVMVolumeCheckResultClean,
+			expectedEvents: 2,
+		},
+		{
+			name:     "waits if volume was published before checking",
+			nodeName: testNode,
+			setupMocks: func(mockLVM *lvmmocks.MockLVM, _ *fsckmocks.MockFsck) {
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("-wi-a-----"), nil).Once()
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("-wi-ao----"), nil).Once()
+			},
+			expectedPhase: lvmv1alpha1.LVMVolumeCheckWaitingForUnpublish,
+		},
+		{
+			name:     "repairs unused volume",
+			nodeName: testNode,
+			spec:     lvmv1alpha1.LVMVolumeCheckSpec{Mode: lvmv1alpha1.LVMVolumeCheckModeRepair},
+			setupMocks: func(mockLVM *lvmmocks.MockLVM, mockFsck *fsckmocks.MockFsck) {
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("-wi-a-----"), nil).Twice()
+				mockFsck.EXPECT().FilesystemType(mock.Anything, device).Return("ext4", nil)
+				mockFsck.EXPECT().Repair(mock.Anything, device, "ext4").Return(fsck.Result{Repaired: true}, nil)
+			},
+			expectedPhase:  lvmv1alpha1.LVMVolumeCheckCompleted,
+			expectedResult: lvmv1alpha1.LVMVolumeCheckResultRepaired,
+			expectedEvents: 2,
+		},
+		{
+			name:     "checks snapshot of volume in use",
+			nodeName: testNode,
+			spec:     lvmv1alpha1.LVMVolumeCheckSpec{Source: lvmv1alpha1.LVMVolumeCheckSourceSnapshot},
+			setupMocks: func(mockLVM *lvmmocks.MockLVM, mockFsck *fsckmocks.MockFsck) {
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("Vwi-aotz--"), nil)
+				mockLVM.EXPECT().LVExists(mock.Anything, snapshot, testDeviceClass).Return(false, nil)
+				mockLVM.EXPECT().CreateThinSnapshotLV(mock.Anything, snapshot, testDeviceClass, testVolume).Return(nil)
+				mockLVM.EXPECT().DeleteLV(mock.Anything, snapshot, testDeviceClass).Return(nil)
+				mockFsck.EXPECT().FilesystemType(mock.Anything, snapshotDevice).Return("xfs", nil)
+				mockFsck.EXPECT().Check(mock.Anything, snapshotDevice, "xfs").Return(fsck.Result{ErrorsFound: true}, nil)
+			},
+			expectedPhase:  lvmv1alpha1.LVMVolumeCheckCompleted,
+			expectedResult: lvmv1alpha1.LVMVolumeCheckResultErrorsFound,
+			expectedEvents: 2,
+		},
+		{
+			name:     "snapshot of thick volume fails",
+			nodeName: testNode,
+			spec:     lvmv1alpha1.LVMVolumeCheckSpec{Source: lvmv1alpha1.LVMVolumeCheckSourceSnapshot},
+			setupMocks: func(mockLVM *lvmmocks.MockLVM, _ *fsckmocks.MockFsck) {
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("-wi-ao----"), nil)
+			},
+			expectedPhase:  lvmv1alpha1.LVMVolumeCheckFailed,
+			expectedEvents: 1,
+		},
+		{
+			name:     "volume without filesystem fails",
+			nodeName: testNode,
+			setupMocks: func(mockLVM *lvmmocks.MockLVM, mockFsck *fsckmocks.MockFsck) {
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("-wi-a-----"), nil).Twice()
+				mockFsck.EXPECT().FilesystemType(mock.Anything, device).Return("", nil)
+			},
+			expectedPhase:  lvmv1alpha1.LVMVolumeCheckFailed,
+			expectedEvents: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			spec := tt.spec
+			spec.PersistentVolumeClaim = lvmv1alpha1.VolumeCheckClaimReference{Name: "data", Namespace: "app"}
+			check := &lvmv1alpha1.LVMVolumeCheck{
+				ObjectMeta: metav1.ObjectMeta{Name: testCheck, Namespace: testNamespace},
+				Spec:       spec,
+			}
+
+			clnt := fake.NewClientBuilder().
+				WithScheme(newScheme(t)).
+				WithObjects(append(testObjects(tt.nodeName), check)...).
+				WithStatusSubresource(&lvmv1alpha1.LVMVolumeCheck{}).
+				Build()
+
+			mockLVM := lvmmocks.NewMockLVM(t)
+			mockFsck := fsckmocks.NewMockFsck(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockLVM, mockFsck)
+			}
+			recorder := events.NewFakeRecorder(10)
+
+			r := NewReconciler(clnt, clnt, recorder, mockLVM, mockFsck, testNode, testNamespace)
+			_, err := r.Reconcile(ctx, controllerruntime.Request{NamespacedName: client.ObjectKeyFromObject(check)})
+			assert.NoError(t, err)
+
+			assert.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(check), check))
+			assert.Equal(t, tt.expectedPhase, check.Status.Phase)
+			assert.Equal(t, tt.expectedResult, check.Status.Result)
+			assert.Len(t, recorder.Events, tt.expectedEvents)
+
+			switch tt.expectedPhase {
+			case "":
+				assert.Empty(t, check.Status.NodeName)
+			case lvmv1alpha1.LVMVolumeCheckCompleted:
+				assert.NotNil(t, check.Status.StartTime)
+				assert.NotNil(t, check.Status.CompletionTime)
+				assert.NotEmpty(t, check.Status.FilesystemType)
+				if tt.expectedResult == lvmv1alpha1.LVMVolumeCheckResultErrorsFound {
+					assert.True(t, strings.HasPrefix(<-recorder.Events, corev1.EventTypeWarning))
+				}
+			default:
+				assert.Equal(t, testNode, check.Status.NodeName)
+				assert.Equal(t, testVolume, check.Status.LogicalVolume)
+			}
+		})
+	}
+}
+
+func TestReconciler_SetupWithManager(t *testing.T) {
+	mgr, err := controllerruntime.NewManager(&rest.Config{}, controllerruntime.Options{Scheme: newScheme(t)})
+	assert.NoError(t, err)
+	r := NewReconciler(fake.NewClientBuilder().Build(), nil, events.NewFakeRecorder(1), nil, nil, testNode, testNamespace)
+	assert.NoError(t, r.SetupWithManager(mgr))
+}
@@ -0,0 +1,677 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume_import
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lsblk"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	ControllerName = "lvms-volume-import"
+
+	// pollInterval is the interval in which an import is retried while it waits for a released
+	// PersistentVolume to be deleted.
+	pollInterval = 10 * time.Second
+
+	// VolumeImportLabel is set on the LogicalVolume and PersistentVolume created for an LVMVolumeImport.
+	// Its value is the name of the LVMVolumeImport.
+	VolumeImportLabel = "lvm.topolvm.io/volume-import"
+
+	// provisionedByAnnotation marks the PersistentVolume as provisioned by TopoLVM so that the
+	// external-provisioner handles it on deletion when the reclaim policy is Delete.
+	provisionedByAnnotation = "pv.kubernetes.io/provisioned-by"
+)
+
+type (
+	EventReasonInfo  string
+	EventReasonError string
+)
+
+const (
+	EventReasonErrorImportFailed EventReasonError = "ImportFailed"
+	EventReasonVolumeImported    EventReasonInfo  = "VolumeImported"
+)
+
+const (
+	ReasonLogicalVolumeNotSelected = "LogicalVolumeNotSelected"
+	ReasonImportInProgress         = "ImportInProgress"
+	ReasonImportFailed             = "ImportFailed"
+	ReasonVolumeImported           = "VolumeImported"
+)
+
+// ErrImportFailed is returned for imports that can not succeed without a change to the LVMVolumeImport
+// or to the node, so they are reported in the status instead of being retried.
+var ErrImportFailed = errors.New("volume import failed")
+
+// Reconciler reconciles LVMVolumeImport objects for the node it runs on.
+// It adopts a logical volume that is not used by a bound PersistentVolume into a TopoLVM LogicalVolume
+// and a static PersistentVolume that is pre-bound to the requested PersistentVolumeClaim. Logical volumes
+// that are not referenced by any LogicalVolume get a new one, while the LogicalVolume of a retained volume
+// is kept and only its released PersistentVolume is replaced.
+type Reconciler struct {
+	client.Client
+	events.EventRecorder
+	lvm.LVM
+	lsblk.LSBLK
+	NodeName string
+}
+
+// NewReconciler returns Reconciler.
+func NewReconciler(client client.Client, eventRecorder events.EventRecorder, lvm lvm.LVM, lsblk lsblk.LSBLK, nodeName string) *Reconciler {
+	return &Reconciler{
+		Client:        client,
+		EventRecorder: eventRecorder,
+		LVM:           lvm,
+		LSBLK:         lsblk,
+		NodeName:      nodeName,
+	}
+}
+
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumeimports,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumeimports/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;update;patch
+
+// Reconcile imports the logical volume requested by the LVMVolumeImport if it is located on this node.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	volumeImport := &lvmv1alpha1.LVMVolumeImport{}
+	if err := r.Get(ctx, req.NamespacedName, volumeImport); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if volumeImport.Spec.NodeName != r.NodeName || !volumeImport.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if volumeImport.Status.Phase == lvmv1alpha1.LVMVolumeImportImported {
+		logger.V(1).Info("volume already imported", "LogicalVolume", volumeImport.Status.LogicalVolume)
+		return ctrl.Result{}, nil
+	}
+
+	requeue, err := r.reconcile(ctx, volumeImport)
+	if errors.Is(err, ErrImportFailed) {
+		r.Eventf(volumeImport, nil, corev1.EventTypeWarning, string(EventReasonErrorImportFailed), "ImportVolume", err.Error())
+		setImportFailed(volumeImport, err)
+		return ctrl.Result{}, r.updateStatus(ctx, volumeImport)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: requeue}, r.updateStatus(ctx, volumeImport)
+}
+
+// reconcile imports the logical volume. It returns the time after which the import has to be retried.
+func (r *Reconciler) reconcile(ctx context.Context, volumeImport *lvmv1alpha1.LVMVolumeImport) (time.Duration, error) {
+	logger := log.FromContext(ctx).WithValues("VGName", volumeImport.Spec.DeviceClass)
+	vgName := volumeImport.Spec.DeviceClass
+
+	vgs, err := r.ListVGs(ctx, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list volume groups: %w", err)
+	}
+	if !containsVG(vgs, vgName) {
+		return 0, fmt.Errorf("%w: volume group %s does not exist on node %s (or was not tagged with %q)",
+			ErrImportFailed, vgName, r.NodeName, lvm.DefaultTag)
+	}
+
+	lvReport, err := r.ListLVs(ctx, vgName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list logical volumes in volume group %s: %w", vgName, err)
+	}
+
+	referenced, err := r.referencedLogicalVolumes(ctx, vgName)
+	if err != nil {
+		return 0, err
+	}
+	pvs, err := r.persistentVolumesByVolumeID(ctx, volumeImport)
+	if err != nil {
+		return 0, err
+	}
+
+	blockDevices, err := r.ListBlockDevices(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list block devices: %w", err)
+	}
+	fsTypes := filesystemsByDeviceName(blockDevices)
+
+	importable, err := importableLogicalVolumes(lvReport, referenced, pvs, fsTypes)
+	if err != nil {
+		return 0, err
+	}
+	volumeImport.Status.ImportableLogicalVolumes = importable
+
+	if volumeImport.Spec.LogicalVolumeName == "" {
+		volumeImport.Status.Phase = lvmv1alpha1.LVMVolumeImportPending
+		meta.SetStatusCondition(&volumeImport.Status.Conditions, metav1.Condition{
+			Type:    lvmv1alpha1.VolumeImported,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonLogicalVolumeNotSelected,
+			Message: "no logical volume selected, see status.importableLogicalVolumes for logical volumes that can be imported",
+		})
+		return 0, nil
+	}
+
+	logicalVolume, err := r.getOrCreateLogicalVolume(ctx, volumeImport, importable)
+	if err != nil {
+		return 0, err
+	}
+	created := logicalVolume.GetLabels()[VolumeImportLabel] == volumeImport.GetName()
+
+	volumeImport.Status.Phase = lvmv1alpha1.LVMVolumeImportImporting
+	volumeImport.Status.LogicalVolume = logicalVolume.GetName()
+	volumeImport.Status.Size = ptr.To(logicalVolume.Spec.Size)
+	meta.SetStatusCondition(&volumeImport.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeImported,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonImportInProgress,
+		Message: fmt.Sprintf("importing logical volume %s as LogicalVolume %s", volumeImport.Spec.LogicalVolumeName, logicalVolume.GetName()),
+	})
+
+	// the filesystem has to be determined before the adoption as it renames the logical volume
+	fsType, err := r.filesystemType(ctx, volumeImport, logicalVolume, fsTypes)
+	if err != nil {
+		return 0, err
+	}
+
+	// the logical volume of a retained LogicalVolume already carries the name TopoLVM expects
+	if created {
+		if err := r.adoptLogicalVolume(ctx, volumeImport, logicalVolume); err != nil {
+			return 0, err
+		}
+	}
+
+	pv, err := r.getOrCreatePersistentVolume(ctx, volumeImport, logicalVolume, fsType)
+	if err != nil {
+		return 0, err
+	}
+	if pv == nil {
+		meta.SetStatusCondition(&volumeImport.Status.Conditions, metav1.Condition{
+			Type:    lvmv1alpha1.VolumeImported,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonImportInProgress,
+			Message: fmt.Sprintf("waiting for the released PersistentVolume %s to be deleted", logicalVolume.Spec.Name),
+		})
+		return pollInterval, nil
+	}
+
+	volumeImport.Status.Phase = lvmv1alpha1.LVMVolumeImportImported
+	volumeImport.Status.PersistentVolume = pv.GetName()
+	volumeImport.Status.FilesystemType = fsType
+	msg := fmt.Sprintf("logical volume %s was imported as PersistentVolume %s", volumeImport.Spec.LogicalVolumeName, pv.GetName())
+	meta.SetStatusCondition(&volumeImport.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeImported,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonVolumeImported,
+		Message: msg,
+	})
+	logger.Info(msg)
+	r.Eventf(volumeImport, nil, corev1.EventTypeNormal, string(EventReasonVolumeImported), "ImportVolume", msg)
+
+	return 0, nil
+}
+
+// referencedLogicalVolumes returns the TopoLVM LogicalVolumes in the volume group on this node
+// by the names of the logical volumes they own.
+func (r *Reconciler) referencedLogicalVolumes(ctx context.Context, deviceClass string) (map[string]*topolvmv1.LogicalVolume, error) {
+	logicalVolumes := &topolvmv1.LogicalVolumeList{}
+	if err := r.List(ctx, logicalVolumes); err != nil {
+		return nil, fmt.Errorf("failed to list TopoLVM LogicalVolumes: %w", err)
+	}
+
+	referenced := make(map[string]*topolvmv1.LogicalVolume)
+	for i := range logicalVolumes.Items {
+		logicalVolume := &logicalVolumes.Items[i]
+		if logicalVolume.Spec.NodeName != r.NodeName || logicalVolume.Spec.DeviceClass != deviceClass {
+			continue
+		}
+		if logicalVolume.Status.VolumeID != "" {
+			referenced[logicalVolume.Status.VolumeID] = logicalVolume
+		}
+		// TopoLVM creates the logical volume with the UID of the LogicalVolume as its name,
+		// so a LogicalVolume without VolumeID might still own it.
+		referenced[string(logicalVolume.GetUID())] = logicalVolume
+	}
+	return referenced, nil
+}
+
+// persistentVolumesByVolumeID returns the PersistentVolumes of TopoLVM by their volume handle. PersistentVolumes
+// created by the import itself are skipped, so that a retried import still finds its logical volume importable.
+func (r *Reconciler) persistentVolumesByVolumeID(ctx context.Context, volumeImport *lvmv1alpha1.LVMVolumeImport) (map[string]*corev1.PersistentVolume, error) {
+	pvList := &corev1.PersistentVolumeList{}
+	if err := r.List(ctx, pvList); err != nil {
+		return nil, fmt.Errorf("failed to list PersistentVolumes: %w", err)
+	}
+
+	pvs := make(map[string]*corev1.PersistentVolume)
+	for i := range pvList.Items {
+		pv := &pvList.Items[i]
+		if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != constants.TopolvmCSIDriverName ||
+			pv.GetLabels()[VolumeImportLabel] == volumeImport.GetName() {
+			continue
+		}
+		pvs[pv.Spec.CSI.VolumeHandle] = pv
+	}
+	return pvs, nil
+}
+
+// getOrCreateLogicalVolume returns the LogicalVolume created for this import, or the LogicalVolume that still
+// references the requested logical volume if it was retained. Otherwise the LogicalVolume is created if the
+// requested logical volume is still importable.
+func (r *Reconciler) getOrCreateLogicalVolume(
+	ctx context.Context,
+	volumeImport *lvmv1alpha1.LVMVolumeImport,
+	importable []lvmv1alpha1.ImportableLogicalVolume,
+) (*topolvmv1.LogicalVolume, error) {
+	logger := log.FromContext(ctx)
+
+	logicalVolume := &topolvmv1.LogicalVolume{}
+	err := r.Get(ctx, client.ObjectKey{Name: volumeImport.GetName()}, logicalVolume)
+	if err == nil && logicalVolume.GetLabels()[VolumeImportLabel] == volumeImport.GetName() {
+		return logicalVolume, nil
+	}
+	if client.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to get LogicalVolume %s: %w", volumeImport.GetName(), err)
+	}
+	exists := err == nil
+
+	var lv *lvmv1alpha1.ImportableLogicalVolume
+	for i := range importable {
+		if importable[i].Name == volumeImport.Spec.LogicalVolumeName {
+			lv = &importable[i]
+			break
+		}
+	}
+	if lv == nil {
+		return nil, fmt.Errorf("%w: logical volume %s does not exist in volume group %s on node %s "+
+			"or is used by a bound PersistentVolume", ErrImportFailed,
+			volumeImport.Spec.LogicalVolumeName, volumeImport.Spec.DeviceClass, r.NodeName)
+	}
+
+	if lv.LogicalVolume != "" {
+		retained := &topolvmv1.LogicalVolume{}
+		if err := r.Get(ctx, client.ObjectKey{Name: lv.LogicalVolume}, retained); err != nil {
+			return nil, fmt.Errorf("failed to get LogicalVolume %s: %w", lv.LogicalVolume, err)
+		}
+		return retained, nil
+	}
+	if exists {
+		return nil, fmt.Errorf("%w: LogicalVolume %s already exists and was not created by this import",
+			ErrImportFailed, logicalVolume.GetName())
+	}
+
+	// the pending deletion annotation keeps TopoLVM from provisioning a new logical volume
+	// for the LogicalVolume until it was adopted
+	logicalVolume = &topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        volumeImport.GetName(),
+			Labels:      map[string]string{VolumeImportLabel: volumeImport.GetName()},
+			Annotations: map[string]string{topolvm.GetLVPendingDeletionKey(): "true"},
+		},
+		Spec: topolvmv1.LogicalVolumeSpec{
+			Name:        volumeImport.GetName(),
+			NodeName:    r.NodeName,
+			DeviceClass: volumeImport.Spec.DeviceClass,
+			Size:        lv.Size,
+		},
+	}
+	if err := r.Create(ctx, logicalVolume); err != nil {
+		return nil, fmt.Errorf("failed to create LogicalVolume %s: %w", logicalVolume.GetName(), err)
+	}
+	logger.Info("created LogicalVolume for import", "LogicalVolume", logicalVolume.GetName())
+
+	return logicalVolume, nil
+}
+
+// adoptLogicalVolume renames the logical volume to the UID of the LogicalVolume, sets the LogicalVolume status
+// and hands the LogicalVolume over to TopoLVM by removing the pending deletion annotation.
+// The rename matches the naming TopoLVM uses for the logical volumes it provisions.
+func (r *Reconciler) adoptLogicalVolume(ctx context.Context, volumeImport *lvmv1alpha1.LVMVolumeImport, logicalVolume *topolvmv1.LogicalVolume) error {
+	logger := log.FromContext(ctx).WithValues("LogicalVolume", logicalVolume.GetName())
+	vgName := volumeImport.Spec.DeviceClass
+	volumeID := string(logicalVolume.GetUID())
+
+	renamed, err := r.LVExists(ctx, volumeID, vgName)
+	if err != nil {
+		return fmt.Errorf("failed to check existence of logical volume %s in volume group %s: %w", volumeID, vgName, err)
+	}
+	if !renamed {
+		exists, err := r.LVExists(ctx, volumeImport.Spec.LogicalVolumeName, vgName)
+		if err != nil {
+			return fmt.Errorf("failed to check existence of logical volume %s in volume group %s: %w",
+				volumeImport.Spec.LogicalVolumeName, vgName, err)
+		}
+		if !exists {
+			return fmt.Errorf("%w: logical volume %s no longer exists in volume group %s",
+				ErrImportFailed, volumeImport.Spec.LogicalVolumeName, vgName)
+		}
+		if err := r.RenameLV(ctx, volumeImport.Spec.LogicalVolumeName, vgName, volumeID); err != nil {
+			return fmt.Errorf("failed to rename logical volume for LogicalVolume %s: %w", logicalVolume.GetName(), err)
+		}
+		logger.Info("renamed logical volume to LogicalVolume UID", "from", volumeImport.Spec.LogicalVolumeName, "to", volumeID)
+	}
+
+	if logicalVolume.Status.VolumeID != volumeID {
+		if logicalVolume.Status.VolumeID != "" {
+			return fmt.Errorf("%w: LogicalVolume %s already references volume %s",
+				ErrImportFailed, logicalVolume.GetName(), logicalVolume.Status.VolumeID)
+		}
+		logicalVolume.Status.VolumeID = volumeID
+		logicalVolume.Status.Code = codes.OK
+		logicalVolume.Status.Message = ""
+		logicalVolume.Status.CurrentSize = ptr.To(logicalVolume.Spec.Size)
+		if err := r.Status().Update(ctx, logicalVolume); err != nil {
+			return fmt.Errorf("failed to update status of LogicalVolume %s: %w", logicalVolume.GetName(), err)
+		}
+	}
+
+	if _, pending := logicalVolume.GetAnnotations()[topolvm.GetLVPendingDeletionKey()]; pending {
+		patch := client.MergeFrom(logicalVolume.DeepCopy())
+		delete(logicalVolume.Annotations, topolvm.GetLVPendingDeletionKey())
+		if err := r.Patch(ctx, logicalVolume, patch); err != nil {
+			return fmt.Errorf("failed to remove pending deletion annotation from LogicalVolume %s: %w", logicalVolume.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// filesystemType determines the filesystem of the imported volume. An explicitly requested filesystem wins over
+// the detected one, which in turn wins over the filesystem configured in the device class StorageClass.
+func (r *Reconciler) filesystemType(
+	ctx context.Context,
+	volumeImport *lvmv1alpha1.LVMVolumeImport,
+	logicalVolume *topolvmv1.LogicalVolume,
+	fsTypes map[string]string,
+) (string, error) {
+	if volumeImport.Spec.VolumeMode == corev1.PersistentVolumeBlock {
+		return "", nil
+	}
+	if volumeImport.Spec.FilesystemType != "" {
+		return string(volumeImport.Spec.FilesystemType), nil
+	}
+	// the logical volume is either still present under its original name or was already renamed in a previous attempt
+	for _, lvName := range []string{volumeImport.Spec.LogicalVolumeName, string(logicalVolume.GetUID())} {
+		if fsType := fsTypes[lvm.DeviceMapperPath(volumeImport.Spec.DeviceClass, lvName)]; fsType != "" {
+			return fsType, nil
+		}
+	}
+
+	sc := &storagev1.StorageClass{}
+	scName := constants.StorageClassPrefix + volumeImport.Spec.DeviceClass
+	if err := r.Get(ctx, client.ObjectKey{Name: scName}, sc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("%w: no filesystem could be detected for logical volume %s and StorageClass %s does not exist, "+
+				"set the filesystem explicitly", ErrImportFailed, volumeImport.Spec.LogicalVolumeName, scName)
+		}
+		return "", fmt.Errorf("failed to get StorageClass %s: %w", scName, err)
+	}
+	return sc.Parameters[constants.FsTypeKey], nil
+}
+
+// getOrCreatePersistentVolume returns the PersistentVolume created for this import or creates it. The PersistentVolume
+// is named after the LogicalVolume spec, so the released PersistentVolume of a retained LogicalVolume is deleted
+// first. No PersistentVolume is returned while the released PersistentVolume is being deleted.
+func (r *Reconciler) getOrCreatePersistentVolume(
+	ctx context.Context,
+	volumeImport *lvmv1alpha1.LVMVolumeImport,
+	logicalVolume *topolvmv1.LogicalVolume,
+	fsType string,
+) (*corev1.PersistentVolume, error) {
+	logger := log.FromContext(ctx)
+
+	pv := &corev1.PersistentVolume{}
+	err := r.Get(ctx, client.ObjectKey{Name: logicalVolume.Spec.Name}, pv)
+	if err == nil {
+		if pv.GetLabels()[VolumeImportLabel] == volumeImport.GetName() {
+			return pv, nil
+		}
+		if pv.Spec.CSI == nil || pv.Spec.CSI.VolumeHandle != logicalVolume.Status.VolumeID || !isRetainedPersistentVolume(pv) {
+			return nil, fmt.Errorf("%w: PersistentVolume %s already exists and was not created by this import",
+				ErrImportFailed, pv.GetName())
+		}
+		// the Retain reclaim policy keeps the logical volume when the PersistentVolume is deleted
+		if pv.DeletionTimestamp.IsZero() {
+			if err := r.Delete(ctx, pv); client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed to delete released PersistentVolume %s: %w", pv.GetName(), err)
+			}
+			logger.Info("deleted released PersistentVolume for import", "PersistentVolume", pv.GetName())
+		}
+		return nil, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get PersistentVolume %s: %w", logicalVolume.Spec.Name, err)
+	}
+
+	pv = persistentVolumeForImport(volumeImport, logicalVolume, fsType)
+	if err := r.Create(ctx, pv); err != nil {
+		return nil, fmt.Errorf("failed to create PersistentVolume %s: %w", pv.GetName(), err)
+	}
+	logger.Info("created PersistentVolume for import", "PersistentVolume", pv.GetName())
+
+	return pv, nil
+}
+
+func persistentVolumeForImport(volumeImport *lvmv1alpha1.LVMVolumeImport, logicalVolume *topolvmv1.LogicalVolume, fsType string) *corev1.PersistentVolume {
+	volumeMode := volumeImport.Spec.VolumeMode
+	if volumeMode == "" {
+		volumeMode = corev1.PersistentVolumeFilesystem
+	}
+	reclaimPolicy := volumeImport.Spec.ReclaimPolicy
+	if reclaimPolicy == "" {
+		reclaimPolicy = corev1.PersistentVolumeReclaimRetain
+	}
+
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        logicalVolume.Spec.Name,
+			Labels:      map[string]string{VolumeImportLabel: volumeImport.GetName()},
+			Annotations: map[string]string{provisionedByAnnotation: constants.TopolvmCSIDriverName},
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: logicalVolume.Spec.Size,
+			},
+			AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			PersistentVolumeReclaimPolicy: reclaimPolicy,
+			StorageClassName:              constants.StorageClassPrefix + volumeImport.Spec.DeviceClass,
+			VolumeMode:                    &volumeMode,
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:       constants.TopolvmCSIDriverName,
+					VolumeHandle: logicalVolume.Status.VolumeID,
+					FSType:       fsType,
+					VolumeAttributes: map[string]string{
+						constants.DeviceClassKey: volumeImport.Spec.DeviceClass,
+					},
+				},
+			},
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      topolvm.GetTopologyNodeKey(),
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{logicalVolume.Spec.NodeName},
+						}},
+					}},
+				},
+			},
+		},
+	}
+
+	if ref := volumeImport.Spec.ClaimRef; ref != nil {
+		pv.Spec.ClaimRef = &corev1.ObjectReference{
+			Kind:       "PersistentVolumeClaim",
+			APIVersion: "v1",
+			Name:       ref.Name,
+			Namespace:  ref.Namespace,
+		}
+	}
+
+	return pv
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, volumeImport *lvmv1alpha1.LVMVolumeImport) error {
+	if err := r.Status().Update(ctx, volumeImport); err != nil {
+		return fmt.Errorf("failed to update status of LVMVolumeImport %s: %w", volumeImport.GetName(), err)
+	}
+	return nil
+}
+
+func setImportFailed(volumeImport *lvmv1alpha1.LVMVolumeImport, err error) {
+	volumeImport.Status.Phase = lvmv1alpha1.LVMVolumeImportFailed
+	meta.SetStatusCondition(&volumeImport.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeImported,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonImportFailed,
+		Message: err.Error(),
+	})
+}
+
+// importableLogicalVolumes returns all logical volumes of the report that hold data and are either not referenced
+// by a LogicalVolume or retained by their LogicalVolume, sorted by name.
+func importableLogicalVolumes(
+	report *lvm.LVReport,
+	referenced map[string]*topolvmv1.LogicalVolume,
+	pvs map[string]*corev1.PersistentVolume,
+	fsTypes map[string]string,
+) ([]lvmv1alpha1.ImportableLogicalVolume, error) {
+	var importable []lvmv1alpha1.ImportableLogicalVolume
+	for _, item := range report.Report {
+		for _, lv := range item.Lv {
+			var logicalVolumeName, pvName string
+			if logicalVolume, ok := referenced[lv.Name]; ok {
+				pv := pvs[logicalVolume.Status.VolumeID]
+				if !isRetained(logicalVolume, pv) {
+					continue
+				}
+				logicalVolumeName = logicalVolume.GetName()
+				if pv != nil {
+					pvName = pv.GetName()
+				}
+			}
+			lvAttr, err := vgmanager.ParsedLvAttr(lv.LvAttr)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse lv_attr from logical volume %s: %w", lv.Name, err)
+			}
+			switch lvAttr.VolumeType {
+			case vgmanager.VolumeTypeThinPool, vgmanager.VolumeTypeThinPoolData, vgmanager.VolumeTypeThinPoolMetadata,
+				vgmanager.VolumeTypeMirrorOrRAIDImage, vgmanager.VolumeTypeMirrorOrRAIDImageOutOfSync,
+				vgmanager.VolumeTypeMirrorLogDevice, vgmanager.VolumeTypePVMove:
+				continue
+			}
+			size, err := strconv.ParseInt(lv.LvSize, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse lv_size from logical volume %s: %w", lv.Name, err)
+			}
+			importable = append(importable, lvmv1alpha1.ImportableLogicalVolume{
+				Name:             lv.Name,
+				Size:             *resource.NewQuantity(size, resource.BinarySI),
+				FilesystemType:   fsTypes[lvm.DeviceMapperPath(lv.VgName, lv.Name)],
+				LogicalVolume:    logicalVolumeName,
+				PersistentVolume: pvName,
+			})
+		}
+	}
+	sort.Slice(importable, func(i, j int) bool {
+		return importable[i].Name < importable[j].Name
+	})
+	return importable, nil
+}
+
+// isRetained returns whether the provisioned LogicalVolume is no longer used by its PersistentVolume, which is the
+// case once the PersistentVolumeClaim of a volume with the Retain reclaim policy was deleted or the PersistentVolume
+// itself was removed. PersistentVolumes that are not released are bound or pre-bound to a claim and keep the volume.
+func isRetained(logicalVolume *topolvmv1.LogicalVolume, pv *corev1.PersistentVolume) bool {
+	if !logicalVolume.DeletionTimestamp.IsZero() || logicalVolume.Status.VolumeID == "" {
+		return false
+	}
+	if _, pending := logicalVolume.GetAnnotations()[topolvm.GetLVPendingDeletionKey()]; pending {
+		return false
+	}
+	return pv == nil || isRetainedPersistentVolume(pv)
+}
+
+// isRetainedPersistentVolume returns whether the PersistentVolume was released by its claim and keeps its volume.
+func isRetainedPersistentVolume(pv *corev1.PersistentVolume) bool {
+	return pv.Spec.PersistentVolumeReclaimPolicy == corev1.PersistentVolumeReclaimRetain &&
+		(pv.Status.Phase == corev1.VolumeReleased || pv.Status.Phase == corev1.VolumeFailed)
+}
+
+// filesystemsByDeviceName maps the name of every block device to its filesystem.
+func filesystemsByDeviceName(blockDevices []lsblk.BlockDevice) map[string]string {
+	fsTypes := make(map[string]string)
+	for _, device := range lsblk.FlattenedBlockDevices(blockDevices) {
+		if device.FSType != "" {
+			fsTypes[device.Name] = device.FSType
+		}
+	}
+	return fsTypes
+}
+
+func containsVG(vgs []lvm.VolumeGroup, name string) bool {
+	for _, vg := range vgs {
+		if vg.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&lvmv1alpha1.LVMVolumeImport{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controller.Options{SkipNameValidation: ptr.To(true)}).
+		Named("lvms_volumeimport").
+		Complete(r)
+}
@@ -0,0 +1,325 @@
+package volume_import
+
+import (
+	"context"
+	"testing"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lsblk"
+	lsblkmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lsblk/mocks"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	lvmmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/events"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const (
+	testNode        = "test-node"
+	testDeviceClass = "vg1"
+	testImport      = "test-import"
+	testUID         = "1b0c5b8c-2d34-4f5e-9c2f-0d4c6f1f3c7a"
+	testRetainedUID = "6a4e2f1d-8c3b-4d5a-b7e9-2f1c0d9e8b7a"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, lvmv1alpha1.AddToScheme(scheme))
+	require.NoError(t, topolvmv1.AddToScheme(scheme))
+	return scheme
+}
+
+func testLVReport() *lvm.LVReport {
+	return &lvm.LVReport{Report: []lvm.LVReportItem{{Lv: []lvm.LogicalVolume{
+		{Name: "thin-pool-1", VgName: testDeviceClass, LvAttr: "twi-a-tz--", LvSize: "10737418240"},
+		{Name: "retained", VgName: testDeviceClass, PoolName: "thin-pool-1", LvAttr: "Vwi-a-tz--", LvSize: "1073741824"},
+		{Name: "in-use", VgName: testDeviceClass, PoolName: "thin-pool-1", LvAttr: "Vwi-aotz--", LvSize: "1073741824"},
+	}}}}
+}
+
+func testBlockDevices() []lsblk.BlockDevice {
+	return []lsblk.BlockDevice{{
+		Name: "/dev/sda", KName: "/dev/sda", Type: "disk", FSType: "LVM2_member",
+		Children: []lsblk.BlockDevice{
+			{Name: "/dev/mapper/vg1-retained", KName: "/dev/dm-3", Type: "lvm", FSType: "ext4"},
+		},
+	}}
+}
+
+func topolvmPersistentVolume(name, volumeID string, phase corev1.PersistentVolumePhase) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+			ClaimRef:                      &corev1.ObjectReference{Name: "data", Namespace: "deleted", UID: "claim-uid"},
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: constants.TopolvmCSIDriverName, VolumeHandle: volumeID},
+			},
+		},
+		Status: corev1.PersistentVolumeStatus{Phase: phase},
+	}
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	inUse := &topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-in-use"},
+		Spec:       topolvmv1.LogicalVolumeSpec{Name: "pvc-in-use", NodeName: testNode, DeviceClass: testDeviceClass},
+		Status:     topolvmv1.LogicalVolumeStatus{VolumeID: "in-use"},
+	}
+	inUsePV := topolvmPersistentVolume("pvc-in-use", "in-use", corev1.VolumeBound)
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.StorageClassPrefix + testDeviceClass},
+		Parameters: map[string]string{constants.FsTypeKey: "xfs"},
+	}
+
+	tests := []struct {
+		name          string
+		spec          lvmv1alpha1.LVMVolumeImportSpec
+		setupMocks    func(*lvmmocks.MockLVM, *lsblkmocks.MockLSBLK)
+		expectedPhase lvmv1alpha1.LVMVolumeImportPhase
+		expectedFS    string
+	}{
+		{
+			name: "import on other node is ignored",
+			spec: lvmv1alpha1.LVMVolumeImportSpec{NodeName: "other-node", DeviceClass: testDeviceClass},
+		},
+		{
+			name: "no logical volume selected lists importable volumes",
+			spec: lvmv1alpha1.LVMVolumeImportSpec{NodeName: testNode, DeviceClass: testDeviceClass},
+			setupMocks: func(mockLVM *lvmmocks.MockLVM, mockLSBLK *lsblkmocks.MockLSBLK) {
+				mockLVM.EXPECT().ListVGs(mock.Anything, true).Return([]lvm.VolumeGroup{{Name: testDeviceClass}}, nil)
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(testLVReport(), nil)
+				mockLSBLK.EXPECT().ListBlockDevices(mock.Anything).Return(testBlockDevices(), nil)
+			},
+			expectedPhase: lvmv1alpha1.LVMVolumeImportPending,
+		},
+		{
+			name: "volume group does not exist",
+			spec: lvmv1alpha1.LVMVolumeImportSpec{NodeName: testNode, DeviceClass: testDeviceClass, LogicalVolumeName: "retained"},
+			setupMocks: func(mockLVM *lvmmocks.MockLVM, _ *lsblkmocks.MockLSBLK) {
+				mockLVM.EXPECT().ListVGs(mock.Anything, true).Return(nil, nil)
+			},
+			expectedPhase: lvmv1alpha1.LVMVolumeImportFailed,
+		},
+		{
+			name: "logical volume of a bound PersistentVolume",
+			spec: lvmv1alpha1.LVMVolumeImportSpec{NodeName: testNode, DeviceClass: testDeviceClass, LogicalVolumeName: "in-use"},
+			setupMocks: func(mockLVM *lvmmocks.MockLVM, mockLSBLK *lsblkmocks.MockLSBLK) {
+				mockLVM.EXPECT().ListVGs(mock.Anything, true).Return([]lvm.VolumeGroup{{Name: testDeviceClass}}, nil)
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(testLVReport(), nil)
+				mockLSBLK.EXPECT().ListBlockDevices(mock.Anything).Return(testBlockDevices(), nil)
+			},
+			expectedPhase: lvmv1alpha1.LVMVolumeImportFailed,
+		},
+		{
+			name: "logical volume is imported with detected filesystem",
+			spec: lvmv1alpha1.LVMVolumeImportSpec{
+				NodeName:          testNode,
+				DeviceClass:       testDeviceClass,
+				LogicalVolumeName: "retained",
+				ClaimRef:          &lvmv1alpha1.VolumeImportClaimReference{Name: "data", Namespace: "default"},
+			},
+			setupMocks: func(mockLVM *lvmmocks.MockLVM, mockLSBLK *lsblkmocks.MockLSBLK) {
+				mockLVM.EXPECT().ListVGs(mock.Anything, true).Return([]lvm.VolumeGroup{{Name: testDeviceClass}}, nil)
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(testLVReport(), nil)
+				mockLSBLK.EXPECT().ListBlockDevices(mock.Anything).Return(testBlockDevices(), nil)
+				mockLVM.EXPECT().LVExists(mock.Anything, testUID, testDeviceClass).Return(false, nil)
+				mockLVM.EXPECT().LVExists(mock.Anything, "retained", testDeviceClass).Return(true, nil)
+				mockLVM.EXPECT().RenameLV(mock.Anything, "retained", testDeviceClass, testUID).Return(nil)
+			},
+			expectedPhase: lvmv1alpha1.LVMVolumeImportImported,
+			expectedFS:    "ext4",
+		},
+		{
+			name: "block volume is imported without filesystem",
+			spec: lvmv1alpha1.LVMVolumeImportSpec{
+				NodeName:          testNode,
+				DeviceClass:       testDeviceClass,
+				LogicalVolumeName: "retained",
+				VolumeMode:        corev1.PersistentVolumeBlock,
+			},
+			setupMocks: func(mockLVM *lvmmocks.MockLVM, mockLSBLK *lsblkmocks.MockLSBLK) {
+				mockLVM.EXPECT().ListVGs(mock.Anything, true).Return([]lvm.VolumeGroup{{Name: testDeviceClass}}, nil)
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(testLVReport(), nil)
+				mockLSBLK.EXPECT().ListBlockDevices(mock.Anything).Return(testBlockDevices(), nil)
+				mockLVM.EXPECT().LVExists(mock.Anything, testUID, testDeviceClass).Return(true, nil)
+			},
+			expectedPhase: lvmv1alpha1.LVMVolumeImportImported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			volumeImport := &lvmv1alpha1.LVMVolumeImport{
+				ObjectMeta: metav1.ObjectMeta{Name: testImport},
+				Spec:       tt.spec,
+			}
+
+			clnt := fake.NewClientBuilder().
+				WithScheme(newScheme(t)).
+				WithObjects(volumeImport, inUse.DeepCopy(), inUsePV.DeepCopy(), storageClass).
+				WithStatusSubresource(&lvmv1alpha1.LVMVolumeImport{}, &topolvmv1.LogicalVolume{}).
+				WithInterceptorFuncs(interceptor.Funcs{Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if _, ok := obj.(*topolvmv1.LogicalVolume); ok {
+						obj.SetUID(testUID)
+					}
+					return client.Create(ctx, obj, opts...)
+				}}).
+				Build()
+
+			mockLVM := lvmmocks.NewMockLVM(t)
+			mockLSBLK := lsblkmocks.NewMockLSBLK(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockLVM, mockLSBLK)
+			}
+			recorder := events.NewFakeRecorder(10)
+
+			r := NewReconciler(clnt, recorder, mockLVM, mockLSBLK, testNode)
+			_, err := r.Reconcile(ctx, controllerruntime.Request{NamespacedName: types.NamespacedName{Name: testImport}})
+			assert.NoError(t, err)
+
+			assert.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(volumeImport), volumeImport))
+			assert.Equal(t, tt.expectedPhase, volumeImport.Status.Phase)
+
+			switch tt.expectedPhase {
+			case lvmv1alpha1.LVMVolumeImportPending:
+				assert.Equal(t, []lvmv1alpha1.ImportableLogicalVolume{
+					{Name: "retained", Size: resource.MustParse("1Gi"), FilesystemType: "ext4"},
+				}, volumeImport.Status.ImportableLogicalVolumes)
+			case lvmv1alpha1.LVMVolumeImportFailed:
+				assert.NotEmpty(t, recorder.Events)
+			case lvmv1alpha1.LVMVolumeImportImported:
+				logicalVolume := &topolvmv1.LogicalVolume{}
+				assert.NoError(t, clnt.Get(ctx, client.ObjectKey{Name: testImport}, logicalVolume))
+				assert.Equal(t, testUID, logicalVolume.Status.VolumeID)
+				assert.NotContains(t, logicalVolume.GetAnnotations(), topolvm.GetLVPendingDeletionKey())
+				assert.Equal(t, resource.MustParse("1Gi"), logicalVolume.Spec.Size)
+
+				pv := &corev1.PersistentVolume{}
+				assert.NoError(t, clnt.Get(ctx, client.ObjectKey{Name: testImport}, pv))
+				assert.Equal(t, testUID, pv.Spec.CSI.VolumeHandle)
+				assert.Equal(t, tt.expectedFS, pv.Spec.CSI.FSType)
+				assert.Equal(t, corev1.PersistentVolumeReclaimRetain, pv.Spec.PersistentVolumeReclaimPolicy)
+				assert.Equal(t, constants.StorageClassPrefix+testDeviceClass, pv.Spec.StorageClassName)
+				assert.Equal(t, []string{testNode}, pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Values)
+				if tt.spec.ClaimRef != nil {
+					assert.Equal(t, tt.spec.ClaimRef.Name, pv.Spec.ClaimRef.Name)
+					assert.Equal(t, tt.spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Namespace)
+				}
+				assert.Equal(t, pv.GetName(), volumeImport.Status.PersistentVolume)
+				assert.Equal(t, tt.expectedFS, volumeImport.Status.FilesystemType)
+			default:
+				assert.Empty(t, volumeImport.Status.Phase)
+			}
+		})
+	}
+}
+
+func TestReconciler_Reconcile_RetainedLogicalVolume(t *testing.T) {
+	ctx := context.Background()
+	lvReport := &lvm.LVReport{Report: []lvm.LVReportItem{{Lv: []lvm.LogicalVolume{
+		{Name: "thin-pool-1", VgName: testDeviceClass, LvAttr: "twi-a-tz--", LvSize: "10737418240"},
+		{Name: testRetainedUID, VgName: testDeviceClass, PoolName: "thin-pool-1", LvAttr: "Vwi-a-tz--", LvSize: "2147483648"},
+	}}}}
+	blockDevices := []lsblk.BlockDevice{{
+		Name: "/dev/sda", KName: "/dev/sda", Type: "disk", FSType: "LVM2_member",
+		Children: []lsblk.BlockDevice{
+			{Name: lvm.DeviceMapperPath(testDeviceClass, testRetainedUID), KName: "/dev/dm-4", Type: "lvm", FSType: "xfs"},
+		},
+	}}
+
+	retained := &topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-retained", UID: testRetainedUID},
+		Spec: topolvmv1.LogicalVolumeSpec{
+			Name: "pvc-retained", NodeName: testNode, DeviceClass: testDeviceClass, Size: resource.MustParse("2Gi"),
+		},
+		Status: topolvmv1.LogicalVolumeStatus{VolumeID: testRetainedUID},
+	}
+	released := topolvmPersistentVolume("pvc-retained", testRetainedUID, corev1.VolumeReleased)
+	volumeImport := &lvmv1alpha1.LVMVolumeImport{
+		ObjectMeta: metav1.ObjectMeta{Name: testImport},
+		Spec: lvmv1alpha1.LVMVolumeImportSpec{
+			NodeName:          testNode,
+			DeviceClass:       testDeviceClass,
+			LogicalVolumeName: testRetainedUID,
+			ClaimRef:          &lvmv1alpha1.VolumeImportClaimReference{Name: "data", Namespace: "restored"},
+		},
+	}
+
+	clnt := fake.NewClientBuilder().
+		WithScheme(newScheme(t)).
+		WithObjects(volumeImport, retained, released).
+		WithStatusSubresource(&lvmv1alpha1.LVMVolumeImport{}, &topolvmv1.LogicalVolume{}).
+		Build()
+
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLSBLK := lsblkmocks.NewMockLSBLK(t)
+	mockLVM.EXPECT().ListVGs(mock.Anything, true).Return([]lvm.VolumeGroup{{Name: testDeviceClass}}, nil).Times(2)
+	mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport, nil).Times(2)
+	mockLSBLK.EXPECT().ListBlockDevices(mock.Anything).Return(blockDevices, nil).Times(2)
+
+	r := NewReconciler(clnt, events.NewFakeRecorder(10), mockLVM, mockLSBLK, testNode)
+	req := controllerruntime.Request{NamespacedName: types.NamespacedName{Name: testImport}}
+
+	// the released PersistentVolume is deleted before it is replaced
+	result, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, pollInterval, result.RequeueAfter)
+	require.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(volumeImport), volumeImport))
+	assert.Equal(t, lvmv1alpha1.LVMVolumeImportImporting, volumeImport.Status.Phase)
+	assert.Equal(t, []lvmv1alpha1.ImportableLogicalVolume{{
+		Name:             testRetainedUID,
+		Size:             resource.MustParse("2Gi"),
+		FilesystemType:   "xfs",
+		LogicalVolume:    "pvc-retained",
+		PersistentVolume: "pvc-retained",
+	}}, volumeImport.Status.ImportableLogicalVolumes)
+
+	result, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	require.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(volumeImport), volumeImport))
+	assert.Equal(t, lvmv1alpha1.LVMVolumeImportImported, volumeImport.Status.Phase)
+	assert.Equal(t, "pvc-retained", volumeImport.Status.LogicalVolume)
+	assert.Equal(t, "pvc-retained", volumeImport.Status.PersistentVolume)
+
+	// the LogicalVolume is kept without creating a new one
+	logicalVolumes := &topolvmv1.LogicalVolumeList{}
+	require.NoError(t, clnt.List(ctx, logicalVolumes))
+	require.Len(t, logicalVolumes.Items, 1)
+	assert.Equal(t, testRetainedUID, logicalVolumes.Items[0].Status.VolumeID)
+
+	pv := &corev1.PersistentVolume{}
+	require.NoError(t, clnt.Get(ctx, client.ObjectKey{Name: "pvc-retained"}, pv))
+	assert.Equal(t, testImport, pv.GetLabels()[VolumeImportLabel])
+	assert.Equal(t, testRetainedUID, pv.Spec.CSI.VolumeHandle)
+	assert.Equal(t, "xfs", pv.Spec.CSI.FSType)
+	assert.Equal(t, resource.MustParse("2Gi"), pv.Spec.Capacity[corev1.ResourceStorage])
+	assert.Equal(t, "restored", pv.Spec.ClaimRef.Namespace)
+	assert.Empty(t, pv.Spec.ClaimRef.UID)
+}
+
+func TestReconciler_SetupWithManager(t *testing.T) {
+	mgr, err := controllerruntime.NewManager(&rest.Config{}, controllerruntime.Options{Scheme: newScheme(t)})
+	assert.NoError(t, err)
+	r := NewReconciler(fake.NewClientBuilder().Build(), events.NewFakeRecorder(1), nil, nil, testNode)
+	assert.NoError(t, r.SetupWithManager(mgr))
+}
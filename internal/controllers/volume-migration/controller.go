@@ -0,0 +1,1225 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume_migration
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	ControllerName = "lvms-volume-migration"
+
+	// pollInterval is the interval in which a migration is checked while a copy is running
+	// or while it waits for the other node.
+	pollInterval = 10 * time.Second
+
+	// catchUpInterval is the interval in which the changes of a volume that is still in use
+	// are copied to the target volume.
+	catchUpInterval = time.Minute
+
+	// MigrationLabel is set to the name of the migration on the LogicalVolume and the PersistentVolume
+	// created for the target volume.
+	MigrationLabel = "lvm.topolvm.io/volume-migration"
+
+	// MigrationFinalizer is set by the vg-manager of the target node to remove the target volume
+	// if the migration fails or is deleted before the PersistentVolumeClaim was switched to it.
+	MigrationFinalizer = "lvm.topolvm.io/volume-migration"
+
+	secretTokenKey  = "token"
+	secretCACertKey = "ca.crt"
+
+	// snapshotSuffix is appended to the name of the volume for the snapshot a copy is read from
+	// while the volume is in use.
+	snapshotSuffix = "-migration"
+
+	// snapshotReservePercent is the size of the copy-on-write space of the snapshot of a thick volume
+	// relative to the size of the volume.
+	snapshotReservePercent = 20
+
+	// provisionedByAnnotation marks the PersistentVolume as provisioned by TopoLVM so that the
+	// external-provisioner handles it on deletion when the reclaim policy is Delete.
+	provisionedByAnnotation = "pv.kubernetes.io/provisioned-by"
+)
+
+type (
+	EventReasonInfo  string
+	EventReasonError string
+)
+
+const (
+	EventReasonErrorMigrationFailed EventReasonError = "MigrationFailed"
+	EventReasonErrorTransferFailed  EventReasonError = "TransferFailed"
+	EventReasonSourceChanged        EventReasonInfo  = "SourceChanged"
+	EventReasonVolumeMigrated       EventReasonInfo  = "VolumeMigrated"
+)
+
+const (
+	ReasonPreparing           = "Preparing"
+	ReasonSyncing             = "Syncing"
+	ReasonTransferFailed      = "TransferFailed"
+	ReasonWaitingForUnpublish = "WaitingForUnpublish"
+	ReasonPaused              = "Paused"
+	ReasonSwitching           = "Switching"
+	ReasonVerifying           = "Verifying"
+	ReasonMigrationFailed     = "MigrationFailed"
+	ReasonVolumeMigrated      = "VolumeMigrated"
+)
+
+// ErrMigrationFailed is returned for migrations that can not succeed without a change to the LVMVolumeMigration
+// or the PersistentVolumeClaim, so they are reported in the status instead of being retried.
+var ErrMigrationFailed = errors.New("volume migration failed")
+
+// claimAnnotationPrefixes are the prefixes of annotations set by Kubernetes during binding,
+// which are not carried over to the recreated PersistentVolumeClaim.
+var claimAnnotationPrefixes = []string{"pv.kubernetes.io/", "volume.kubernetes.io/", "volume.beta.kubernetes.io/"}
+
+// transfer is a copy of a volume to the target volume running in the background.
+type transfer struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	read   atomic.Int64
+	phase  lvmv1alpha1.LVMVolumeMigrationPhase
+
+	result PassResult
+	err    error
+}
+
+// Reconciler reconciles LVMVolumeMigration objects for the node it runs on.
+// The vg-manager of the node holding the volume copies the volume to the vg-manager of the target node,
+// which creates the new volume, receives the data and switches the PersistentVolumeClaim to it.
+// If the volume only moves to another device class of its node, the vg-manager of the node handles both sides
+// and copies the volume directly.
+type Reconciler struct {
+	client.Client
+	events.EventRecorder
+	lvm.LVM
+
+	// APIReader reads the PersistentVolumeClaims outside the namespace of the operator, which are not part of
+	// the cache of vg-manager, and the Secrets of the migrations, which are not cached.
+	APIReader client.Reader
+	Receiver  *Receiver
+	Send      SendFunc
+	Copy      CopyFunc
+	NodeName  string
+	Namespace string
+	// TargetAddress is the address the Receiver of this node is reachable on from the other nodes.
+	TargetAddress string
+
+	mu        sync.Mutex
+	transfers map[types.NamespacedName]*transfer
+	checksums map[types.NamespacedName][]Checksum
+}
+
+// NewReconciler returns Reconciler.
+func NewReconciler(
+	client client.Client,
+	apiReader client.Reader,
+	eventRecorder events.EventRecorder,
+	lvm lvm.LVM,
+	receiver *Receiver,
+	nodeName, namespace, targetAddress string,
+) *Reconciler {
+	return &Reconciler{
+		Client:        client,
+		EventRecorder: eventRecorder,
+		LVM:           lvm,
+		APIReader:     apiReader,
+		Receiver:      receiver,
+		Send:          Send,
+		Copy:          Copy,
+		NodeName:      nodeName,
+		Namespace:     namespace,
+		TargetAddress: targetAddress,
+		transfers:     make(map[types.NamespacedName]*transfer),
+		checksums:     make(map[types.NamespacedName][]Checksum),
+	}
+}
+
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumemigrations,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumemigrations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumemigrations/finalizers,verbs=update
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumegroupnodestatuses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;create;patch;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;create;update;delete
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;update;patch
+
+// Reconcile advances the part of the LVMVolumeMigration that is handled by this node.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	migration := &lvmv1alpha1.LVMVolumeMigration{}
+	if err := r.Get(ctx, req.NamespacedName, migration); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.stopTransfer(req.NamespacedName)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !migration.DeletionTimestamp.IsZero() {
+		r.stopTransfer(req.NamespacedName)
+		return ctrl.Result{}, r.finalize(ctx, migration)
+	}
+	switch migration.Status.Phase {
+	case lvmv1alpha1.LVMVolumeMigrationCompleted:
+		r.stopTransfer(req.NamespacedName)
+		return ctrl.Result{}, nil
+	case lvmv1alpha1.LVMVolumeMigrationFailed:
+		r.stopTransfer(req.NamespacedName)
+		return ctrl.Result{}, r.finalize(ctx, migration)
+	}
+
+	status := migration.Status.DeepCopy()
+	var requeue time.Duration
+	var err error
+	switch {
+	case migration.Status.SourceNodeName == "":
+		requeue, err = r.resolve(ctx, migration)
+	case isLocal(migration) && migration.Status.SourceNodeName == r.NodeName:
+		if requeue, err = r.reconcileTarget(ctx, migration); err == nil && isSyncing(migration) {
+			requeue, err = r.reconcileSource(ctx, migration)
+		}
+	case migration.Status.TargetNodeName == r.NodeName:
+		requeue, err = r.reconcileTarget(ctx, migration)
+	case migration.Status.SourceNodeName == r.NodeName:
+		requeue, err = r.reconcileSource(ctx, migration)
+	default:
+		return ctrl.Result{}, nil
+	}
+	if errors.Is(err, ErrMigrationFailed) {
+		return ctrl.Result{}, r.fail(ctx, migration, err)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !equality.Semantic.DeepEqual(status, &migration.Status) {
+		if err := r.updateStatus(ctx, migration); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+// resolve determines the volume of the PersistentVolumeClaim. It is handled by the vg-manager
+// of the node that holds the volume, which becomes the source of the migration.
+func (r *Reconciler) resolve(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration) (time.Duration, error) {
+	claimRef := migration.Spec.PersistentVolumeClaim
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Name: claimRef.Name, Namespace: claimRef.Namespace}, pvc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, fmt.Errorf("%w: PersistentVolumeClaim %s/%s not found", ErrMigrationFailed, claimRef.Namespace, claimRef.Name)
+		}
+		return 0, fmt.Errorf("failed to get PersistentVolumeClaim %s/%s: %w", claimRef.Namespace, claimRef.Name, err)
+	}
+	if pvc.Spec.VolumeName == "" {
+		return 0, fmt.Errorf("%w: PersistentVolumeClaim %s/%s is not bound", ErrMigrationFailed, claimRef.Namespace, claimRef.Name)
+	}
+
+	logicalVolumes := &topolvmv1.LogicalVolumeList{}
+	if err := r.List(ctx, logicalVolumes); err != nil {
+		return 0, fmt.Errorf("failed to list TopoLVM LogicalVolumes: %w", err)
+	}
+	logicalVolume := findLogicalVolume(logicalVolumes, pvc.Spec.VolumeName)
+	if logicalVolume == nil || logicalVolume.Status.VolumeID == "" {
+		return 0, fmt.Errorf("%w: PersistentVolume %s of PersistentVolumeClaim %s/%s is not provisioned by LVMS",
+			ErrMigrationFailed, pvc.Spec.VolumeName, claimRef.Namespace, claimRef.Name)
+	}
+	// the migration is handled by the vg-manager on the node of the volume
+	if logicalVolume.Spec.NodeName != r.NodeName {
+		return 0, nil
+	}
+
+	vgName := logicalVolume.Spec.DeviceClass
+	targetNodeName := migration.Spec.TargetNodeName
+	if targetNodeName == "" {
+		targetNodeName = r.NodeName
+	}
+	targetDeviceClass := migration.Spec.TargetDeviceClass
+	if targetDeviceClass == "" {
+		targetDeviceClass = vgName
+	}
+	if targetNodeName == r.NodeName && targetDeviceClass == vgName {
+		return 0, fmt.Errorf("%w: the volume of PersistentVolumeClaim %s/%s is already located in device class %s on node %s",
+			ErrMigrationFailed, claimRef.Namespace, claimRef.Name, vgName, r.NodeName)
+	}
+	// snapshots and clones are local to the node of their source and would keep the source volume in place
+	for _, lv := range logicalVolumes.Items {
+		if lv.Spec.Source == logicalVolume.GetName() {
+			return 0, fmt.Errorf("%w: the volume of PersistentVolumeClaim %s/%s still has the snapshot or clone %s, "+
+				"delete its VolumeSnapshots before the migration", ErrMigrationFailed, claimRef.Namespace, claimRef.Name, lv.Spec.Name)
+		}
+	}
+	if err := r.verifyTargetNode(ctx, targetNodeName, targetDeviceClass); err != nil {
+		return 0, err
+	}
+	if targetDeviceClass != vgName {
+		// the recreated PersistentVolumeClaim uses the StorageClass of the target device class
+		scName := constants.StorageClassPrefix + targetDeviceClass
+		if err := r.Get(ctx, client.ObjectKey{Name: scName}, &storagev1.StorageClass{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				return 0, fmt.Errorf("%w: StorageClass %s of device class %s not found", ErrMigrationFailed, scName, targetDeviceClass)
+			}
+			return 0, fmt.Errorf("failed to get StorageClass %s: %w", scName, err)
+		}
+	}
+
+	lvReport, err := r.ListLVs(ctx, vgName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list logical volumes in volume group %s: %w", vgName, err)
+	}
+	volume := findLV(lvReport, logicalVolume.Status.VolumeID)
+	if volume == nil {
+		return 0, fmt.Errorf("%w: logical volume %s not found in volume group %s", ErrMigrationFailed, logicalVolume.Status.VolumeID, vgName)
+	}
+	size, err := strconv.ParseInt(volume.LvSize, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse lv_size from logical volume %s: %w", volume.Name, err)
+	}
+
+	migration.Status.SourceNodeName = r.NodeName
+	migration.Status.DeviceClass = vgName
+	migration.Status.TargetNodeName = targetNodeName
+	migration.Status.TargetDeviceClass = targetDeviceClass
+	migration.Status.SourcePersistentVolumeName = pvc.Spec.VolumeName
+	migration.Status.SourceLogicalVolume = volume.Name
+	migration.Status.SizeBytes = size
+	setInProgress(migration, lvmv1alpha1.LVMVolumeMigrationPreparing, ReasonPreparing,
+		fmt.Sprintf("waiting for the volume to be created in %s", target(migration)))
+	log.FromContext(ctx).Info("resolved volume for migration", "LV", volume.Name, "VGName", vgName,
+		"targetNode", targetNodeName, "targetDeviceClass", targetDeviceClass)
+
+	return pollInterval, nil
+}
+
+// verifyTargetNode checks that the target node exists and has a volume group for the device class.
+func (r *Reconciler) verifyTargetNode(ctx context.Context, nodeName, deviceClass string) error {
+	if err := r.Get(ctx, types.NamespacedName{Name: nodeName}, &corev1.Node{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: target node %s not found", ErrMigrationFailed, nodeName)
+		}
+		return fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+
+	nodeStatus := &lvmv1alpha1.LVMVolumeGroupNodeStatus{}
+	if err := r.Get(ctx, types.NamespacedName{Name: nodeName, Namespace: r.Namespace}, nodeStatus); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to get LVMVolumeGroupNodeStatus of node %s: %w", nodeName, err)
+	}
+	if vgStatus := nodeStatus.VolumeGroupStatus(deviceClass); vgStatus != nil && vgStatus.Status == lvmv1alpha1.VGStatusReady {
+		return nil
+	}
+	return fmt.Errorf("%w: target node %s has no ready volume group for device class %s", ErrMigrationFailed, nodeName, deviceClass)
+}
+
+// reconcileTarget creates the target volume and accepts the copies of the source node for it
+// until the source node hands the migration over to switch the PersistentVolumeClaim.
+func (r *Reconciler) reconcileTarget(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration) (time.Duration, error) {
+	switch migration.Status.Phase {
+	case lvmv1alpha1.LVMVolumeMigrationPreparing, lvmv1alpha1.LVMVolumeMigrationInitialSync,
+		lvmv1alpha1.LVMVolumeMigrationCatchingUp, lvmv1alpha1.LVMVolumeMigrationFinalSync,
+		lvmv1alpha1.LVMVolumeMigrationVerifying:
+		return r.prepareTarget(ctx, migration)
+	case lvmv1alpha1.LVMVolumeMigrationSwitching:
+		return r.switchClaim(ctx, migration)
+	}
+	return pollInterval, nil
+}
+
+func (r *Reconciler) prepareTarget(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration) (time.Duration, error) {
+	if controllerutil.AddFinalizer(migration, MigrationFinalizer) {
+		if err := r.Update(ctx, migration); err != nil {
+			return 0, fmt.Errorf("failed to add finalizer to LVMVolumeMigration %s: %w", migration.GetName(), err)
+		}
+	}
+
+	logicalVolume, err := r.getOrCreateTargetLogicalVolume(ctx, migration)
+	if err != nil {
+		return 0, err
+	}
+	if logicalVolume.Status.Code != codes.OK {
+		return 0, fmt.Errorf("%w: logical volume could not be created on node %s: %s",
+			ErrMigrationFailed, r.NodeName, logicalVolume.Status.Message)
+	}
+	if logicalVolume.Status.VolumeID == "" {
+		setInProgress(migration, lvmv1alpha1.LVMVolumeMigrationPreparing, ReasonPreparing,
+			fmt.Sprintf("waiting for LogicalVolume %s to be created on node %s", logicalVolume.GetName(), r.NodeName))
+		return pollInterval, nil
+	}
+
+	// copies on the same node are written directly to the target volume
+	if !isLocal(migration) {
+		token, err := r.ensureSecret(ctx, migration)
+		if err != nil {
+			return 0, err
+		}
+		r.Receiver.Register(string(migration.GetUID()), token, lvm.DeviceMapperPath(migration.Status.TargetDeviceClass, logicalVolume.Status.VolumeID))
+		migration.Status.TargetAddress = r.TargetAddress
+	}
+
+	migration.Status.TargetLogicalVolumeName = logicalVolume.GetName()
+	migration.Status.TargetLogicalVolume = logicalVolume.Status.VolumeID
+	if migration.Status.Phase == lvmv1alpha1.LVMVolumeMigrationPreparing {
+		setInProgress(migration, lvmv1alpha1.LVMVolumeMigrationInitialSync, ReasonSyncing,
+			fmt.Sprintf("waiting for node %s to copy the volume", migration.Status.SourceNodeName))
+	}
+	return pollInterval, nil
+}
+
+func (r *Reconciler) getOrCreateTargetLogicalVolume(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration) (*topolvmv1.LogicalVolume, error) {
+	name := targetLogicalVolumeName(migration)
+	logicalVolume := &topolvmv1.LogicalVolume{}
+	err := r.Get(ctx, client.ObjectKey{Name: name}, logicalVolume)
+	if err == nil {
+		if logicalVolume.GetLabels()[MigrationLabel] != migration.GetName() {
+			return nil, fmt.Errorf("%w: LogicalVolume %s already exists and was not created by this migration", ErrMigrationFailed, name)
+		}
+		return logicalVolume, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get LogicalVolume %s: %w", name, err)
+	}
+
+	// within the same device class, the volume is created with the same lvcreate options as the source volume
+	var lvcreateOptionClass string
+	if migration.Status.TargetDeviceClass == migration.Status.DeviceClass {
+		logicalVolumes := &topolvmv1.LogicalVolumeList{}
+		if err := r.List(ctx, logicalVolumes); err != nil {
+			return nil, fmt.Errorf("failed to list TopoLVM LogicalVolumes: %w", err)
+		}
+		if source := findLogicalVolume(logicalVolumes, migration.Status.SourcePersistentVolumeName); source != nil {
+			lvcreateOptionClass = source.Spec.LvcreateOptionClass
+		}
+	}
+
+	logicalVolume = &topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{MigrationLabel: migration.GetName()},
+		},
+		Spec: topolvmv1.LogicalVolumeSpec{
+			Name:                name,
+			NodeName:            r.NodeName,
+			Size:                *resource.NewQuantity(migration.Status.SizeBytes, resource.BinarySI),
+			DeviceClass:         migration.Status.TargetDeviceClass,
+			LvcreateOptionClass: lvcreateOptionClass,
+		},
+	}
+	if err := r.Create(ctx, logicalVolume); err != nil {
+		return nil, fmt.Errorf("failed to create LogicalVolume %s: %w", name, err)
+	}
+	log.FromContext(ctx).Info("created LogicalVolume for migration", "LogicalVolume", name)
+	return logicalVolume, nil
+}
+
+// ensureSecret returns the token that authenticates the source node. The Secret also contains the certificate
+// of the Receiver of this node, which changes whenever vg-manager restarts.
+func (r *Reconciler) ensureSecret(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration) (string, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Name: secretName(migration), Namespace: migration.GetNamespace()}
+	err := r.APIReader.Get(ctx, key, secret)
+	if apierrors.IsNotFound(err) {
+		token := make([]byte, 32)
+		if _, err := rand.Read(token); err != nil {
+			return "", fmt.Errorf("failed to generate token for migration: %w", err)
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Data: map[string][]byte{
+				secretTokenKey:  []byte(hex.EncodeToString(token)),
+				secretCACertKey: r.Receiver.CACert(),
+			},
+		}
+		if err := controllerutil.SetControllerReference(migration, secret, r.Scheme()); err != nil {
+			return "", fmt.Errorf("failed to set controller reference on Secret %s: %w", key.Name, err)
+		}
+		if err := r.Create(ctx, secret); err != nil {
+			return "", fmt.Errorf("failed to create Secret %s: %w", key.Name, err)
+		}
+		return string(secret.Data[secretTokenKey]), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get Secret %s: %w", key.Name, err)
+	}
+
+	if !bytes.Equal(secret.Data[secretCACertKey], r.Receiver.CACert()) {
+		secret.Data[secretCACertKey] = r.Receiver.CACert()
+		if err := r.Update(ctx, secret); err != nil {
+			return "", fmt.Errorf("failed to update Secret %s: %w", key.Name, err)
+		}
+	}
+	return string(secret.Data[secretTokenKey]), nil
+}
+
+// reconcileSource copies the volume to the target volume. The first copy is read from a snapshot of the volume,
+// so the volume can stay in use. While the volume is in use, the changes are copied from new snapshots
+// periodically. Once the volume is no longer in use, the last changes are copied from the volume itself
+// and the migration is handed over to the target node. After the PersistentVolumeClaim was deleted,
+// the volume is copied once more to verify that it did not change before the source volume is removed.
+func (r *Reconciler) reconcileSource(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration) (time.Duration, error) {
+	if !isSyncing(migration) {
+		// waiting for the target node
+		return pollInterval, nil
+	}
+
+	key := client.ObjectKeyFromObject(migration)
+	if t := r.transfer(key); t != nil {
+		select {
+		case <-t.done:
+			r.removeTransfer(key)
+			return r.passCompleted(ctx, migration, t)
+		default:
+			migration.Status.Progress = progress(t.read.Load(), migration.Status.SizeBytes)
+			return pollInterval, nil
+		}
+	}
+	// waiting for the target volume to be created
+	if migration.Status.TargetLogicalVolume == "" {
+		return pollInterval, nil
+	}
+
+	paused, err := r.isPaused(ctx, migration.Status.DeviceClass)
+	if err != nil {
+		return 0, err
+	}
+	if paused {
+		setInProgress(migration, migration.Status.Phase, ReasonPaused,
+			"waiting for the node to leave maintenance and the LVMCluster to be unpaused")
+		return pollInterval, nil
+	}
+
+	lvReport, volume, open, err := r.sourceVolume(ctx, migration)
+	if err != nil {
+		return 0, err
+	}
+
+	switch migration.Status.Phase {
+	case lvmv1alpha1.LVMVolumeMigrationCatchingUp:
+		if !open {
+			setInProgress(migration, lvmv1alpha1.LVMVolumeMigrationFinalSync, ReasonSyncing,
+				fmt.Sprintf("copying the last changes of logical volume %s to %s", volume.Name, target(migration)))
+			return r.startPass(ctx, migration, lvReport, volume, false)
+		}
+		if migration.Status.LastPassTime != nil && time.Since(migration.Status.LastPassTime.Time) < catchUpInterval {
+			return pollInterval, nil
+		}
+	case lvmv1alpha1.LVMVolumeMigrationFinalSync:
+		if open {
+			setWaitingForUnpublish(migration)
+			return pollInterval, nil
+		}
+		return r.startPass(ctx, migration, lvReport, volume, false)
+	case lvmv1alpha1.LVMVolumeMigrationVerifying:
+		if open {
+			setInProgress(migration, lvmv1alpha1.LVMVolumeMigrationVerifying, ReasonVerifying,
+				fmt.Sprintf("waiting for logical volume %s to be closed", volume.Name))
+			return pollInterval, nil
+		}
+		return r.startPass(ctx, migration, lvReport, volume, false)
+	}
+	return r.startPass(ctx, migration, lvReport, volume, true)
+}
+
+// sourceVolume returns the logical volume of the migration and whether it is in use.
+func (r *Reconciler) sourceVolume(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration) (*lvm.LVReport, *lvm.LogicalVolume, bool, error) {
+	vgName := migration.Status.DeviceClass
+	lvReport, err := r.ListLVs(ctx, vgName)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to list logical volumes in volume group %s: %w", vgName, err)
+	}
+	volume := findLV(lvReport, migration.Status.SourceLogicalVolume)
+	if volume == nil {
+		return nil, nil, false, fmt.Errorf("%w: logical volume %s not found in volume group %s",
+			ErrMigrationFailed, migration.Status.SourceLogicalVolume, vgName)
+	}
+	lvAttr, err := vgmanager.ParsedLvAttr(volume.LvAttr)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("could not parse lv_attr from logical volume %s: %w", volume.Name, err)
+	}
+	return lvReport, volume, lvAttr.Open == vgmanager.OpenTrue, nil
+}
+
+// startPass starts a copy of the volume to the target volume in the background. If fromSnapshot is set,
+// the copy is read from a snapshot of the volume that is removed once the copy completed.
+func (r *Reconciler) startPass(
+	ctx context.Context,
+	migration *lvmv1alpha1.LVMVolumeMigration,
+	lvReport *lvm.LVReport,
+	volume *lvm.LogicalVolume,
+	fromSnapshot bool,
+) (time.Duration, error) {
+	logger := log.FromContext(ctx).WithValues("LV", volume.Name, "VGName", migration.Status.DeviceClass)
+	vgName := migration.Status.DeviceClass
+
+	var dst Destination
+	if !isLocal(migration) {
+		var err error
+		if dst, err = r.destination(ctx, migration); err != nil {
+			return 0, err
+		}
+	}
+	targetPath := lvm.DeviceMapperPath(migration.Status.TargetDeviceClass, migration.Status.TargetLogicalVolume)
+
+	devicePath := lvm.DeviceMapperPath(vgName, volume.Name)
+	var snapshotName string
+	if fromSnapshot {
+		snapshotName = volume.Name + snapshotSuffix
+		// leftover of an interrupted copy
+		if findLV(lvReport, snapshotName) != nil {
+			if err := r.DeleteLV(ctx, snapshotName, vgName); err != nil {
+				return 0, fmt.Errorf("failed to delete snapshot %s of previous copy: %w", snapshotName, err)
+			}
+		}
+		lvAttr, err := vgmanager.ParsedLvAttr(volume.LvAttr)
+		if err != nil {
+			return 0, fmt.Errorf("could not parse lv_attr from logical volume %s: %w", volume.Name, err)
+		}
+		if lvAttr.VolumeType == vgmanager.VolumeTypeThinVolume {
+			err = r.CreateThinSnapshotLV(ctx, snapshotName, vgName, volume.Name)
+		} else {
+			reserve := migration.Status.SizeBytes * snapshotReservePercent / 100
+			err = r.CreateSnapshotLV(ctx, snapshotName, vgName, volume.Name, reserve, nil, true)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to create snapshot of logical volume %s for copy: %w", volume.Name, err)
+		}
+		devicePath = lvm.DeviceMapperPath(vgName, snapshotName)
+	}
+
+	key := client.ObjectKeyFromObject(migration)
+	transferCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	t := &transfer{cancel: cancel, done: make(chan struct{}), phase: migration.Status.Phase}
+	previous := r.previousChecksums(key)
+	size := migration.Status.SizeBytes
+	r.setTransfer(key, t)
+
+	go func() {
+		defer close(t.done)
+		defer cancel()
+		if isLocal(migration) {
+			t.result, t.err = r.Copy(transferCtx, devicePath, size, targetPath, previous, &t.read)
+		} else {
+			t.result, t.err = r.Send(transferCtx, devicePath, size, dst, previous, &t.read)
+		}
+		if snapshotName != "" {
+			if err := r.DeleteLV(context.WithoutCancel(transferCtx), snapshotName, vgName); err != nil {
+				t.err = errors.Join(t.err, fmt.Errorf("failed to delete snapshot %s after copy: %w", snapshotName, err))
+			}
+		}
+	}()
+
+	if migration.Status.StartTime == nil {
+		migration.Status.StartTime = ptr.To(metav1.Now())
+	}
+	migration.Status.Progress = "0%"
+	msg := fmt.Sprintf("copying logical volume %s to %s", volume.Name, target(migration))
+	if len(previous) > 0 {
+		msg = fmt.Sprintf("copying changes of logical volume %s to %s", volume.Name, target(migration))
+	}
+	setInProgress(migration, migration.Status.Phase, ReasonSyncing, msg)
+	logger.Info(msg, "fromSnapshot", fromSnapshot, "incremental", len(previous) > 0)
+
+	return pollInterval, nil
+}
+
+// passCompleted records the result of a copy and advances the migration.
+func (r *Reconciler) passCompleted(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration, t *transfer) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+	if t.err != nil {
+		logger.Error(t.err, "copy of volume failed, retrying")
+		msg := fmt.Sprintf("copy to %s failed and is retried: %v", target(migration), t.err)
+		r.Eventf(migration, nil, corev1.EventTypeWarning, string(EventReasonErrorTransferFailed), "MigrateVolume", msg)
+		setInProgress(migration, t.phase, ReasonTransferFailed, msg)
+		return pollInterval, nil
+	}
+
+	key := client.ObjectKeyFromObject(migration)
+	r.setChecksums(key, t.result.Checksums)
+	migration.Status.Passes++
+	migration.Status.BytesTransferred += t.result.ChangedBytes
+	migration.Status.LastPassChangedBytes = t.result.ChangedBytes
+	migration.Status.LastPassTime = ptr.To(metav1.Now())
+	migration.Status.Progress = "100%"
+	logger.Info("copy of volume completed", "passes", migration.Status.Passes, "changedBytes", t.result.ChangedBytes)
+
+	if t.phase == lvmv1alpha1.LVMVolumeMigrationVerifying {
+		return r.verificationCompleted(ctx, migration, t)
+	}
+	if t.phase != lvmv1alpha1.LVMVolumeMigrationFinalSync {
+		setWaitingForUnpublish(migration)
+		return pollInterval, nil
+	}
+
+	// a workload that started during the last copy might have changed the volume again
+	_, _, open, err := r.sourceVolume(ctx, migration)
+	if err != nil {
+		return 0, err
+	}
+	if open {
+		setWaitingForUnpublish(migration)
+		return pollInterval, nil
+	}
+	setInProgress(migration, lvmv1alpha1.LVMVolumeMigrationSwitching, ReasonSwitching,
+		fmt.Sprintf("switching PersistentVolumeClaim to the volume in %s", target(migration)))
+	return pollInterval, nil
+}
+
+// verificationCompleted hands the migration back to the target node once a copy after the deletion of the
+// PersistentVolumeClaim found no changes on the closed volume. Changes are copied by the verifying copy
+// and verified by another one, as the source volume is removed after the hand over.
+func (r *Reconciler) verificationCompleted(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration, t *transfer) (time.Duration, error) {
+	_, volume, open, err := r.sourceVolume(ctx, migration)
+	if err != nil {
+		return 0, err
+	}
+	if open {
+		setInProgress(migration, lvmv1alpha1.LVMVolumeMigrationVerifying, ReasonVerifying,
+			fmt.Sprintf("waiting for logical volume %s to be closed", volume.Name))
+		return pollInterval, nil
+	}
+	if t.result.ChangedBytes > 0 {
+		msg := fmt.Sprintf("logical volume %s changed by %d bytes after the last copy, verifying it again",
+			volume.Name, t.result.ChangedBytes)
+		log.FromContext(ctx).Info(msg)
+		r.Eventf(migration, nil, corev1.EventTypeNormal, string(EventReasonSourceChanged), "MigrateVolume", msg)
+		setInProgress(migration, lvmv1alpha1.LVMVolumeMigrationVerifying, ReasonVerifying, msg)
+		return pollInterval, nil
+	}
+	migration.Status.SourceVerified = true
+	setInProgress(migration, lvmv1alpha1.LVMVolumeMigrationSwitching, ReasonSwitching,
+		fmt.Sprintf("switching PersistentVolumeClaim to the volume in %s", target(migration)))
+	return pollInterval, nil
+}
+
+// destination returns where the volume is copied to from the status and the Secret of the migration.
+func (r *Reconciler) destination(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration) (Destination, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Name: secretName(migration), Namespace: migration.GetNamespace()}
+	if err := r.APIReader.Get(ctx, key, secret); err != nil {
+		return Destination{}, fmt.Errorf("failed to get Secret %s: %w", key.Name, err)
+	}
+	return Destination{
+		Address: migration.Status.TargetAddress,
+		Session: string(migration.GetUID()),
+		Token:   string(secret.Data[secretTokenKey]),
+		CACert:  secret.Data[secretCACertKey],
+	}, nil
+}
+
+// switchClaim recreates the PersistentVolumeClaim bound to a PersistentVolume of the target volume.
+// The claim is only recreated once the source node verified the source volume after the claim was deleted.
+// The PersistentVolume and the source volume are removed afterwards.
+func (r *Reconciler) switchClaim(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+	claimRef := migration.Spec.PersistentVolumeClaim
+	claimKey := types.NamespacedName{Name: claimRef.Name, Namespace: claimRef.Namespace}
+
+	paused, err := r.isPaused(ctx, migration.Status.TargetDeviceClass)
+	if err != nil {
+		return 0, err
+	}
+	if paused {
+		setInProgress(migration, migration.Status.Phase, ReasonPaused,
+			"waiting for the node to leave maintenance and the LVMCluster to be unpaused")
+		return pollInterval, nil
+	}
+
+	sourcePV := &corev1.PersistentVolume{}
+	if err := r.Get(ctx, client.ObjectKey{Name: migration.Status.SourcePersistentVolumeName}, sourcePV); client.IgnoreNotFound(err) != nil {
+		return 0, fmt.Errorf("failed to get PersistentVolume %s: %w", migration.Status.SourcePersistentVolumeName, err)
+	} else if apierrors.IsNotFound(err) {
+		sourcePV = nil
+	}
+
+	// the claim is recorded before it is deleted so that it can be recreated after a restart
+	if migration.Status.Claim == nil {
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := r.APIReader.Get(ctx, claimKey, pvc); err != nil {
+			return 0, fmt.Errorf("failed to get PersistentVolumeClaim %s: %w", claimKey, err)
+		}
+		if pvc.Spec.VolumeName != migration.Status.SourcePersistentVolumeName || sourcePV == nil {
+			return 0, fmt.Errorf("%w: PersistentVolumeClaim %s is no longer bound to PersistentVolume %s",
+				ErrMigrationFailed, claimKey, migration.Status.SourcePersistentVolumeName)
+		}
+		migration.Status.Claim = migratedClaim(pvc)
+		migration.Status.ReclaimPolicy = sourcePV.Spec.PersistentVolumeReclaimPolicy
+		if err := r.updateStatus(ctx, migration); err != nil {
+			return 0, err
+		}
+	}
+
+	targetLV := &topolvmv1.LogicalVolume{}
+	if err := r.Get(ctx, client.ObjectKey{Name: migration.Status.TargetLogicalVolumeName}, targetLV); err != nil {
+		return 0, fmt.Errorf("failed to get LogicalVolume %s: %w", migration.Status.TargetLogicalVolumeName, err)
+	}
+	targetPV, err := r.getOrCreateTargetPersistentVolume(ctx, migration, sourcePV, targetLV)
+	if err != nil {
+		return 0, err
+	}
+
+	if sourcePV != nil && sourcePV.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimRetain {
+		// the source PersistentVolume must not delete the volume when the claim is deleted
+		patch := client.MergeFrom(sourcePV.DeepCopy())
+		sourcePV.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+		if err := r.Patch(ctx, sourcePV, patch); err != nil {
+			return 0, fmt.Errorf("failed to retain PersistentVolume %s: %w", sourcePV.GetName(), err)
+		}
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	err = r.APIReader.Get(ctx, claimKey, pvc)
+	switch {
+	case apierrors.IsNotFound(err) && !migration.Status.SourceVerified:
+		// writes of pods that used the claim after the last copy are only visible once it is deleted
+		setInProgress(migration, lvmv1alpha1.LVMVolumeMigrationVerifying, ReasonVerifying,
+			fmt.Sprintf("waiting for node %s to verify that logical volume %s did not change since the last copy",
+				migration.Status.SourceNodeName, migration.Status.SourceLogicalVolume))
+		return pollInterval, nil
+	case apierrors.IsNotFound(err):
+		// the volume no longer accepts copies once the claim is switched
+		r.Receiver.Unregister(string(migration.GetUID()))
+		pvc = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        claimRef.Name,
+				Namespace:   claimRef.Namespace,
+				Labels:      migration.Status.Claim.Labels,
+				Annotations: migration.Status.Claim.Annotations,
+			},
+			Spec: *migration.Status.Claim.Spec.DeepCopy(),
+		}
+		pvc.Spec.VolumeName = targetPV.GetName()
+		pvc.Spec.StorageClassName = ptr.To(targetPV.Spec.StorageClassName)
+		if err := r.Create(ctx, pvc); err != nil {
+			return 0, fmt.Errorf("failed to recreate PersistentVolumeClaim %s: %w", claimKey, err)
+		}
+		logger.Info("recreated PersistentVolumeClaim for migrated volume", "PersistentVolumeClaim", claimKey, "PersistentVolume", targetPV.GetName())
+		setInProgress(migration, migration.Status.Phase, ReasonSwitching,
+			fmt.Sprintf("waiting for PersistentVolumeClaim %s to be bound to PersistentVolume %s", claimKey, targetPV.GetName()))
+		return pollInterval, nil
+	case err != nil:
+		return 0, fmt.Errorf("failed to get PersistentVolumeClaim %s: %w", claimKey, err)
+	case pvc.Spec.VolumeName == migration.Status.SourcePersistentVolumeName:
+		if pvc.DeletionTimestamp.IsZero() {
+			if err := r.Delete(ctx, pvc); client.IgnoreNotFound(err) != nil {
+				return 0, fmt.Errorf("failed to delete PersistentVolumeClaim %s: %w", claimKey, err)
+			}
+			logger.Info("deleted PersistentVolumeClaim to rebind it to the migrated volume", "PersistentVolumeClaim", claimKey)
+		}
+		setInProgress(migration, migration.Status.Phase, ReasonSwitching,
+			fmt.Sprintf("waiting for PersistentVolumeClaim %s to be deleted, it is recreated once no pod uses it anymore", claimKey))
+		return pollInterval, nil
+	case pvc.Spec.VolumeName != targetPV.GetName():
+		setInProgress(migration, migration.Status.Phase, ReasonSwitching,
+			fmt.Sprintf("PersistentVolumeClaim %s was recreated for PersistentVolume %s, delete it to continue the migration",
+				claimKey, pvc.Spec.VolumeName))
+		return pollInterval, nil
+	case pvc.Status.Phase != corev1.ClaimBound:
+		setInProgress(migration, migration.Status.Phase, ReasonSwitching,
+			fmt.Sprintf("waiting for PersistentVolumeClaim %s to be bound to PersistentVolume %s", claimKey, targetPV.GetName()))
+		return pollInterval, nil
+	}
+
+	r.Receiver.Unregister(string(migration.GetUID()))
+	if err := r.removeSourceVolume(ctx, migration, sourcePV); err != nil {
+		return 0, err
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName(migration), Namespace: migration.GetNamespace()}}
+	if err := r.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+		return 0, fmt.Errorf("failed to delete Secret %s: %w", secret.GetName(), err)
+	}
+	if controllerutil.RemoveFinalizer(migration, MigrationFinalizer) {
+		if err := r.Update(ctx, migration); err != nil {
+			return 0, fmt.Errorf("failed to remove finalizer from LVMVolumeMigration %s: %w", migration.GetName(), err)
+		}
+	}
+
+	migration.Status.Phase = lvmv1alpha1.LVMVolumeMigrationCompleted
+	migration.Status.CompletionTime = ptr.To(metav1.Now())
+	msg := fmt.Sprintf("PersistentVolumeClaim %s was migrated from device class %s on node %s to %s",
+		claimKey, migration.Status.DeviceClass, migration.Status.SourceNodeName, target(migration))
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeMigrated,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonVolumeMigrated,
+		Message: msg,
+	})
+	logger.Info(msg)
+	r.Eventf(migration, nil, corev1.EventTypeNormal, string(EventReasonVolumeMigrated), "MigrateVolume", msg)
+
+	return 0, nil
+}
+
+func (r *Reconciler) getOrCreateTargetPersistentVolume(
+	ctx context.Context,
+	migration *lvmv1alpha1.LVMVolumeMigration,
+	sourcePV *corev1.PersistentVolume,
+	logicalVolume *topolvmv1.LogicalVolume,
+) (*corev1.PersistentVolume, error) {
+	pv := &corev1.PersistentVolume{}
+	err := r.Get(ctx, client.ObjectKey{Name: logicalVolume.GetName()}, pv)
+	if err == nil {
+		return pv, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get PersistentVolume %s: %w", logicalVolume.GetName(), err)
+	}
+	if sourcePV == nil {
+		return nil, fmt.Errorf("%w: PersistentVolume %s was deleted before the migrated volume was switched to",
+			ErrMigrationFailed, migration.Status.SourcePersistentVolumeName)
+	}
+
+	pv = persistentVolumeForMigration(migration, sourcePV, logicalVolume)
+	if err := r.Create(ctx, pv); err != nil {
+		return nil, fmt.Errorf("failed to create PersistentVolume %s: %w", pv.GetName(), err)
+	}
+	log.FromContext(ctx).Info("created PersistentVolume for migrated volume", "PersistentVolume", pv.GetName())
+	return pv, nil
+}
+
+// removeSourceVolume deletes the PersistentVolume of the source volume and its LogicalVolume,
+// which lets TopoLVM remove the logical volume on the source node.
+func (r *Reconciler) removeSourceVolume(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration, sourcePV *corev1.PersistentVolume) error {
+	if sourcePV != nil {
+		if err := r.Delete(ctx, sourcePV); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete PersistentVolume %s: %w", sourcePV.GetName(), err)
+		}
+	}
+
+	logicalVolumes := &topolvmv1.LogicalVolumeList{}
+	if err := r.List(ctx, logicalVolumes); err != nil {
+		return fmt.Errorf("failed to list TopoLVM LogicalVolumes: %w", err)
+	}
+	if source := findLogicalVolume(logicalVolumes, migration.Status.SourcePersistentVolumeName); source != nil {
+		if err := r.Delete(ctx, source); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete LogicalVolume %s: %w", source.GetName(), err)
+		}
+		log.FromContext(ctx).Info("deleted LogicalVolume of source node", "LogicalVolume", source.GetName())
+	}
+	return nil
+}
+
+// finalize removes the target volume unless the PersistentVolumeClaim is already being switched to it.
+func (r *Reconciler) finalize(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration) error {
+	if migration.Status.TargetNodeName != r.NodeName || !controllerutil.ContainsFinalizer(migration, MigrationFinalizer) {
+		return nil
+	}
+	r.Receiver.Unregister(string(migration.GetUID()))
+
+	if migration.Status.Phase != lvmv1alpha1.LVMVolumeMigrationSwitching &&
+		migration.Status.Phase != lvmv1alpha1.LVMVolumeMigrationVerifying {
+		logicalVolume := &topolvmv1.LogicalVolume{}
+		err := r.Get(ctx, client.ObjectKey{Name: targetLogicalVolumeName(migration)}, logicalVolume)
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to get LogicalVolume %s: %w", targetLogicalVolumeName(migration), err)
+		}
+		if err == nil && logicalVolume.GetLabels()[MigrationLabel] == migration.GetName() {
+			if err := r.Delete(ctx, logicalVolume); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete LogicalVolume %s: %w", logicalVolume.GetName(), err)
+			}
+			log.FromContext(ctx).Info("deleted LogicalVolume of unfinished migration", "LogicalVolume", logicalVolume.GetName())
+		}
+	}
+
+	controllerutil.RemoveFinalizer(migration, MigrationFinalizer)
+	if err := r.Update(ctx, migration); err != nil {
+		return fmt.Errorf("failed to remove finalizer from LVMVolumeMigration %s: %w", migration.GetName(), err)
+	}
+	return nil
+}
+
+// isPaused checks if the node is in maintenance or the LVMCluster controlling the volume group is paused.
+func (r *Reconciler) isPaused(ctx context.Context, vgName string) (bool, error) {
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: r.NodeName}, node); err != nil {
+		return false, fmt.Errorf("failed to get node %s: %w", r.NodeName, err)
+	}
+	if node.GetAnnotations()[constants.MaintenanceAnnotation] == "true" {
+		return true, nil
+	}
+
+	volumeGroup := &lvmv1alpha1.LVMVolumeGroup{}
+	if err := r.Get(ctx, types.NamespacedName{Name: vgName, Namespace: r.Namespace}, volumeGroup); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	owner := metav1.GetControllerOf(volumeGroup)
+	if owner == nil || owner.Kind != "LVMCluster" {
+		return false, nil
+	}
+	lvmCluster := &lvmv1alpha1.LVMCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: volumeGroup.GetNamespace()}, lvmCluster); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return lvmCluster.Spec.Paused, nil
+}
+
+func (r *Reconciler) fail(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration, err error) error {
+	r.Eventf(migration, nil, corev1.EventTypeWarning, string(EventReasonErrorMigrationFailed), "MigrateVolume", err.Error())
+	migration.Status.Phase = lvmv1alpha1.LVMVolumeMigrationFailed
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeMigrated,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonMigrationFailed,
+		Message: err.Error(),
+	})
+	return r.updateStatus(ctx, migration)
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, migration *lvmv1alpha1.LVMVolumeMigration) error {
+	if err := r.Status().Update(ctx, migration); err != nil {
+		return fmt.Errorf("failed to update status of LVMVolumeMigration %s: %w", migration.GetName(), err)
+	}
+	return nil
+}
+
+func (r *Reconciler) transfer(key types.NamespacedName) *transfer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.transfers[key]
+}
+
+func (r *Reconciler) setTransfer(key types.NamespacedName, t *transfer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transfers[key] = t
+}
+
+func (r *Reconciler) removeTransfer(key types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.transfers, key)
+}
+
+func (r *Reconciler) previousChecksums(key types.NamespacedName) []Checksum {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.checksums[key]
+}
+
+func (r *Reconciler) setChecksums(key types.NamespacedName, checksums []Checksum) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checksums[key] = checksums
+}
+
+// stopTransfer cancels a running copy and forgets the checksums of the migration.
+func (r *Reconciler) stopTransfer(key types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.transfers[key]; ok {
+		t.cancel()
+		delete(r.transfers, key)
+	}
+	delete(r.checksums, key)
+}
+
+func setWaitingForUnpublish(migration *lvmv1alpha1.LVMVolumeMigration) {
+	claimRef := migration.Spec.PersistentVolumeClaim
+	setInProgress(migration, lvmv1alpha1.LVMVolumeMigrationCatchingUp, ReasonWaitingForUnpublish,
+		fmt.Sprintf("copying changes every %s until all pods using PersistentVolumeClaim %s/%s are stopped",
+			catchUpInterval, claimRef.Namespace, claimRef.Name))
+}
+
+func setInProgress(migration *lvmv1alpha1.LVMVolumeMigration, phase lvmv1alpha1.LVMVolumeMigrationPhase, reason, msg string) {
+	if phase == "" {
+		phase = lvmv1alpha1.LVMVolumeMigrationPending
+	}
+	migration.Status.Phase = phase
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeMigrated,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: msg,
+	})
+}
+
+// migratedClaim records the PersistentVolumeClaim without the fields set by Kubernetes during binding.
+func migratedClaim(pvc *corev1.PersistentVolumeClaim) *lvmv1alpha1.MigratedClaim {
+	claim := &lvmv1alpha1.MigratedClaim{
+		Labels: pvc.GetLabels(),
+		Spec:   *pvc.Spec.DeepCopy(),
+	}
+	claim.Spec.VolumeName = ""
+	for key, value := range pvc.GetAnnotations() {
+		if hasAnyPrefix(key, claimAnnotationPrefixes) {
+			continue
+		}
+		if claim.Annotations == nil {
+			claim.Annotations = make(map[string]string)
+		}
+		claim.Annotations[key] = value
+	}
+	return claim
+}
+
+func persistentVolumeForMigration(
+	migration *lvmv1alpha1.LVMVolumeMigration,
+	sourcePV *corev1.PersistentVolume,
+	logicalVolume *topolvmv1.LogicalVolume,
+) *corev1.PersistentVolume {
+	reclaimPolicy := migration.Status.ReclaimPolicy
+	if reclaimPolicy == "" {
+		reclaimPolicy = corev1.PersistentVolumeReclaimDelete
+	}
+	claimRef := migration.Spec.PersistentVolumeClaim
+
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        logicalVolume.GetName(),
+			Labels:      map[string]string{MigrationLabel: migration.GetName()},
+			Annotations: map[string]string{provisionedByAnnotation: constants.TopolvmCSIDriverName},
+		},
+		Spec: *sourcePV.Spec.DeepCopy(),
+	}
+	pv.Spec.PersistentVolumeReclaimPolicy = reclaimPolicy
+	if migration.Status.TargetDeviceClass != migration.Status.DeviceClass {
+		pv.Spec.StorageClassName = constants.StorageClassPrefix + migration.Status.TargetDeviceClass
+	}
+	pv.Spec.ClaimRef = &corev1.ObjectReference{
+		Kind:       "PersistentVolumeClaim",
+		APIVersion: "v1",
+		Name:       claimRef.Name,
+		Namespace:  claimRef.Namespace,
+	}
+	if pv.Spec.CSI != nil {
+		pv.Spec.CSI.VolumeHandle = logicalVolume.Status.VolumeID
+	}
+	pv.Spec.NodeAffinity = &corev1.VolumeNodeAffinity{
+		Required: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      topolvm.GetTopologyNodeKey(),
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{logicalVolume.Spec.NodeName},
+				}},
+			}},
+		},
+	}
+	return pv
+}
+
+// isLocal returns whether the volume stays on its node and only moves to another device class.
+func isLocal(migration *lvmv1alpha1.LVMVolumeMigration) bool {
+	return migration.Status.SourceNodeName == migration.Status.TargetNodeName
+}
+
+// isSyncing returns whether the volume is being copied to the target volume.
+func isSyncing(migration *lvmv1alpha1.LVMVolumeMigration) bool {
+	switch migration.Status.Phase {
+	case lvmv1alpha1.LVMVolumeMigrationInitialSync, lvmv1alpha1.LVMVolumeMigrationCatchingUp,
+		lvmv1alpha1.LVMVolumeMigrationFinalSync, lvmv1alpha1.LVMVolumeMigrationVerifying:
+		return true
+	}
+	return false
+}
+
+// target describes where the volume is migrated to in messages.
+func target(migration *lvmv1alpha1.LVMVolumeMigration) string {
+	if migration.Status.TargetDeviceClass != migration.Status.DeviceClass {
+		return fmt.Sprintf("device class %s on node %s", migration.Status.TargetDeviceClass, migration.Status.TargetNodeName)
+	}
+	return fmt.Sprintf("node %s", migration.Status.TargetNodeName)
+}
+
+// targetLogicalVolumeName is the name of the LogicalVolume and the PersistentVolume of the target volume,
+// which follows the naming of PersistentVolumes provisioned by TopoLVM.
+func targetLogicalVolumeName(migration *lvmv1alpha1.LVMVolumeMigration) string {
+	return "pvc-" + string(migration.GetUID())
+}
+
+func secretName(migration *lvmv1alpha1.LVMVolumeMigration) string {
+	return "lvms-migration-" + migration.GetName()
+}
+
+func findLogicalVolume(logicalVolumes *topolvmv1.LogicalVolumeList, pvName string) *topolvmv1.LogicalVolume {
+	for i := range logicalVolumes.Items {
+		if logicalVolumes.Items[i].Spec.Name == pvName {
+			return &logicalVolumes.Items[i]
+		}
+	}
+	return nil
+}
+
+func findLV(report *lvm.LVReport, name string) *lvm.LogicalVolume {
+	for _, item := range report.Report {
+		for i := range item.Lv {
+			if item.Lv[i].Name == name {
+				return &item.Lv[i]
+			}
+		}
+	}
+	return nil
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func progress(read, size int64) string {
+	if size <= 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%d%%", min(read*100/size, 100))
+}
+
+// SetupWithManager sets up the controller with the Manager. Status changes are not filtered,
+// as they hand the migration over between the vg-manager of the source and the target node.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&lvmv1alpha1.LVMVolumeMigration{}).
+		WithOptions(controller.Options{SkipNameValidation: ptr.To(true)}).
+		Named("lvms_volumemigration").
+		Complete(r)
+}
@@ -0,0 +1,495 @@
+package volume_migration
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	lvmmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	sourceNode      = "source-node"
+	targetNode      = "target-node"
+	testNamespace   = "openshift-lvm-storage"
+	testDeviceClass = "vg1"
+	testMigration   = "test-migration"
+	testVolume      = "0d2b1c3e-6a1f-4e4c-8d43-2b9a7a3c1f10"
+	targetVolume    = "7e6f4f7c-2f5c-4a0d-9a0e-5f3c2b1d0e9a"
+	testSize        = int64(1073741824)
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, lvmv1alpha1.AddToScheme(scheme))
+	require.NoError(t, topolvmv1.AddToScheme(scheme))
+	return scheme
+}
+
+func testObjects() []client.Object {
+	return []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: sourceNode}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: targetNode}},
+		&lvmv1alpha1.LVMVolumeGroupNodeStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: targetNode, Namespace: testNamespace},
+			Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+				VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
+					{VGStatus: lvmv1alpha1.VGStatus{Name: testDeviceClass, Status: lvmv1alpha1.VGStatusReady}},
+				},
+			},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "data", Namespace: "app",
+				Labels:      map[string]string{"app": "db"},
+				Annotations: map[string]string{"pv.kubernetes.io/bind-completed": "yes", "owner": "team"},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				VolumeName:       "pvc-1",
+				StorageClassName: ptr.To("lvms-vg1"),
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+				PersistentVolumeSource: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{
+					Driver: "topolvm.io", VolumeHandle: testVolume, FSType: "xfs",
+				}},
+			},
+		},
+		&topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+			Spec:       topolvmv1.LogicalVolumeSpec{Name: "pvc-1", NodeName: sourceNode, DeviceClass: testDeviceClass},
+			Status:     topolvmv1.LogicalVolumeStatus{VolumeID: testVolume},
+		},
+	}
+}
+
+func testLVMVolumeMigration(status lvmv1alpha1.LVMVolumeMigrationStatus) *lvmv1alpha1.LVMVolumeMigration {
+	return &lvmv1alpha1.LVMVolumeMigration{
+		ObjectMeta: metav1.ObjectMeta{Name: testMigration, Namespace: testNamespace, UID: "migration-uid"},
+		Spec: lvmv1alpha1.LVMVolumeMigrationSpec{
+			PersistentVolumeClaim: lvmv1alpha1.VolumeMigrationClaimReference{Name: "data", Namespace: "app"},
+			TargetNodeName:        targetNode,
+		},
+		Status: status,
+	}
+}
+
+// resolvedStatus is the status of a migration after the source node resolved the volume.
+func resolvedStatus(phase lvmv1alpha1.LVMVolumeMigrationPhase) lvmv1alpha1.LVMVolumeMigrationStatus {
+	return lvmv1alpha1.LVMVolumeMigrationStatus{
+		Phase:                      phase,
+		SourceNodeName:             sourceNode,
+		DeviceClass:                testDeviceClass,
+		TargetNodeName:             targetNode,
+		TargetDeviceClass:          testDeviceClass,
+		SourcePersistentVolumeName: "pvc-1",
+		SourceLogicalVolume:        testVolume,
+		SizeBytes:                  testSize,
+	}
+}
+
+// syncingStatus is the status of a migration after the target node prepared the volume.
+func syncingStatus(phase lvmv1alpha1.LVMVolumeMigrationPhase) lvmv1alpha1.LVMVolumeMigrationStatus {
+	status := resolvedStatus(phase)
+	status.TargetLogicalVolumeName = "pvc-migration-uid"
+	status.TargetLogicalVolume = targetVolume
+	status.TargetAddress = "10.0.0.2:9444"
+	return status
+}
+
+func lvReport(attr string) *lvm.LVReport {
+	return &lvm.LVReport{Report: []lvm.LVReportItem{{Lv: []lvm.LogicalVolume{
+		{Name: testVolume, VgName: testDeviceClass, LvAttr: attr, LvSize: "1073741824", PoolName: "thin-pool-1"},
+	}}}}
+}
+
+func newTestReconciler(t *testing.T, nodeName string, mockLVM lvm.LVM, objs ...client.Object) (*Reconciler, client.Client) {
+	t.Helper()
+	clnt := fake.NewClientBuilder().
+		WithScheme(newScheme(t)).
+		WithObjects(append(testObjects(), objs...)...).
+		WithStatusSubresource(&lvmv1alpha1.LVMVolumeMigration{}, &topolvmv1.LogicalVolume{}).
+		Build()
+	receiver, err := NewReceiver(":0")
+	require.NoError(t, err)
+	return NewReconciler(clnt, clnt, events.NewFakeRecorder(10), mockLVM, receiver, nodeName, testNamespace, "10.0.0.2:9444"), clnt
+}
+
+func reconcile(t *testing.T, r *Reconciler, clnt client.Client) *lvmv1alpha1.LVMVolumeMigration {
+	t.Helper()
+	ctx := context.Background()
+	key := types.NamespacedName{Name: testMigration, Namespace: testNamespace}
+	_, err := r.Reconcile(ctx, controllerruntime.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	migration := &lvmv1alpha1.LVMVolumeMigration{}
+	require.NoError(t, clnt.Get(ctx, key, migration))
+	return migration
+}
+
+func waitForTransfer(t *testing.T, r *Reconciler) {
+	t.Helper()
+	transfer := r.transfer(types.NamespacedName{Name: testMigration, Namespace: testNamespace})
+	require.NotNil(t, transfer, "copy should be running")
+	<-transfer.done
+}
+
+func TestReconciler_Resolve(t *testing.T) {
+	mockLVM := lvmmocks.NewMockLVM(t)
+	mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("Vwi-aotz--"), nil)
+
+	r, clnt := newTestReconciler(t, sourceNode, mockLVM, testLVMVolumeMigration(lvmv1alpha1.LVMVolumeMigrationStatus{}))
+	migration := reconcile(t, r, clnt)
+
+	assert.Equal(t, resolvedStatus(lvmv1alpha1.LVMVolumeMigrationPreparing).SourceLogicalVolume, migration.Status.SourceLogicalVolume)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationPreparing, migration.Status.Phase)
+	assert.Equal(t, sourceNode, migration.Status.SourceNodeName)
+	assert.Equal(t, testSize, migration.Status.SizeBytes)
+
+	// the target node waits for the source node to resolve the volume
+	r, clnt = newTestReconciler(t, targetNode, lvmmocks.NewMockLVM(t), testLVMVolumeMigration(lvmv1alpha1.LVMVolumeMigrationStatus{}))
+	migration = reconcile(t, r, clnt)
+	assert.Empty(t, migration.Status.Phase)
+}
+
+func TestReconciler_ResolveFailed(t *testing.T) {
+	tests := []struct {
+		name       string
+		targetNode string
+		objs       []client.Object
+	}{
+		{"target is source node", sourceNode, nil},
+		{"target node without volume group", "other-node", []client.Object{&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "other-node"}}}},
+		{"volume with snapshot", targetNode, []client.Object{&topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "snapshot-1"},
+			Spec:       topolvmv1.LogicalVolumeSpec{Name: "snapshot-1", NodeName: sourceNode, Source: "pvc-1"},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migration := testLVMVolumeMigration(lvmv1alpha1.LVMVolumeMigrationStatus{})
+			migration.Spec.TargetNodeName = tt.targetNode
+			r, clnt := newTestReconciler(t, sourceNode, lvmmocks.NewMockLVM(t), append(tt.objs, migration)...)
+
+			migration = reconcile(t, r, clnt)
+			assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationFailed, migration.Status.Phase)
+		})
+	}
+}
+
+func TestReconciler_PrepareTarget(t *testing.T) {
+	ctx := context.Background()
+	r, clnt := newTestReconciler(t, targetNode, lvmmocks.NewMockLVM(t), testLVMVolumeMigration(resolvedStatus(lvmv1alpha1.LVMVolumeMigrationPreparing)))
+
+	migration := reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationPreparing, migration.Status.Phase, "should wait for the volume to be created")
+	assert.Contains(t, migration.GetFinalizers(), MigrationFinalizer)
+
+	logicalVolume := &topolvmv1.LogicalVolume{}
+	require.NoError(t, clnt.Get(ctx, client.ObjectKey{Name: "pvc-migration-uid"}, logicalVolume))
+	assert.Equal(t, targetNode, logicalVolume.Spec.NodeName)
+	assert.Equal(t, testDeviceClass, logicalVolume.Spec.DeviceClass)
+	assert.True(t, resource.NewQuantity(testSize, resource.BinarySI).Equal(logicalVolume.Spec.Size))
+
+	// TopoLVM creates the logical volume
+	logicalVolume.Status.VolumeID = targetVolume
+	require.NoError(t, clnt.Status().Update(ctx, logicalVolume))
+
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationInitialSync, migration.Status.Phase)
+	assert.Equal(t, targetVolume, migration.Status.TargetLogicalVolume)
+	assert.Equal(t, "10.0.0.2:9444", migration.Status.TargetAddress)
+
+	secret := &corev1.Secret{}
+	require.NoError(t, clnt.Get(ctx, types.NamespacedName{Name: "lvms-migration-" + testMigration, Namespace: testNamespace}, secret))
+	assert.NotEmpty(t, secret.Data[secretTokenKey])
+	assert.Equal(t, r.Receiver.CACert(), secret.Data[secretCACertKey])
+	assert.Contains(t, r.Receiver.sessions, "migration-uid")
+
+	// the migration is cleaned up on the target node when it fails
+	migration.Status.Phase = lvmv1alpha1.LVMVolumeMigrationFailed
+	require.NoError(t, clnt.Status().Update(ctx, migration))
+	migration = reconcile(t, r, clnt)
+	assert.NotContains(t, migration.GetFinalizers(), MigrationFinalizer)
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, client.ObjectKey{Name: "pvc-migration-uid"}, &topolvmv1.LogicalVolume{})))
+	assert.NotContains(t, r.Receiver.sessions, "migration-uid")
+}
+
+func TestReconciler_Sync(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "lvms-migration-" + testMigration, Namespace: testNamespace},
+		Data:       map[string][]byte{secretTokenKey: []byte("token"), secretCACertKey: []byte("cert")},
+	}
+	mockLVM := lvmmocks.NewMockLVM(t)
+	r, clnt := newTestReconciler(t, sourceNode, mockLVM, secret, testLVMVolumeMigration(syncingStatus(lvmv1alpha1.LVMVolumeMigrationInitialSync)))
+
+	var sent []string
+	var previous [][]Checksum
+	r.Send = func(_ context.Context, devicePath string, size int64, dst Destination, prev []Checksum, read *atomic.Int64) (PassResult, error) {
+		assert.Equal(t, testSize, size)
+		assert.Equal(t, Destination{Address: "10.0.0.2:9444", Session: "migration-uid", Token: "token", CACert: []byte("cert")}, dst)
+		sent = append(sent, devicePath)
+		previous = append(previous, prev)
+		read.Store(size)
+		return PassResult{Checksums: []Checksum{{1}}, ChangedBytes: int64(len(sent)) * 1024}, nil
+	}
+
+	// the first copy is read from a snapshot of the volume in use
+	mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("Vwi-aotz--"), nil).Once()
+	mockLVM.EXPECT().CreateThinSnapshotLV(mock.Anything, testVolume+snapshotSuffix, testDeviceClass, testVolume).Return(nil).Once()
+	mockLVM.EXPECT().DeleteLV(mock.Anything, testVolume+snapshotSuffix, testDeviceClass).Return(nil).Once()
+	migration := reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationInitialSync, migration.Status.Phase)
+	assert.NotNil(t, migration.Status.StartTime)
+	waitForTransfer(t, r)
+
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationCatchingUp, migration.Status.Phase)
+	assert.Equal(t, int32(1), migration.Status.Passes)
+	assert.Equal(t, int64(1024), migration.Status.BytesTransferred)
+	assert.Equal(t, "100%", migration.Status.Progress)
+
+	// the last changes are copied from the volume once it is no longer in use
+	mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("Vwi-a-tz--"), nil).Twice()
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationFinalSync, migration.Status.Phase)
+	waitForTransfer(t, r)
+
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationSwitching, migration.Status.Phase)
+	assert.Equal(t, int32(2), migration.Status.Passes)
+	assert.Equal(t, int64(2048), migration.Status.LastPassChangedBytes)
+	assert.Equal(t, int64(3072), migration.Status.BytesTransferred)
+
+	assert.Equal(t, []string{
+		lvm.DeviceMapperPath(testDeviceClass, testVolume+snapshotSuffix),
+		lvm.DeviceMapperPath(testDeviceClass, testVolume),
+	}, sent)
+	assert.Equal(t, [][]Checksum{nil, {{1}}}, previous, "second copy should only send changed chunks")
+}
+
+func TestReconciler_VerifySource(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "lvms-migration-" + testMigration, Namespace: testNamespace},
+		Data:       map[string][]byte{secretTokenKey: []byte("token"), secretCACertKey: []byte("cert")},
+	}
+	mockLVM := lvmmocks.NewMockLVM(t)
+	migration := testLVMVolumeMigration(syncingStatus(lvmv1alpha1.LVMVolumeMigrationVerifying))
+	r, clnt := newTestReconciler(t, sourceNode, mockLVM, secret, migration)
+	r.setChecksums(client.ObjectKeyFromObject(migration), []Checksum{{1}})
+
+	changed := []int64{512, 0}
+	var sent []string
+	var previous [][]Checksum
+	r.Send = func(_ context.Context, devicePath string, size int64, _ Destination, prev []Checksum, read *atomic.Int64) (PassResult, error) {
+		sent = append(sent, devicePath)
+		previous = append(previous, prev)
+		read.Store(size)
+		return PassResult{Checksums: []Checksum{{byte(len(sent) + 1)}}, ChangedBytes: changed[len(sent)-1]}, nil
+	}
+
+	// the volume is not verified while it is still open
+	mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("Vwi-aotz--"), nil).Once()
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationVerifying, migration.Status.Phase)
+	assert.Nil(t, r.transfer(client.ObjectKeyFromObject(migration)))
+
+	// changes since the last copy are copied and verified again
+	mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("Vwi-a-tz--"), nil)
+	reconcile(t, r, clnt)
+	waitForTransfer(t, r)
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationVerifying, migration.Status.Phase)
+	assert.False(t, migration.Status.SourceVerified)
+	assert.Equal(t, int64(512), migration.Status.LastPassChangedBytes)
+
+	// the migration is handed back to the target node once the volume did not change
+	reconcile(t, r, clnt)
+	waitForTransfer(t, r)
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationSwitching, migration.Status.Phase)
+	assert.True(t, migration.Status.SourceVerified)
+	assert.Equal(t, int32(2), migration.Status.Passes)
+
+	assert.Equal(t, []string{lvm.DeviceMapperPath(testDeviceClass, testVolume), lvm.DeviceMapperPath(testDeviceClass, testVolume)}, sent)
+	assert.Equal(t, [][]Checksum{{{1}}, {{2}}}, previous, "verifying copies should only send changed chunks")
+}
+
+func TestReconciler_SwitchClaim(t *testing.T) {
+	ctx := context.Background()
+	targetLV := &topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-migration-uid", Labels: map[string]string{MigrationLabel: testMigration}},
+		Spec:       topolvmv1.LogicalVolumeSpec{Name: "pvc-migration-uid", NodeName: targetNode, DeviceClass: testDeviceClass},
+		Status:     topolvmv1.LogicalVolumeStatus{VolumeID: targetVolume},
+	}
+	migration := testLVMVolumeMigration(syncingStatus(lvmv1alpha1.LVMVolumeMigrationSwitching))
+	migration.Finalizers = []string{MigrationFinalizer}
+	r, clnt := newTestReconciler(t, targetNode, lvmmocks.NewMockLVM(t), targetLV, migration)
+
+	// the claim bound to the source volume is recorded and deleted
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationSwitching, migration.Status.Phase)
+	require.NotNil(t, migration.Status.Claim)
+	assert.Equal(t, map[string]string{"owner": "team"}, migration.Status.Claim.Annotations)
+	assert.Equal(t, corev1.PersistentVolumeReclaimDelete, migration.Status.ReclaimPolicy)
+
+	targetPV := &corev1.PersistentVolume{}
+	require.NoError(t, clnt.Get(ctx, client.ObjectKey{Name: "pvc-migration-uid"}, targetPV))
+	assert.Equal(t, targetVolume, targetPV.Spec.CSI.VolumeHandle)
+	assert.Equal(t, "xfs", targetPV.Spec.CSI.FSType)
+	assert.Equal(t, corev1.PersistentVolumeReclaimDelete, targetPV.Spec.PersistentVolumeReclaimPolicy)
+	assert.Equal(t, []string{targetNode}, targetPV.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Values)
+
+	sourcePV := &corev1.PersistentVolume{}
+	require.NoError(t, clnt.Get(ctx, client.ObjectKey{Name: "pvc-1"}, sourcePV))
+	assert.Equal(t, corev1.PersistentVolumeReclaimRetain, sourcePV.Spec.PersistentVolumeReclaimPolicy)
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, types.NamespacedName{Name: "data", Namespace: "app"}, &corev1.PersistentVolumeClaim{})))
+
+	// the claim is only recreated once the source node verified the volume
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationVerifying, migration.Status.Phase)
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, types.NamespacedName{Name: "data", Namespace: "app"}, &corev1.PersistentVolumeClaim{})))
+	require.NoError(t, clnt.Get(ctx, client.ObjectKey{Name: "pvc-1"}, &topolvmv1.LogicalVolume{}))
+
+	migration.Status.Phase = lvmv1alpha1.LVMVolumeMigrationSwitching
+	migration.Status.SourceVerified = true
+	require.NoError(t, clnt.Status().Update(ctx, migration))
+
+	// the claim is recreated for the target volume
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationSwitching, migration.Status.Phase)
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, clnt.Get(ctx, types.NamespacedName{Name: "data", Namespace: "app"}, pvc))
+	assert.Equal(t, "pvc-migration-uid", pvc.Spec.VolumeName)
+	assert.Equal(t, map[string]string{"app": "db"}, pvc.GetLabels())
+
+	// the migration completes once the claim is bound
+	pvc.Status.Phase = corev1.ClaimBound
+	require.NoError(t, clnt.Status().Update(ctx, pvc))
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationCompleted, migration.Status.Phase)
+	assert.NotNil(t, migration.Status.CompletionTime)
+	assert.NotContains(t, migration.GetFinalizers(), MigrationFinalizer)
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, client.ObjectKey{Name: "pvc-1"}, &corev1.PersistentVolume{})))
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, client.ObjectKey{Name: "pvc-1"}, &topolvmv1.LogicalVolume{})))
+}
+
+func TestReconciler_DeviceClassMigration(t *testing.T) {
+	ctx := context.Background()
+	nodeStatus := &lvmv1alpha1.LVMVolumeGroupNodeStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: sourceNode, Namespace: testNamespace},
+		Status: lvmv1alpha1.LVMVolumeGroupNodeStatusStatus{
+			VolumeGroups: []lvmv1alpha1.VolumeGroupStatus{
+				{VGStatus: lvmv1alpha1.VGStatus{Name: testDeviceClass, Status: lvmv1alpha1.VGStatusReady}},
+				{VGStatus: lvmv1alpha1.VGStatus{Name: "vg2", Status: lvmv1alpha1.VGStatusReady}},
+			},
+		},
+	}
+	storageClass := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "lvms-vg2"}, Provisioner: "topolvm.io"}
+	migration := testLVMVolumeMigration(lvmv1alpha1.LVMVolumeMigrationStatus{})
+	migration.Spec.TargetNodeName = ""
+	migration.Spec.TargetDeviceClass = "vg2"
+
+	mockLVM := lvmmocks.NewMockLVM(t)
+	r, clnt := newTestReconciler(t, sourceNode, mockLVM, nodeStatus, storageClass, migration)
+	r.Send = func(context.Context, string, int64, Destination, []Checksum, *atomic.Int64) (PassResult, error) {
+		t.Fatal("volume on the same node should not be sent to a receiver")
+		return PassResult{}, nil
+	}
+	var targets []string
+	r.Copy = func(_ context.Context, _ string, size int64, targetPath string, prev []Checksum, read *atomic.Int64) (PassResult, error) {
+		targets = append(targets, targetPath)
+		read.Store(size)
+		if len(prev) > 0 {
+			return PassResult{Checksums: prev}, nil
+		}
+		return PassResult{Checksums: []Checksum{{1}}, ChangedBytes: size}, nil
+	}
+
+	mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("Vwi-a-tz--"), nil)
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationPreparing, migration.Status.Phase)
+	assert.Equal(t, sourceNode, migration.Status.TargetNodeName)
+	assert.Equal(t, "vg2", migration.Status.TargetDeviceClass)
+
+	migration = reconcile(t, r, clnt)
+	logicalVolume := &topolvmv1.LogicalVolume{}
+	require.NoError(t, clnt.Get(ctx, client.ObjectKey{Name: "pvc-migration-uid"}, logicalVolume))
+	assert.Equal(t, sourceNode, logicalVolume.Spec.NodeName)
+	assert.Equal(t, "vg2", logicalVolume.Spec.DeviceClass)
+	logicalVolume.Status.VolumeID = targetVolume
+	require.NoError(t, clnt.Status().Update(ctx, logicalVolume))
+
+	// the volume is copied directly without a Secret for the receiver
+	mockLVM.EXPECT().CreateThinSnapshotLV(mock.Anything, testVolume+snapshotSuffix, testDeviceClass, testVolume).Return(nil).Once()
+	mockLVM.EXPECT().DeleteLV(mock.Anything, testVolume+snapshotSuffix, testDeviceClass).Return(nil).Once()
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationInitialSync, migration.Status.Phase)
+	assert.Empty(t, migration.Status.TargetAddress)
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, types.NamespacedName{Name: "lvms-migration-" + testMigration, Namespace: testNamespace}, &corev1.Secret{})))
+	waitForTransfer(t, r)
+
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationCatchingUp, migration.Status.Phase)
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationFinalSync, migration.Status.Phase)
+	waitForTransfer(t, r)
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationSwitching, migration.Status.Phase)
+	assert.Equal(t, []string{lvm.DeviceMapperPath("vg2", targetVolume), lvm.DeviceMapperPath("vg2", targetVolume)}, targets)
+
+	// the volume is verified on the same node after the claim was deleted
+	reconcile(t, r, clnt)
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationVerifying, migration.Status.Phase)
+	waitForTransfer(t, r)
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationSwitching, migration.Status.Phase)
+	assert.True(t, migration.Status.SourceVerified)
+	assert.Len(t, targets, 3)
+
+	// the claim is recreated with the StorageClass of the target device class
+	migration = reconcile(t, r, clnt)
+	assert.Equal(t, lvmv1alpha1.LVMVolumeMigrationSwitching, migration.Status.Phase)
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, clnt.Get(ctx, types.NamespacedName{Name: "data", Namespace: "app"}, pvc))
+	assert.Equal(t, "pvc-migration-uid", pvc.Spec.VolumeName)
+	assert.Equal(t, "lvms-vg2", ptr.Deref(pvc.Spec.StorageClassName, ""))
+	targetPV := &corev1.PersistentVolume{}
+	require.NoError(t, clnt.Get(ctx, client.ObjectKey{Name: "pvc-migration-uid"}, targetPV))
+	assert.Equal(t, "lvms-vg2", targetPV.Spec.StorageClassName)
+	assert.Equal(t, []string{sourceNode}, targetPV.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Values)
+}
+
+func TestReconciler_SetupWithManager(t *testing.T) {
+	mgr, err := controllerruntime.NewManager(&rest.Config{}, controllerruntime.Options{Scheme: newScheme(t)})
+	assert.NoError(t, err)
+	r := NewReconciler(fake.NewClientBuilder().Build(), nil, events.NewFakeRecorder(1), nil, nil, targetNode, testNamespace, "")
+	assert.NoError(t, r.SetupWithManager(mgr))
+}
@@ -0,0 +1,435 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume_migration
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// ServerName is the name in the self-signed certificate of the receiver that the sender verifies.
+	ServerName = "lvms-vg-manager-migration"
+
+	// ChunkSize is the size of the blocks the volume is compared and sent in.
+	ChunkSize = 4 * 1024 * 1024
+
+	// frameHeaderSize is the size of the offset and the length that precede every chunk sent to the receiver.
+	frameHeaderSize = 12
+
+	migrationPathPrefix = "/migrations/"
+)
+
+var (
+	ErrUnknownSession  = errors.New("unknown migration session")
+	ErrInvalidFrame    = errors.New("invalid chunk")
+	ErrTransferRefused = errors.New("transfer refused by target")
+)
+
+// Checksum is the checksum of a chunk of a volume.
+type Checksum [sha256.Size]byte
+
+// Destination describes where the chunks of a volume are sent to.
+type Destination struct {
+	// Address is the host and port of the receiver.
+	Address string
+	// Session identifies the volume on the receiver.
+	Session string
+	// Token authenticates the sender for the session.
+	Token string
+	// CACert is the PEM encoded certificate of the receiver.
+	CACert []byte
+}
+
+// PassResult is the result of a copy of a volume to the receiver.
+type PassResult struct {
+	// Checksums are the checksums of all chunks of the volume, used to skip unchanged chunks in the next pass.
+	Checksums []Checksum
+	// ChangedBytes is the amount of data sent to the receiver.
+	ChangedBytes int64
+}
+
+// SendFunc copies the changed chunks of the device of a volume to the destination.
+// read is updated with the amount of data read from the device.
+type SendFunc func(ctx context.Context, devicePath string, size int64, dst Destination, previous []Checksum, read *atomic.Int64) (PassResult, error)
+
+// CopyFunc copies the changed chunks of the device of a volume to the device of a volume on the same node.
+// read is updated with the amount of data read from the device.
+type CopyFunc func(ctx context.Context, devicePath string, size int64, targetPath string, previous []Checksum, read *atomic.Int64) (PassResult, error)
+
+// Send reads the device in chunks and sends every chunk whose checksum differs from the checksum
+// of the previous pass in a single request to the receiver. Without checksums of a previous pass,
+// the whole device is sent.
+func Send(ctx context.Context, devicePath string, size int64, dst Destination, previous []Checksum, read *atomic.Int64) (PassResult, error) {
+	device, err := os.Open(devicePath)
+	if err != nil {
+		return PassResult{}, fmt.Errorf("failed to open device %s: %w", devicePath, err)
+	}
+	defer func() {
+		_ = device.Close()
+	}()
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(dst.CACert) {
+		return PassResult{}, fmt.Errorf("no valid certificate found for receiver %s", dst.Address)
+	}
+	httpClient := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: roots, ServerName: ServerName, MinVersion: tls.VersionTLS12},
+	}}
+
+	body, writer := io.Pipe()
+	type chunkResult struct {
+		PassResult
+		err error
+	}
+	results := make(chan chunkResult, 1)
+	go func() {
+		checksums, changed, err := writeChunks(device, size, writer, previous, read)
+		_ = writer.CloseWithError(err)
+		results <- chunkResult{PassResult{Checksums: checksums, ChangedBytes: changed}, err}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+dst.Address+migrationPathPrefix+dst.Session, body)
+	if err != nil {
+		_ = body.CloseWithError(err)
+		return PassResult{}, fmt.Errorf("failed to create request for receiver %s: %w", dst.Address, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+dst.Token)
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		_ = body.CloseWithError(err)
+		return PassResult{}, fmt.Errorf("failed to send volume to receiver %s: %w", dst.Address, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusNoContent {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return PassResult{}, fmt.Errorf("%w: %s: %s", ErrTransferRefused, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	result := <-results
+	if result.err != nil {
+		return PassResult{}, result.err
+	}
+	return result.PassResult, nil
+}
+
+// Copy reads the device in chunks like Send, but writes every changed chunk directly to the target device
+// on the same node instead of sending it to a receiver.
+func Copy(ctx context.Context, devicePath string, size int64, targetPath string, previous []Checksum, read *atomic.Int64) (PassResult, error) {
+	device, err := os.Open(devicePath)
+	if err != nil {
+		return PassResult{}, fmt.Errorf("failed to open device %s: %w", devicePath, err)
+	}
+	defer func() {
+		_ = device.Close()
+	}()
+
+	reader, writer := io.Pipe()
+	stop := context.AfterFunc(ctx, func() {
+		_ = reader.CloseWithError(ctx.Err())
+	})
+	defer stop()
+
+	type chunkResult struct {
+		PassResult
+		err error
+	}
+	results := make(chan chunkResult, 1)
+	go func() {
+		checksums, changed, err := writeChunks(device, size, writer, previous, read)
+		_ = writer.CloseWithError(err)
+		results <- chunkResult{PassResult{Checksums: checksums, ChangedBytes: changed}, err}
+	}()
+
+	if _, err := receiveChunks(reader, targetPath); err != nil {
+		_ = reader.CloseWithError(err)
+		<-results
+		return PassResult{}, err
+	}
+	result := <-results
+	if result.err != nil {
+		return PassResult{}, result.err
+	}
+	return result.PassResult, nil
+}
+
+// writeChunks writes all chunks of the device that changed since the previous pass as frames to w.
+func writeChunks(device io.ReaderAt, size int64, w io.Writer, previous []Checksum, read *atomic.Int64) ([]Checksum, int64, error) {
+	checksums := make([]Checksum, 0, (size+ChunkSize-1)/ChunkSize)
+	buf := make([]byte, ChunkSize)
+	header := make([]byte, frameHeaderSize)
+	var changed int64
+
+	for offset := int64(0); offset < size; offset += ChunkSize {
+		n := int(min(ChunkSize, size-offset))
+		if _, err := device.ReadAt(buf[:n], offset); err != nil {
+			return nil, 0, fmt.Errorf("failed to read device at offset %d: %w", offset, err)
+		}
+		read.Add(int64(n))
+
+		checksum := Checksum(sha256.Sum256(buf[:n]))
+		index := len(checksums)
+		checksums = append(checksums, checksum)
+		if index < len(previous) && previous[index] == checksum {
+			continue
+		}
+
+		binary.BigEndian.PutUint64(header[:8], uint64(offset))
+		binary.BigEndian.PutUint32(header[8:], uint32(n))
+		if _, err := w.Write(header); err != nil {
+			return nil, 0, err
+		}
+		if _, err := w.Write(buf[:n]); err != nil {
+			return nil, 0, err
+		}
+		changed += int64(n)
+	}
+
+	return checksums, changed, nil
+}
+
+// session is a volume on the node of the receiver that accepts chunks.
+type session struct {
+	sync.Mutex
+	token      string
+	devicePath string
+}
+
+// Receiver receives the chunks of migrated volumes on the target node and writes them to the device of the
+// volume. It serves TLS with a self-signed certificate that is created on startup and that the sender trusts
+// through the Secret of the migration. Only registered sessions with a matching token are accepted.
+type Receiver struct {
+	addr      string
+	tlsConfig *tls.Config
+	caCert    []byte
+
+	mu       sync.RWMutex
+	sessions map[string]*session
+}
+
+// NewReceiver creates a Receiver that listens on addr.
+func NewReceiver(addr string, tlsOpts ...func(*tls.Config)) (*Receiver, error) {
+	cert, caCert, err := selfSignedCertificate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate for migration receiver: %w", err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	for _, opt := range tlsOpts {
+		opt(tlsConfig)
+	}
+
+	return &Receiver{
+		addr:      addr,
+		tlsConfig: tlsConfig,
+		caCert:    caCert,
+		sessions:  make(map[string]*session),
+	}, nil
+}
+
+// CACert returns the PEM encoded certificate of the receiver.
+func (r *Receiver) CACert() []byte {
+	return r.caCert
+}
+
+// TLSConfig returns the TLS configuration the receiver serves with.
+func (r *Receiver) TLSConfig() *tls.Config {
+	return r.tlsConfig
+}
+
+// Register accepts chunks for the device with the token as part of the session.
+func (r *Receiver) Register(id, token, devicePath string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.sessions[id]; ok && s.token == token && s.devicePath == devicePath {
+		return
+	}
+	r.sessions[id] = &session{token: token, devicePath: devicePath}
+}
+
+// Unregister stops accepting chunks for the session.
+func (r *Receiver) Unregister(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, id)
+}
+
+// Start serves the receiver until the context is cancelled.
+func (r *Receiver) Start(ctx context.Context) error {
+	listener, err := tls.Listen("tcp", r.addr, r.tlsConfig)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s for migrations: %w", r.addr, err)
+	}
+	server := &http.Server{Handler: r, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.FromContext(ctx).Info("serving volume migrations", "addr", r.addr)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP writes the chunks of a request to the device of its session.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost || !strings.HasPrefix(req.URL.Path, migrationPathPrefix) {
+		http.NotFound(w, req)
+		return
+	}
+	id := strings.TrimPrefix(req.URL.Path, migrationPathPrefix)
+
+	r.mu.RLock()
+	s, ok := r.sessions[id]
+	r.mu.RUnlock()
+	token, hasToken := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || !hasToken || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		http.Error(w, ErrUnknownSession.Error(), http.StatusForbidden)
+		return
+	}
+
+	// only a single pass is written to a volume at a time
+	s.Lock()
+	defer s.Unlock()
+
+	written, err := receiveChunks(req.Body, s.devicePath)
+	if err != nil {
+		log.FromContext(req.Context()).Error(err, "failed to receive volume", "session", id)
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrInvalidFrame) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	log.FromContext(req.Context()).V(1).Info("received volume", "session", id, "bytes", written)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// receiveChunks writes the frames read from body to the device and syncs it.
+func receiveChunks(body io.Reader, devicePath string) (int64, error) {
+	device, err := os.OpenFile(devicePath, os.O_WRONLY, 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open device %s: %w", devicePath, err)
+	}
+	defer func() {
+		_ = device.Close()
+	}()
+	size, err := device.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to determine size of device %s: %w", devicePath, err)
+	}
+
+	reader := bufio.NewReader(body)
+	header := make([]byte, frameHeaderSize)
+	buf := make([]byte, ChunkSize)
+	var written int64
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return written, fmt.Errorf("%w: failed to read header: %w", ErrInvalidFrame, err)
+		}
+		offset := int64(binary.BigEndian.Uint64(header[:8]))
+		length := int64(binary.BigEndian.Uint32(header[8:]))
+		if length == 0 || length > ChunkSize || offset < 0 || offset+length > size {
+			return written, fmt.Errorf("%w: %d bytes at offset %d exceed device of %d bytes", ErrInvalidFrame, length, offset, size)
+		}
+		if _, err := io.ReadFull(reader, buf[:length]); err != nil {
+			return written, fmt.Errorf("%w: failed to read chunk at offset %d: %w", ErrInvalidFrame, offset, err)
+		}
+		if _, err := device.WriteAt(buf[:length], offset); err != nil {
+			return written, fmt.Errorf("failed to write chunk at offset %d to device %s: %w", offset, devicePath, err)
+		}
+		written += length
+	}
+
+	if err := device.Sync(); err != nil {
+		return written, fmt.Errorf("failed to sync device %s: %w", devicePath, err)
+	}
+	return written, nil
+}
+
+// selfSignedCertificate creates the certificate of the receiver and returns it with its PEM encoding.
+func selfSignedCertificate() (tls.Certificate, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: ServerName},
+		DNSNames:              []string{ServerName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	return cert, certPEM, nil
+}
@@ -0,0 +1,129 @@
+package volume_migration
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReceiver(t *testing.T) (*Receiver, string) {
+	t.Helper()
+	receiver, err := NewReceiver(":0")
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(receiver)
+	server.TLS = receiver.TLSConfig()
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return receiver, server.Listener.Addr().String()
+}
+
+func newDevice(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func TestSend(t *testing.T) {
+	ctx := context.Background()
+	size := int64(2*ChunkSize + 1024)
+
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	source := newDevice(t, "source", data)
+	target := newDevice(t, "target", make([]byte, size))
+
+	receiver, addr := newTestReceiver(t)
+	receiver.Register("session", "token", target)
+	dst := Destination{Address: addr, Session: "session", Token: "token", CACert: receiver.CACert()}
+
+	read := &atomic.Int64{}
+	result, err := Send(ctx, source, size, dst, nil, read)
+	require.NoError(t, err)
+	assert.Equal(t, size, result.ChangedBytes)
+	assert.Equal(t, size, read.Load())
+	assert.Len(t, result.Checksums, 3)
+
+	received, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, received), "target should contain the source after the first pass")
+
+	// only the changed chunk is sent in the next pass
+	copy(data[ChunkSize:], bytes.Repeat([]byte{1}, 10))
+	require.NoError(t, os.WriteFile(source, data, 0600))
+
+	result, err = Send(ctx, source, size, dst, result.Checksums, &atomic.Int64{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(ChunkSize), result.ChangedBytes)
+
+	received, err = os.ReadFile(target)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, received), "target should contain the changes after the incremental pass")
+}
+
+func TestSend_Refused(t *testing.T) {
+	ctx := context.Background()
+	source := newDevice(t, "source", make([]byte, 1024))
+	target := newDevice(t, "target", make([]byte, 512))
+
+	receiver, addr := newTestReceiver(t)
+	receiver.Register("session", "token", target)
+
+	_, err := Send(ctx, source, 1024, Destination{Address: addr, Session: "session", Token: "wrong", CACert: receiver.CACert()}, nil, &atomic.Int64{})
+	assert.ErrorIs(t, err, ErrTransferRefused, "wrong token should be refused")
+
+	_, err = Send(ctx, source, 1024, Destination{Address: addr, Session: "other", Token: "token", CACert: receiver.CACert()}, nil, &atomic.Int64{})
+	assert.ErrorIs(t, err, ErrTransferRefused, "unregistered session should be refused")
+
+	_, err = Send(ctx, source, 1024, Destination{Address: addr, Session: "session", Token: "token", CACert: receiver.CACert()}, nil, &atomic.Int64{})
+	assert.ErrorIs(t, err, ErrTransferRefused, "chunks beyond the target device should be refused")
+
+	other, err := NewReceiver(":0")
+	require.NoError(t, err)
+	_, err = Send(ctx, source, 1024, Destination{Address: addr, Session: "session", Token: "token", CACert: other.CACert()}, nil, &atomic.Int64{})
+	assert.Error(t, err, "receiver with an untrusted certificate should be rejected")
+	assert.NotErrorIs(t, err, ErrTransferRefused)
+
+	receiver.Unregister("session")
+	_, err = Send(ctx, source, 512, Destination{Address: addr, Session: "session", Token: "token", CACert: receiver.CACert()}, nil, &atomic.Int64{})
+	assert.ErrorIs(t, err, ErrTransferRefused, "unregistered session should be refused")
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	size := int64(2*ChunkSize + 1024)
+
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	source := newDevice(t, "source", data)
+	target := newDevice(t, "target", make([]byte, size))
+
+	result, err := Copy(ctx, source, size, target, nil, &atomic.Int64{})
+	require.NoError(t, err)
+	assert.Equal(t, size, result.ChangedBytes)
+
+	copy(data[:10], bytes.Repeat([]byte{1}, 10))
+	require.NoError(t, os.WriteFile(source, data, 0600))
+	result, err = Copy(ctx, source, size, target, result.Checksums, &atomic.Int64{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(ChunkSize), result.ChangedBytes)
+
+	received, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, received), "target should contain the source after the incremental copy")
+
+	_, err = Copy(ctx, source, size, newDevice(t, "small", make([]byte, 512)), nil, &atomic.Int64{})
+	assert.ErrorIs(t, err, ErrInvalidFrame, "chunks beyond the target device should be rejected")
+}
@@ -0,0 +1,471 @@
+/*
+Copyright © 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume_revert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	snapapi "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	ControllerName = "lvms-volume-revert"
+
+	// pollInterval is the interval in which a revert is checked while it waits for the volume to be
+	// unpublished or for the merge to complete.
+	pollInterval = 10 * time.Second
+)
+
+type (
+	EventReasonInfo  string
+	EventReasonError string
+)
+
+const (
+	EventReasonErrorRevertFailed EventReasonError = "RevertFailed"
+	EventReasonMergeStarted      EventReasonInfo  = "MergeStarted"
+	EventReasonVolumeReverted    EventReasonInfo  = "VolumeReverted"
+)
+
+const (
+	ReasonResolving           = "Resolving"
+	ReasonWaitingForUnpublish = "WaitingForUnpublish"
+	ReasonPaused              = "Paused"
+	ReasonMerging             = "Merging"
+	ReasonMergeDeferred       = "MergeDeferred"
+	ReasonRevertFailed        = "RevertFailed"
+	ReasonVolumeReverted      = "VolumeReverted"
+)
+
+// ErrRevertFailed is returned for reverts that can not succeed without a change to the LVMVolumeRevert,
+// the PersistentVolumeClaim or the VolumeSnapshot, so they are reported in the status instead of being retried.
+var ErrRevertFailed = errors.New("volume revert failed")
+
+// revertTarget is the logical volume and the snapshot logical volume of a revert.
+type revertTarget struct {
+	NodeName    string
+	DeviceClass string
+	Volume      string
+	Snapshot    string
+}
+
+// Reconciler reconciles LVMVolumeRevert objects for the node it runs on.
+// It reverts the volume of a PersistentVolumeClaim to a VolumeSnapshot by merging the snapshot logical volume
+// into the logical volume of the claim with lvconvert --merge once the volume is no longer in use.
+type Reconciler struct {
+	client.Client
+	events.EventRecorder
+	lvm.LVM
+
+	// APIReader reads the PersistentVolumeClaims and VolumeSnapshots outside the namespace of the operator,
+	// which are not part of the cache of vg-manager.
+	APIReader client.Reader
+	NodeName  string
+	Namespace string
+}
+
+// NewReconciler returns Reconciler.
+func NewReconciler(client client.Client, apiReader client.Reader, eventRecorder events.EventRecorder, lvm lvm.LVM, nodeName, namespace string) *Reconciler {
+	return &Reconciler{
+		Client:        client,
+		EventRecorder: eventRecorder,
+		LVM:           lvm,
+		APIReader:     apiReader,
+		NodeName:      nodeName,
+		Namespace:     namespace,
+	}
+}
+
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumereverts,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=lvm.topolvm.io,resources=lvmvolumereverts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshotcontents,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;update;patch
+
+// Reconcile reverts the volume requested by the LVMVolumeRevert if it is located on this node.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	revert := &lvmv1alpha1.LVMVolumeRevert{}
+	if err := r.Get(ctx, req.NamespacedName, revert); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !revert.DeletionTimestamp.IsZero() ||
+		revert.Status.Phase == lvmv1alpha1.LVMVolumeRevertCompleted ||
+		revert.Status.Phase == lvmv1alpha1.LVMVolumeRevertFailed {
+		return ctrl.Result{}, nil
+	}
+	if revert.Status.NodeName != "" && revert.Status.NodeName != r.NodeName {
+		return ctrl.Result{}, nil
+	}
+
+	target, err := r.resolve(ctx, revert)
+	if errors.Is(err, ErrRevertFailed) {
+		return ctrl.Result{}, r.fail(ctx, revert, err)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if target == nil {
+		// the VolumeSnapshot is not ready yet
+		return ctrl.Result{RequeueAfter: pollInterval}, r.updateStatus(ctx, revert)
+	}
+	// the revert is handled by the vg-manager on the node of the volume
+	if target.NodeName != r.NodeName {
+		return ctrl.Result{}, nil
+	}
+
+	revert.Status.NodeName = target.NodeName
+	revert.Status.DeviceClass = target.DeviceClass
+	revert.Status.LogicalVolume = target.Volume
+	revert.Status.SnapshotLogicalVolume = target.Snapshot
+
+	requeue, err := r.reconcile(ctx, revert, target)
+	if errors.Is(err, ErrRevertFailed) {
+		return ctrl.Result{}, r.fail(ctx, revert, err)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: requeue}, r.updateStatus(ctx, revert)
+}
+
+// resolve determines the logical volume of the PersistentVolumeClaim and the snapshot logical volume
+// of the VolumeSnapshot. It returns nil if the VolumeSnapshot is not ready to use yet.
+func (r *Reconciler) resolve(ctx context.Context, revert *lvmv1alpha1.LVMVolumeRevert) (*revertTarget, error) {
+	claimRef := revert.Spec.PersistentVolumeClaim
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Name: claimRef.Name, Namespace: claimRef.Namespace}, pvc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: PersistentVolumeClaim %s/%s not found", ErrRevertFailed, claimRef.Namespace, claimRef.Name)
+		}
+		return nil, fmt.Errorf("failed to get PersistentVolumeClaim %s/%s: %w", claimRef.Namespace, claimRef.Name, err)
+	}
+	if pvc.Spec.VolumeName == "" {
+		return nil, fmt.Errorf("%w: PersistentVolumeClaim %s/%s is not bound", ErrRevertFailed, claimRef.Namespace, claimRef.Name)
+	}
+
+	logicalVolumes := &topolvmv1.LogicalVolumeList{}
+	if err := r.List(ctx, logicalVolumes); err != nil {
+		return nil, fmt.Errorf("failed to list TopoLVM LogicalVolumes: %w", err)
+	}
+	var logicalVolume *topolvmv1.LogicalVolume
+	for i := range logicalVolumes.Items {
+		if logicalVolumes.Items[i].Spec.Name == pvc.Spec.VolumeName {
+			logicalVolume = &logicalVolumes.Items[i]
+			break
+		}
+	}
+	if logicalVolume == nil || logicalVolume.Status.VolumeID == "" {
+		return nil, fmt.Errorf("%w: PersistentVolume %s of PersistentVolumeClaim %s/%s is not provisioned by LVMS",
+			ErrRevertFailed, pvc.Spec.VolumeName, claimRef.Namespace, claimRef.Name)
+	}
+
+	snapshot := &snapapi.VolumeSnapshot{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Name: revert.Spec.VolumeSnapshotName, Namespace: claimRef.Namespace}, snapshot); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: VolumeSnapshot %s/%s not found", ErrRevertFailed, claimRef.Namespace, revert.Spec.VolumeSnapshotName)
+		}
+		return nil, fmt.Errorf("failed to get VolumeSnapshot %s/%s: %w", claimRef.Namespace, revert.Spec.VolumeSnapshotName, err)
+	}
+	if ptr.Deref(snapshot.Spec.Source.PersistentVolumeClaimName, "") != claimRef.Name {
+		return nil, fmt.Errorf("%w: VolumeSnapshot %s/%s is not a snapshot of PersistentVolumeClaim %s",
+			ErrRevertFailed, claimRef.Namespace, snapshot.GetName(), claimRef.Name)
+	}
+	if snapshot.Status == nil || !ptr.Deref(snapshot.Status.ReadyToUse, false) || snapshot.Status.BoundVolumeSnapshotContentName == nil {
+		setPending(revert, fmt.Sprintf("waiting for VolumeSnapshot %s/%s to be ready to use", claimRef.Namespace, snapshot.GetName()))
+		return nil, nil
+	}
+
+	content := &snapapi.VolumeSnapshotContent{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Name: *snapshot.Status.BoundVolumeSnapshotContentName}, content); err != nil {
+		return nil, fmt.Errorf("failed to get VolumeSnapshotContent %s: %w", *snapshot.Status.BoundVolumeSnapshotContentName, err)
+	}
+	if content.Spec.Driver != constants.TopolvmCSIDriverName || content.Status == nil || content.Status.SnapshotHandle == nil {
+		return nil, fmt.Errorf("%w: VolumeSnapshotContent %s was not created by LVMS", ErrRevertFailed, content.GetName())
+	}
+
+	return &revertTarget{
+		NodeName:    logicalVolume.Spec.NodeName,
+		DeviceClass: logicalVolume.Spec.DeviceClass,
+		Volume:      logicalVolume.Status.VolumeID,
+		Snapshot:    *content.Status.SnapshotHandle,
+	}, nil
+}
+
+// reconcile advances the revert on the node. It returns the time after which the revert has to be checked again.
+func (r *Reconciler) reconcile(ctx context.Context, revert *lvmv1alpha1.LVMVolumeRevert, target *revertTarget) (time.Duration, error) {
+	logger := log.FromContext(ctx).WithValues("VGName", target.DeviceClass, "LV", target.Volume, "snapshot", target.Snapshot)
+	vgName := target.DeviceClass
+
+	paused, err := r.isPaused(ctx, vgName)
+	if err != nil {
+		return 0, err
+	}
+	if paused {
+		setInProgress(revert, revert.Status.Phase, ReasonPaused,
+			"waiting for the node to leave maintenance and the LVMCluster to be unpaused")
+		return pollInterval, nil
+	}
+
+	lvReport, err := r.ListLVs(ctx, vgName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list logical volumes in volume group %s: %w", vgName, err)
+	}
+	var volume, snapshot *lvm.LogicalVolume
+	for _, item := range lvReport.Report {
+		for i := range item.Lv {
+			switch item.Lv[i].Name {
+			case target.Volume:
+				volume = &item.Lv[i]
+			case target.Snapshot:
+				snapshot = &item.Lv[i]
+			}
+		}
+	}
+	if volume == nil {
+		return 0, fmt.Errorf("%w: logical volume %s not found in volume group %s", ErrRevertFailed, target.Volume, vgName)
+	}
+	volumeAttr, err := vgmanager.ParsedLvAttr(volume.LvAttr)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse lv_attr from logical volume %s: %w", volume.Name, err)
+	}
+
+	if revert.Status.Phase == lvmv1alpha1.LVMVolumeRevertMerging {
+		// the snapshot is removed by LVM once it is merged
+		if snapshot == nil {
+			return 0, r.complete(ctx, revert)
+		}
+		return r.checkMerge(ctx, revert, volume.Name, volumeAttr, snapshot)
+	}
+
+	if snapshot == nil {
+		return 0, fmt.Errorf("%w: snapshot logical volume %s not found in volume group %s", ErrRevertFailed, target.Snapshot, vgName)
+	}
+	if snapshot.Origin != volume.Name {
+		return 0, fmt.Errorf("%w: logical volume %s is not a snapshot of logical volume %s", ErrRevertFailed, snapshot.Name, volume.Name)
+	}
+	snapshotAttr, err := vgmanager.ParsedLvAttr(snapshot.LvAttr)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse lv_attr from logical volume %s: %w", snapshot.Name, err)
+	}
+
+	if volumeAttr.Open == vgmanager.OpenTrue || snapshotAttr.Open == vgmanager.OpenTrue {
+		claimRef := revert.Spec.PersistentVolumeClaim
+		setInProgress(revert, lvmv1alpha1.LVMVolumeRevertWaitingForUnpublish, ReasonWaitingForUnpublish,
+			fmt.Sprintf("logical volume %s is in use, waiting for all pods using PersistentVolumeClaim %s/%s to be stopped",
+				volume.Name, claimRef.Namespace, claimRef.Name))
+		return pollInterval, nil
+	}
+
+	if err := r.MergeSnapshotLV(ctx, snapshot.Name, vgName); err != nil {
+		return 0, err
+	}
+	revert.Status.StartTime = ptr.To(metav1.Now())
+	revert.Status.Progress = "0%"
+	if snapshotAttr.VolumeType == vgmanager.VolumeTypeSnapshot {
+		revert.Status.SnapshotUsageAtStart = snapshot.DataPercent
+	}
+	msg := fmt.Sprintf("merging snapshot logical volume %s into logical volume %s", snapshot.Name, volume.Name)
+	setInProgress(revert, lvmv1alpha1.LVMVolumeRevertMerging, ReasonMerging, msg)
+	logger.Info(msg)
+	r.Eventf(revert, nil, corev1.EventTypeNormal, string(EventReasonMergeStarted), "RevertVolume", msg)
+
+	return pollInterval, nil
+}
+
+// checkMerge reports the progress of a running merge. A merge that was deferred by LVM because the volume
+// was in use is started by reactivating the volume once it is no longer in use.
+func (r *Reconciler) checkMerge(
+	ctx context.Context,
+	revert *lvmv1alpha1.LVMVolumeRevert,
+	volumeName string,
+	volumeAttr vgmanager.LvAttr,
+	snapshot *lvm.LogicalVolume,
+) (time.Duration, error) {
+	vgName := revert.Status.DeviceClass
+
+	snapshotAttr, err := vgmanager.ParsedLvAttr(snapshot.LvAttr)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse lv_attr from logical volume %s: %w", snapshot.Name, err)
+	}
+	if snapshotAttr.State == vgmanager.StateSnapshotMergeFailed || snapshotAttr.State == vgmanager.StateSuspendedSnapshotMergeFailed {
+		return 0, fmt.Errorf("%w: merge of snapshot logical volume %s failed", ErrRevertFailed, snapshot.Name)
+	}
+
+	if snapshotAttr.VolumeType == vgmanager.VolumeTypeMergingSnapshot {
+		revert.Status.Progress = mergeProgress(revert.Status.SnapshotUsageAtStart, snapshot.DataPercent)
+		setInProgress(revert, lvmv1alpha1.LVMVolumeRevertMerging, ReasonMerging,
+			fmt.Sprintf("merging snapshot logical volume %s into logical volume %s", snapshot.Name, volumeName))
+		return pollInterval, nil
+	}
+
+	if volumeAttr.Open == vgmanager.OpenTrue {
+		setInProgress(revert, lvmv1alpha1.LVMVolumeRevertMerging, ReasonMergeDeferred,
+			fmt.Sprintf("merge is deferred until logical volume %s is no longer in use", volumeName))
+		return pollInterval, nil
+	}
+
+	// LVM starts a deferred merge on the next activation of the origin
+	log.FromContext(ctx).Info("reactivating logical volume to start deferred merge", "LV", volumeName)
+	if err := r.DeactivateLV(ctx, volumeName, vgName); err != nil {
+		return 0, err
+	}
+	if err := r.ActivateLV(ctx, volumeName, vgName); err != nil {
+		return 0, err
+	}
+	return pollInterval, nil
+}
+
+// complete marks the revert as completed and deletes the VolumeSnapshot that was consumed by the merge.
+func (r *Reconciler) complete(ctx context.Context, revert *lvmv1alpha1.LVMVolumeRevert) error {
+	claimRef := revert.Spec.PersistentVolumeClaim
+
+	snapshot := &snapapi.VolumeSnapshot{}
+	snapshot.SetName(revert.Spec.VolumeSnapshotName)
+	snapshot.SetNamespace(claimRef.Namespace)
+	if err := r.Delete(ctx, snapshot); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete merged VolumeSnapshot %s/%s: %w", claimRef.Namespace, snapshot.GetName(), err)
+	}
+
+	revert.Status.Phase = lvmv1alpha1.LVMVolumeRevertCompleted
+	revert.Status.Progress = "100%"
+	revert.Status.CompletionTime = ptr.To(metav1.Now())
+	msg := fmt.Sprintf("PersistentVolumeClaim %s/%s was reverted to VolumeSnapshot %s",
+		claimRef.Namespace, claimRef.Name, revert.Spec.VolumeSnapshotName)
+	meta.SetStatusCondition(&revert.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeReverted,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonVolumeReverted,
+		Message: msg,
+	})
+	log.FromContext(ctx).Info(msg)
+	r.Eventf(revert, nil, corev1.EventTypeNormal, string(EventReasonVolumeReverted), "RevertVolume", msg)
+
+	return nil
+}
+
+// isPaused checks if the node is in maintenance or the LVMCluster controlling the volume group is paused.
+func (r *Reconciler) isPaused(ctx context.Context, vgName string) (bool, error) {
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: r.NodeName}, node); err != nil {
+		return false, fmt.Errorf("failed to get node %s: %w", r.NodeName, err)
+	}
+	if node.GetAnnotations()[constants.MaintenanceAnnotation] == "true" {
+		return true, nil
+	}
+
+	volumeGroup := &lvmv1alpha1.LVMVolumeGroup{}
+	if err := r.Get(ctx, types.NamespacedName{Name: vgName, Namespace: r.Namespace}, volumeGroup); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	owner := metav1.GetControllerOf(volumeGroup)
+	if owner == nil || owner.Kind != "LVMCluster" {
+		return false, nil
+	}
+	lvmCluster := &lvmv1alpha1.LVMCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: volumeGroup.GetNamespace()}, lvmCluster); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return lvmCluster.Spec.Paused, nil
+}
+
+func (r *Reconciler) fail(ctx context.Context, revert *lvmv1alpha1.LVMVolumeRevert, err error) error {
+	r.Eventf(revert, nil, corev1.EventTypeWarning, string(EventReasonErrorRevertFailed), "RevertVolume", err.Error())
+	revert.Status.Phase = lvmv1alpha1.LVMVolumeRevertFailed
+	meta.SetStatusCondition(&revert.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeReverted,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonRevertFailed,
+		Message: err.Error(),
+	})
+	return r.updateStatus(ctx, revert)
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, revert *lvmv1alpha1.LVMVolumeRevert) error {
+	if err := r.Status().Update(ctx, revert); err != nil {
+		return fmt.Errorf("failed to update status of LVMVolumeRevert %s: %w", revert.GetName(), err)
+	}
+	return nil
+}
+
+func setPending(revert *lvmv1alpha1.LVMVolumeRevert, msg string) {
+	setInProgress(revert, lvmv1alpha1.LVMVolumeRevertPending, ReasonResolving, msg)
+}
+
+func setInProgress(revert *lvmv1alpha1.LVMVolumeRevert, phase lvmv1alpha1.LVMVolumeRevertPhase, reason, msg string) {
+	if phase == "" {
+		phase = lvmv1alpha1.LVMVolumeRevertPending
+	}
+	revert.Status.Phase = phase
+	meta.SetStatusCondition(&revert.Status.Conditions, metav1.Condition{
+		Type:    lvmv1alpha1.VolumeReverted,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: msg,
+	})
+}
+
+// mergeProgress returns the percentage of a thick snapshot that was merged, based on the usage of its
+// copy-on-write space when the merge started. Thin snapshots are merged at once, so no progress is reported.
+func mergeProgress(usageAtStart, usage string) string {
+	start, err := strconv.ParseFloat(usageAtStart, 64)
+	if err != nil || start <= 0 {
+		return "0%"
+	}
+	current, err := strconv.ParseFloat(usage, 64)
+	if err != nil {
+		return "0%"
+	}
+	progress := min(max((start-current)/start*100, 0), 100)
+	return fmt.Sprintf("%.0f%%", progress)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&lvmv1alpha1.LVMVolumeRevert{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controller.Options{SkipNameValidation: ptr.To(true)}).
+		Named("lvms_volumerevert").
+		Complete(r)
+}
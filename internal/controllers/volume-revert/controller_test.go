@@ -0,0 +1,225 @@
+package volume_revert
+
+import (
+	"context"
+	"testing"
+
+	snapapi "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	lvmmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNode        = "test-node"
+	testNamespace   = "openshift-lvm-storage"
+	testDeviceClass = "vg1"
+	testRevert      = "test-revert"
+	testVolume      = "0d2b1c3e-6a1f-4e4c-8d43-2b9a7a3c1f10"
+	testSnapshot    = "7e6f4f7c-2f5c-4a0d-9a0e-5f3c2b1d0e9a"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, lvmv1alpha1.AddToScheme(scheme))
+	require.NoError(t, topolvmv1.AddToScheme(scheme))
+	require.NoError(t, snapapi.AddToScheme(scheme))
+	return scheme
+}
+
+func testObjects(nodeName, sourcePVC string) []client.Object {
+	return []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode}},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "app"},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pvc-1"},
+		},
+		&topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+			Spec:       topolvmv1.LogicalVolumeSpec{Name: "pvc-1", NodeName: nodeName, DeviceClass: testDeviceClass},
+			Status:     topolvmv1.LogicalVolumeStatus{VolumeID: testVolume},
+		},
+		&snapapi.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-snap", Namespace: "app"},
+			Spec:       snapapi.VolumeSnapshotSpec{Source: snapapi.VolumeSnapshotSource{PersistentVolumeClaimName: ptr.To(sourcePVC)}},
+			Status:     &snapapi.VolumeSnapshotStatus{ReadyToUse: ptr.To(true), BoundVolumeSnapshotContentName: ptr.To("content-1")},
+		},
+		&snapapi.VolumeSnapshotContent{
+			ObjectMeta: metav1.ObjectMeta{Name: "content-1"},
+			Spec:       snapapi.VolumeSnapshotContentSpec{Driver: constants.TopolvmCSIDriverName},
+			Status:     &snapapi.VolumeSnapshotContentStatus{SnapshotHandle: ptr.To(testSnapshot)},
+		},
+	}
+}
+
+func lvReport(volumeAttr string, snapshot *lvm.LogicalVolume) *lvm.LVReport {
+	lvs := []lvm.LogicalVolume{{Name: testVolume, VgName: testDeviceClass, LvAttr: volumeAttr, LvSize: "10737418240"}}
+	if snapshot != nil {
+		lvs = append(lvs, *snapshot)
+	}
+	return &lvm.LVReport{Report: []lvm.LVReportItem{{Lv: lvs}}}
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	thickSnapshot := func(attr, dataPercent string) *lvm.LogicalVolume {
+		return &lvm.LogicalVolume{Name: testSnapshot, VgName: testDeviceClass, Origin: testVolume, LvAttr: attr, DataPercent: dataPercent}
+	}
+
+	tests := []struct {
+		name             string
+		nodeName         string
+		sourcePVC        string
+		status           lvmv1alpha1.LVMVolumeRevertStatus
+		setupMocks       func(*lvmmocks.MockLVM)
+		expectedPhase    lvmv1alpha1.LVMVolumeRevertPhase
+		expectedProgress string
+	}{
+		{
+			name:      "volume on other node is ignored",
+			nodeName:  "other-node",
+			sourcePVC: "data",
+		},
+		{
+			name:          "snapshot of other claim fails",
+			nodeName:      testNode,
+			sourcePVC:     "other",
+			expectedPhase: lvmv1alpha1.LVMVolumeRevertFailed,
+		},
+		{
+			name:      "waits for volume to be unpublished",
+			nodeName:  testNode,
+			sourcePVC: "data",
+			setupMocks: func(mockLVM *lvmmocks.MockLVM) {
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("owi-aoz---", thickSnapshot("swi-a-s---", "40.00")), nil)
+			},
+			expectedPhase: lvmv1alpha1.LVMVolumeRevertWaitingForUnpublish,
+		},
+		{
+			name:      "starts merge of unused volume",
+			nodeName:  testNode,
+			sourcePVC: "data",
+			setupMocks: func(mockLVM *lvmmocks.MockLVM) {
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("owi-a-z---", thickSnapshot("swi-a-s---", "40.00")), nil)
+				mockLVM.EXPECT().MergeSnapshotLV(mock.Anything, testSnapshot, testDeviceClass).Return(nil)
+			},
+			expectedPhase:    lvmv1alpha1.LVMVolumeRevertMerging,
+			expectedProgress: "0%",
+		},
+		{
+			name:      "reports merge progress",
+			nodeName:  testNode,
+			sourcePVC: "data",
+			status: lvmv1alpha1.LVMVolumeRevertStatus{
+				Phase: lvmv1alpha1.LVMVolumeRevertMerging, NodeName: testNode, DeviceClass: testDeviceClass, SnapshotUsageAtStart: "40.00",
+			},
+			setupMocks: func(mockLVM *lvmmocks.MockLVM) {
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("Owi-a-z---", thickSnapshot("Swi-a-s---", "10.00")), nil)
+			},
+			expectedPhase:    lvmv1alpha1.LVMVolumeRevertMerging,
+			expectedProgress: "75%",
+		},
+		{
+			name:      "reactivates volume for deferred merge",
+			nodeName:  testNode,
+			sourcePVC: "data",
+			status: lvmv1alpha1.LVMVolumeRevertStatus{
+				Phase: lvmv1alpha1.LVMVolumeRevertMerging, NodeName: testNode, DeviceClass: testDeviceClass,
+			},
+			setupMocks: func(mockLVM *lvmmocks.MockLVM) {
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("Vwi-a-tz--", &lvm.LogicalVolume{
+					Name: testSnapshot, VgName: testDeviceClass, Origin: testVolume, PoolName: "thin-pool-1", LvAttr: "Vwi---tz-k",
+				}), nil)
+				mockLVM.EXPECT().DeactivateLV(mock.Anything, testVolume, testDeviceClass).Return(nil)
+				mockLVM.EXPECT().ActivateLV(mock.Anything, testVolume, testDeviceClass).Return(nil)
+			},
+			expectedPhase: lvmv1alpha1.LVMVolumeRevertMerging,
+		},
+		{
+			name:      "completes once snapshot is merged",
+			nodeName:  testNode,
+			sourcePVC: "data",
+			status: lvmv1alpha1.LVMVolumeRevertStatus{
+				Phase: lvmv1alpha1.LVMVolumeRevertMerging, NodeName: testNode, DeviceClass: testDeviceClass,
+			},
+			setupMocks: func(mockLVM *lvmmocks.MockLVM) {
+				mockLVM.EXPECT().ListLVs(mock.Anything, testDeviceClass).Return(lvReport("-wi-a-----", nil), nil)
+			},
+			expectedPhase:    lvmv1alpha1.LVMVolumeRevertCompleted,
+			expectedProgress: "100%",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			revert := &lvmv1alpha1.LVMVolumeRevert{
+				ObjectMeta: metav1.ObjectMeta{Name: testRevert, Namespace: testNamespace},
+				Spec: lvmv1alpha1.LVMVolumeRevertSpec{
+					PersistentVolumeClaim: lvmv1alpha1.VolumeRevertClaimReference{Name: "data", Namespace: "app"},
+					VolumeSnapshotName:    "data-snap",
+				},
+				Status: tt.status,
+			}
+
+			clnt := fake.NewClientBuilder().
+				WithScheme(newScheme(t)).
+				WithObjects(append(testObjects(tt.nodeName, tt.sourcePVC), revert)...).
+				WithStatusSubresource(&lvmv1alpha1.LVMVolumeRevert{}).
+				Build()
+
+			mockLVM := lvmmocks.NewMockLVM(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockLVM)
+			}
+			recorder := events.NewFakeRecorder(10)
+
+			r := NewReconciler(clnt, clnt, recorder, mockLVM, testNode, testNamespace)
+			_, err := r.Reconcile(ctx, controllerruntime.Request{NamespacedName: client.ObjectKeyFromObject(revert)})
+			assert.NoError(t, err)
+
+			assert.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(revert), revert))
+			assert.Equal(t, tt.expectedPhase, revert.Status.Phase)
+			assert.Equal(t, tt.expectedProgress, revert.Status.Progress)
+
+			switch tt.expectedPhase {
+			case "":
+				assert.Empty(t, revert.Status.NodeName)
+			case lvmv1alpha1.LVMVolumeRevertFailed:
+				assert.NotEmpty(t, recorder.Events)
+			case lvmv1alpha1.LVMVolumeRevertCompleted:
+				assert.NotNil(t, revert.Status.CompletionTime)
+				err := clnt.Get(ctx, types.NamespacedName{Name: "data-snap", Namespace: "app"}, &snapapi.VolumeSnapshot{})
+				assert.True(t, apierrors.IsNotFound(err), "merged VolumeSnapshot should be deleted")
+			default:
+				assert.Equal(t, testNode, revert.Status.NodeName)
+				assert.Equal(t, testVolume, revert.Status.LogicalVolume)
+				assert.Equal(t, testSnapshot, revert.Status.SnapshotLogicalVolume)
+			}
+		})
+	}
+}
+
+func TestReconciler_SetupWithManager(t *testing.T) {
+	mgr, err := controllerruntime.NewManager(&rest.Config{}, controllerruntime.Options{Scheme: newScheme(t)})
+	assert.NoError(t, err)
+	r := NewReconciler(fake.NewClientBuilder().Build(), nil, events.NewFakeRecorder(1), nil, testNode, testNamespace)
+	assert.NoError(t, r.SetupWithManager(mgr))
+}
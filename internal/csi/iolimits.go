@@ -0,0 +1,142 @@
+package csi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// IOLimits are the limits of the I/O a pod can issue to a volume. A zero value means unlimited.
+type IOLimits struct {
+	ReadIOPS  int64
+	WriteIOPS int64
+	ReadBPS   int64
+	WriteBPS  int64
+}
+
+// ParseIOLimits reads the I/O limits from the given parameters or annotations.
+// Limits found in later sources take precedence over earlier ones.
+func ParseIOLimits(sources ...map[string]string) (IOLimits, error) {
+	limits := IOLimits{}
+	for _, source := range sources {
+		for key, limit := range map[string]*int64{
+			constants.ReadIOPSKey:  &limits.ReadIOPS,
+			constants.WriteIOPSKey: &limits.WriteIOPS,
+			constants.ReadBPSKey:   &limits.ReadBPS,
+			constants.WriteBPSKey:  &limits.WriteBPS,
+		} {
+			value, ok := source[key]
+			if !ok {
+				continue
+			}
+			parsed, err := parseIOLimit(key, value)
+			if err != nil {
+				return IOLimits{}, err
+			}
+			*limit = parsed
+		}
+	}
+	return limits, nil
+}
+
+func parseIOLimit(key, value string) (int64, error) {
+	var limit int64
+	if key == constants.ReadBPSKey || key == constants.WriteBPSKey {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %q for %s: %w", value, key, err)
+		}
+		limit = quantity.Value()
+	} else {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %q for %s: %w", value, key, err)
+		}
+		limit = parsed
+	}
+	if limit <= 0 {
+		return 0, fmt.Errorf("invalid value %q for %s: the limit has to be positive", value, key)
+	}
+	return limit, nil
+}
+
+func (l IOLimits) IsZero() bool {
+	return l == IOLimits{}
+}
+
+// ioMax returns the entry of the limits for the device in the io.max format of cgroup v2.
+// Limits that are not set are written as "max" so that a previously applied limit is removed.
+func (l IOLimits) ioMax(device string) string {
+	format := func(limit int64) string {
+		if limit == 0 {
+			return "max"
+		}
+		return strconv.FormatInt(limit, 10)
+	}
+	return fmt.Sprintf("%s rbps=%s wbps=%s riops=%s wiops=%s",
+		device, format(l.ReadBPS), format(l.WriteBPS), format(l.ReadIOPS), format(l.WriteIOPS))
+}
+
+// deviceNumber returns the major:minor number of the device mapper device at the path,
+// as found in sysfs for the dm-N device the path links to.
+func deviceNumber(root, devicePath string) (string, error) {
+	target, err := filepath.EvalSymlinks(filepath.Join(root, devicePath))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve device %s: %w", devicePath, err)
+	}
+	dev, err := os.ReadFile(filepath.Join(root, "sys", "block", filepath.Base(target), "dev"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read device number of %s: %w", devicePath, err)
+	}
+	return strings.TrimSpace(string(dev)), nil
+}
+
+// podCgroup returns the cgroup of the pod with the uid, which depends on the cgroup driver
+// of the kubelet and the QoS class of the pod.
+func podCgroup(root, podUID string) (string, error) {
+	systemdUID := strings.ReplaceAll(podUID, "-", "_")
+	candidates := []string{
+		filepath.Join("kubepods.slice", fmt.Sprintf("kubepods-pod%s.slice", systemdUID)),
+		filepath.Join("kubepods.slice", "kubepods-burstable.slice", fmt.Sprintf("kubepods-burstable-pod%s.slice", systemdUID)),
+		filepath.Join("kubepods.slice", "kubepods-besteffort.slice", fmt.Sprintf("kubepods-besteffort-pod%s.slice", systemdUID)),
+		filepath.Join("kubepods", "pod"+podUID),
+		filepath.Join("kubepods", "burstable", "pod"+podUID),
+		filepath.Join("kubepods", "besteffort", "pod"+podUID),
+	}
+	for _, candidate := range candidates {
+		path := filepath.Join(root, cgroupRoot, candidate)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to check cgroup %s: %w", path, err)
+		}
+	}
+	return "", fmt.Errorf("cgroup of pod %s not found: %w", podUID, os.ErrNotExist)
+}
+
+// applyIOLimits writes the limits of the device to io.max of the cgroup of the pod.
+// The cgroup of the pod limits the I/O of all of its containers together.
+func applyIOLimits(root, podUID, device string, limits IOLimits) error {
+	cgroup, err := podCgroup(root, podUID)
+	if err != nil {
+		return err
+	}
+	// io.max is only present if the io controller is enabled for the cgroup, it is never created here
+	file, err := os.OpenFile(filepath.Join(cgroup, "io.max"), os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open io.max of pod %s, is the io controller of cgroup v2 enabled: %w", podUID, err)
+	}
+	_, err = file.WriteString(limits.ioMax(device))
+	if err := errors.Join(err, file.Close()); err != nil {
+		return fmt.Errorf("failed to write io.max of pod %s: %w", podUID, err)
+	}
+	return nil
+}
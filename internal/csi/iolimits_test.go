@@ -0,0 +1,52 @@
+package csi
+
+import (
+	"testing"
+
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIOLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []map[string]string
+		want    IOLimits
+		wantErr bool
+	}{
+		{name: "no limits", sources: []map[string]string{nil, {"other": "1"}}},
+		{
+			name: "storage class parameters",
+			sources: []map[string]string{{
+				constants.ReadIOPSKey:  "1000",
+				constants.WriteIOPSKey: "500",
+				constants.ReadBPSKey:   "100Mi",
+				constants.WriteBPSKey:  "50M",
+			}},
+			want: IOLimits{ReadIOPS: 1000, WriteIOPS: 500, ReadBPS: 100 << 20, WriteBPS: 50_000_000},
+		},
+		{
+			name: "claim annotations take precedence",
+			sources: []map[string]string{
+				{constants.ReadIOPSKey: "1000", constants.WriteIOPSKey: "500"},
+				{constants.ReadIOPSKey: "2000"},
+			},
+			want: IOLimits{ReadIOPS: 2000, WriteIOPS: 500},
+		},
+		{name: "invalid iops", sources: []map[string]string{{constants.ReadIOPSKey: "1k"}}, wantErr: true},
+		{name: "invalid bps", sources: []map[string]string{{constants.WriteBPSKey: "fast"}}, wantErr: true},
+		{name: "zero limit", sources: []map[string]string{{constants.WriteIOPSKey: "0"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIOLimits(tt.sources...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package csi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/lvm"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/mkfs"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// podUIDKey is the volume context entry of the pod uid, passed by the kubelet as the CSIDriver sets podInfoOnMount
+const podUIDKey = "csi.storage.k8s.io/pod.uid"
+
+// NodeServer wraps the node server of TopoLVM to apply the settings of LVMS when a volume is published:
+// the filesystem of the volume is created with the mkfs options of its StorageClass and the I/O limits
+// of the volume are applied to the cgroup of the pod. I/O limits are read from the parameters of the
+// StorageClass, overridden by the annotations of the PersistentVolumeClaim.
+// Volumes are not published while their filesystem is checked by an LVMVolumeCheck.
+// All other calls are passed to the wrapped node server.
+type NodeServer struct {
+	csi.NodeServer
+
+	client    client.Client
+	apiReader client.Reader
+	mkfs      mkfs.Mkfs
+	nodeName  string
+
+	// root is the path the host filesystem is found at
+	root string
+}
+
+func NewNodeServer(nodeServer csi.NodeServer, client client.Client, apiReader client.Reader, mkfs mkfs.Mkfs, nodeName string) *NodeServer {
+	return &NodeServer{
+		NodeServer: nodeServer,
+		client:     client,
+		apiReader:  apiReader,
+		mkfs:       mkfs,
+		nodeName:   nodeName,
+		root:       "/",
+	}
+}
+
+// publishedVolume is a volume provisioned by LVMS with the settings that apply to it.
+type publishedVolume struct {
+	logicalVolume *topolvmv1.LogicalVolume
+	claim         *types.NamespacedName
+	parameters    map[string]string
+	annotations   map[string]string
+}
+
+func (s *NodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	volume, err := s.publishedVolume(ctx, req.GetVolumeId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get volume %s: %v", req.GetVolumeId(), err)
+	}
+
+	if volume != nil {
+		checking, err := s.isChecked(ctx, req.GetVolumeId(), volume)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get checks of volume %s: %v", req.GetVolumeId(), err)
+		}
+		if checking {
+			return nil, status.Errorf(codes.Unavailable, "the filesystem of volume %s is being checked", req.GetVolumeId())
+		}
+	}
+
+	// the filesystem is created before TopoLVM would do so with its default options
+	if volume != nil {
+		if err := s.format(ctx, req, volume); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to format volume %s: %v", req.GetVolumeId(), err)
+		}
+	}
+
+	resp, err := s.NodeServer.NodePublishVolume(ctx, req)
+	if err != nil || volume == nil {
+		return resp, err
+	}
+
+	// the publish is retried by the kubelet on failure, which is idempotent for the already published volume
+	if err := s.applyIOLimits(ctx, req.GetVolumeId(), req.GetVolumeContext()[podUIDKey], volume); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to apply I/O limits to volume %s: %v", req.GetVolumeId(), err)
+	}
+	return resp, nil
+}
+
+func (s *NodeServer) format(ctx context.Context, req *csi.NodePublishVolumeRequest, volume *publishedVolume) error {
+	mount := req.GetVolumeCapability().GetMount()
+	options := strings.Fields(volume.parameters[constants.MkfsOptionsKey])
+	if mount == nil || len(options) == 0 {
+		return nil
+	}
+	if mount.GetFsType() == "" {
+		return fmt.Errorf("mkfs options %v are set, but the filesystem type is not", options)
+	}
+
+	_, err := s.mkfs.Format(ctx, lvm.DeviceMapperPath(volume.logicalVolume.Spec.DeviceClass, req.GetVolumeId()), mount.GetFsType(), options)
+	return err
+}
+
+func (s *NodeServer) applyIOLimits(ctx context.Context, volumeID, podUID string, volume *publishedVolume) error {
+	limits, err := ParseIOLimits(volume.parameters, volume.annotations)
+	if err != nil {
+		return err
+	}
+	if limits.IsZero() {
+		return nil
+	}
+	if podUID == "" {
+		return fmt.Errorf("pod uid is missing in the volume context")
+	}
+
+	device, err := deviceNumber(s.root, lvm.DeviceMapperPath(volume.logicalVolume.Spec.DeviceClass, volumeID))
+	if err != nil {
+		return err
+	}
+	if err := applyIOLimits(s.root, podUID, device, limits); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("applied I/O limits", "volumeID", volumeID, "pod", podUID, "device", device, "limits", limits)
+	return nil
+}
+
+// isChecked returns whether a check of the volume itself has not finished yet. The checks are read from the
+// API server, as a check that just entered the Checking phase may not be in the cache yet. Checks that are not
+// resolved to the volume are matched by the PersistentVolumeClaim, so the volume is not published before it is
+// resolved and the check could find it open.
+func (s *NodeServer) isChecked(ctx context.Context, volumeID string, volume *publishedVolume) (bool, error) {
+	checks := &lvmv1alpha1.LVMVolumeCheckList{}
+	if err := s.apiReader.List(ctx, checks); err != nil {
+		return false, fmt.Errorf("failed to list LVMVolumeChecks: %w", err)
+	}
+	for _, check := range checks.Items {
+		if check.Status.Phase == lvmv1alpha1.LVMVolumeCheckCompleted ||
+			check.Status.Phase == lvmv1alpha1.LVMVolumeCheckFailed ||
+			check.Spec.Source == lvmv1alpha1.LVMVolumeCheckSourceSnapshot {
+			continue
+		}
+		if check.Status.LogicalVolume != "" {
+			if check.Status.NodeName == s.nodeName && check.Status.LogicalVolume == volumeID {
+				return true, nil
+			}
+			continue
+		}
+		claim := check.Spec.PersistentVolumeClaim
+		if volume.claim != nil && claim.Name == volume.claim.Name && claim.Namespace == volume.claim.Namespace {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// publishedVolume returns the volume with the parameters of its StorageClass and the annotations of its
+// PersistentVolumeClaim. Volumes without LogicalVolume or PersistentVolume are returned as nil.
+func (s *NodeServer) publishedVolume(ctx context.Context, volumeID string) (*publishedVolume, error) {
+	logicalVolumes := &topolvmv1.LogicalVolumeList{}
+	if err := s.client.List(ctx, logicalVolumes); err != nil {
+		return nil, fmt.Errorf("failed to list LogicalVolumes: %w", err)
+	}
+	volume := &publishedVolume{}
+	for i := range logicalVolumes.Items {
+		if logicalVolumes.Items[i].Status.VolumeID == volumeID && logicalVolumes.Items[i].Spec.NodeName == s.nodeName {
+			volume.logicalVolume = &logicalVolumes.Items[i]
+			break
+		}
+	}
+	if volume.logicalVolume == nil {
+		return nil, nil
+	}
+
+	pv := &corev1.PersistentVolume{}
+	if err := s.client.Get(ctx, client.ObjectKey{Name: volume.logicalVolume.Spec.Name}, pv); k8serrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get PersistentVolume %s: %w", volume.logicalVolume.Spec.Name, err)
+	}
+
+	if pv.Spec.StorageClassName != "" {
+		storageClass := &storagev1.StorageClass{}
+		if err := s.client.Get(ctx, client.ObjectKey{Name: pv.Spec.StorageClassName}, storageClass); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to get StorageClass %s: %w", pv.Spec.StorageClassName, err)
+		}
+		volume.parameters = storageClass.Parameters
+	}
+	if claimRef := pv.Spec.ClaimRef; claimRef != nil {
+		volume.claim = &types.NamespacedName{Name: claimRef.Name, Namespace: claimRef.Namespace}
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := s.apiReader.Get(ctx, *volume.claim, pvc); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to get PersistentVolumeClaim %s/%s: %w", claimRef.Namespace, claimRef.Name, err)
+		}
+		volume.annotations = pvc.Annotations
+	}
+	return volume, nil
+}
@@ -0,0 +1,263 @@
+package csi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	lvmv1alpha1 "github.com/openshift/lvm-operator/v4/api/v1alpha1"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/constants"
+	"github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/mkfs"
+	mkfsmocks "github.com/openshift/lvm-operator/v4/internal/controllers/vgmanager/mkfs/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNode     = "test-node"
+	testVolumeID = "0b3d0f2c-3a8e-4c36-9a4b-5f1d2e3c4b5a"
+	testPodUID   = "8d2f6c1e-7b4a-4e59-a3c2-1f0e9d8c7b6a"
+)
+
+type fakeNodeServer struct {
+	csi.UnimplementedNodeServer
+}
+
+func (fakeNodeServer) NodePublishVolume(context.Context, *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+// newHost creates the device of the volume and the cgroup of the pod with the systemd cgroup driver.
+func newHost(t *testing.T) (root, ioMax string) {
+	t.Helper()
+	root = t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "dev", "mapper"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "dev", "dm-3"), nil, 0o644))
+	require.NoError(t, os.Symlink("../dm-3", filepath.Join(root, "dev", "mapper", "vg1-0b3d0f2c--3a8e--4c36--9a4b--5f1d2e3c4b5a")))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "sys", "block", "dm-3"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "sys", "block", "dm-3", "dev"), []byte("253:3\n"), 0o644))
+
+	cgroup := filepath.Join(root, cgroupRoot, "kubepods.slice", "kubepods-burstable.slice",
+		"kubepods-burstable-pod8d2f6c1e_7b4a_4e59_a3c2_1f0e9d8c7b6a.slice")
+	require.NoError(t, os.MkdirAll(cgroup, 0o755))
+	ioMax = filepath.Join(cgroup, "io.max")
+	require.NoError(t, os.WriteFile(ioMax, nil, 0o644))
+	return root, ioMax
+}
+
+func newTestNodeServer(t *testing.T, root string, mkfs mkfs.Mkfs, parameters, claimAnnotations map[string]string, extraObjs ...client.Object) *NodeServer {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, topolvmv1.AddToScheme(scheme))
+	require.NoError(t, lvmv1alpha1.AddToScheme(scheme))
+
+	objs := []client.Object{
+		&topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-uid"},
+			Spec:       topolvmv1.LogicalVolumeSpec{Name: "pvc-uid", NodeName: testNode, DeviceClass: "vg1"},
+			Status:     topolvmv1.LogicalVolumeStatus{VolumeID: testVolumeID},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-uid"},
+			Spec: corev1.PersistentVolumeSpec{
+				StorageClassName: "lvms-vg1",
+				ClaimRef:         &corev1.ObjectReference{Name: "data", Namespace: "app"},
+			},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "app", Annotations: claimAnnotations},
+		},
+		&storagev1.StorageClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "lvms-vg1"},
+			Parameters: parameters,
+		},
+	}
+	clnt := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, extraObjs...)...).Build()
+	s := NewNodeServer(fakeNodeServer{}, clnt, clnt, mkfs, testNode)
+	s.root = root
+	return s
+}
+
+func TestNodeServer_NodePublishVolume(t *testing.T) {
+	root, ioMax := newHost(t)
+	s := newTestNodeServer(t, root, mkfsmocks.NewMockMkfs(t),
+		map[string]string{constants.ReadIOPSKey: "1000", constants.WriteBPSKey: "10Mi"},
+		map[string]string{constants.WriteIOPSKey: "200"})
+
+	_, err := s.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+		VolumeId:      testVolumeID,
+		VolumeContext: map[string]string{podUIDKey: testPodUID},
+	})
+	require.NoError(t, err)
+
+	written, err := os.ReadFile(ioMax)
+	require.NoError(t, err)
+	assert.Equal(t, "253:3 rbps=max wbps=10485760 riops=1000 wiops=200", string(written))
+}
+
+func TestNodeServer_NodePublishVolume_NoCgroup(t *testing.T) {
+	root, ioMax := newHost(t)
+	require.NoError(t, os.Remove(ioMax))
+	s := newTestNodeServer(t, root, mkfsmocks.NewMockMkfs(t), map[string]string{constants.ReadIOPSKey: "1000"}, nil)
+
+	_, err := s.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+		VolumeId:      testVolumeID,
+		VolumeContext: map[string]string{podUIDKey: testPodUID},
+	})
+	assert.ErrorContains(t, err, "io controller")
+}
+
+func TestNodeServer_NodePublishVolume_UnknownVolume(t *testing.T) {
+	s := newTestNodeServer(t, t.TempDir(), mkfsmocks.NewMockMkfs(t), nil, nil)
+
+	_, err := s.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+		VolumeId:      "unknown",
+		VolumeContext: map[string]string{podUIDKey: testPodUID},
+	})
+	assert.NoError(t, err)
+}
+
+func TestNodeServer_NodePublishVolume_Checking(t *testing.T) {
+	check := &lvmv1alpha1.LVMVolumeCheck{
+		ObjectMeta: metav1.ObjectMeta{Name: "check", Namespace: "openshift-lvm-storage"},
+		Status: lvmv1alpha1.LVMVolumeCheckStatus{
+			Phase:         lvmv1alpha1.LVMVolumeCheckChecking,
+			NodeName:      testNode,
+			LogicalVolume: testVolumeID,
+		},
+	}
+	s := newTestNodeServer(t, t.TempDir(), mkfsmocks.NewMockMkfs(t), nil, nil, check)
+
+	_, err := s.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+		VolumeId:      testVolumeID,
+		VolumeContext: map[string]string{podUIDKey: testPodUID},
+	})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestNodeServer_NodePublishVolume_CheckPhases(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   *lvmv1alpha1.LVMVolumeCheck
+		blocked bool
+	}{
+		{
+			name: "pending check of the claim",
+			check: &lvmv1alpha1.LVMVolumeCheck{
+				Spec: lvmv1alpha1.LVMVolumeCheckSpec{
+					PersistentVolumeClaim: lvmv1alpha1.VolumeCheckClaimReference{Name: "data", Namespace: "app"},
+				},
+				Status: lvmv1alpha1.LVMVolumeCheckStatus{Phase: lvmv1alpha1.LVMVolumeCheckPending},
+			},
+			blocked: true,
+		},
+		{
+			name: "check waiting for unpublish",
+			check: &lvmv1alpha1.LVMVolumeCheck{
+				Status: lvmv1alpha1.LVMVolumeCheckStatus{
+					Phase:         lvmv1alpha1.LVMVolumeCheckWaitingForUnpublish,
+					NodeName:      testNode,
+					LogicalVolume: testVolumeID,
+				},
+			},
+			blocked: true,
+		},
+		{
+			name: "pending check of another claim",
+			check: &lvmv1alpha1.LVMVolumeCheck{
+				Spec: lvmv1alpha1.LVMVolumeCheckSpec{
+					PersistentVolumeClaim: lvmv1alpha1.VolumeCheckClaimReference{Name: "other", Namespace: "app"},
+				},
+			},
+		},
+		{
+			name: "completed check",
+			check: &lvmv1alpha1.LVMVolumeCheck{
+				Status: lvmv1alpha1.LVMVolumeCheckStatus{
+					Phase:         lvmv1alpha1.LVMVolumeCheckCompleted,
+					NodeName:      testNode,
+					LogicalVolume: testVolumeID,
+				},
+			},
+		},
+		{
+			name: "check of a snapshot",
+			check: &lvmv1alpha1.LVMVolumeCheck{
+				Spec: lvmv1alpha1.LVMVolumeCheckSpec{Source: lvmv1alpha1.LVMVolumeCheckSourceSnapshot},
+				Status: lvmv1alpha1.LVMVolumeCheckStatus{
+					Phase:         lvmv1alpha1.LVMVolumeCheckChecking,
+					NodeName:      testNode,
+					LogicalVolume: testVolumeID,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check.ObjectMeta = metav1.ObjectMeta{Name: "check", Namespace: "openshift-lvm-storage"}
+			root, _ := newHost(t)
+			s := newTestNodeServer(t, root, mkfsmocks.NewMockMkfs(t), nil, nil, tt.check)
+
+			_, err := s.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+				VolumeId:      testVolumeID,
+				VolumeContext: map[string]string{podUIDKey: testPodUID},
+			})
+			if tt.blocked {
+				assert.Equal(t, codes.Unavailable, status.Code(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNodeServer_NodePublishVolume_MkfsOptions(t *testing.T) {
+	mountCapability := &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{FsType: "xfs"}},
+	}
+	blockCapability := &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+	}
+
+	tests := []struct {
+		name       string
+		capability *csi.VolumeCapability
+		parameters map[string]string
+		wantFormat bool
+	}{
+		{"filesystem with mkfs options", mountCapability, map[string]string{constants.MkfsOptionsKey: "-m reflink=1"}, true},
+		{"filesystem without mkfs options", mountCapability, nil, false},
+		{"block volume", blockCapability, map[string]string{constants.MkfsOptionsKey: "-m reflink=1"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMkfs := mkfsmocks.NewMockMkfs(t)
+			if tt.wantFormat {
+				mockMkfs.EXPECT().Format(mock.Anything, "/dev/mapper/vg1-0b3d0f2c--3a8e--4c36--9a4b--5f1d2e3c4b5a",
+					"xfs", []string{"-m", "reflink=1"}).Return(true, nil).Once()
+			}
+			s := newTestNodeServer(t, t.TempDir(), mockMkfs, tt.parameters, nil)
+
+			_, err := s.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+				VolumeId:         testVolumeID,
+				VolumeCapability: tt.capability,
+				VolumeContext:    map[string]string{podUIDKey: testPodUID},
+			})
+			assert.NoError(t, err)
+		})
+	}
+}